@@ -13,9 +13,12 @@ import (
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
 
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
 	"github.com/m19cmjigen/sandbox-project-management/backend/internal/infrastructure/router"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/config"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/logger"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
 )
 
 func main() {
@@ -37,8 +40,12 @@ func main() {
 	log.Info("Starting application",
 		zap.String("port", cfg.Server.Port),
 		zap.String("gin_mode", cfg.Server.GinMode),
+		zap.String("report_timezone", cfg.Report.Timezone),
 	)
 
+	// 日付の判定・表示で使うタイムゾーン（組織ごとの設定が無い場合）
+	normalizer.SetLocation(cfg.Report.Location)
+
 	// 本番モードでデフォルトのJWT_SECRETが使われていれば警告を出す
 	if cfg.Server.GinMode == "release" && cfg.Auth.JWTSecret == "dev-secret-change-in-production" {
 		log.Warn("SECURITY WARNING: JWT_SECRET is using the insecure default value in release mode. Set JWT_SECRET environment variable to a strong random secret.")
@@ -70,8 +77,29 @@ func main() {
 		log.Fatal("Failed to ping database", zap.Error(err))
 	}
 
+	// DB に保存する Jira API トークンの暗号化鍵（TOKEN_ENCRYPTION_KEYS）
+	cipher, err := secrets.LoadTokenCipher()
+	if err != nil {
+		log.Fatal("Failed to load token encryption keys", zap.Error(err))
+	}
+	if !cipher.Enabled() {
+		log.Warn("SECURITY WARNING: TOKEN_ENCRYPTION_KEYS is not set. Jira API tokens are stored in the database as plaintext.")
+	}
+
+	// Jira 認証情報の取得元と優先順位（JIRA_CREDENTIAL_SOURCES）。バッチと同じチェーンで手動同期する
+	creds, err := secrets.LoadCredentialProvider(db, cipher)
+	if err != nil {
+		log.Fatal("Failed to load jira credential sources", zap.Error(err))
+	}
+
+	// 手動同期の Jira クライアントと Syncer の設定（JIRA_SEARCH_API, BATCH_*）。バッチと共通
+	syncOpts, err := batch.LoadSyncOptions()
+	if err != nil {
+		log.Fatal("Failed to load sync options", zap.Error(err))
+	}
+
 	// ルーターの初期化
-	r := router.NewRouter(cfg, db, log)
+	r := router.NewRouter(cfg, db, log, cipher, creds, syncOpts)
 
 	// HTTPサーバーの設定
 	srv := &http.Server{
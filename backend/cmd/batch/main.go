@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
@@ -15,6 +18,7 @@ import (
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/logger"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/metrics"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
 )
 
@@ -37,6 +41,9 @@ func run() error {
 	}
 	defer log.Sync()
 
+	// 期日判定・日次集計で使うタイムゾーン（組織ごとの設定が無い場合）
+	normalizer.SetLocation(cfg.Report.Location)
+
 	// DB 接続
 	db, err := sqlx.Connect("postgres", cfg.Database.GetDSN())
 	if err != nil {
@@ -44,47 +51,150 @@ func run() error {
 	}
 	defer db.Close()
 
-	// Jira 認証情報を環境変数から読み込む
-	// 本番環境では ECS タスク定義の secrets フィールドで AWS Secrets Manager から注入される
-	// 詳細は docs/secrets-management.md を参照
-	jiraCreds, err := secrets.LoadJira()
+	// Jira クライアントと Syncer の設定（JIRA_SEARCH_API, BATCH_*）。API の手動同期と共通
+	syncOpts, err := batch.LoadSyncOptions()
 	if err != nil {
-		return fmt.Errorf("load jira credentials: %w", err)
+		return err
 	}
 
-	jiraClient := jiraclient.New(jiraclient.Config{
-		BaseURL:  jiraCreds.BaseURL,
-		Email:    jiraCreds.Email,
-		APIToken: jiraCreds.APIToken,
-	})
-
-	workerCount, _ := strconv.Atoi(getEnv("BATCH_WORKER_COUNT", "5"))
-	// BATCH_SYNC_MODE: "full"（デフォルト）または "delta"
+	// BATCH_SYNC_MODE: "full"（デフォルト）、"delta"、"resume"、"snapshot" または "recalc"
 	syncMode := getEnv("BATCH_SYNC_MODE", "full")
 	// METRICS_NAMESPACE: CloudWatch メトリクスのネームスペース。空の場合はメトリクス送信を無効化
 	metricsNamespace := getEnv("METRICS_NAMESPACE", "")
 
-	repo := batch.NewRepository(db)
-	syncer := batch.NewSyncer(jiraClient, repo, log.Logger, workerCount)
-
 	// METRICS_NAMESPACE が設定されている場合は CloudWatch EMF でメトリクスを送信する
+	var recorder metrics.Recorder = metrics.NoopRecorder{}
 	if metricsNamespace != "" {
-		syncer.SetRecorder(metrics.NewEMFRecorder(metricsNamespace, os.Stdout))
+		recorder = metrics.NewEMFRecorder(metricsNamespace, os.Stdout)
 		log.Info("metrics enabled", zap.String("namespace", metricsNamespace))
 	}
 
+	// ECS はタスク停止時に SIGTERM を送り、猶予期間後に SIGKILL する。
+	// シグナルを受けたら進行中の Jira リクエストとリトライ待機を中断し、
+	// sync_logs を ABORTED として確定させてから終了する（resume モードで再開可能）。
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 日次集計は全接続のプロジェクト・組織が対象のため、接続ごとには実行しない
+	if syncMode == "snapshot" {
+		log.Info("starting batch", zap.String("sync_mode", syncMode), zap.String("report_timezone", cfg.Report.Timezone))
+		return batch.NewSyncer(nil, batch.NewRepository(db, 0), log.Logger, 0).RunDelaySnapshot(ctx)
+	}
+
+	// 遅延ステータスの再計算は DB のデータだけで行うため、Jira の認証情報を解決せず
+	// 無効な接続・認証情報未設定の接続も含めた全接続を対象にする
+	if syncMode == "recalc" {
+		log.Info("starting batch", zap.String("sync_mode", syncMode), zap.String("report_timezone", cfg.Report.Timezone))
+		conns, err := batch.ListConnections(ctx, db)
+		if err != nil {
+			return err
+		}
+		newRepo := func(id int64) batch.Repository { return batch.NewRepository(db, id) }
+		return batch.RunDelayRecalcAll(ctx, conns, newRepo, log.Logger, recorder)
+	}
+
+	// TOKEN_ENCRYPTION_KEYS: DB に保存された Jira API トークンの復号に使う鍵（docs/secrets-management.md を参照）
+	cipher, err := secrets.LoadTokenCipher()
+	if err != nil {
+		return fmt.Errorf("load token encryption keys: %w", err)
+	}
+
+	// JIRA_CREDENTIAL_SOURCES: 認証情報の取得元と優先順位（デフォルト "env,file,db"、API の手動同期と共通）
+	creds, err := secrets.LoadCredentialProvider(db, cipher)
+	if err != nil {
+		return fmt.Errorf("load jira credential sources: %w", err)
+	}
+
+	conns, err := loadConnections(ctx, db, creds, log.Logger)
+	if err != nil {
+		return err
+	}
+
+	// 接続の認証方式（basic / pat / oauth）に応じて Jira クライアントを構成する。OAuth アプリは JIRA_OAUTH_* で設定する
+	jiraAuth := batch.NewJiraAuth(db, cipher, cfg.JiraOAuth)
+
 	log.Info("starting batch",
-		zap.String("jira_base_url", jiraCreds.BaseURL),
-		zap.Int("worker_count", workerCount),
+		zap.Int("connections", len(conns)),
+		zap.String("jira_search_api", string(syncOpts.SearchAPI)),
+		zap.Float64("rate_limit_rps", syncOpts.RateLimitRPS),
+		zap.Int("worker_count", syncOpts.WorkerCount),
+		zap.Int("batch_size", syncOpts.BatchSize),
 		zap.String("sync_mode", syncMode),
+		zap.String("report_timezone", cfg.Report.Timezone),
 	)
 
-	switch syncMode {
-	case "delta":
-		return syncer.RunDeltaSync(context.Background())
-	default:
-		return syncer.RunFullSync(context.Background())
+	// 接続ごとに Jira クライアント（レート制限）と Syncer を分けて並列に同期する
+	return runConnections(ctx, conns, func(ctx context.Context, conn batch.Connection) error {
+		connLog := log.Logger.With(zap.String("connection", conn.Name))
+		connLog.Info("starting connection sync",
+			zap.String("jira_base_url", conn.BaseURL),
+			zap.String("api_flavor", conn.APIFlavor),
+			zap.String("auth_type", conn.AuthType),
+			zap.String("credential_source", conn.CredentialSource),
+		)
+
+		flavor, err := jiraclient.ParseAPIFlavor(conn.APIFlavor)
+		if err != nil {
+			return err
+		}
+		clientCfg := jiraclient.Config{Flavor: flavor}
+		if err := jiraAuth.Configure(&clientCfg, conn.ID, conn.Credentials()); err != nil {
+			return fmt.Errorf("configure jira client: %w", err)
+		}
+		syncer := syncOpts.NewConnectionSyncer(clientCfg, batch.NewRepository(db, conn.ID), connLog)
+		syncer.SetRecorder(recorder)
+
+		switch syncMode {
+		case "delta":
+			return syncer.RunDeltaSync(ctx)
+		case "resume":
+			return syncer.RunResumeSync(ctx)
+		default:
+			return syncer.RunFullSync(ctx)
+		}
+	})
+}
+
+// loadConnections returns the enabled Jira connections to sync with their
+// credentials resolved by creds, the same provider chain the API uses for
+// manual syncs (see secrets.LoadCredentialProvider and docs/secrets-management.md).
+func loadConnections(ctx context.Context, db *sqlx.DB, creds secrets.CredentialProvider, log *zap.Logger) ([]batch.Connection, error) {
+	conns, err := batch.ListEnabledConnections(ctx, db, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	// 認証情報が未設定の接続は同期できない
+	configured := conns[:0]
+	for _, conn := range conns {
+		if conn.CredentialSource == "" {
+			log.Warn("skipping jira connection without credentials", zap.String("connection", conn.Name))
+			continue
+		}
+		configured = append(configured, conn)
+	}
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("no enabled jira connection is configured")
+	}
+	return configured, nil
+}
+
+// runConnections runs fn for every connection in parallel and returns the
+// errors of the connections that failed.
+func runConnections(ctx context.Context, conns []batch.Connection, fn func(context.Context, batch.Connection) error) error {
+	errs := make([]error, len(conns))
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx, conn); err != nil {
+				errs[i] = fmt.Errorf("connection %q: %w", conn.Name, err)
+			}
+		}()
 	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
 func getEnv(key, defaultVal string) string {
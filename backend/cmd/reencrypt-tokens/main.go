@@ -0,0 +1,74 @@
+// Command reencrypt-tokens encrypts the Jira API tokens and webhook secrets
+// stored in jira_connections and the OAuth tokens stored in jira_oauth_tokens
+// with the current token encryption key.
+//
+// Run it once after enabling encryption (TOKEN_ENCRYPTION_KEYS) to encrypt the
+// tokens stored as plaintext, and after each key rotation to re-encrypt the
+// tokens encrypted with the retired key. With -plaintext it decrypts the tokens
+// instead, to disable encryption or roll back migration 000023.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/config"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/logger"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "reencrypt-tokens error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var opts batch.ReencryptOptions
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "report the number of tokens to rewrite without updating them")
+	flag.BoolVar(&opts.Plaintext, "plaintext", false, "decrypt the tokens and store them as plaintext")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	log, err := logger.New(cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		return fmt.Errorf("init logger: %w", err)
+	}
+	defer log.Sync()
+
+	cipher, err := secrets.LoadTokenCipher()
+	if err != nil {
+		return fmt.Errorf("load token encryption keys: %w", err)
+	}
+
+	db, err := sqlx.Connect("postgres", cfg.Database.GetDSN())
+	if err != nil {
+		return fmt.Errorf("connect db: %w", err)
+	}
+	defer db.Close()
+
+	n, err := batch.ReencryptTokens(context.Background(), db, cipher, opts)
+	if err != nil {
+		return err
+	}
+
+	log.Info("jira tokens rewritten",
+		zap.Int("rows", n),
+		zap.String("key_id", cipher.CurrentKeyID()),
+		zap.Bool("plaintext", opts.Plaintext),
+		zap.Bool("dry_run", opts.DryRun),
+	)
+	return nil
+}
@@ -0,0 +1,211 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+// Connection is a Jira site whose projects and issues are synced.
+type Connection struct {
+	ID       int64  `db:"id"`
+	Name     string `db:"name"`
+	BaseURL  string `db:"base_url"`
+	Email    string `db:"email"`
+	APIToken string `db:"api_token"`
+	// APIFlavor is the REST API of the site ("cloud" or "datacenter"; see
+	// jiraclient.APIFlavor). It is a setting of the connection, not a credential.
+	APIFlavor string `db:"api_flavor"`
+	// AuthType and CloudID are the authentication of the resolved credentials
+	// (see secrets.JiraCredentials).
+	AuthType string `db:"-"`
+	CloudID  string `db:"-"`
+	// CredentialSource is the source the credentials were resolved from (see
+	// secrets.CredentialProvider).
+	CredentialSource string `db:"-"`
+	// WebhookSecret is the stored (encrypted) secret webhooks of the connection
+	// are signed with. It is only read by ReencryptTokens.
+	WebhookSecret string `db:"webhook_secret"`
+}
+
+// Credentials returns the resolved credentials of the connection.
+func (c Connection) Credentials() secrets.JiraCredentials {
+	return secrets.JiraCredentials{
+		BaseURL:  c.BaseURL,
+		Email:    c.Email,
+		APIToken: c.APIToken,
+		AuthType: c.AuthType,
+		CloudID:  c.CloudID,
+		Source:   c.CredentialSource,
+	}
+}
+
+// ListConnections returns every Jira connection ordered by ID, enabled or not,
+// without credentials. It is used by jobs that only work on the stored data.
+func ListConnections(ctx context.Context, db *sqlx.DB) ([]Connection, error) {
+	var conns []Connection
+	err := db.SelectContext(ctx, &conns, `
+		SELECT id, name, api_flavor
+		FROM jira_connections
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list jira connections: %w", err)
+	}
+	return conns, nil
+}
+
+// ListEnabledConnections returns the enabled Jira connections ordered by ID,
+// with their credentials resolved by creds. Connections for which creds has no
+// credentials are returned without them.
+func ListEnabledConnections(ctx context.Context, db *sqlx.DB, creds secrets.CredentialProvider) ([]Connection, error) {
+	var conns []Connection
+	err := db.SelectContext(ctx, &conns, `
+		SELECT id, name, api_flavor
+		FROM jira_connections
+		WHERE enabled
+		ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list jira connections: %w", err)
+	}
+	for i := range conns {
+		c, err := creds.Credentials(ctx, conns[i].Name)
+		if errors.Is(err, secrets.ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolve credentials of jira connection %q: %w", conns[i].Name, err)
+		}
+		conns[i].BaseURL = c.BaseURL
+		conns[i].Email = c.Email
+		conns[i].APIToken = c.APIToken
+		conns[i].AuthType = c.AuthType
+		conns[i].CloudID = c.CloudID
+		conns[i].CredentialSource = c.Source
+	}
+	return conns, nil
+}
+
+// ReencryptOptions controls ReencryptTokens.
+type ReencryptOptions struct {
+	// Plaintext decrypts the tokens and stores them as plaintext, to roll back
+	// encryption before the keys are removed.
+	Plaintext bool
+	// DryRun counts the tokens to rewrite without updating them.
+	DryRun bool
+}
+
+// ReencryptTokens encrypts the API tokens and webhook secrets of the Jira
+// connections and their OAuth tokens that are stored as plaintext or encrypted with a key other than
+// the current one, and returns the number of rows rewritten. All rows are
+// rewritten in one transaction, so that a failure leaves every token unchanged.
+func ReencryptTokens(ctx context.Context, db *sqlx.DB, cipher *secrets.TokenCipher, opts ReencryptOptions) (int, error) {
+	if !opts.Plaintext && !cipher.Enabled() {
+		return 0, secrets.ErrNoEncryptionKey
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var conns []Connection
+	err = tx.SelectContext(ctx, &conns, `
+		SELECT id, name, base_url, email, api_token, webhook_secret
+		FROM jira_connections
+		ORDER BY id
+		FOR UPDATE`)
+	if err != nil {
+		return 0, fmt.Errorf("list jira connections: %w", err)
+	}
+
+	rewritten := 0
+	for _, conn := range conns {
+		token, tokenChanged, err := rewriteToken(cipher, opts, conn.APIToken)
+		if err != nil {
+			return 0, fmt.Errorf("api token of jira connection %q: %w", conn.Name, err)
+		}
+		webhookSecret, secretChanged, err := rewriteToken(cipher, opts, conn.WebhookSecret)
+		if err != nil {
+			return 0, fmt.Errorf("webhook secret of jira connection %q: %w", conn.Name, err)
+		}
+		if !tokenChanged && !secretChanged {
+			continue
+		}
+		rewritten++
+		if opts.DryRun {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE jira_connections SET api_token = $1, webhook_secret = $2 WHERE id = $3`,
+			token, webhookSecret, conn.ID)
+		if err != nil {
+			return 0, fmt.Errorf("update api token of jira connection %q: %w", conn.Name, err)
+		}
+	}
+
+	var oauthTokens []struct {
+		ConnectionID int64  `db:"connection_id"`
+		AccessToken  string `db:"access_token"`
+		RefreshToken string `db:"refresh_token"`
+	}
+	err = tx.SelectContext(ctx, &oauthTokens, `
+		SELECT connection_id, access_token, refresh_token
+		FROM jira_oauth_tokens
+		ORDER BY connection_id
+		FOR UPDATE`)
+	if err != nil {
+		return 0, fmt.Errorf("list jira oauth tokens: %w", err)
+	}
+	for _, t := range oauthTokens {
+		accessToken, accessChanged, err := rewriteToken(cipher, opts, t.AccessToken)
+		if err != nil {
+			return 0, fmt.Errorf("oauth access token of jira connection %d: %w", t.ConnectionID, err)
+		}
+		refreshToken, refreshChanged, err := rewriteToken(cipher, opts, t.RefreshToken)
+		if err != nil {
+			return 0, fmt.Errorf("oauth refresh token of jira connection %d: %w", t.ConnectionID, err)
+		}
+		if !accessChanged && !refreshChanged {
+			continue
+		}
+		rewritten++
+		if opts.DryRun {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE jira_oauth_tokens SET access_token = $1, refresh_token = $2 WHERE connection_id = $3`,
+			accessToken, refreshToken, t.ConnectionID)
+		if err != nil {
+			return 0, fmt.Errorf("update oauth tokens of jira connection %d: %w", t.ConnectionID, err)
+		}
+	}
+
+	if opts.DryRun {
+		return rewritten, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return rewritten, nil
+}
+
+// rewriteToken returns value re-encrypted with the current key (or decrypted
+// with opts.Plaintext) and whether it differs from the stored value.
+func rewriteToken(cipher *secrets.TokenCipher, opts ReencryptOptions, value string) (string, bool, error) {
+	if opts.Plaintext && !secrets.IsEncrypted(value) || !opts.Plaintext && !cipher.NeedsReencrypt(value) {
+		return value, false, nil
+	}
+	token, err := cipher.Decrypt(value)
+	if err != nil {
+		return "", false, fmt.Errorf("decrypt: %w", err)
+	}
+	if !opts.Plaintext {
+		if token, err = cipher.Encrypt(token); err != nil {
+			return "", false, fmt.Errorf("encrypt: %w", err)
+		}
+	}
+	return token, true, nil
+}
@@ -0,0 +1,210 @@
+package batch
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+func newTestCipher(t *testing.T) *secrets.TokenCipher {
+	t.Helper()
+	cipher, err := secrets.NewTokenCipher("k1", map[string][]byte{"k1": make([]byte, 32)})
+	require.NoError(t, err)
+	return cipher
+}
+
+// stubCredentials is a secrets.CredentialProvider keyed by connection name.
+type stubCredentials map[string]secrets.JiraCredentials
+
+func (s stubCredentials) Credentials(_ context.Context, connection string) (secrets.JiraCredentials, error) {
+	creds, ok := s[connection]
+	if !ok {
+		return secrets.JiraCredentials{}, secrets.ErrNoCredentials
+	}
+	return creds, nil
+}
+
+// failingCredentials is a secrets.CredentialProvider that always fails.
+type failingCredentials struct{}
+
+func (failingCredentials) Credentials(context.Context, string) (secrets.JiraCredentials, error) {
+	return secrets.JiraCredentials{}, errors.New("missing required environment variables: JIRA_EMAIL")
+}
+
+func TestListConnections_IncludesDisabled(t *testing.T) {
+	db, mock := newRepoDB(t)
+	mock.ExpectQuery(`SELECT id, name, api_flavor\s+FROM jira_connections\s+ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "api_flavor"}).
+			AddRow(1, "default", "cloud").
+			AddRow(2, "disabled", "cloud"))
+
+	conns, err := ListConnections(context.Background(), db)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Connection{{ID: 1, Name: "default", APIFlavor: "cloud"}, {ID: 2, Name: "disabled", APIFlavor: "cloud"}}, conns)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEnabledConnections(t *testing.T) {
+	db, mock := newRepoDB(t)
+	mock.ExpectQuery(`SELECT id, name, api_flavor\s+FROM jira_connections\s+WHERE enabled`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "api_flavor"}).
+			AddRow(1, "default", "cloud").
+			AddRow(3, "acquired", "datacenter").
+			AddRow(4, "unconfigured", "cloud"))
+	creds := stubCredentials{
+		"default":  {BaseURL: "https://a.atlassian.net", Email: "a@example.com", APIToken: "token-a", Source: secrets.SourceEnv},
+		"acquired": {BaseURL: "https://b.atlassian.net", Email: "b@example.com", APIToken: "token-b", Source: secrets.SourceDB},
+	}
+
+	conns, err := ListEnabledConnections(context.Background(), db, creds)
+
+	require.NoError(t, err)
+	require.Len(t, conns, 3)
+	assert.Equal(t, Connection{ID: 1, Name: "default", BaseURL: "https://a.atlassian.net", Email: "a@example.com", APIToken: "token-a", APIFlavor: "cloud", CredentialSource: "env"}, conns[0])
+	assert.Equal(t, "token-b", conns[1].APIToken)
+	assert.Equal(t, "datacenter", conns[1].APIFlavor)
+	assert.Equal(t, "db", conns[1].CredentialSource)
+	// 認証情報が無い接続は認証情報なしで返す（スキップは呼び出し側が判断する）
+	assert.Equal(t, Connection{ID: 4, Name: "unconfigured", APIFlavor: "cloud"}, conns[2])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListEnabledConnections_DBError(t *testing.T) {
+	db, mock := newRepoDB(t)
+	mock.ExpectQuery(`FROM jira_connections`).WillReturnError(sqlmock.ErrCancelled)
+
+	_, err := ListEnabledConnections(context.Background(), db, stubCredentials{})
+
+	assert.Error(t, err)
+}
+
+func TestListEnabledConnections_ProviderError(t *testing.T) {
+	db, mock := newRepoDB(t)
+	mock.ExpectQuery(`FROM jira_connections`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "default"))
+
+	_, err := ListEnabledConnections(context.Background(), db, failingCredentials{})
+
+	assert.ErrorContains(t, err, `jira connection "default"`)
+}
+
+func TestReencryptTokens(t *testing.T) {
+	db, mock := newRepoDB(t)
+	oldCipher := newTestCipher(t)
+	oldToken, err := oldCipher.Encrypt("token-b")
+	require.NoError(t, err)
+	cipher, err := secrets.NewTokenCipher("k2", map[string][]byte{"k1": make([]byte, 32), "k2": []byte("0123456789abcdef0123456789abcdef")})
+	require.NoError(t, err)
+	current, err := cipher.Encrypt("token-c")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM jira_connections\s+ORDER BY id\s+FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "base_url", "email", "api_token", "webhook_secret"}).
+			AddRow(1, "default", "https://a.atlassian.net", "a@example.com", "token-a", "").
+			AddRow(2, "acquired", "https://b.atlassian.net", "b@example.com", oldToken, current).
+			AddRow(3, "rotated", "https://c.atlassian.net", "c@example.com", current, "hook-c").
+			AddRow(4, "empty", "", "", "", ""))
+	// 平文と古い鍵で暗号化された値のみ現在の鍵で暗号化し直す（Webhook シークレットも同様）
+	mock.ExpectExec(`UPDATE jira_connections SET api_token = \$1, webhook_secret = \$2 WHERE id = \$3`).
+		WithArgs(encryptedWith{cipher, "token-a"}, "", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE jira_connections SET api_token`).
+		WithArgs(encryptedWith{cipher, "token-b"}, current, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE jira_connections SET api_token`).
+		WithArgs(current, encryptedWith{cipher, "hook-c"}, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// OAuth トークンはアクセストークン・リフレッシュトークンのどちらかが対象なら行ごと書き換える
+	mock.ExpectQuery(`FROM jira_oauth_tokens\s+ORDER BY connection_id\s+FOR UPDATE`).
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id", "access_token", "refresh_token"}).
+			AddRow(3, current, current).
+			AddRow(5, "access-plain", current))
+	mock.ExpectExec(`UPDATE jira_oauth_tokens SET access_token = \$1, refresh_token = \$2 WHERE connection_id = \$3`).
+		WithArgs(encryptedWith{cipher, "access-plain"}, current, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := ReencryptTokens(context.Background(), db, cipher, ReencryptOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReencryptTokens_Plaintext(t *testing.T) {
+	db, mock := newRepoDB(t)
+	cipher := newTestCipher(t)
+	encrypted, err := cipher.Encrypt("token-b")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM jira_connections`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "base_url", "email", "api_token"}).
+			AddRow(1, "default", "https://a.atlassian.net", "a@example.com", "token-a").
+			AddRow(2, "acquired", "https://b.atlassian.net", "b@example.com", encrypted))
+	mock.ExpectExec(`UPDATE jira_connections SET api_token`).
+		WithArgs("token-b", "", int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM jira_oauth_tokens`).
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id", "access_token", "refresh_token"}))
+	mock.ExpectCommit()
+
+	n, err := ReencryptTokens(context.Background(), db, cipher, ReencryptOptions{Plaintext: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReencryptTokens_DryRun(t *testing.T) {
+	db, mock := newRepoDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM jira_connections`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "base_url", "email", "api_token"}).
+			AddRow(1, "default", "https://a.atlassian.net", "a@example.com", "token-a"))
+	mock.ExpectQuery(`FROM jira_oauth_tokens`).
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id", "access_token", "refresh_token"}).
+			AddRow(3, "access-plain", "refresh-plain"))
+	mock.ExpectRollback()
+
+	n, err := ReencryptTokens(context.Background(), db, newTestCipher(t), ReencryptOptions{DryRun: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReencryptTokens_NoKey(t *testing.T) {
+	db, _ := newRepoDB(t)
+	disabled, err := secrets.NewTokenCipher("", nil)
+	require.NoError(t, err)
+
+	_, err = ReencryptTokens(context.Background(), db, disabled, ReencryptOptions{})
+
+	assert.ErrorIs(t, err, secrets.ErrNoEncryptionKey)
+}
+
+// encryptedWith matches an argument that cipher decrypts to plaintext.
+type encryptedWith struct {
+	cipher    *secrets.TokenCipher
+	plaintext string
+}
+
+func (e encryptedWith) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok || e.cipher.NeedsReencrypt(s) {
+		return false
+	}
+	plain, err := e.cipher.Decrypt(s)
+	return err == nil && plain == e.plaintext
+}
@@ -0,0 +1,173 @@
+package batch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/config"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+// JiraAuth builds the Jira client configuration of a connection from its
+// resolved credentials. The batch and the API share it so that scheduled and
+// manual syncs authenticate the same way.
+type JiraAuth struct {
+	DB     *sqlx.DB
+	Cipher *secrets.TokenCipher
+	// OAuth is the Atlassian OAuth 2.0 (3LO) app; nil when it is not configured.
+	OAuth *jiraclient.OAuthConfig
+}
+
+// NewJiraAuth returns a JiraAuth with the OAuth app of cfg, if configured.
+func NewJiraAuth(db *sqlx.DB, cipher *secrets.TokenCipher, cfg config.JiraOAuthConfig) JiraAuth {
+	a := JiraAuth{DB: db, Cipher: cipher}
+	if cfg.ClientID != "" {
+		a.OAuth = &jiraclient.OAuthConfig{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       strings.Fields(cfg.Scopes),
+		}
+	}
+	return a
+}
+
+// Configure sets the base URL and authenticator of cfg for the connection.
+func (a JiraAuth) Configure(cfg *jiraclient.Config, connectionID int64, creds secrets.JiraCredentials) error {
+	switch creds.AuthType {
+	case "", secrets.AuthTypeBasic:
+		cfg.BaseURL = creds.BaseURL
+		cfg.Auth = jiraclient.BasicAuth{Email: creds.Email, APIToken: creds.APIToken}
+	case secrets.AuthTypePAT:
+		cfg.BaseURL = creds.BaseURL
+		cfg.Auth = jiraclient.BearerAuth{Token: creds.APIToken}
+	case secrets.AuthTypeOAuth:
+		if a.OAuth == nil {
+			return errors.New("jira oauth app not configured (JIRA_OAUTH_CLIENT_ID)")
+		}
+		if creds.CloudID == "" {
+			return jiraclient.ErrOAuthReauthorize
+		}
+		// OAuth のアクセストークンはサイトの URL ではなく API ゲートウェイ経由で使う
+		cfg.BaseURL = a.OAuth.SiteURL(creds.CloudID)
+		cfg.Auth = jiraclient.NewOAuthAuth(a.OAuth, NewOAuthTokenStore(a.DB, a.Cipher, connectionID))
+	default:
+		return fmt.Errorf("unsupported jira auth type %q", creds.AuthType)
+	}
+	return nil
+}
+
+// OAuthTokenStore persists the OAuth token of a connection in jira_oauth_tokens,
+// encrypted with the token cipher. It implements jiraclient.OAuthTokenStore.
+type OAuthTokenStore struct {
+	db           *sqlx.DB
+	cipher       *secrets.TokenCipher
+	connectionID int64
+}
+
+// NewOAuthTokenStore returns the token store of the connection.
+func NewOAuthTokenStore(db *sqlx.DB, cipher *secrets.TokenCipher, connectionID int64) *OAuthTokenStore {
+	return &OAuthTokenStore{db: db, cipher: cipher, connectionID: connectionID}
+}
+
+// oauthTokenRow is a row of jira_oauth_tokens with the tokens encrypted.
+type oauthTokenRow struct {
+	AccessToken  string    `db:"access_token"`
+	RefreshToken string    `db:"refresh_token"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	Scope        string    `db:"scope"`
+}
+
+const oauthTokenQuery = `
+	SELECT access_token, refresh_token, expires_at, scope
+	FROM jira_oauth_tokens
+	WHERE connection_id = $1`
+
+// Load implements jiraclient.OAuthTokenStore.
+func (s *OAuthTokenStore) Load(ctx context.Context) (jiraclient.OAuthToken, error) {
+	return s.load(ctx, s.db, oauthTokenQuery)
+}
+
+// Update implements jiraclient.OAuthTokenStore. The row is locked until the
+// refreshed token is saved, so that concurrent refreshes in other processes
+// wait and then see the rotated refresh token.
+func (s *OAuthTokenStore) Update(ctx context.Context, refresh func(jiraclient.OAuthToken) (jiraclient.OAuthToken, error)) (jiraclient.OAuthToken, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return jiraclient.OAuthToken{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := s.load(ctx, tx, oauthTokenQuery+` FOR UPDATE`)
+	if err != nil {
+		return jiraclient.OAuthToken{}, err
+	}
+	token, err := refresh(current)
+	if err != nil {
+		return jiraclient.OAuthToken{}, err
+	}
+	if err := s.save(ctx, tx, token); err != nil {
+		return jiraclient.OAuthToken{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return jiraclient.OAuthToken{}, fmt.Errorf("commit: %w", err)
+	}
+	return token, nil
+}
+
+// Save stores the token obtained by the consent flow, replacing any previous one.
+func (s *OAuthTokenStore) Save(ctx context.Context, token jiraclient.OAuthToken) error {
+	return s.save(ctx, s.db, token)
+}
+
+func (s *OAuthTokenStore) load(ctx context.Context, q sqlx.QueryerContext, query string) (jiraclient.OAuthToken, error) {
+	var row oauthTokenRow
+	err := sqlx.GetContext(ctx, q, &row, query, s.connectionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jiraclient.OAuthToken{}, jiraclient.ErrOAuthReauthorize
+	}
+	if err != nil {
+		return jiraclient.OAuthToken{}, fmt.Errorf("fetch oauth token: %w", err)
+	}
+
+	token := jiraclient.OAuthToken{ExpiresAt: row.ExpiresAt, Scope: row.Scope}
+	if token.AccessToken, err = s.cipher.Decrypt(row.AccessToken); err != nil {
+		return jiraclient.OAuthToken{}, fmt.Errorf("decrypt oauth access token: %w", err)
+	}
+	if token.RefreshToken, err = s.cipher.Decrypt(row.RefreshToken); err != nil {
+		return jiraclient.OAuthToken{}, fmt.Errorf("decrypt oauth refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func (s *OAuthTokenStore) save(ctx context.Context, e sqlx.ExecerContext, token jiraclient.OAuthToken) error {
+	accessToken, err := s.cipher.Encrypt(token.AccessToken)
+	if err != nil {
+		return fmt.Errorf("encrypt oauth access token: %w", err)
+	}
+	refreshToken, err := s.cipher.Encrypt(token.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("encrypt oauth refresh token: %w", err)
+	}
+	_, err = e.ExecContext(ctx, `
+		INSERT INTO jira_oauth_tokens (connection_id, access_token, refresh_token, expires_at, scope)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (connection_id) DO UPDATE SET
+			access_token  = EXCLUDED.access_token,
+			refresh_token = EXCLUDED.refresh_token,
+			expires_at    = EXCLUDED.expires_at,
+			scope         = EXCLUDED.scope`,
+		s.connectionID, accessToken, refreshToken, token.ExpiresAt, token.Scope,
+	)
+	if err != nil {
+		return fmt.Errorf("save oauth token: %w", err)
+	}
+	return nil
+}
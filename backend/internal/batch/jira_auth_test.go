@@ -0,0 +1,117 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/config"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+var oauthTokenColumns = []string{"access_token", "refresh_token", "expires_at", "scope"}
+
+func TestJiraAuth_Configure(t *testing.T) {
+	a := NewJiraAuth(nil, newTestCipher(t), config.JiraOAuthConfig{ClientID: "client", Scopes: "read:jira-work offline_access"})
+	require.NotNil(t, a.OAuth)
+	assert.Equal(t, []string{"read:jira-work", "offline_access"}, a.OAuth.Scopes)
+
+	var cfg jiraclient.Config
+	require.NoError(t, a.Configure(&cfg, 1, secrets.JiraCredentials{BaseURL: "https://a.atlassian.net", Email: "a@example.com", APIToken: "token", AuthType: secrets.AuthTypeBasic}))
+	assert.Equal(t, "https://a.atlassian.net", cfg.BaseURL)
+	assert.Equal(t, jiraclient.BasicAuth{Email: "a@example.com", APIToken: "token"}, cfg.Auth)
+
+	require.NoError(t, a.Configure(&cfg, 2, secrets.JiraCredentials{BaseURL: "https://jira.example.com", APIToken: "pat", AuthType: secrets.AuthTypePAT}))
+	assert.Equal(t, jiraclient.BearerAuth{Token: "pat"}, cfg.Auth)
+
+	// OAuth は API ゲートウェイ経由でサイトにアクセスする
+	require.NoError(t, a.Configure(&cfg, 3, secrets.JiraCredentials{BaseURL: "https://c.atlassian.net", AuthType: secrets.AuthTypeOAuth, CloudID: "cloud-1"}))
+	assert.Equal(t, "https://api.atlassian.com/ex/jira/cloud-1", cfg.BaseURL)
+	assert.IsType(t, &jiraclient.OAuthAuth{}, cfg.Auth)
+}
+
+func TestJiraAuth_ConfigureOAuthErrors(t *testing.T) {
+	creds := secrets.JiraCredentials{BaseURL: "https://c.atlassian.net", AuthType: secrets.AuthTypeOAuth, CloudID: "cloud-1"}
+
+	noApp := NewJiraAuth(nil, newTestCipher(t), config.JiraOAuthConfig{})
+	assert.Error(t, noApp.Configure(&jiraclient.Config{}, 3, creds))
+
+	a := NewJiraAuth(nil, newTestCipher(t), config.JiraOAuthConfig{ClientID: "client"})
+	creds.CloudID = ""
+	assert.ErrorIs(t, a.Configure(&jiraclient.Config{}, 3, creds), jiraclient.ErrOAuthReauthorize)
+}
+
+func TestOAuthTokenStore_Load(t *testing.T) {
+	db, mock := newRepoDB(t)
+	cipher := newTestCipher(t)
+	access, _ := cipher.Encrypt("access-1")
+	refresh, _ := cipher.Encrypt("refresh-1")
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM jira_oauth_tokens\s+WHERE connection_id = \$1`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(oauthTokenColumns).AddRow(access, refresh, expiresAt, "read:jira-work"))
+
+	token, err := NewOAuthTokenStore(db, cipher, 3).Load(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, jiraclient.OAuthToken{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: expiresAt, Scope: "read:jira-work"}, token)
+}
+
+func TestOAuthTokenStore_LoadNotAuthorized(t *testing.T) {
+	db, mock := newRepoDB(t)
+	mock.ExpectQuery(`FROM jira_oauth_tokens`).WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(oauthTokenColumns))
+
+	_, err := NewOAuthTokenStore(db, newTestCipher(t), 3).Load(context.Background())
+
+	assert.ErrorIs(t, err, jiraclient.ErrOAuthReauthorize)
+}
+
+func TestOAuthTokenStore_Update(t *testing.T) {
+	db, mock := newRepoDB(t)
+	cipher := newTestCipher(t)
+	access, _ := cipher.Encrypt("access-1")
+	refresh, _ := cipher.Encrypt("refresh-1")
+	refreshed := jiraclient.OAuthToken{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresAt: time.Now().Add(time.Hour), Scope: "read:jira-work"}
+
+	// 行ロックを取ったまま更新し、ローテーション後のリフレッシュトークンを暗号化して保存する
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM jira_oauth_tokens\s+WHERE connection_id = \$1 FOR UPDATE`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(oauthTokenColumns).AddRow(access, refresh, time.Now().Add(-time.Minute), "read:jira-work"))
+	mock.ExpectExec(`INSERT INTO jira_oauth_tokens .* ON CONFLICT \(connection_id\) DO UPDATE`).
+		WithArgs(int64(3), encryptedWith{cipher, "access-2"}, encryptedWith{cipher, "refresh-2"}, refreshed.ExpiresAt, "read:jira-work").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var got string
+	token, err := NewOAuthTokenStore(db, cipher, 3).Update(context.Background(), func(current jiraclient.OAuthToken) (jiraclient.OAuthToken, error) {
+		got = current.RefreshToken
+		return refreshed, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-1", got)
+	assert.Equal(t, refreshed, token)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthTokenStore_UpdateRefreshError(t *testing.T) {
+	db, mock := newRepoDB(t)
+	cipher := newTestCipher(t)
+	access, _ := cipher.Encrypt("access-1")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(oauthTokenColumns).AddRow(access, access, time.Now(), ""))
+	mock.ExpectRollback()
+
+	_, err := NewOAuthTokenStore(db, cipher, 3).Update(context.Background(), func(jiraclient.OAuthToken) (jiraclient.OAuthToken, error) {
+		return jiraclient.OAuthToken{}, errors.New("refresh failed")
+	})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
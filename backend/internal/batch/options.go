@@ -0,0 +1,81 @@
+package batch
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+// SyncOptions are the Jira client and syncer settings shared by the scheduled
+// batch and the manual syncs started from the API, so that both ingest the same
+// data at the same rate.
+type SyncOptions struct {
+	// SearchAPI is the issue search endpoint used with Jira Cloud sites.
+	SearchAPI jiraclient.SearchAPI
+	// RateLimitRPS and RateLimitBurst configure the rate limiter shared by all
+	// workers of a connection. 0 disables the limiter.
+	RateLimitRPS   float64
+	RateLimitBurst int
+	WorkerCount    int
+	BatchSize      int
+	FetchChangelog bool
+	SyncSprints    bool
+}
+
+// LoadSyncOptions reads the sync options from the environment.
+//
+// Expected environment variables:
+//
+//	JIRA_SEARCH_API        — "enhanced" (default) or "legacy"; only applies to Jira Cloud
+//	BATCH_RATE_LIMIT_RPS   — Jira API requests per second per connection (default 10, 0 disables)
+//	BATCH_RATE_LIMIT_BURST — requests the rate limiter allows at once (default 10)
+//	BATCH_WORKER_COUNT     — projects synced in parallel per connection (default 5)
+//	BATCH_UPSERT_SIZE      — maximum issues written per upsert (default 500)
+//	BATCH_FETCH_CHANGELOG  — record status/due date history of new and updated issues (default true)
+//	BATCH_SYNC_SPRINTS     — sync boards and sprints in full syncs (default true)
+func LoadSyncOptions() (SyncOptions, error) {
+	var opts SyncOptions
+	var err error
+	if opts.SearchAPI, err = jiraclient.ParseSearchAPI(os.Getenv("JIRA_SEARCH_API")); err != nil {
+		return opts, fmt.Errorf("parse JIRA_SEARCH_API: %w", err)
+	}
+	if opts.RateLimitRPS, err = strconv.ParseFloat(getEnv("BATCH_RATE_LIMIT_RPS", "10"), 64); err != nil {
+		return opts, fmt.Errorf("parse BATCH_RATE_LIMIT_RPS: %w", err)
+	}
+	opts.RateLimitBurst, _ = strconv.Atoi(getEnv("BATCH_RATE_LIMIT_BURST", "10"))
+	opts.WorkerCount, _ = strconv.Atoi(getEnv("BATCH_WORKER_COUNT", "5"))
+	opts.BatchSize, _ = strconv.Atoi(getEnv("BATCH_UPSERT_SIZE", "500"))
+	if opts.FetchChangelog, err = strconv.ParseBool(getEnv("BATCH_FETCH_CHANGELOG", "true")); err != nil {
+		return opts, fmt.Errorf("parse BATCH_FETCH_CHANGELOG: %w", err)
+	}
+	if opts.SyncSprints, err = strconv.ParseBool(getEnv("BATCH_SYNC_SPRINTS", "true")); err != nil {
+		return opts, fmt.Errorf("parse BATCH_SYNC_SPRINTS: %w", err)
+	}
+	return opts, nil
+}
+
+// NewConnectionSyncer creates the Jira client and syncer of a connection.
+// cfg is the client configuration of the connection's site and credentials
+// (see JiraAuth.Configure); the search API and rate limit are set from opts.
+func (opts SyncOptions) NewConnectionSyncer(cfg jiraclient.Config, repo Repository, log *zap.Logger) *Syncer {
+	cfg.SearchAPI = opts.SearchAPI
+	cfg.RequestsPerSecond = opts.RateLimitRPS
+	cfg.Burst = opts.RateLimitBurst
+
+	s := NewSyncer(jiraclient.New(cfg), repo, log, opts.WorkerCount)
+	s.SetBatchSize(opts.BatchSize)
+	s.SetChangelog(opts.FetchChangelog)
+	s.SetSprints(opts.SyncSprints)
+	return s
+}
+
+func getEnv(key, defaultVal string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultVal
+}
@@ -0,0 +1,50 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+func TestLoadSyncOptions_Defaults(t *testing.T) {
+	for _, key := range []string{"JIRA_SEARCH_API", "BATCH_RATE_LIMIT_RPS", "BATCH_RATE_LIMIT_BURST", "BATCH_WORKER_COUNT", "BATCH_UPSERT_SIZE", "BATCH_FETCH_CHANGELOG", "BATCH_SYNC_SPRINTS"} {
+		t.Setenv(key, "")
+	}
+
+	opts, err := LoadSyncOptions()
+
+	require.NoError(t, err)
+	assert.Equal(t, SyncOptions{
+		SearchAPI:      jiraclient.SearchAPIEnhanced,
+		RateLimitRPS:   10,
+		RateLimitBurst: 10,
+		WorkerCount:    5,
+		BatchSize:      500,
+		FetchChangelog: true,
+		SyncSprints:    true,
+	}, opts)
+}
+
+func TestLoadSyncOptions_Invalid(t *testing.T) {
+	t.Setenv("BATCH_FETCH_CHANGELOG", "maybe")
+
+	_, err := LoadSyncOptions()
+
+	assert.ErrorContains(t, err, "BATCH_FETCH_CHANGELOG")
+}
+
+func TestSyncOptions_NewConnectionSyncer(t *testing.T) {
+	opts := SyncOptions{WorkerCount: 3, BatchSize: 50, FetchChangelog: true, SyncSprints: true}
+
+	s := opts.NewConnectionSyncer(jiraclient.Config{BaseURL: "https://example.atlassian.net"}, &mockRepository{}, zap.NewNop())
+
+	// スケジュール実行と手動同期で同じ取り込みを行う
+	assert.Equal(t, 3, s.workerCount)
+	assert.Equal(t, 50, s.batchSize)
+	assert.True(t, s.changelog)
+	assert.True(t, s.sprints)
+}
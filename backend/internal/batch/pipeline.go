@@ -0,0 +1,245 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+const (
+	defaultBatchSize = 500
+	// maxBatchSize keeps a single upsert under PostgreSQL's limit of 65535 bind
+	// parameters (26 columns per issue).
+	maxBatchSize = 2500
+)
+
+// issueBatcher normalizes pages of Jira issues and upserts them in batches of at
+// most batchSize, so that memory use does not grow with the number of issues.
+//
+// Pages and batches do not line up: a page may be split across two batches.
+// The batcher therefore tracks page boundaries and reports, after each flush,
+// the cursor of the last page whose issues have all been written. That cursor is
+// safe to store as a resume checkpoint.
+type issueBatcher struct {
+	repo         Repository
+	projectIDMap map[string]int64
+	// policies maps jira_project_id to the delay policy used for the delay status
+	// of the project's issues. Projects without an entry use the default policy.
+	policies map[string]normalizer.DelayPolicy
+	// fields maps canonical issue columns to the custom Jira fields they are read from.
+	fields    normalizer.FieldMapping
+	batchSize int
+	// onFlush is called after each upsert with the number of issues upserted, and
+	// the boundary of the last fully written page (nil if no page was completed).
+	onFlush func(ctx context.Context, upserted int, page *pageCursor) error
+	// fetchChangelog, when set, enables history recording: the status/due date
+	// transitions of new or updated issues are stored in issue_history with each
+	// upsert. It is used when the changelog embedded in the search result is
+	// truncated.
+	fetchChangelog changelogFetcher
+	log            *zap.Logger
+
+	buf        []normalizer.DBIssue
+	changelogs []*jiraclient.Changelog // buf と同じ並び（履歴を記録しない場合は空）
+	added      int                     // 受け取ったチケット数（累計）
+	written    int                     // upsert 済みのチケット数（累計）
+	upserted   int                     // UpsertIssues が返した件数（累計）
+	pages      []pageCursor            // upsert 完了待ちのページ境界
+}
+
+// issueSettings are the admin settings applied to every issue of a sync.
+type issueSettings struct {
+	policies map[string]normalizer.DelayPolicy
+	fields   normalizer.FieldMapping
+}
+
+// changelogFetcher returns the complete changelog of an issue.
+type changelogFetcher func(ctx context.Context, issueIDOrKey string) ([]jiraclient.ChangelogHistory, error)
+
+// pageCursor marks the end of a page: once end issues are written, next is a
+// valid resume cursor.
+type pageCursor struct {
+	end  int                   // ページ末尾までのチケット数（累計）
+	next jiraclient.PageCursor // 次ページのカーソル
+}
+
+func newIssueBatcher(repo Repository, projectIDMap map[string]int64, batchSize int) *issueBatcher {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &issueBatcher{
+		repo:         repo,
+		projectIDMap: projectIDMap,
+		batchSize:    batchSize,
+		buf:          make([]normalizer.DBIssue, 0, batchSize),
+		log:          zap.NewNop(),
+	}
+}
+
+// addPage normalizes a page of issues and flushes every full batch.
+func (b *issueBatcher) addPage(ctx context.Context, issues []jiraclient.Issue, next jiraclient.PageCursor) error {
+	now := normalizer.Now()
+	for _, issue := range issues {
+		di := normalizer.ConvertIssueWithPolicy(issue, now, b.policy(issue.Fields.Project.ID))
+		b.fields.Apply(&di, issue.Fields)
+		b.buf = append(b.buf, di)
+		if b.fetchChangelog != nil {
+			b.changelogs = append(b.changelogs, issue.Changelog)
+		}
+		b.added++
+		if len(b.buf) >= b.batchSize {
+			if err := b.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	b.pages = append(b.pages, pageCursor{end: b.added, next: next})
+	// ページ末尾でちょうどバッチが埋まった場合は、このページの境界も確定させる
+	if len(b.buf) == 0 {
+		return b.notify(ctx, 0)
+	}
+	return nil
+}
+
+// policy returns the delay policy of the given Jira project.
+func (b *issueBatcher) policy(jiraProjectID string) normalizer.DelayPolicy {
+	if p, ok := b.policies[jiraProjectID]; ok {
+		return p
+	}
+	return normalizer.DefaultDelayPolicy
+}
+
+// close flushes the remaining buffered issues.
+func (b *issueBatcher) close(ctx context.Context) error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	return b.flush(ctx)
+}
+
+func (b *issueBatcher) flush(ctx context.Context) error {
+	issues := b.buf
+	var n int
+	if b.fetchChangelog == nil {
+		var err error
+		if n, err = b.repo.UpsertIssues(ctx, issues, b.projectIDMap); err != nil {
+			return fmt.Errorf("upsert issues: %w", err)
+		}
+	} else {
+		// upsert で last_updated_at が上書きされる前に、新規・更新されたチケットを判定する
+		changed, err := b.repo.FindChangedIssues(ctx, b.buf)
+		if err != nil {
+			return fmt.Errorf("find changed issues: %w", err)
+		}
+		var history []normalizer.DBIssueHistory
+		if issues, history, err = b.collectHistory(ctx, changed); err != nil {
+			return err
+		}
+		// 履歴と同じトランザクションで保存し、履歴を記録できなかったチケットを更新済みにしない
+		if n, err = b.repo.UpsertIssuesWithHistory(ctx, issues, b.projectIDMap, history); err != nil {
+			return fmt.Errorf("upsert issues with history: %w", err)
+		}
+	}
+	if _, err := b.repo.ReplaceIssueLinks(ctx, issues); err != nil {
+		return fmt.Errorf("replace issue links: %w", err)
+	}
+
+	b.written += len(b.buf)
+	b.upserted += n
+	b.buf = b.buf[:0]
+	b.changelogs = b.changelogs[:0]
+	return b.notify(ctx, n)
+}
+
+// collectHistory returns the status/due date transitions of the changed issues in
+// the buffer, together with the issues to upsert. Changelogs embedded in the
+// search result are used as is; truncated ones are fetched in full. An issue
+// whose changelog cannot be fetched is logged and left out of the upsert, so
+// that one issue does not fail the whole sync and the issue is still seen as
+// changed (and its history retried) the next time it is synced.
+func (b *issueBatcher) collectHistory(ctx context.Context, changed map[string]bool) ([]normalizer.DBIssue, []normalizer.DBIssueHistory, error) {
+	var history []normalizer.DBIssueHistory
+	skipped := make(map[string]bool)
+	for i, issue := range b.buf {
+		if !changed[issue.JiraIssueID] {
+			continue
+		}
+
+		var histories []jiraclient.ChangelogHistory
+		if cl := b.changelogs[i]; cl != nil && cl.IsComplete() {
+			histories = cl.Histories
+		} else {
+			var err error
+			histories, err = b.fetchChangelog(ctx, issue.JiraIssueID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				b.log.Warn("failed to fetch issue changelog, skipping issue until next sync",
+					zap.String("issue_key", issue.JiraIssueKey),
+					zap.Error(err),
+				)
+				skipped[issue.JiraIssueID] = true
+				continue
+			}
+		}
+		history = append(history, normalizer.ConvertChangelog(issue.JiraIssueID, histories)...)
+	}
+
+	if len(skipped) == 0 {
+		return b.buf, history, nil
+	}
+	issues := make([]normalizer.DBIssue, 0, len(b.buf)-len(skipped))
+	for _, issue := range b.buf {
+		if !skipped[issue.JiraIssueID] {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, history, nil
+}
+
+// notify pops the page boundaries covered by the written issues and calls onFlush.
+func (b *issueBatcher) notify(ctx context.Context, upserted int) error {
+	var page *pageCursor
+	for len(b.pages) > 0 && b.pages[0].end <= b.written {
+		p := b.pages[0]
+		page = &p
+		b.pages = b.pages[1:]
+	}
+	if b.onFlush == nil || (upserted == 0 && page == nil) {
+		return nil
+	}
+	return b.onFlush(ctx, upserted, page)
+}
+
+// syncProgress accumulates the number of issues upserted by all workers of a sync
+// and reflects it in sync_logs.issues_synced while the sync is still RUNNING.
+type syncProgress struct {
+	repo  Repository
+	log   *zap.Logger
+	logID int64
+	total int64 // base（再開前の件数）を含む累計
+}
+
+func newSyncProgress(repo Repository, log *zap.Logger, logID int64, base int) *syncProgress {
+	return &syncProgress{repo: repo, log: log, logID: logID, total: int64(base)}
+}
+
+// add records n more upserted issues. Failing to update the progress never fails
+// the sync; it is only logged.
+func (p *syncProgress) add(ctx context.Context, n int) {
+	if n == 0 {
+		return
+	}
+	total := int(atomic.AddInt64(&p.total, int64(n)))
+	if err := p.repo.UpdateSyncProgress(ctx, p.logID, total); err != nil {
+		p.log.Warn("failed to update sync progress", zap.Int64("sync_log_id", p.logID), zap.Error(err))
+		return
+	}
+	p.log.Info("sync progress", zap.Int64("sync_log_id", p.logID), zap.Int("issues_synced", total))
+}
@@ -0,0 +1,247 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+func makeIssuePage(projectID string, from, n int) []jiraclient.Issue {
+	issues := make([]jiraclient.Issue, n)
+	for i := range issues {
+		id := fmt.Sprintf("%d", from+i)
+		issues[i] = makeIssue(id, "P-"+id, projectID)
+	}
+	return issues
+}
+
+func TestIssueBatcher_FlushesFixedSizeBatches(t *testing.T) {
+	repo := &mockRepository{}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 3)
+
+	var cursors []int
+	b.onFlush = func(_ context.Context, _ int, page *pageCursor) error {
+		if page != nil {
+			cursors = append(cursors, page.next.StartAt)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	// ページ 4 件 + 4 件 → バッチ 3, 3, 2
+	if err := b.addPage(ctx, makeIssuePage("10", 0, 4), jiraclient.PageCursor{StartAt: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.addPage(ctx, makeIssuePage("10", 4, 4), jiraclient.PageCursor{StartAt: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{3, 3, 2}; !reflect.DeepEqual(repo.upsertBatches, want) {
+		t.Errorf("expected batches %v, got %v", want, repo.upsertBatches)
+	}
+	// 1 ページ目は 2 回目の flush（6 件目）で書き終わり、2 ページ目は close で書き終わる
+	if want := []int{4, 8}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("expected cursors %v, got %v", want, cursors)
+	}
+	if b.upserted != 8 {
+		t.Errorf("expected 8 upserted, got %d", b.upserted)
+	}
+}
+
+func TestIssueBatcher_PageEndingOnBatchBoundary(t *testing.T) {
+	repo := &mockRepository{}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 2)
+
+	var pages []pageCursor
+	b.onFlush = func(_ context.Context, _ int, page *pageCursor) error {
+		if page != nil {
+			pages = append(pages, *page)
+		}
+		return nil
+	}
+
+	if err := b.addPage(context.Background(), makeIssuePage("10", 0, 2), jiraclient.PageCursor{Token: "tok-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// バッチとページ境界が一致した場合は close を待たずにチェックポイントできる
+	if want := []pageCursor{{end: 2, next: jiraclient.PageCursor{Token: "tok-2"}}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("expected %v, got %v", want, pages)
+	}
+}
+
+func TestIssueBatcher_UpsertErrorStops(t *testing.T) {
+	repo := &mockRepository{upsertIssuesErr: fmt.Errorf("db down")}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 1)
+
+	called := false
+	b.onFlush = func(context.Context, int, *pageCursor) error {
+		called = true
+		return nil
+	}
+
+	if err := b.addPage(context.Background(), makeIssuePage("10", 0, 2), jiraclient.PageCursor{Token: "tok-2"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if called {
+		t.Error("onFlush must not be called when the upsert fails")
+	}
+	if len(repo.upsertBatches) != 1 {
+		t.Errorf("expected processing to stop after the first batch, got %d batches", len(repo.upsertBatches))
+	}
+}
+
+func statusHistory(id, from, to string) jiraclient.ChangelogHistory {
+	return jiraclient.ChangelogHistory{
+		ID:      id,
+		Created: "2026-02-10T09:00:00.000+0900",
+		Items:   []jiraclient.ChangelogItem{{Field: "status", FieldID: "status", FromString: from, ToString: to}},
+	}
+}
+
+func TestIssueBatcher_RecordsHistoryOfChangedIssues(t *testing.T) {
+	jira := &mockJiraClient{changelogs: map[string][]jiraclient.ChangelogHistory{
+		"2": {statusHistory("21", "To Do", "In Progress"), statusHistory("22", "In Progress", "Done")},
+	}}
+	// 1: 埋め込みの changelog が完全、2: 途中までなので取得し直す、3: 変更なし
+	repo := &mockRepository{unchangedIssues: map[string]bool{"3": true}}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 10)
+	b.fetchChangelog = jira.GetIssueChangelogContext
+
+	issues := makeIssuePage("10", 1, 3)
+	issues[0].Changelog = &jiraclient.Changelog{Total: 1, Histories: []jiraclient.ChangelogHistory{statusHistory("11", "To Do", "Done")}}
+	issues[1].Changelog = &jiraclient.Changelog{Total: 2, Histories: []jiraclient.ChangelogHistory{statusHistory("21", "To Do", "In Progress")}}
+	issues[2].Changelog = &jiraclient.Changelog{Total: 1, Histories: []jiraclient.ChangelogHistory{statusHistory("31", "To Do", "Done")}}
+
+	ctx := context.Background()
+	if err := b.addPage(ctx, issues, jiraclient.PageCursor{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"2"}; !reflect.DeepEqual(jira.changelogCalls, want) {
+		t.Errorf("expected changelog fetched for %v, got %v", want, jira.changelogCalls)
+	}
+	var got []string
+	for _, h := range repo.history {
+		got = append(got, h.JiraIssueID+"/"+h.JiraHistoryID)
+	}
+	if want := []string{"1/11", "2/21", "2/22"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected history %v, got %v", want, got)
+	}
+}
+
+func TestIssueBatcher_ChangelogErrorSkipsIssue(t *testing.T) {
+	jira := &mockJiraClient{changelogErr: fmt.Errorf("not found")}
+	repo := &mockRepository{}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 10)
+	b.fetchChangelog = jira.GetIssueChangelogContext
+
+	var pages []*pageCursor
+	b.onFlush = func(_ context.Context, _ int, page *pageCursor) error {
+		pages = append(pages, page)
+		return nil
+	}
+
+	// 1: 埋め込みの changelog が完全、2: 途中までで取得に失敗する
+	issues := makeIssuePage("10", 1, 2)
+	issues[0].Changelog = &jiraclient.Changelog{Total: 1, Histories: []jiraclient.ChangelogHistory{statusHistory("11", "To Do", "Done")}}
+	issues[1].Changelog = &jiraclient.Changelog{Total: 2, Histories: []jiraclient.ChangelogHistory{statusHistory("21", "To Do", "In Progress")}}
+
+	ctx := context.Background()
+	if err := b.addPage(ctx, issues, jiraclient.PageCursor{}); err != nil {
+		t.Fatal(err)
+	}
+	// 履歴の取得失敗で同期自体は失敗させない
+	if err := b.close(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// 取得に失敗したチケットは保存せず、次回の同期でも新規・更新扱いにする
+	if len(repo.upsertedIssues) != 1 || repo.upsertedIssues[0].JiraIssueID != "1" {
+		t.Errorf("expected only issue 1 to be upserted, got %+v", repo.upsertedIssues)
+	}
+	if len(repo.history) != 1 || repo.history[0].JiraIssueID != "1" {
+		t.Errorf("expected only the history of issue 1, got %+v", repo.history)
+	}
+	if len(pages) != 1 || pages[0] == nil {
+		t.Errorf("expected the page to be completed, got %v", pages)
+	}
+}
+
+func TestIssueBatcher_HistoryErrorStops(t *testing.T) {
+	repo := &mockRepository{insertHistoryErr: fmt.Errorf("db down")}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 1)
+	b.fetchChangelog = (&mockJiraClient{}).GetIssueChangelogContext
+
+	called := false
+	b.onFlush = func(context.Context, int, *pageCursor) error {
+		called = true
+		return nil
+	}
+
+	if err := b.addPage(context.Background(), makeIssuePage("10", 0, 2), jiraclient.PageCursor{StartAt: 2}); err == nil {
+		t.Fatal("expected error")
+	}
+	// 履歴と同じトランザクションのため、チケットも更新済みにならない
+	if len(repo.upsertedIssues) != 0 {
+		t.Errorf("expected no issue to be upserted, got %d", len(repo.upsertedIssues))
+	}
+	if called {
+		t.Error("onFlush must not be called when the history cannot be stored")
+	}
+}
+
+func TestIssueBatcher_NoHistoryByDefault(t *testing.T) {
+	repo := &mockRepository{}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 10)
+
+	issues := makeIssuePage("10", 1, 1)
+	issues[0].Changelog = &jiraclient.Changelog{Total: 1, Histories: []jiraclient.ChangelogHistory{statusHistory("11", "To Do", "Done")}}
+	if err := b.addPage(context.Background(), issues, jiraclient.PageCursor{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.history) != 0 {
+		t.Errorf("expected no history when changelog ingestion is disabled, got %d", len(repo.history))
+	}
+}
+
+func TestIssueBatcher_ReplacesLinksOfEachBatch(t *testing.T) {
+	repo := &mockRepository{}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 2)
+
+	ctx := context.Background()
+	if err := b.addPage(ctx, makeIssuePage("10", 0, 3), jiraclient.PageCursor{StartAt: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"0", "1", "2"}; !reflect.DeepEqual(repo.linkedIssues, want) {
+		t.Errorf("expected links of %v to be replaced, got %v", want, repo.linkedIssues)
+	}
+}
+
+func TestIssueBatcher_ReplaceLinksErrorStops(t *testing.T) {
+	repo := &mockRepository{replaceLinksErr: fmt.Errorf("db down")}
+	b := newIssueBatcher(repo, map[string]int64{"10": 1}, 1)
+
+	err := b.addPage(context.Background(), makeIssuePage("10", 0, 2), jiraclient.PageCursor{StartAt: 2})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(repo.upsertBatches) != 1 {
+		t.Errorf("expected to stop after the first batch, got %v", repo.upsertBatches)
+	}
+}
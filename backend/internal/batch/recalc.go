@@ -0,0 +1,114 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/metrics"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+// RunDelayRecalc re-evaluates the delay status of every open issue from the data
+// stored in the DB, using the delay policy of its project and today's date in the
+// project's timezone (the organization's, else the reporting timezone).
+// It does not call Jira; it exists because delay_status is otherwise only
+// recomputed when an issue is upserted, so an issue whose due date passes
+// overnight would keep its old status until Jira reports a change.
+// The run is recorded in sync_logs with sync type RECALC.
+func (s *Syncer) RunDelayRecalc(ctx context.Context) error {
+	start := time.Now()
+	s.log.Info("delay recalc started")
+
+	logID, err := s.repo.StartSyncLog(ctx, "RECALC")
+	if err != nil {
+		return fmt.Errorf("start sync log: %w", err)
+	}
+
+	evaluated, updated, recalcErr := s.runDelayRecalc(ctx)
+
+	// sync_logs の更新は失敗・中断でも必ず行う
+	status := "SUCCESS"
+	errMsg := ""
+	switch {
+	case ctx.Err() != nil:
+		status = "ABORTED"
+		recalcErr = fmt.Errorf("delay recalc aborted: %w", ctx.Err())
+		errMsg = recalcErr.Error()
+	case recalcErr != nil:
+		status = "FAILURE"
+		errMsg = recalcErr.Error()
+	}
+
+	if finishErr := s.repo.FinishSyncLog(context.WithoutCancel(ctx), logID, status, 0, updated, errMsg); finishErr != nil {
+		s.log.Error("failed to finish sync log", zap.Error(finishErr))
+	}
+
+	duration := time.Since(start)
+	s.log.Info("delay recalc finished",
+		zap.String("status", status),
+		zap.Int("issues_evaluated", evaluated),
+		zap.Int("issues_updated", updated),
+		zap.Duration("duration", duration),
+	)
+
+	s.recorder.RecordSync(metrics.SyncResult{
+		SyncType:     "RECALC",
+		Success:      recalcErr == nil,
+		Duration:     duration,
+		IssuesSynced: updated,
+	})
+
+	return recalcErr
+}
+
+// RunDelayRecalcAll runs RunDelayRecalc for every connection in conns with the
+// repository returned by newRepo. Since the recalc does not call Jira, it also
+// covers disabled connections and connections without credentials.
+// A failing connection does not stop the others; their errors are joined.
+func RunDelayRecalcAll(ctx context.Context, conns []Connection, newRepo func(connectionID int64) Repository, log *zap.Logger, recorder metrics.Recorder) error {
+	var errs []error
+	for _, conn := range conns {
+		syncer := NewSyncer(nil, newRepo(conn.ID), log.With(zap.String("connection", conn.Name)), 0)
+		syncer.SetRecorder(recorder)
+		if err := syncer.RunDelayRecalc(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("connection %q: %w", conn.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runDelayRecalc computes the delay status of the open issues and writes the ones
+// that changed. Returns the number of issues evaluated and updated.
+func (s *Syncer) runDelayRecalc(ctx context.Context) (evaluated, updated int, err error) {
+	policies, err := s.repo.GetDelayPolicies(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("get delay policies: %w", err)
+	}
+	issues, err := s.repo.ListOpenIssues(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("list open issues: %w", err)
+	}
+
+	now := normalizer.Now()
+	var updates []DelayStatusUpdate
+	for _, issue := range issues {
+		policy, ok := policies[issue.JiraProjectID]
+		if !ok {
+			policy = normalizer.DefaultDelayPolicy
+		}
+		status := policy.CalcDelayStatus(issue.StatusCategory, issue.DueDate, now)
+		if status != issue.DelayStatus {
+			updates = append(updates, DelayStatusUpdate{IssueID: issue.ID, DelayStatus: status})
+		}
+	}
+
+	updated, err = s.repo.UpdateDelayStatuses(ctx, updates)
+	if err != nil {
+		return len(issues), 0, fmt.Errorf("update delay statuses: %w", err)
+	}
+	return len(issues), updated, nil
+}
@@ -0,0 +1,117 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/metrics"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+func TestRunDelayRecalc_UpdatesChangedIssuesOnly(t *testing.T) {
+	today := normalizer.Now()
+	yesterday := today.AddDate(0, 0, -1).Format("2006-01-02")
+	in5Days := today.AddDate(0, 0, 5).Format("2006-01-02")
+
+	repo := &mockRepository{
+		syncLogID: 7,
+		openIssues: []OpenIssue{
+			// 期日が過ぎた → RED
+			{ID: 1, JiraProjectID: "10", StatusCategory: "In Progress", DueDate: &yesterday, DelayStatus: "YELLOW"},
+			// 変化なし
+			{ID: 2, JiraProjectID: "10", StatusCategory: "To Do", DueDate: &in5Days, DelayStatus: "GREEN"},
+			// プロジェクト 20 は 7 日前から YELLOW
+			{ID: 3, JiraProjectID: "20", StatusCategory: "To Do", DueDate: &in5Days, DelayStatus: "GREEN"},
+		},
+		delayPolicies: map[string]normalizer.DelayPolicy{"20": {YellowDays: 7, NoDueDateStatus: "YELLOW"}},
+	}
+	jira := &mockJiraClient{}
+	syncer := newTestSyncer(jira, repo)
+
+	if err := syncer.RunDelayRecalc(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := []DelayStatusUpdate{{IssueID: 1, DelayStatus: "RED"}, {IssueID: 3, DelayStatus: "YELLOW"}}
+	if !reflect.DeepEqual(repo.delayUpdates, want) {
+		t.Errorf("expected updates %v, got %v", want, repo.delayUpdates)
+	}
+	if repo.startedSyncType != "RECALC" || repo.finishedStatus != "SUCCESS" || repo.finishedIssues != 2 {
+		t.Errorf("expected RECALC sync log finished as SUCCESS with 2 issues, got %s %s %d",
+			repo.startedSyncType, repo.finishedStatus, repo.finishedIssues)
+	}
+	if jira.searchCallCount != 0 || len(jira.changelogCalls) != 0 {
+		t.Error("recalc must not call Jira")
+	}
+}
+
+func TestRunDelayRecalc_ErrorRecordsFailure(t *testing.T) {
+	repo := &mockRepository{syncLogID: 1, openIssuesErr: errors.New("db down")}
+	syncer := newTestSyncer(&mockJiraClient{}, repo)
+
+	if err := syncer.RunDelayRecalc(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if repo.finishedStatus != "FAILURE" || repo.finishedErrMsg == "" {
+		t.Errorf("expected FAILURE with an error message, got %s %q", repo.finishedStatus, repo.finishedErrMsg)
+	}
+}
+
+func TestRunDelayRecalc_CancelledRecordsAborted(t *testing.T) {
+	repo := &mockRepository{syncLogID: 1, openIssuesErr: context.Canceled}
+	syncer := newTestSyncer(&mockJiraClient{}, repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := syncer.RunDelayRecalc(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if repo.finishedStatus != "ABORTED" {
+		t.Errorf("expected ABORTED, got %s", repo.finishedStatus)
+	}
+	if repo.finishCtxErr != nil {
+		t.Error("sync log must be finished with a live context")
+	}
+}
+
+func TestRunDelayRecalcAll_WithoutCredentials(t *testing.T) {
+	// 認証情報の無い接続・無効な接続も Jira に触れずに再計算する
+	conns := []Connection{{ID: 1, Name: "default"}, {ID: 2, Name: "disabled"}}
+	repos := map[int64]*mockRepository{
+		1: {syncLogID: 1, openIssues: []OpenIssue{{ID: 1, JiraProjectID: "10", StatusCategory: "To Do", DelayStatus: "GREEN"}}},
+		2: {syncLogID: 2},
+	}
+
+	err := RunDelayRecalcAll(context.Background(), conns, func(id int64) Repository { return repos[id] }, zap.NewNop(), metrics.NoopRecorder{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	for id, repo := range repos {
+		if repo.startedSyncType != "RECALC" || repo.finishedStatus != "SUCCESS" {
+			t.Errorf("connection %d: expected RECALC finished as SUCCESS, got %s %s", id, repo.startedSyncType, repo.finishedStatus)
+		}
+	}
+	if want := []DelayStatusUpdate{{IssueID: 1, DelayStatus: "YELLOW"}}; !reflect.DeepEqual(repos[1].delayUpdates, want) {
+		t.Errorf("expected updates %v, got %v", want, repos[1].delayUpdates)
+	}
+}
+
+func TestRunDelayRecalcAll_ContinuesAfterFailure(t *testing.T) {
+	conns := []Connection{{ID: 1, Name: "broken"}, {ID: 2, Name: "ok"}}
+	repos := map[int64]*mockRepository{
+		1: {syncLogID: 1, openIssuesErr: errors.New("db down")},
+		2: {syncLogID: 2},
+	}
+
+	err := RunDelayRecalcAll(context.Background(), conns, func(id int64) Repository { return repos[id] }, zap.NewNop(), metrics.NoopRecorder{})
+	if err == nil || !strings.Contains(err.Error(), `connection "broken"`) {
+		t.Fatalf("expected error of the broken connection, got: %v", err)
+	}
+	if repos[2].finishedStatus != "SUCCESS" {
+		t.Errorf("expected the other connection to succeed, got %s", repos[2].finishedStatus)
+	}
+}
@@ -7,22 +7,156 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/calendar"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
 )
 
-// Repository defines the DB operations required by the sync process.
+// ProjectSyncResult is the outcome of fetching one project's issues during a full sync.
+type ProjectSyncResult struct {
+	JiraProjectID string
+	ProjectKey    string
+	Status        string // "SUCCESS" | "FAILURE"
+	IssuesSynced  int
+	ErrorMessage  string // empty string on success
+	Duration      time.Duration
+}
+
+// SyncCheckpoint records how far a full sync has progressed for one project,
+// so that an interrupted sync can be resumed. NextStartAt is an offset into the
+// project's issues ordered by creation, which issue updates made between the
+// interruption and the resume do not shift.
+type SyncCheckpoint struct {
+	JiraProjectID string
+	NextStartAt   int    // startAt of the next page to fetch (legacy search API)
+	NextPageToken string // nextPageToken of the next page to fetch (enhanced search API)
+	IssuesSynced  int    // issues ingested so far
+	Completed     bool   // true once every page of the project has been ingested
+}
+
+// OpenIssue is the stored state of an open issue that its delay status is
+// computed from.
+type OpenIssue struct {
+	ID             int64
+	JiraProjectID  string
+	StatusCategory string
+	DueDate        *string // "YYYY-MM-DD"
+	DelayStatus    string
+}
+
+// DelayStatusUpdate sets the delay status of one issue.
+type DelayStatusUpdate struct {
+	IssueID     int64
+	DelayStatus string
+}
+
+// Repository defines the DB operations required by the sync process. Projects,
+// issues, versions, boards, sprints and sync logs are read and written for a
+// single Jira connection (see NewRepository).
 type Repository interface {
 	// UpsertProjects inserts or updates projects and returns the number of rows affected.
 	UpsertProjects(ctx context.Context, projects []normalizer.DBProject) (int, error)
 	// UpsertIssues inserts or updates issues. projectIDMap maps jira_project_id → DB id.
+	// Stored issues whose last_updated_at is newer than the incoming one are left
+	// unchanged, so that out-of-order webhook events never overwrite newer data.
 	// Returns the number of rows affected.
 	UpsertIssues(ctx context.Context, issues []normalizer.DBIssue, projectIDMap map[string]int64) (int, error)
-	// GetProjectIDMap returns a map of jira_project_id → DB id for all known projects.
+	// ReplaceIssueLinks replaces the stored issue links of the given issues with
+	// their Links. Issues that are not stored are ignored. Returns the number of
+	// links written.
+	ReplaceIssueLinks(ctx context.Context, issues []normalizer.DBIssue) (int, error)
+	// ReplaceProjectVersions replaces the stored versions (releases) of a project
+	// with versions. Returns the number of versions written.
+	ReplaceProjectVersions(ctx context.Context, projectID int64, versions []normalizer.DBVersion) (int, error)
+	// ReplaceProjectBoards replaces the stored agile boards of a project with boards
+	// and returns a map of jira_board_id → DB id of the boards written.
+	ReplaceProjectBoards(ctx context.Context, projectID int64, boards []normalizer.DBBoard) (map[int64]int64, error)
+	// ReplaceBoardSprints replaces the stored sprints of a board with sprints.
+	// Returns the number of sprints written.
+	ReplaceBoardSprints(ctx context.Context, boardID int64, sprints []normalizer.DBSprint) (int, error)
+	// GetIssuesSyncedClosedSprintIDs returns the jira_sprint_ids of the given sprints
+	// whose issues were already stored after the sprint was closed.
+	GetIssuesSyncedClosedSprintIDs(ctx context.Context, jiraSprintIDs []int64) (map[int64]bool, error)
+	// ReplaceSprintIssues replaces the issues of a stored sprint with jiraIssueIDs,
+	// keeping when each remaining issue was first seen in the sprint. Returns the
+	// number of issues added.
+	ReplaceSprintIssues(ctx context.Context, jiraSprintID int64, jiraIssueIDs []string) (int, error)
+	// GetProjectIDMap returns a map of jira_project_id → DB id for all known projects
+	// of the connection.
 	GetProjectIDMap(ctx context.Context) (map[string]int64, error)
+	// GetDelayPolicies returns the effective delay policy of every known project of
+	// the connection keyed by jira_project_id. Each setting is taken from the project's own policy, else the
+	// nearest organization up the tree that sets it, else the global default policy.
+	// The business calendar is the one of the nearest organization that selects one,
+	// else the default calendar; without either, the policy counts calendar days.
+	// Likewise the timezone is the one of the nearest organization that sets one,
+	// else nil (the reporting timezone).
+	GetDelayPolicies(ctx context.Context) (map[string]normalizer.DelayPolicy, error)
+	// GetProjectDelayPolicy returns the DB id and effective delay policy (resolved
+	// as by GetDelayPolicies) of the project of the connection with the given
+	// jira_project_id, loading only that project's calendar. ok is false if the
+	// project is not stored.
+	GetProjectDelayPolicy(ctx context.Context, jiraProjectID string) (projectID int64, policy normalizer.DelayPolicy, ok bool, err error)
+	// GetFieldMapping returns the Jira field each canonical issue column is read
+	// from on the connection's site (custom field IDs differ between sites).
+	GetFieldMapping(ctx context.Context) (normalizer.FieldMapping, error)
+	// MarkMissingProjectsDeleted soft-deletes projects (and their issues) whose
+	// jira_project_id is not in jiraProjectIDs. Returns the number of projects marked.
+	MarkMissingProjectsDeleted(ctx context.Context, jiraProjectIDs []string) (int, error)
+	// MarkProjectDeleted soft-deletes the project (and its issues) with the given
+	// jira_project_id. Returns false if it is not stored or already deleted.
+	MarkProjectDeleted(ctx context.Context, jiraProjectID string) (bool, error)
+	// MarkIssueDeleted soft-deletes the issue with the given jira_issue_id as of
+	// deletedAt, which also becomes its last_updated_at so that older events do not
+	// restore it. Returns false if it is not stored, already deleted, or was updated
+	// after deletedAt.
+	MarkIssueDeleted(ctx context.Context, jiraIssueID string, deletedAt time.Time) (bool, error)
+	// MarkMissingIssuesDeleted soft-deletes issues of the given project whose
+	// jira_issue_id is not in jiraIssueIDs and that were last written before
+	// syncStart, so that issues stored by webhooks while the project was being
+	// fetched are kept. Returns the number of issues marked.
+	MarkMissingIssuesDeleted(ctx context.Context, projectID int64, jiraIssueIDs []string, syncStart time.Time) (int, error)
+	// CountProjectIssues returns the number of issues of the given project that
+	// are not soft-deleted.
+	CountProjectIssues(ctx context.Context, projectID int64) (int, error)
+	// FindChangedIssues returns the jira_issue_ids of the given issues that are not
+	// stored yet or whose last_updated_at is newer than the stored one.
+	FindChangedIssues(ctx context.Context, issues []normalizer.DBIssue) (map[string]bool, error)
+	// InsertIssueHistory stores status/due date transitions of known issues, ignoring
+	// transitions that were already recorded. Returns the number of rows inserted.
+	InsertIssueHistory(ctx context.Context, history []normalizer.DBIssueHistory) (int, error)
+	// UpsertIssuesWithHistory upserts issues as UpsertIssues and stores history as
+	// InsertIssueHistory in one transaction, so that an issue is never stored as up
+	// to date without its history. Returns the number of issues upserted.
+	UpsertIssuesWithHistory(ctx context.Context, issues []normalizer.DBIssue, projectIDMap map[string]int64, history []normalizer.DBIssueHistory) (int, error)
+	// ListOpenIssues returns every issue of the connection that is not Done and not
+	// soft-deleted.
+	ListOpenIssues(ctx context.Context) ([]OpenIssue, error)
+	// UpdateDelayStatuses sets the delay status of the given issues and returns the
+	// number of issues whose status actually changed.
+	UpdateDelayStatuses(ctx context.Context, updates []DelayStatusUpdate) (int, error)
 	// StartSyncLog creates a sync_log record in RUNNING state and returns its ID.
 	StartSyncLog(ctx context.Context, syncType string) (int64, error)
+	// ReopenSyncLog marks an unfinished sync log as RUNNING again before it is resumed.
+	ReopenSyncLog(ctx context.Context, id int64) error
+	// UpdateSyncProgress records the number of issues synced so far by a RUNNING sync.
+	UpdateSyncProgress(ctx context.Context, id int64, issuesSynced int) error
 	// FinishSyncLog updates the sync_log record with the final status.
 	FinishSyncLog(ctx context.Context, id int64, status string, projectsSynced, issuesSynced int, errMsg string) error
+	// RecordProjectResults stores the per-project outcomes of the given sync log.
+	RecordProjectResults(ctx context.Context, syncLogID int64, results []ProjectSyncResult) error
+	// SaveCheckpoint inserts or updates the checkpoint of a project for the given sync log.
+	SaveCheckpoint(ctx context.Context, syncLogID int64, cp SyncCheckpoint) error
+	// GetCheckpoints returns the checkpoints of the given sync log keyed by jira_project_id.
+	GetCheckpoints(ctx context.Context, syncLogID int64) (map[string]SyncCheckpoint, error)
+	// GetUnfinishedSyncLogID returns the ID of the most recent sync log of syncType if
+	// it is still RUNNING or was ABORTED. Returns 0 otherwise.
+	GetUnfinishedSyncLogID(ctx context.Context, syncType string) (int64, error)
+	// RecordDelaySnapshots stores the current RED/YELLOW/GREEN counts of every project
+	// (of every connection) and organization as the snapshot of date ("YYYY-MM-DD"), replacing an existing
+	// snapshot of the same date. Returns the number of project and organization rows.
+	RecordDelaySnapshots(ctx context.Context, date string) (projects, organizations int, err error)
 	// GetLastSuccessfulSyncTime returns the executed_at of the most recent successful sync log
 	// for the given syncType. Returns nil if no successful sync has been recorded.
 	GetLastSuccessfulSyncTime(ctx context.Context, syncType string) (*time.Time, error)
@@ -30,12 +164,14 @@ type Repository interface {
 
 // sqlxRepository is the PostgreSQL implementation of Repository.
 type sqlxRepository struct {
-	db *sqlx.DB
+	db           *sqlx.DB
+	connectionID int64
 }
 
-// NewRepository creates a new Repository backed by the given sqlx.DB.
-func NewRepository(db *sqlx.DB) Repository {
-	return &sqlxRepository{db: db}
+// NewRepository creates a new Repository backed by the given sqlx.DB for the
+// Jira connection with the given ID.
+func NewRepository(db *sqlx.DB, connectionID int64) Repository {
+	return &sqlxRepository{db: db, connectionID: connectionID}
 }
 
 func (r *sqlxRepository) UpsertProjects(ctx context.Context, projects []normalizer.DBProject) (int, error) {
@@ -44,16 +180,18 @@ func (r *sqlxRepository) UpsertProjects(ctx context.Context, projects []normaliz
 	}
 
 	const q = `
-		INSERT INTO projects (jira_project_id, key, name, lead_account_id, lead_email)
-		VALUES (:jira_project_id, :key, :name, :lead_account_id, :lead_email)
-		ON CONFLICT (jira_project_id) DO UPDATE SET
+		INSERT INTO projects (connection_id, jira_project_id, key, name, lead_account_id, lead_email)
+		VALUES (:connection_id, :jira_project_id, :key, :name, :lead_account_id, :lead_email)
+		ON CONFLICT (connection_id, jira_project_id) DO UPDATE SET
 			key            = EXCLUDED.key,
 			name           = EXCLUDED.name,
 			lead_account_id = EXCLUDED.lead_account_id,
 			lead_email     = EXCLUDED.lead_email,
+			deleted_at     = NULL,
 			updated_at     = CURRENT_TIMESTAMP`
 
 	type row struct {
+		ConnectionID  int64  `db:"connection_id"`
 		JiraProjectID string `db:"jira_project_id"`
 		Key           string `db:"key"`
 		Name          string `db:"name"`
@@ -64,6 +202,7 @@ func (r *sqlxRepository) UpsertProjects(ctx context.Context, projects []normaliz
 	rows := make([]row, len(projects))
 	for i, p := range projects {
 		rows[i] = row{
+			ConnectionID:  r.connectionID,
 			JiraProjectID: p.JiraProjectID,
 			Key:           p.Key,
 			Name:          p.Name,
@@ -81,23 +220,53 @@ func (r *sqlxRepository) UpsertProjects(ctx context.Context, projects []normaliz
 }
 
 func (r *sqlxRepository) UpsertIssues(ctx context.Context, issues []normalizer.DBIssue, projectIDMap map[string]int64) (int, error) {
+	return r.upsertIssues(ctx, r.db, issues, projectIDMap)
+}
+
+func (r *sqlxRepository) UpsertIssuesWithHistory(ctx context.Context, issues []normalizer.DBIssue, projectIDMap map[string]int64, history []normalizer.DBIssueHistory) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := r.upsertIssues(ctx, tx, issues, projectIDMap)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.insertIssueHistory(ctx, tx, history); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return n, nil
+}
+
+func (r *sqlxRepository) upsertIssues(ctx context.Context, e sqlx.ExtContext, issues []normalizer.DBIssue, projectIDMap map[string]int64) (int, error) {
 	if len(issues) == 0 {
 		return 0, nil
 	}
 
 	const q = `
 		INSERT INTO issues (
-			jira_issue_id, jira_issue_key, project_id, summary,
+			connection_id, jira_issue_id, jira_issue_key, project_id, summary,
 			status, status_category, due_date,
 			assignee_name, assignee_account_id,
-			delay_status, priority, issue_type, last_updated_at
+			delay_status, priority, issue_type, last_updated_at,
+			original_due_date, postpone_count, slipped, original_due_date_from_changelog,
+			start_date, story_points, epic_key, sprint_name, labels, components,
+			parent_jira_issue_id, parent_issue_key, fix_version_ids
 		) VALUES (
-			:jira_issue_id, :jira_issue_key, :project_id, :summary,
+			:connection_id, :jira_issue_id, :jira_issue_key, :project_id, :summary,
 			:status, :status_category, :due_date,
 			:assignee_name, :assignee_account_id,
-			:delay_status, :priority, :issue_type, :last_updated_at
+			:delay_status, :priority, :issue_type, :last_updated_at,
+			:original_due_date, :postpone_count, :slipped, :original_due_date_from_changelog,
+			:start_date, :story_points, :epic_key, :sprint_name, :labels, :components,
+			:parent_jira_issue_id, :parent_issue_key, :fix_version_ids
 		)
-		ON CONFLICT (jira_issue_id) DO UPDATE SET
+		ON CONFLICT (connection_id, jira_issue_id) DO UPDATE SET
 			jira_issue_key      = EXCLUDED.jira_issue_key,
 			summary             = EXCLUDED.summary,
 			status              = EXCLUDED.status,
@@ -109,22 +278,57 @@ func (r *sqlxRepository) UpsertIssues(ctx context.Context, issues []normalizer.D
 			priority            = EXCLUDED.priority,
 			issue_type          = EXCLUDED.issue_type,
 			last_updated_at     = EXCLUDED.last_updated_at,
-			updated_at          = CURRENT_TIMESTAMP`
+			-- 完全な changelog から求めた値は保存済みの値より優先する（000012 で現在の期日を初期値にしたため）。
+			-- changelog が無い場合は最初に記録した期日を保持し、期日の後ろ倒しを数える
+			original_due_date   = CASE WHEN EXCLUDED.original_due_date_from_changelog THEN EXCLUDED.original_due_date
+				ELSE COALESCE(issues.original_due_date, EXCLUDED.original_due_date) END,
+			postpone_count      = CASE WHEN EXCLUDED.original_due_date_from_changelog THEN EXCLUDED.postpone_count
+				ELSE GREATEST(EXCLUDED.postpone_count,
+					issues.postpone_count + CASE WHEN EXCLUDED.due_date > issues.due_date THEN 1 ELSE 0 END) END,
+			slipped             = COALESCE(EXCLUDED.due_date > CASE WHEN EXCLUDED.original_due_date_from_changelog THEN EXCLUDED.original_due_date
+				ELSE COALESCE(issues.original_due_date, EXCLUDED.original_due_date) END, FALSE),
+			original_due_date_from_changelog = issues.original_due_date_from_changelog OR EXCLUDED.original_due_date_from_changelog,
+			start_date          = EXCLUDED.start_date,
+			story_points        = EXCLUDED.story_points,
+			epic_key            = EXCLUDED.epic_key,
+			sprint_name         = EXCLUDED.sprint_name,
+			labels              = EXCLUDED.labels,
+			components          = EXCLUDED.components,
+			parent_jira_issue_id = EXCLUDED.parent_jira_issue_id,
+			parent_issue_key    = EXCLUDED.parent_issue_key,
+			fix_version_ids     = EXCLUDED.fix_version_ids,
+			deleted_at          = NULL,
+			updated_at          = CURRENT_TIMESTAMP
+		WHERE issues.last_updated_at <= EXCLUDED.last_updated_at`
 
 	type row struct {
-		JiraIssueID       string    `db:"jira_issue_id"`
-		JiraIssueKey      string    `db:"jira_issue_key"`
-		ProjectID         int64     `db:"project_id"`
-		Summary           string    `db:"summary"`
-		Status            string    `db:"status"`
-		StatusCategory    string    `db:"status_category"`
-		DueDate           *string   `db:"due_date"`
-		AssigneeName      string    `db:"assignee_name"`
-		AssigneeAccountID string    `db:"assignee_account_id"`
-		DelayStatus       string    `db:"delay_status"`
-		Priority          string    `db:"priority"`
-		IssueType         string    `db:"issue_type"`
-		LastUpdatedAt     time.Time `db:"last_updated_at"`
+		ConnectionID      int64          `db:"connection_id"`
+		JiraIssueID       string         `db:"jira_issue_id"`
+		JiraIssueKey      string         `db:"jira_issue_key"`
+		ProjectID         int64          `db:"project_id"`
+		Summary           string         `db:"summary"`
+		Status            string         `db:"status"`
+		StatusCategory    string         `db:"status_category"`
+		DueDate           *string        `db:"due_date"`
+		AssigneeName      string         `db:"assignee_name"`
+		AssigneeAccountID string         `db:"assignee_account_id"`
+		DelayStatus       string         `db:"delay_status"`
+		Priority          string         `db:"priority"`
+		IssueType         string         `db:"issue_type"`
+		LastUpdatedAt     time.Time      `db:"last_updated_at"`
+		OriginalDueDate   *string        `db:"original_due_date"`
+		PostponeCount     int            `db:"postpone_count"`
+		Slipped           bool           `db:"slipped"`
+		FromChangelog     bool           `db:"original_due_date_from_changelog"`
+		StartDate         *string        `db:"start_date"`
+		StoryPoints       *float64       `db:"story_points"`
+		EpicKey           *string        `db:"epic_key"`
+		SprintName        *string        `db:"sprint_name"`
+		Labels            pq.StringArray `db:"labels"`
+		Components        pq.StringArray `db:"components"`
+		ParentJiraIssueID *string        `db:"parent_jira_issue_id"`
+		ParentIssueKey    *string        `db:"parent_issue_key"`
+		FixVersionIDs     pq.StringArray `db:"fix_version_ids"`
 	}
 
 	var rows []row
@@ -135,6 +339,7 @@ func (r *sqlxRepository) UpsertIssues(ctx context.Context, issues []normalizer.D
 			continue
 		}
 		rows = append(rows, row{
+			ConnectionID:      r.connectionID,
 			JiraIssueID:       issue.JiraIssueID,
 			JiraIssueKey:      issue.JiraIssueKey,
 			ProjectID:         projectID,
@@ -148,6 +353,19 @@ func (r *sqlxRepository) UpsertIssues(ctx context.Context, issues []normalizer.D
 			Priority:          issue.Priority,
 			IssueType:         issue.IssueType,
 			LastUpdatedAt:     issue.LastUpdatedAt,
+			OriginalDueDate:   issue.OriginalDueDate,
+			PostponeCount:     issue.PostponeCount,
+			Slipped:           issue.Slipped,
+			FromChangelog:     issue.OriginalDueDateFromChangelog,
+			StartDate:         issue.StartDate,
+			StoryPoints:       issue.StoryPoints,
+			EpicKey:           issue.EpicKey,
+			SprintName:        issue.SprintName,
+			Labels:            nonNilStrings(issue.Labels),
+			Components:        nonNilStrings(issue.Components),
+			ParentJiraIssueID: issue.ParentJiraIssueID,
+			ParentIssueKey:    issue.ParentIssueKey,
+			FixVersionIDs:     nonNilStrings(issue.FixVersionIDs),
 		})
 	}
 
@@ -155,7 +373,7 @@ func (r *sqlxRepository) UpsertIssues(ctx context.Context, issues []normalizer.D
 		return 0, nil
 	}
 
-	result, err := r.db.NamedExecContext(ctx, q, rows)
+	result, err := sqlx.NamedExecContext(ctx, e, q, rows)
 	if err != nil {
 		return 0, fmt.Errorf("upsert issues: %w", err)
 	}
@@ -163,8 +381,291 @@ func (r *sqlxRepository) UpsertIssues(ctx context.Context, issues []normalizer.D
 	return int(n), nil
 }
 
+// nonNilStrings returns s, or an empty slice if s is nil, for NOT NULL array columns.
+func nonNilStrings(s []string) pq.StringArray {
+	if s == nil {
+		return pq.StringArray{}
+	}
+	return pq.StringArray(s)
+}
+
+func (r *sqlxRepository) ReplaceIssueLinks(ctx context.Context, issues []normalizer.DBIssue) (int, error) {
+	if len(issues) == 0 {
+		return 0, nil
+	}
+
+	// 1 往復で処理するため列ごとの配列に展開する
+	var (
+		jiraIssueIDs                              []string
+		sourceIDs, linkIDs, linkTypes, directions []string
+		descriptions                              []sql.NullString
+		linkedIDs, linkedKeys                     []string
+	)
+	for _, issue := range issues {
+		jiraIssueIDs = append(jiraIssueIDs, issue.JiraIssueID)
+		for _, l := range issue.Links {
+			sourceIDs = append(sourceIDs, issue.JiraIssueID)
+			linkIDs = append(linkIDs, l.JiraLinkID)
+			linkTypes = append(linkTypes, l.LinkType)
+			directions = append(directions, l.Direction)
+			descriptions = append(descriptions, sql.NullString{String: l.Description, Valid: l.Description != ""})
+			linkedIDs = append(linkedIDs, l.LinkedJiraIssueID)
+			linkedKeys = append(linkedKeys, l.LinkedIssueKey)
+		}
+	}
+
+	// Jira から消えたリンクの削除と upsert を同一ステートメントで行う（対象の行は重ならない）
+	result, err := r.db.ExecContext(ctx, `
+		WITH incoming AS (
+			SELECT i.id AS issue_id, l.jira_link_id, l.link_type, l.direction, l.description,
+				l.linked_jira_issue_id, l.linked_issue_key
+			FROM unnest(
+				$2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[], $8::text[]
+			) AS l(jira_issue_id, jira_link_id, link_type, direction, description,
+				linked_jira_issue_id, linked_issue_key)
+			JOIN issues i ON i.connection_id = $9 AND i.jira_issue_id = l.jira_issue_id
+		), removed AS (
+			DELETE FROM issue_links x
+			USING issues i
+			WHERE x.issue_id = i.id
+			  AND i.connection_id = $9
+			  AND i.jira_issue_id = ANY($1)
+			  AND NOT EXISTS (
+				SELECT 1 FROM incoming n WHERE n.issue_id = x.issue_id AND n.jira_link_id = x.jira_link_id
+			  )
+		)
+		INSERT INTO issue_links (
+			issue_id, jira_link_id, link_type, direction, description,
+			linked_jira_issue_id, linked_issue_key
+		)
+		SELECT issue_id, jira_link_id, link_type, direction, description,
+			linked_jira_issue_id, linked_issue_key
+		FROM incoming
+		ON CONFLICT (issue_id, jira_link_id) DO UPDATE SET
+			link_type            = EXCLUDED.link_type,
+			direction            = EXCLUDED.direction,
+			description          = EXCLUDED.description,
+			linked_jira_issue_id = EXCLUDED.linked_jira_issue_id,
+			linked_issue_key     = EXCLUDED.linked_issue_key`,
+		pq.Array(jiraIssueIDs),
+		pq.Array(sourceIDs), pq.Array(linkIDs), pq.Array(linkTypes), pq.Array(directions),
+		pq.Array(descriptions), pq.Array(linkedIDs), pq.Array(linkedKeys), r.connectionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("replace issue links: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+func (r *sqlxRepository) ReplaceProjectVersions(ctx context.Context, projectID int64, versions []normalizer.DBVersion) (int, error) {
+	var (
+		versionIDs, names        []string
+		descriptions             []sql.NullString
+		startDates, releaseDates []sql.NullString
+		released, archived       []bool
+	)
+	for _, v := range versions {
+		versionIDs = append(versionIDs, v.JiraVersionID)
+		names = append(names, v.Name)
+		descriptions = append(descriptions, sql.NullString{String: v.Description, Valid: v.Description != ""})
+		startDates = append(startDates, nullString(v.StartDate))
+		releaseDates = append(releaseDates, nullString(v.ReleaseDate))
+		released = append(released, v.Released)
+		archived = append(archived, v.Archived)
+	}
+
+	// Jira から消えたバージョンの削除と upsert を同一ステートメントで行う
+	result, err := r.db.ExecContext(ctx, `
+		WITH incoming AS (
+			SELECT * FROM unnest(
+				$2::text[], $3::text[], $4::text[], $5::date[], $6::date[], $7::boolean[], $8::boolean[]
+			) AS v(jira_version_id, name, description, start_date, release_date, released, archived)
+		), removed AS (
+			DELETE FROM project_versions x
+			WHERE x.project_id = $1
+			  AND NOT EXISTS (SELECT 1 FROM incoming n WHERE n.jira_version_id = x.jira_version_id)
+		)
+		INSERT INTO project_versions (
+			connection_id, jira_version_id, project_id, name, description, start_date, release_date, released, archived
+		)
+		SELECT $9, jira_version_id, $1, name, description, start_date, release_date, released, archived
+		FROM incoming
+		ON CONFLICT (connection_id, jira_version_id) DO UPDATE SET
+			project_id   = EXCLUDED.project_id,
+			name         = EXCLUDED.name,
+			description  = EXCLUDED.description,
+			start_date   = EXCLUDED.start_date,
+			release_date = EXCLUDED.release_date,
+			released     = EXCLUDED.released,
+			archived     = EXCLUDED.archived`,
+		projectID,
+		pq.Array(versionIDs), pq.Array(names), pq.Array(descriptions),
+		pq.Array(startDates), pq.Array(releaseDates), pq.Array(released), pq.Array(archived),
+		r.connectionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("replace project versions: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+func (r *sqlxRepository) ReplaceProjectBoards(ctx context.Context, projectID int64, boards []normalizer.DBBoard) (map[int64]int64, error) {
+	var (
+		boardIDs          []int64
+		names, boardTypes []string
+	)
+	for _, b := range boards {
+		boardIDs = append(boardIDs, b.JiraBoardID)
+		names = append(names, b.Name)
+		boardTypes = append(boardTypes, b.BoardType)
+	}
+
+	// Jira から消えたボードの削除と upsert を同一ステートメントで行う
+	rows, err := r.db.QueryxContext(ctx, `
+		WITH incoming AS (
+			SELECT * FROM unnest($2::bigint[], $3::text[], $4::text[]) AS b(jira_board_id, name, board_type)
+		), removed AS (
+			DELETE FROM jira_boards x
+			WHERE x.project_id = $1
+			  AND NOT EXISTS (SELECT 1 FROM incoming n WHERE n.jira_board_id = x.jira_board_id)
+		)
+		INSERT INTO jira_boards (connection_id, jira_board_id, project_id, name, board_type)
+		SELECT $5, jira_board_id, $1, name, board_type
+		FROM incoming
+		ON CONFLICT (connection_id, jira_board_id) DO UPDATE SET
+			project_id = EXCLUDED.project_id,
+			name       = EXCLUDED.name,
+			board_type = EXCLUDED.board_type
+		RETURNING jira_board_id, id`,
+		projectID, pq.Array(boardIDs), pq.Array(names), pq.Array(boardTypes), r.connectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("replace project boards: %w", err)
+	}
+	defer rows.Close()
+
+	m := make(map[int64]int64, len(boards))
+	for rows.Next() {
+		var jiraBoardID, id int64
+		if err := rows.Scan(&jiraBoardID, &id); err != nil {
+			return nil, fmt.Errorf("scan board: %w", err)
+		}
+		m[jiraBoardID] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("replace project boards: %w", err)
+	}
+	return m, nil
+}
+
+func (r *sqlxRepository) ReplaceBoardSprints(ctx context.Context, boardID int64, sprints []normalizer.DBSprint) (int, error) {
+	var (
+		sprintIDs                           []int64
+		names, states                       []string
+		goals                               []sql.NullString
+		startDates, endDates, completeDates []sql.NullTime
+	)
+	for _, sp := range sprints {
+		sprintIDs = append(sprintIDs, sp.JiraSprintID)
+		names = append(names, sp.Name)
+		states = append(states, sp.State)
+		goals = append(goals, sql.NullString{String: sp.Goal, Valid: sp.Goal != ""})
+		startDates = append(startDates, nullUTCTime(sp.StartDate))
+		endDates = append(endDates, nullUTCTime(sp.EndDate))
+		completeDates = append(completeDates, nullUTCTime(sp.CompleteDate))
+	}
+
+	// Jira から消えたスプリントの削除と upsert を同一ステートメントで行う
+	result, err := r.db.ExecContext(ctx, `
+		WITH incoming AS (
+			SELECT * FROM unnest(
+				$2::bigint[], $3::text[], $4::text[], $5::text[], $6::timestamp[], $7::timestamp[], $8::timestamp[]
+			) AS s(jira_sprint_id, name, state, goal, start_date, end_date, complete_date)
+		), removed AS (
+			DELETE FROM sprints x
+			WHERE x.board_id = $1
+			  AND NOT EXISTS (SELECT 1 FROM incoming n WHERE n.jira_sprint_id = x.jira_sprint_id)
+		)
+		INSERT INTO sprints (connection_id, jira_sprint_id, board_id, name, state, goal, start_date, end_date, complete_date)
+		SELECT $9, jira_sprint_id, $1, name, state, goal, start_date, end_date, complete_date
+		FROM incoming
+		ON CONFLICT (connection_id, jira_sprint_id) DO UPDATE SET
+			board_id      = EXCLUDED.board_id,
+			name          = EXCLUDED.name,
+			state         = EXCLUDED.state,
+			goal          = EXCLUDED.goal,
+			start_date    = EXCLUDED.start_date,
+			end_date      = EXCLUDED.end_date,
+			complete_date = EXCLUDED.complete_date`,
+		boardID,
+		pq.Array(sprintIDs), pq.Array(names), pq.Array(states), pq.Array(goals),
+		pq.Array(startDates), pq.Array(endDates), pq.Array(completeDates), r.connectionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("replace board sprints: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// nullUTCTime converts an optional time to a sql.NullTime in UTC, the timezone of
+// the TIMESTAMP columns.
+func nullUTCTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func (r *sqlxRepository) GetIssuesSyncedClosedSprintIDs(ctx context.Context, jiraSprintIDs []int64) (map[int64]bool, error) {
+	m := make(map[int64]bool)
+	if len(jiraSprintIDs) == 0 {
+		return m, nil
+	}
+	var ids []int64
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT jira_sprint_id FROM sprints
+		WHERE connection_id = $1 AND jira_sprint_id = ANY($2) AND issues_synced_state = 'closed'`,
+		r.connectionID, pq.Array(jiraSprintIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get issues synced closed sprints: %w", err)
+	}
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m, nil
+}
+
+func (r *sqlxRepository) ReplaceSprintIssues(ctx context.Context, jiraSprintID int64, jiraIssueIDs []string) (int, error) {
+	// スプリントに残っているチケットは first_seen_at を保持し、外れたチケットのみ削除する
+	result, err := r.db.ExecContext(ctx, `
+		WITH sprint AS (
+			UPDATE sprints SET issues_synced_state = state
+			WHERE connection_id = $3 AND jira_sprint_id = $1
+			RETURNING id
+		), removed AS (
+			DELETE FROM sprint_issues x
+			USING sprint
+			WHERE x.sprint_id = sprint.id AND NOT (x.jira_issue_id = ANY($2::text[]))
+		)
+		INSERT INTO sprint_issues (sprint_id, jira_issue_id)
+		SELECT sprint.id, i.jira_issue_id
+		FROM sprint, unnest($2::text[]) AS i(jira_issue_id)
+		ON CONFLICT (sprint_id, jira_issue_id) DO NOTHING`,
+		jiraSprintID, nonNilStrings(jiraIssueIDs), r.connectionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("replace sprint issues: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
 func (r *sqlxRepository) GetProjectIDMap(ctx context.Context) (map[string]int64, error) {
-	rows, err := r.db.QueryxContext(ctx, `SELECT id, jira_project_id FROM projects`)
+	rows, err := r.db.QueryxContext(ctx, `SELECT id, jira_project_id FROM projects WHERE connection_id = $1`, r.connectionID)
 	if err != nil {
 		return nil, fmt.Errorf("get project id map: %w", err)
 	}
@@ -182,11 +683,438 @@ func (r *sqlxRepository) GetProjectIDMap(ctx context.Context) (map[string]int64,
 	return m, rows.Err()
 }
 
+func (r *sqlxRepository) GetDelayPolicies(ctx context.Context) (map[string]normalizer.DelayPolicy, error) {
+	return r.queryDelayPolicies(ctx, `p.connection_id = $1`, r.connectionID)
+}
+
+func (r *sqlxRepository) GetProjectDelayPolicy(ctx context.Context, jiraProjectID string) (int64, normalizer.DelayPolicy, bool, error) {
+	var projectID int64
+	err := r.db.GetContext(ctx, &projectID,
+		`SELECT id FROM projects WHERE connection_id = $1 AND jira_project_id = $2`,
+		r.connectionID, jiraProjectID,
+	)
+	if err == sql.ErrNoRows {
+		return 0, normalizer.DelayPolicy{}, false, nil
+	}
+	if err != nil {
+		return 0, normalizer.DelayPolicy{}, false, fmt.Errorf("get project: %w", err)
+	}
+
+	policies, err := r.queryDelayPolicies(ctx, `p.id = $1`, projectID)
+	if err != nil {
+		return 0, normalizer.DelayPolicy{}, false, err
+	}
+	policy, ok := policies[jiraProjectID]
+	if !ok {
+		policy = normalizer.DefaultDelayPolicy
+	}
+	return projectID, policy, true, nil
+}
+
+// queryDelayPolicies resolves the effective delay policies of the projects
+// matching where (a condition on projects p), keyed by jira_project_id.
+func (r *sqlxRepository) queryDelayPolicies(ctx context.Context, where string, args ...interface{}) (map[string]normalizer.DelayPolicy, error) {
+	// 項目ごとに プロジェクト → 組織（下位から順に） → 全体デフォルト の順で最初に設定された値を使う
+	// カレンダーは 組織（下位から順に） → デフォルトのカレンダー の順
+	// タイムゾーンは 組織（下位から順に）、無ければ NULL（REPORT_TIMEZONE を使う）
+	const q = `
+		SELECT
+			p.jira_project_id,
+			COALESCE(pp.yellow_days, (
+				SELECT dp.yellow_days
+				FROM delay_policies dp
+				JOIN organizations a ON a.id = dp.organization_id
+				WHERE o.path LIKE a.path || '%' AND dp.yellow_days IS NOT NULL
+				ORDER BY a.level DESC
+				LIMIT 1
+			), g.yellow_days) AS yellow_days,
+			COALESCE(pp.no_due_date_status, (
+				SELECT dp.no_due_date_status
+				FROM delay_policies dp
+				JOIN organizations a ON a.id = dp.organization_id
+				WHERE o.path LIKE a.path || '%' AND dp.no_due_date_status IS NOT NULL
+				ORDER BY a.level DESC
+				LIMIT 1
+			), g.no_due_date_status) AS no_due_date_status,
+			COALESCE((
+				SELECT a.business_calendar_id
+				FROM organizations a
+				WHERE o.path LIKE a.path || '%' AND a.business_calendar_id IS NOT NULL
+				ORDER BY a.level DESC
+				LIMIT 1
+			), (SELECT id FROM business_calendars WHERE is_default)) AS business_calendar_id,
+			(
+				SELECT a.timezone
+				FROM organizations a
+				WHERE o.path LIKE a.path || '%' AND a.timezone IS NOT NULL
+				ORDER BY a.level DESC
+				LIMIT 1
+			) AS timezone
+		FROM projects p
+		LEFT JOIN organizations o ON o.id = p.organization_id
+		LEFT JOIN delay_policies pp ON pp.project_id = p.id
+		LEFT JOIN delay_policies g ON g.organization_id IS NULL AND g.project_id IS NULL
+		WHERE `
+
+	rows, err := r.db.QueryxContext(ctx, q+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get delay policies: %w", err)
+	}
+	defer rows.Close()
+
+	m := make(map[string]normalizer.DelayPolicy)
+	calendarIDs := make(map[string]int64)
+	locations := make(map[string]*time.Location)
+	for rows.Next() {
+		var jiraID string
+		var yellowDays, calendarID sql.NullInt64
+		var noDueDateStatus, timezone sql.NullString
+		if err := rows.Scan(&jiraID, &yellowDays, &noDueDateStatus, &calendarID, &timezone); err != nil {
+			return nil, err
+		}
+		// どこにも設定が無い項目は組み込みのデフォルトを使う
+		policy := normalizer.DefaultDelayPolicy
+		if yellowDays.Valid {
+			policy.YellowDays = int(yellowDays.Int64)
+		}
+		if noDueDateStatus.Valid {
+			policy.NoDueDateStatus = noDueDateStatus.String
+		}
+		if calendarID.Valid {
+			calendarIDs[jiraID] = calendarID.Int64
+		}
+		if timezone.Valid {
+			loc, ok := locations[timezone.String]
+			if !ok {
+				if loc, err = time.LoadLocation(timezone.String); err != nil {
+					return nil, fmt.Errorf("project %s: invalid timezone %q: %w", jiraID, timezone.String, err)
+				}
+				locations[timezone.String] = loc
+			}
+			policy.Location = loc
+		}
+		m[jiraID] = policy
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(calendarIDs) == 0 {
+		return m, nil
+	}
+
+	calendars, err := r.getCalendars(ctx, calendarIDs)
+	if err != nil {
+		return nil, err
+	}
+	for jiraID, id := range calendarIDs {
+		policy := m[jiraID]
+		policy.Calendar = calendars[id]
+		m[jiraID] = policy
+	}
+	return m, nil
+}
+
+// getCalendars loads the holidays of the calendars referenced by calendarIDs.
+// Calendars without holidays still exclude weekends.
+func (r *sqlxRepository) getCalendars(ctx context.Context, calendarIDs map[string]int64) (map[int64]*calendar.Calendar, error) {
+	holidays := make(map[int64][]calendar.Holiday)
+	var ids []int64
+	for _, id := range calendarIDs {
+		if _, ok := holidays[id]; !ok {
+			holidays[id] = nil
+			ids = append(ids, id)
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT calendar_id, TO_CHAR(holiday_date, 'YYYY-MM-DD')
+		FROM calendar_holidays
+		WHERE calendar_id = ANY($1)`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get calendar holidays: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var h calendar.Holiday
+		if err := rows.Scan(&id, &h.Date); err != nil {
+			return nil, err
+		}
+		holidays[id] = append(holidays[id], h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	calendars := make(map[int64]*calendar.Calendar, len(holidays))
+	for id, hs := range holidays {
+		calendars[id] = calendar.New(hs)
+	}
+	return calendars, nil
+}
+
+func (r *sqlxRepository) GetFieldMapping(ctx context.Context) (normalizer.FieldMapping, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT column_name, jira_field_id FROM jira_field_mappings WHERE connection_id = $1`,
+		r.connectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get field mapping: %w", err)
+	}
+	defer rows.Close()
+
+	m := make(normalizer.FieldMapping)
+	for rows.Next() {
+		var column, fieldID string
+		if err := rows.Scan(&column, &fieldID); err != nil {
+			return nil, err
+		}
+		m[column] = fieldID
+	}
+	return m, rows.Err()
+}
+
+func (r *sqlxRepository) MarkMissingProjectsDeleted(ctx context.Context, jiraProjectIDs []string) (int, error) {
+	// プロジェクトと配下のチケットを同一ステートメントで論理削除する
+	const q = `
+		WITH gone AS (
+			UPDATE projects SET deleted_at = CURRENT_TIMESTAMP
+			WHERE connection_id = $2 AND deleted_at IS NULL AND NOT (jira_project_id = ANY($1))
+			RETURNING id
+		), gone_issues AS (
+			UPDATE issues SET deleted_at = CURRENT_TIMESTAMP
+			WHERE deleted_at IS NULL AND project_id IN (SELECT id FROM gone)
+		)
+		SELECT COUNT(*) FROM gone`
+
+	var n int
+	if err := r.db.QueryRowContext(ctx, q, pq.Array(jiraProjectIDs), r.connectionID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("mark missing projects deleted: %w", err)
+	}
+	return n, nil
+}
+
+func (r *sqlxRepository) MarkProjectDeleted(ctx context.Context, jiraProjectID string) (bool, error) {
+	// プロジェクトと配下のチケットを同一ステートメントで論理削除する
+	const q = `
+		WITH gone AS (
+			UPDATE projects SET deleted_at = CURRENT_TIMESTAMP
+			WHERE connection_id = $2 AND deleted_at IS NULL AND jira_project_id = $1
+			RETURNING id
+		), gone_issues AS (
+			UPDATE issues SET deleted_at = CURRENT_TIMESTAMP
+			WHERE deleted_at IS NULL AND project_id IN (SELECT id FROM gone)
+		)
+		SELECT COUNT(*) FROM gone`
+
+	var n int
+	if err := r.db.QueryRowContext(ctx, q, jiraProjectID, r.connectionID).Scan(&n); err != nil {
+		return false, fmt.Errorf("mark project deleted: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *sqlxRepository) MarkIssueDeleted(ctx context.Context, jiraIssueID string, deletedAt time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE issues SET deleted_at = CURRENT_TIMESTAMP, last_updated_at = $2
+		WHERE connection_id = $3 AND jira_issue_id = $1 AND deleted_at IS NULL AND last_updated_at <= $2`,
+		jiraIssueID, deletedAt, r.connectionID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("mark issue deleted: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *sqlxRepository) MarkMissingIssuesDeleted(ctx context.Context, projectID int64, jiraIssueIDs []string, syncStart time.Time) (int, error) {
+	// nil スライスは NULL として渡され ANY が常に NULL になるため、空配列に揃える
+	if jiraIssueIDs == nil {
+		jiraIssueIDs = []string{}
+	}
+	// updated_at は CURRENT_TIMESTAMP（セッションのタイムゾーン）で記録されるため、
+	// 同期開始時刻も timestamptz からセッションのタイムゾーンに変換して比較する
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE issues SET deleted_at = CURRENT_TIMESTAMP
+		WHERE project_id = $1 AND deleted_at IS NULL AND NOT (jira_issue_id = ANY($2))
+			AND updated_at < $3::timestamptz::timestamp`,
+		projectID, pq.Array(jiraIssueIDs), syncStart,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("mark missing issues deleted: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+func (r *sqlxRepository) CountProjectIssues(ctx context.Context, projectID int64) (int, error) {
+	var n int
+	err := r.db.GetContext(ctx, &n,
+		`SELECT COUNT(*) FROM issues WHERE project_id = $1 AND deleted_at IS NULL`, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("count project issues: %w", err)
+	}
+	return n, nil
+}
+
+// dbTimestampLayout formats a time as a TIMESTAMP literal in its own location,
+// matching how lib/pq stores time.Time values in TIMESTAMP columns.
+const dbTimestampLayout = "2006-01-02 15:04:05.999999"
+
+func (r *sqlxRepository) FindChangedIssues(ctx context.Context, issues []normalizer.DBIssue) (map[string]bool, error) {
+	changed := make(map[string]bool)
+	if len(issues) == 0 {
+		return changed, nil
+	}
+
+	ids := make([]string, len(issues))
+	updated := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.JiraIssueID
+		updated[i] = issue.LastUpdatedAt.Format(dbTimestampLayout)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.jira_issue_id
+		FROM unnest($1::text[], $2::timestamp[]) AS u(jira_issue_id, last_updated_at)
+		LEFT JOIN issues i ON i.connection_id = $3 AND i.jira_issue_id = u.jira_issue_id
+		WHERE i.id IS NULL OR u.last_updated_at > i.last_updated_at`,
+		pq.Array(ids), pq.Array(updated), r.connectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find changed issues: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		changed[id] = true
+	}
+	return changed, rows.Err()
+}
+
+func (r *sqlxRepository) InsertIssueHistory(ctx context.Context, history []normalizer.DBIssueHistory) (int, error) {
+	return r.insertIssueHistory(ctx, r.db, history)
+}
+
+func (r *sqlxRepository) insertIssueHistory(ctx context.Context, e sqlx.ExecerContext, history []normalizer.DBIssueHistory) (int, error) {
+	if len(history) == 0 {
+		return 0, nil
+	}
+
+	// 1 往復で挿入するため列ごとの配列に展開する
+	var (
+		issueIDs, historyIDs, fields []string
+		from, to, authorIDs, authors []sql.NullString
+		changedAt                    []string
+	)
+	for _, h := range history {
+		issueIDs = append(issueIDs, h.JiraIssueID)
+		historyIDs = append(historyIDs, h.JiraHistoryID)
+		fields = append(fields, h.Field)
+		from = append(from, nullString(h.FromValue))
+		to = append(to, nullString(h.ToValue))
+		authorIDs = append(authorIDs, sql.NullString{String: h.AuthorAccountID, Valid: h.AuthorAccountID != ""})
+		authors = append(authors, sql.NullString{String: h.AuthorName, Valid: h.AuthorName != ""})
+		changedAt = append(changedAt, h.ChangedAt.UTC().Format(dbTimestampLayout))
+	}
+
+	result, err := e.ExecContext(ctx, `
+		INSERT INTO issue_history (
+			issue_id, jira_history_id, field, from_value, to_value,
+			author_account_id, author_name, changed_at
+		)
+		SELECT i.id, h.jira_history_id, h.field, h.from_value, h.to_value,
+			h.author_account_id, h.author_name, h.changed_at
+		FROM unnest(
+			$1::text[], $2::text[], $3::text[], $4::text[], $5::text[],
+			$6::text[], $7::text[], $8::timestamp[]
+		) AS h(jira_issue_id, jira_history_id, field, from_value, to_value,
+			author_account_id, author_name, changed_at)
+		JOIN issues i ON i.connection_id = $9 AND i.jira_issue_id = h.jira_issue_id
+		ON CONFLICT (issue_id, jira_history_id, field) DO NOTHING`,
+		pq.Array(issueIDs), pq.Array(historyIDs), pq.Array(fields), pq.Array(from), pq.Array(to),
+		pq.Array(authorIDs), pq.Array(authors), pq.Array(changedAt), r.connectionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert issue history: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+func (r *sqlxRepository) ListOpenIssues(ctx context.Context) ([]OpenIssue, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT i.id, p.jira_project_id, i.status_category,
+			TO_CHAR(i.due_date, 'YYYY-MM-DD'), i.delay_status
+		FROM issues i
+		JOIN projects p ON p.id = i.project_id
+		WHERE i.connection_id = $1 AND i.deleted_at IS NULL AND i.status_category <> 'Done'`,
+		r.connectionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list open issues: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []OpenIssue
+	for rows.Next() {
+		var issue OpenIssue
+		var dueDate sql.NullString
+		if err := rows.Scan(&issue.ID, &issue.JiraProjectID, &issue.StatusCategory, &dueDate, &issue.DelayStatus); err != nil {
+			return nil, err
+		}
+		if dueDate.Valid {
+			issue.DueDate = &dueDate.String
+		}
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+func (r *sqlxRepository) UpdateDelayStatuses(ctx context.Context, updates []DelayStatusUpdate) (int, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, len(updates))
+	statuses := make([]string, len(updates))
+	for i, u := range updates {
+		ids[i] = u.IssueID
+		statuses[i] = u.DelayStatus
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE issues i SET delay_status = u.delay_status
+		FROM unnest($1::bigint[], $2::text[]) AS u(id, delay_status)
+		WHERE i.id = u.id AND i.delay_status IS DISTINCT FROM u.delay_status`,
+		pq.Array(ids), pq.Array(statuses),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("update delay statuses: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
 func (r *sqlxRepository) StartSyncLog(ctx context.Context, syncType string) (int64, error) {
 	var id int64
 	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO sync_logs (sync_type, status) VALUES ($1, 'RUNNING') RETURNING id`,
-		syncType,
+		`INSERT INTO sync_logs (connection_id, sync_type, status) VALUES ($1, $2, 'RUNNING') RETURNING id`,
+		r.connectionID, syncType,
 	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("start sync log: %w", err)
@@ -197,8 +1125,8 @@ func (r *sqlxRepository) StartSyncLog(ctx context.Context, syncType string) (int
 func (r *sqlxRepository) GetLastSuccessfulSyncTime(ctx context.Context, syncType string) (*time.Time, error) {
 	var t time.Time
 	err := r.db.QueryRowContext(ctx,
-		`SELECT executed_at FROM sync_logs WHERE sync_type = $1 AND status = 'SUCCESS' ORDER BY executed_at DESC LIMIT 1`,
-		syncType,
+		`SELECT executed_at FROM sync_logs WHERE connection_id = $1 AND sync_type = $2 AND status = 'SUCCESS' ORDER BY executed_at DESC LIMIT 1`,
+		r.connectionID, syncType,
 	).Scan(&t)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -209,6 +1137,29 @@ func (r *sqlxRepository) GetLastSuccessfulSyncTime(ctx context.Context, syncType
 	return &t, nil
 }
 
+func (r *sqlxRepository) ReopenSyncLog(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sync_logs SET status = 'RUNNING', completed_at = NULL, error_message = NULL WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("reopen sync log: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxRepository) UpdateSyncProgress(ctx context.Context, id int64, issuesSynced int) error {
+	// ワーカーが並列に更新するため、古い値で上書きしないよう GREATEST を使う
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sync_logs SET issues_synced = GREATEST(COALESCE(issues_synced, 0), $2) WHERE id = $1 AND status = 'RUNNING'`,
+		id, issuesSynced,
+	)
+	if err != nil {
+		return fmt.Errorf("update sync progress: %w", err)
+	}
+	return nil
+}
+
 func (r *sqlxRepository) FinishSyncLog(ctx context.Context, id int64, status string, projectsSynced, issuesSynced int, errMsg string) error {
 	var errMsgPtr *string
 	if errMsg != "" {
@@ -231,3 +1182,208 @@ func (r *sqlxRepository) FinishSyncLog(ctx context.Context, id int64, status str
 	}
 	return nil
 }
+
+func (r *sqlxRepository) RecordProjectResults(ctx context.Context, syncLogID int64, results []ProjectSyncResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	const q = `
+		INSERT INTO sync_project_results (
+			sync_log_id, project_id, jira_project_id, project_key,
+			status, issues_synced, error_message, duration_ms
+		) VALUES (
+			:sync_log_id,
+			(SELECT id FROM projects WHERE connection_id = :connection_id AND jira_project_id = :jira_project_id),
+			:jira_project_id, :project_key,
+			:status, :issues_synced, :error_message, :duration_ms
+		)
+		ON CONFLICT (sync_log_id, jira_project_id) DO UPDATE SET
+			project_id    = EXCLUDED.project_id,
+			status        = EXCLUDED.status,
+			issues_synced = EXCLUDED.issues_synced,
+			error_message = EXCLUDED.error_message,
+			duration_ms   = EXCLUDED.duration_ms`
+
+	type row struct {
+		SyncLogID     int64   `db:"sync_log_id"`
+		ConnectionID  int64   `db:"connection_id"`
+		JiraProjectID string  `db:"jira_project_id"`
+		ProjectKey    string  `db:"project_key"`
+		Status        string  `db:"status"`
+		IssuesSynced  int     `db:"issues_synced"`
+		ErrorMessage  *string `db:"error_message"`
+		DurationMs    int64   `db:"duration_ms"`
+	}
+
+	rows := make([]row, len(results))
+	for i, res := range results {
+		var errMsg *string
+		if res.ErrorMessage != "" {
+			m := res.ErrorMessage
+			errMsg = &m
+		}
+		rows[i] = row{
+			SyncLogID:     syncLogID,
+			ConnectionID:  r.connectionID,
+			JiraProjectID: res.JiraProjectID,
+			ProjectKey:    res.ProjectKey,
+			Status:        res.Status,
+			IssuesSynced:  res.IssuesSynced,
+			ErrorMessage:  errMsg,
+			DurationMs:    res.Duration.Milliseconds(),
+		}
+	}
+
+	if _, err := r.db.NamedExecContext(ctx, q, rows); err != nil {
+		return fmt.Errorf("record project results: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxRepository) SaveCheckpoint(ctx context.Context, syncLogID int64, cp SyncCheckpoint) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO sync_checkpoints (sync_log_id, jira_project_id, next_start_at, next_page_token, issues_synced, completed)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+		ON CONFLICT (sync_log_id, jira_project_id) DO UPDATE SET
+			next_start_at   = EXCLUDED.next_start_at,
+			next_page_token = EXCLUDED.next_page_token,
+			issues_synced   = EXCLUDED.issues_synced,
+			completed       = EXCLUDED.completed,
+			updated_at      = CURRENT_TIMESTAMP`,
+		syncLogID, cp.JiraProjectID, cp.NextStartAt, cp.NextPageToken, cp.IssuesSynced, cp.Completed,
+	)
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *sqlxRepository) GetCheckpoints(ctx context.Context, syncLogID int64) (map[string]SyncCheckpoint, error) {
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT jira_project_id, next_start_at, COALESCE(next_page_token, ''), issues_synced, completed
+		FROM sync_checkpoints
+		WHERE sync_log_id = $1`,
+		syncLogID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	m := make(map[string]SyncCheckpoint)
+	for rows.Next() {
+		var cp SyncCheckpoint
+		if err := rows.Scan(&cp.JiraProjectID, &cp.NextStartAt, &cp.NextPageToken, &cp.IssuesSynced, &cp.Completed); err != nil {
+			return nil, err
+		}
+		m[cp.JiraProjectID] = cp
+	}
+	return m, rows.Err()
+}
+
+func (r *sqlxRepository) GetUnfinishedSyncLogID(ctx context.Context, syncType string) (int64, error) {
+	// 最新の同期だけを対象にする（その後に完了した同期があれば古い中断分は再開しない）
+	var (
+		id     int64
+		status string
+	)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, status FROM sync_logs WHERE connection_id = $1 AND sync_type = $2 ORDER BY executed_at DESC LIMIT 1`,
+		r.connectionID, syncType,
+	).Scan(&id, &status)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get unfinished sync log: %w", err)
+	}
+	if status != "RUNNING" && status != "ABORTED" {
+		return 0, nil
+	}
+	return id, nil
+}
+
+func (r *sqlxRepository) RecordDelaySnapshots(ctx context.Context, date string) (int, int, error) {
+	// プロジェクト単位と組織単位（配下の組織を含む）を同一ステートメントで記録する
+	const q = `
+		WITH project_stats AS (
+			SELECT
+				p.id AS project_id,
+				p.organization_id,
+				COUNT(i.id) FILTER (WHERE i.delay_status = 'RED')    AS red_issues,
+				COUNT(i.id) FILTER (WHERE i.delay_status = 'YELLOW') AS yellow_issues,
+				COUNT(i.id) FILTER (WHERE i.delay_status = 'GREEN')  AS green_issues,
+				COUNT(i.id)                                          AS total_issues
+			FROM projects p
+			LEFT JOIN issues i ON i.project_id = p.id AND i.deleted_at IS NULL
+			WHERE p.deleted_at IS NULL
+			GROUP BY p.id, p.organization_id
+		), project_status AS (
+			SELECT *,
+				CASE
+					WHEN red_issues    > 0 THEN 'RED'
+					WHEN yellow_issues > 0 THEN 'YELLOW'
+					ELSE 'GREEN'
+				END AS delay_status
+			FROM project_stats
+		), projects_saved AS (
+			INSERT INTO delay_snapshots (
+				snapshot_date, project_id,
+				red_issues, yellow_issues, green_issues, total_issues,
+				red_projects, yellow_projects, green_projects, total_projects
+			)
+			SELECT $1::date, project_id,
+				red_issues, yellow_issues, green_issues, total_issues,
+				(delay_status = 'RED')::int, (delay_status = 'YELLOW')::int, (delay_status = 'GREEN')::int, 1
+			FROM project_status
+			ON CONFLICT (snapshot_date, project_id) WHERE project_id IS NOT NULL DO UPDATE SET
+				red_issues      = EXCLUDED.red_issues,
+				yellow_issues   = EXCLUDED.yellow_issues,
+				green_issues    = EXCLUDED.green_issues,
+				total_issues    = EXCLUDED.total_issues,
+				red_projects    = EXCLUDED.red_projects,
+				yellow_projects = EXCLUDED.yellow_projects,
+				green_projects  = EXCLUDED.green_projects,
+				total_projects  = EXCLUDED.total_projects,
+				created_at      = CURRENT_TIMESTAMP
+			RETURNING 1
+		), organizations_saved AS (
+			INSERT INTO delay_snapshots (
+				snapshot_date, organization_id,
+				red_issues, yellow_issues, green_issues, total_issues,
+				red_projects, yellow_projects, green_projects, total_projects
+			)
+			SELECT $1::date, o.id,
+				COALESCE(SUM(ps.red_issues), 0),
+				COALESCE(SUM(ps.yellow_issues), 0),
+				COALESCE(SUM(ps.green_issues), 0),
+				COALESCE(SUM(ps.total_issues), 0),
+				COUNT(ps.project_id) FILTER (WHERE ps.delay_status = 'RED'),
+				COUNT(ps.project_id) FILTER (WHERE ps.delay_status = 'YELLOW'),
+				COUNT(ps.project_id) FILTER (WHERE ps.delay_status = 'GREEN'),
+				COUNT(ps.project_id)
+			FROM organizations o
+			LEFT JOIN organizations d ON d.path LIKE o.path || '%'
+			LEFT JOIN project_status ps ON ps.organization_id = d.id
+			GROUP BY o.id
+			ON CONFLICT (snapshot_date, organization_id) WHERE organization_id IS NOT NULL DO UPDATE SET
+				red_issues      = EXCLUDED.red_issues,
+				yellow_issues   = EXCLUDED.yellow_issues,
+				green_issues    = EXCLUDED.green_issues,
+				total_issues    = EXCLUDED.total_issues,
+				red_projects    = EXCLUDED.red_projects,
+				yellow_projects = EXCLUDED.yellow_projects,
+				green_projects  = EXCLUDED.green_projects,
+				total_projects  = EXCLUDED.total_projects,
+				created_at      = CURRENT_TIMESTAMP
+			RETURNING 1
+		)
+		SELECT (SELECT COUNT(*) FROM projects_saved), (SELECT COUNT(*) FROM organizations_saved)`
+
+	var projects, organizations int
+	if err := r.db.QueryRowContext(ctx, q, date).Scan(&projects, &organizations); err != nil {
+		return 0, 0, fmt.Errorf("record delay snapshots: %w", err)
+	}
+	return projects, organizations, nil
+}
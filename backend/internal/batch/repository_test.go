@@ -3,11 +3,13 @@ package batch
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -28,7 +30,7 @@ func newRepoDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
 
 func TestUpsertProjects_Empty(t *testing.T) {
 	db, _ := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	n, err := repo.UpsertProjects(context.Background(), nil)
 
@@ -38,7 +40,7 @@ func TestUpsertProjects_Empty(t *testing.T) {
 
 func TestUpsertProjects_Success(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectExec(`INSERT INTO projects`).
 		WillReturnResult(sqlmock.NewResult(1, 2))
@@ -57,7 +59,7 @@ func TestUpsertProjects_Success(t *testing.T) {
 
 func TestUpsertIssues_Empty(t *testing.T) {
 	db, _ := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	n, err := repo.UpsertIssues(context.Background(), nil, map[string]int64{})
 
@@ -67,7 +69,7 @@ func TestUpsertIssues_Empty(t *testing.T) {
 
 func TestUpsertIssues_UnknownProject(t *testing.T) {
 	db, _ := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	// All issues have unknown jira_project_id → all skipped → no DB call
 	issues := []normalizer.DBIssue{
@@ -81,7 +83,7 @@ func TestUpsertIssues_UnknownProject(t *testing.T) {
 
 func TestUpsertIssues_Success(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectExec(`INSERT INTO issues`).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -104,11 +106,277 @@ func TestUpsertIssues_Success(t *testing.T) {
 	assert.Equal(t, 1, n)
 }
 
+func TestUpsertIssues_SkipsOlderUpdates(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// 保存済みの方が新しいチケットは更新されず、影響行数に含まれない
+	mock.ExpectExec(`INSERT INTO issues(.|\n)*WHERE issues.last_updated_at <= EXCLUDED.last_updated_at`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	issues := []normalizer.DBIssue{
+		{JiraIssueID: "I1", JiraProjectID: "P1", Summary: "old", LastUpdatedAt: time.Now().Add(-time.Hour)},
+	}
+	n, err := repo.UpsertIssues(context.Background(), issues, map[string]int64{"P1": 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestUpsertIssues_CustomFields(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	startDate, epicKey, sprintName := "2026-03-01", "PROJ-1", "Sprint 2"
+	storyPoints := 5.0
+
+	// 先頭 18 列は接続 ID と既存の列。未設定のラベル・コンポーネントは空配列で書き込む
+	args := make([]driver.Value, 18)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args = append(args, startDate, storyPoints, epicKey, sprintName, pq.StringArray{"backend"}, pq.StringArray{},
+		sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
+	mock.ExpectExec(`INSERT INTO issues`).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	issues := []normalizer.DBIssue{
+		{
+			JiraIssueID:   "I1",
+			JiraIssueKey:  "KEY-1",
+			JiraProjectID: "P1",
+			DelayStatus:   "GREEN",
+			LastUpdatedAt: time.Now(),
+			StartDate:     &startDate,
+			StoryPoints:   &storyPoints,
+			EpicKey:       &epicKey,
+			SprintName:    &sprintName,
+			Labels:        []string{"backend"},
+		},
+	}
+	n, err := repo.UpsertIssues(context.Background(), issues, map[string]int64{"P1": 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertIssues_ChangelogOriginalDueDateTakesPrecedence(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// 完全な changelog から求めた期日は保存済みの値（000012 で現在の期日にした初期値）を上書きする
+	original := "2026-03-01"
+	args := make([]driver.Value, 14)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args = append(args, &original, 2, true, true)
+	for range 9 {
+		args = append(args, sqlmock.AnyArg())
+	}
+	mock.ExpectExec(`INSERT INTO issues(.|\n)*original_due_date\s+= CASE WHEN EXCLUDED.original_due_date_from_changelog THEN EXCLUDED.original_due_date\s+ELSE COALESCE\(issues.original_due_date, EXCLUDED.original_due_date\) END`).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	issues := []normalizer.DBIssue{
+		{JiraIssueID: "I1", JiraProjectID: "P1", LastUpdatedAt: time.Now(),
+			OriginalDueDate: &original, PostponeCount: 2, Slipped: true, OriginalDueDateFromChangelog: true},
+	}
+	_, err := repo.UpsertIssues(context.Background(), issues, map[string]int64{"P1": 10})
+
+	assert.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertIssues_Parent(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	parentID, parentKey := "10000", "PROJ-100"
+	args := make([]driver.Value, 24)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args = append(args, parentID, parentKey, sqlmock.AnyArg())
+	mock.ExpectExec(`INSERT INTO issues`).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	issues := []normalizer.DBIssue{
+		{JiraIssueID: "I1", JiraProjectID: "P1", LastUpdatedAt: time.Now(), ParentJiraIssueID: &parentID, ParentIssueKey: &parentKey},
+	}
+	_, err := repo.UpsertIssues(context.Background(), issues, map[string]int64{"P1": 10})
+
+	assert.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertIssues_FixVersions(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	args := make([]driver.Value, 26)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	args = append(args, pq.StringArray{"V1", "V2"})
+	mock.ExpectExec(`INSERT INTO issues`).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	issues := []normalizer.DBIssue{
+		{JiraIssueID: "I1", JiraProjectID: "P1", LastUpdatedAt: time.Now(), FixVersionIDs: []string{"V1", "V2"}},
+	}
+	_, err := repo.UpsertIssues(context.Background(), issues, map[string]int64{"P1": 10})
+
+	assert.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- ReplaceIssueLinks tests ---
+
+func TestReplaceIssueLinks_Empty(t *testing.T) {
+	db, _ := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	n, err := repo.ReplaceIssueLinks(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestReplaceIssueLinks(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// リンクの無いチケット（I2）も既存リンクの削除対象として渡す
+	mock.ExpectExec(`DELETE FROM issue_links(.|\n)*INSERT INTO issue_links`).
+		WithArgs(
+			pq.Array([]string{"I1", "I2"}),
+			pq.Array([]string{"I1"}), pq.Array([]string{"L1"}), pq.Array([]string{"Blocks"}), pq.Array([]string{"outward"}),
+			pq.Array([]sql.NullString{{String: "blocks", Valid: true}}), pq.Array([]string{"I3"}), pq.Array([]string{"PROJ-3"}),
+			int64(1),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	issues := []normalizer.DBIssue{
+		{JiraIssueID: "I1", Links: []normalizer.DBIssueLink{{
+			JiraLinkID: "L1", LinkType: "Blocks", Direction: "outward", Description: "blocks",
+			LinkedJiraIssueID: "I3", LinkedIssueKey: "PROJ-3",
+		}}},
+		{JiraIssueID: "I2"},
+	}
+	n, err := repo.ReplaceIssueLinks(context.Background(), issues)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceProjectVersions(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectExec(`DELETE FROM project_versions(.|\n)*INSERT INTO project_versions`).
+		WithArgs(
+			int64(1),
+			pq.Array([]string{"V1"}), pq.Array([]string{"v1.0"}), pq.Array([]sql.NullString{{}}),
+			pq.Array([]sql.NullString{{}}), pq.Array([]sql.NullString{{String: "2026-03-31", Valid: true}}),
+			pq.Array([]bool{false}), pq.Array([]bool{false}), int64(1),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	releaseDate := "2026-03-31"
+	n, err := repo.ReplaceProjectVersions(context.Background(), 1, []normalizer.DBVersion{
+		{JiraVersionID: "V1", Name: "v1.0", ReleaseDate: &releaseDate},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- Sprint tests ---
+
+func TestReplaceProjectBoards(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`DELETE FROM jira_boards(.|\n)*INSERT INTO jira_boards`).
+		WithArgs(int64(1), pq.Array([]int64{7}), pq.Array([]string{"PROJ board"}), pq.Array([]string{"scrum"}), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"jira_board_id", "id"}).AddRow(7, 3))
+
+	m, err := repo.ReplaceProjectBoards(context.Background(), 1, []normalizer.DBBoard{
+		{JiraBoardID: 7, Name: "PROJ board", BoardType: "scrum"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{7: 3}, m)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceBoardSprints(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// 日時は UTC で保存する
+	start := time.Date(2026, 2, 16, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	mock.ExpectExec(`DELETE FROM sprints(.|\n)*INSERT INTO sprints`).
+		WithArgs(
+			int64(3),
+			pq.Array([]int64{11}), pq.Array([]string{"Sprint 1"}), pq.Array([]string{"active"}), pq.Array([]sql.NullString{{}}),
+			pq.Array([]sql.NullTime{{Time: start.UTC(), Valid: true}}), pq.Array([]sql.NullTime{{}}), pq.Array([]sql.NullTime{{}}),
+			int64(1),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := repo.ReplaceBoardSprints(context.Background(), 3, []normalizer.DBSprint{
+		{JiraSprintID: 11, Name: "Sprint 1", State: "active", StartDate: &start},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIssuesSyncedClosedSprintIDs(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT jira_sprint_id FROM sprints`).
+		WithArgs(int64(1), pq.Array([]int64{11, 12})).
+		WillReturnRows(sqlmock.NewRows([]string{"jira_sprint_id"}).AddRow(11))
+
+	m, err := repo.GetIssuesSyncedClosedSprintIDs(context.Background(), []int64{11, 12})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{11: true}, m)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceSprintIssues_Empty(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// チケットが 0 件でも既存のメンバーを削除するため実行する
+	mock.ExpectExec(`UPDATE sprints SET issues_synced_state(.|\n)*INSERT INTO sprint_issues`).
+		WithArgs(int64(11), pq.StringArray{}, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	n, err := repo.ReplaceSprintIssues(context.Background(), 11, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 // --- GetProjectIDMap tests ---
 
 func TestGetProjectIDMap_Empty(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectQuery(`SELECT id, jira_project_id FROM projects`).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "jira_project_id"}))
@@ -121,7 +389,7 @@ func TestGetProjectIDMap_Empty(t *testing.T) {
 
 func TestGetProjectIDMap_WithRows(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectQuery(`SELECT id, jira_project_id FROM projects`).
 		WillReturnRows(
@@ -136,14 +404,448 @@ func TestGetProjectIDMap_WithRows(t *testing.T) {
 	assert.Equal(t, map[string]int64{"P1": 1, "P2": 2}, m)
 }
 
+// --- GetDelayPolicies tests ---
+
+func TestGetDelayPolicies_FallsBackToDefault(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`FROM projects p\s+LEFT JOIN organizations o`).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"jira_project_id", "yellow_days", "no_due_date_status", "business_calendar_id", "timezone"}).
+				AddRow("P1", 7, "GREEN", nil, nil).
+				AddRow("P2", nil, nil, nil, nil),
+		)
+
+	m, err := repo.GetDelayPolicies(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]normalizer.DelayPolicy{
+		"P1": {YellowDays: 7, NoDueDateStatus: "GREEN"},
+		"P2": normalizer.DefaultDelayPolicy,
+	}, m)
+}
+
+func TestGetDelayPolicies_LoadsCalendars(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`FROM projects p\s+LEFT JOIN organizations o`).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"jira_project_id", "yellow_days", "no_due_date_status", "business_calendar_id", "timezone"}).
+				AddRow("P1", 3, "YELLOW", 1, nil).
+				AddRow("P2", 3, "YELLOW", 2, nil).
+				AddRow("P3", 3, "YELLOW", nil, nil),
+		)
+	mock.ExpectQuery(`FROM calendar_holidays`).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"calendar_id", "holiday_date"}).
+				AddRow(1, "2026-02-23"),
+		)
+
+	m, err := repo.GetDelayPolicies(context.Background())
+
+	require.NoError(t, err)
+	holiday := time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)
+	require.NotNil(t, m["P1"].Calendar)
+	assert.False(t, m["P1"].Calendar.IsBusinessDay(holiday))
+	// 休日が無いカレンダーでも土日は除く
+	require.NotNil(t, m["P2"].Calendar)
+	assert.True(t, m["P2"].Calendar.IsBusinessDay(holiday))
+	assert.Nil(t, m["P3"].Calendar)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDelayPolicies_LoadsTimezones(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`FROM projects p\s+LEFT JOIN organizations o`).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"jira_project_id", "yellow_days", "no_due_date_status", "business_calendar_id", "timezone"}).
+				AddRow("P1", 3, "YELLOW", nil, "America/New_York").
+				AddRow("P2", 3, "YELLOW", nil, nil),
+		)
+
+	m, err := repo.GetDelayPolicies(context.Background())
+
+	require.NoError(t, err)
+	require.NotNil(t, m["P1"].Location)
+	assert.Equal(t, "America/New_York", m["P1"].Location.String())
+	assert.Nil(t, m["P2"].Location)
+}
+
+func TestGetDelayPolicies_InvalidTimezone(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`FROM projects p\s+LEFT JOIN organizations o`).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"jira_project_id", "yellow_days", "no_due_date_status", "business_calendar_id", "timezone"}).
+				AddRow("P1", 3, "YELLOW", nil, "Mars/Olympus"),
+		)
+
+	_, err := repo.GetDelayPolicies(context.Background())
+
+	assert.ErrorContains(t, err, "invalid timezone")
+}
+
+func TestGetProjectDelayPolicy_LoadsOnlyTheProject(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT id FROM projects WHERE connection_id = \$1 AND jira_project_id = \$2`).
+		WithArgs(int64(1), "P2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectQuery(`WHERE p\.id = \$1`).
+		WithArgs(int64(5)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"jira_project_id", "yellow_days", "no_due_date_status", "business_calendar_id", "timezone"}).
+				AddRow("P2", 5, "GREEN", 2, "America/New_York"),
+		)
+	mock.ExpectQuery(`FROM calendar_holidays`).
+		WithArgs(pq.Array([]int64{2})).
+		WillReturnRows(sqlmock.NewRows([]string{"calendar_id", "holiday_date"}).AddRow(2, "2026-02-23"))
+
+	id, policy, ok, err := repo.GetProjectDelayPolicy(context.Background(), "P2")
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), id)
+	assert.Equal(t, 5, policy.YellowDays)
+	assert.Equal(t, "GREEN", policy.NoDueDateStatus)
+	require.NotNil(t, policy.Location)
+	assert.Equal(t, "America/New_York", policy.Location.String())
+	require.NotNil(t, policy.Calendar)
+	assert.False(t, policy.Calendar.IsBusinessDay(time.Date(2026, 2, 23, 0, 0, 0, 0, time.UTC)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetProjectDelayPolicy_UnknownProject(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT id FROM projects`).
+		WithArgs(int64(1), "P9").
+		WillReturnError(sql.ErrNoRows)
+
+	_, _, ok, err := repo.GetProjectDelayPolicy(context.Background(), "P9")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- GetFieldMapping tests ---
+
+func TestGetFieldMapping(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 2)
+
+	// カスタムフィールドの ID はサイトごとに異なるため、接続の対応だけを読む
+	mock.ExpectQuery(`SELECT column_name, jira_field_id FROM jira_field_mappings WHERE connection_id = \$1`).
+		WithArgs(int64(2)).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"column_name", "jira_field_id"}).
+				AddRow("start_date", "customfield_10015").
+				AddRow("labels", "labels"),
+		)
+
+	m, err := repo.GetFieldMapping(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, normalizer.FieldMapping{"start_date": "customfield_10015", "labels": "labels"}, m)
+}
+
+// --- ListOpenIssues / UpdateDelayStatuses tests ---
+
+func TestListOpenIssues(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`FROM issues i\s+JOIN projects p`).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "jira_project_id", "status_category", "due_date", "delay_status"}).
+				AddRow(1, "P1", "To Do", "2026-03-01", "GREEN").
+				AddRow(2, "P1", "In Progress", nil, "YELLOW"),
+		)
+
+	issues, err := repo.ListOpenIssues(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+	require.NotNil(t, issues[0].DueDate)
+	assert.Equal(t, "2026-03-01", *issues[0].DueDate)
+	assert.Nil(t, issues[1].DueDate)
+}
+
+func TestUpdateDelayStatuses_Empty(t *testing.T) {
+	db, _ := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	n, err := repo.UpdateDelayStatuses(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestUpdateDelayStatuses_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectExec(`UPDATE issues i SET delay_status`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := repo.UpdateDelayStatuses(context.Background(), []DelayStatusUpdate{
+		{IssueID: 1, DelayStatus: "RED"},
+		{IssueID: 2, DelayStatus: "YELLOW"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+// --- MarkMissingProjectsDeleted tests ---
+
+func TestMarkMissingProjectsDeleted_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`UPDATE projects SET deleted_at`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	n, err := repo.MarkMissingProjectsDeleted(context.Background(), []string{"P1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+// --- MarkProjectDeleted / MarkIssueDeleted tests ---
+
+func TestMarkProjectDeleted(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`UPDATE projects SET deleted_at`).
+		WithArgs("P1", int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	deleted, err := repo.MarkProjectDeleted(context.Background(), "P1")
+
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+}
+
+func TestMarkIssueDeleted_Stale(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// 削除日時より後に更新されたチケットは削除しない
+	deletedAt := time.Date(2026, 2, 24, 10, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE issues SET deleted_at = CURRENT_TIMESTAMP, last_updated_at = \$2(.|\n)*last_updated_at <= \$2`).
+		WithArgs("I1", deletedAt, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.MarkIssueDeleted(context.Background(), "I1", deletedAt)
+
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- MarkMissingIssuesDeleted tests ---
+
+func TestMarkMissingIssuesDeleted_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	start := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE issues SET deleted_at[\s\S]*updated_at < \$3`).
+		WithArgs(int64(10), sqlmock.AnyArg(), start).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := repo.MarkMissingIssuesDeleted(context.Background(), 10, []string{"I1", "I2"}, start)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestMarkMissingIssuesDeleted_NilIDsMarksAll(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	// nil は空配列として渡される（NULL だと ANY が常に NULL になる）
+	start := time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(`UPDATE issues SET deleted_at`).
+		WithArgs(int64(10), "{}", start).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	n, err := repo.MarkMissingIssuesDeleted(context.Background(), 10, nil, start)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+}
+
+// --- CountProjectIssues tests ---
+
+func TestCountProjectIssues_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM issues WHERE project_id = \$1 AND deleted_at IS NULL`).
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	n, err := repo.CountProjectIssues(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+}
+
+// --- RecordProjectResults tests ---
+
+func TestRecordProjectResults_Empty(t *testing.T) {
+	db, _ := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	err := repo.RecordProjectResults(context.Background(), 1, nil)
+
+	assert.NoError(t, err)
+}
+
+func TestRecordProjectResults_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectExec(`INSERT INTO sync_project_results`).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	results := []ProjectSyncResult{
+		{JiraProjectID: "P1", ProjectKey: "KEY1", Status: "SUCCESS", IssuesSynced: 10, Duration: time.Second},
+		{JiraProjectID: "P2", ProjectKey: "KEY2", Status: "FAILURE", ErrorMessage: "HTTP 404"},
+	}
+	err := repo.RecordProjectResults(context.Background(), 7, results)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- Checkpoint tests ---
+
+func TestSaveCheckpoint_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectExec(`INSERT INTO sync_checkpoints`).
+		WithArgs(int64(7), "P1", 100, "", 100, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SaveCheckpoint(context.Background(), 7, SyncCheckpoint{JiraProjectID: "P1", NextStartAt: 100, IssuesSynced: 100})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCheckpoints_WithRows(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT jira_project_id, next_start_at`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"jira_project_id", "next_start_at", "next_page_token", "issues_synced", "completed"}).
+			AddRow("P1", 300, "", 300, true).
+			AddRow("P2", 100, "", 100, false).
+			AddRow("P3", 0, "tok-abc", 100, false))
+
+	cps, err := repo.GetCheckpoints(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Len(t, cps, 3)
+	assert.True(t, cps["P1"].Completed)
+	assert.Equal(t, 100, cps["P2"].NextStartAt)
+	assert.Equal(t, "tok-abc", cps["P3"].NextPageToken)
+}
+
+func TestGetUnfinishedSyncLogID_NoRows(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT id, status FROM sync_logs`).
+		WithArgs(int64(1), "FULL").
+		WillReturnError(sql.ErrNoRows)
+
+	id, err := repo.GetUnfinishedSyncLogID(context.Background(), "FULL")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), id)
+}
+
+func TestGetUnfinishedSyncLogID_Found(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT id, status FROM sync_logs`).
+		WithArgs(int64(1), "FULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(42, "RUNNING"))
+
+	id, err := repo.GetUnfinishedSyncLogID(context.Background(), "FULL")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+}
+
+func TestGetUnfinishedSyncLogID_Aborted(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT id, status FROM sync_logs`).
+		WithArgs(int64(1), "FULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(43, "ABORTED"))
+
+	id, err := repo.GetUnfinishedSyncLogID(context.Background(), "FULL")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(43), id)
+}
+
+func TestGetUnfinishedSyncLogID_LatestFinished(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`SELECT id, status FROM sync_logs`).
+		WithArgs(int64(1), "FULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(44, "SUCCESS"))
+
+	id, err := repo.GetUnfinishedSyncLogID(context.Background(), "FULL")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), id)
+}
+
+// --- ReopenSyncLog tests ---
+
+func TestReopenSyncLog_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectExec(`UPDATE sync_logs SET status = 'RUNNING'`).
+		WithArgs(int64(43)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.ReopenSyncLog(context.Background(), 43)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // --- StartSyncLog tests ---
 
 func TestStartSyncLog_Success(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectQuery(`INSERT INTO sync_logs`).
-		WithArgs("FULL").
+		WithArgs(int64(1), "FULL").
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
 
 	id, err := repo.StartSyncLog(context.Background(), "FULL")
@@ -156,10 +858,10 @@ func TestStartSyncLog_Success(t *testing.T) {
 
 func TestGetLastSuccessfulSyncTime_NoRows(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectQuery(`SELECT executed_at FROM sync_logs`).
-		WithArgs("FULL").
+		WithArgs(int64(1), "FULL").
 		WillReturnError(sql.ErrNoRows)
 
 	result, err := repo.GetLastSuccessfulSyncTime(context.Background(), "FULL")
@@ -170,11 +872,11 @@ func TestGetLastSuccessfulSyncTime_NoRows(t *testing.T) {
 
 func TestGetLastSuccessfulSyncTime_WithRow(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	now := time.Now().Truncate(time.Second)
 	mock.ExpectQuery(`SELECT executed_at FROM sync_logs`).
-		WithArgs("FULL").
+		WithArgs(int64(1), "FULL").
 		WillReturnRows(sqlmock.NewRows([]string{"executed_at"}).AddRow(now))
 
 	result, err := repo.GetLastSuccessfulSyncTime(context.Background(), "FULL")
@@ -184,11 +886,27 @@ func TestGetLastSuccessfulSyncTime_WithRow(t *testing.T) {
 	assert.Equal(t, now, *result)
 }
 
+// --- UpdateSyncProgress tests ---
+
+func TestUpdateSyncProgress_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectExec(`UPDATE sync_logs SET issues_synced`).
+		WithArgs(int64(3), 1500).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.UpdateSyncProgress(context.Background(), 3, 1500)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 // --- FinishSyncLog tests ---
 
 func TestFinishSyncLog_Success(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectExec(`UPDATE sync_logs SET`).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -200,7 +918,7 @@ func TestFinishSyncLog_Success(t *testing.T) {
 
 func TestFinishSyncLog_WithErrorMessage(t *testing.T) {
 	db, mock := newRepoDB(t)
-	repo := NewRepository(db)
+	repo := NewRepository(db, 1)
 
 	mock.ExpectExec(`UPDATE sync_logs SET`).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -209,3 +927,143 @@ func TestFinishSyncLog_WithErrorMessage(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+// --- FindChangedIssues tests ---
+
+func TestFindChangedIssues_Empty(t *testing.T) {
+	db, _ := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	got, err := repo.FindChangedIssues(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestFindChangedIssues_WithRows(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	updated := time.Date(2026, 2, 20, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM unnest`).
+		WithArgs(`{"I1","I2"}`, `{"2026-02-20 10:00:00","2026-02-20 10:00:00"}`, int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"jira_issue_id"}).AddRow("I2"))
+
+	got, err := repo.FindChangedIssues(context.Background(), []normalizer.DBIssue{
+		{JiraIssueID: "I1", LastUpdatedAt: updated},
+		{JiraIssueID: "I2", LastUpdatedAt: updated},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"I2": true}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- InsertIssueHistory tests ---
+
+func TestInsertIssueHistory_Empty(t *testing.T) {
+	db, _ := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	n, err := repo.InsertIssueHistory(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestInsertIssueHistory_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	to := "Done"
+	mock.ExpectExec(`INSERT INTO issue_history`).
+		WithArgs(`{"I1"}`, `{"100"}`, `{"status"}`, `{NULL}`, `{"Done"}`, `{NULL}`, `{NULL}`, `{"2026-02-10 00:30:00"}`, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := repo.InsertIssueHistory(context.Background(), []normalizer.DBIssueHistory{{
+		JiraIssueID:   "I1",
+		JiraHistoryID: "100",
+		Field:         "status",
+		ToValue:       &to,
+		ChangedAt:     time.Date(2026, 2, 10, 9, 30, 0, 0, time.FixedZone("JST", 9*60*60)),
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- UpsertIssuesWithHistory tests ---
+
+func TestUpsertIssuesWithHistory_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	issues := []normalizer.DBIssue{{JiraIssueID: "I1", JiraIssueKey: "P-1", JiraProjectID: "P1", LastUpdatedAt: time.Now()}}
+	to := "Done"
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO issues`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO issue_history`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := repo.UpsertIssuesWithHistory(context.Background(), issues, map[string]int64{"P1": 10}, []normalizer.DBIssueHistory{{
+		JiraIssueID:   "I1",
+		JiraHistoryID: "100",
+		Field:         "status",
+		ToValue:       &to,
+		ChangedAt:     time.Now(),
+	}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertIssuesWithHistory_HistoryErrorRollsBack(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	issues := []normalizer.DBIssue{{JiraIssueID: "I1", JiraIssueKey: "P-1", JiraProjectID: "P1", LastUpdatedAt: time.Now()}}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO issues`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO issue_history`).WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	_, err := repo.UpsertIssuesWithHistory(context.Background(), issues, map[string]int64{"P1": 10}, []normalizer.DBIssueHistory{{
+		JiraIssueID:   "I1",
+		JiraHistoryID: "100",
+		Field:         "status",
+		ChangedAt:     time.Now(),
+	}})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- RecordDelaySnapshots tests ---
+
+func TestRecordDelaySnapshots_Success(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`INSERT INTO delay_snapshots`).
+		WithArgs("2026-02-24").
+		WillReturnRows(sqlmock.NewRows([]string{"projects", "organizations"}).AddRow(12, 4))
+
+	projects, orgs, err := repo.RecordDelaySnapshots(context.Background(), "2026-02-24")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, projects)
+	assert.Equal(t, 4, orgs)
+}
+
+func TestRecordDelaySnapshots_DBError(t *testing.T) {
+	db, mock := newRepoDB(t)
+	repo := NewRepository(db, 1)
+
+	mock.ExpectQuery(`INSERT INTO delay_snapshots`).WillReturnError(sql.ErrConnDone)
+
+	_, _, err := repo.RecordDelaySnapshots(context.Background(), "2026-02-24")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+// RunDelaySnapshot records today's (reporting timezone) RED/YELLOW/GREEN counts of every project
+// and organization in delay_snapshots. It does not call Jira and is meant to run
+// once a day after the full sync; running it again on the same day overwrites
+// that day's snapshot.
+func (s *Syncer) RunDelaySnapshot(ctx context.Context) error {
+	date := normalizer.Now().Format("2006-01-02")
+	s.log.Info("delay snapshot started", zap.String("snapshot_date", date))
+
+	projects, organizations, err := s.repo.RecordDelaySnapshots(ctx, date)
+	if err != nil {
+		return fmt.Errorf("record delay snapshots: %w", err)
+	}
+
+	s.log.Info("delay snapshot finished",
+		zap.String("snapshot_date", date),
+		zap.Int("projects", projects),
+		zap.Int("organizations", organizations),
+	)
+	return nil
+}
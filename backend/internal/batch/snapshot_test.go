@@ -0,0 +1,30 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+func TestRunDelaySnapshot_RecordsTodayInJST(t *testing.T) {
+	repo := &mockRepository{projectIDMap: map[string]int64{"10": 1}}
+	syncer := newTestSyncer(&mockJiraClient{}, repo)
+
+	if err := syncer.RunDelaySnapshot(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if want := normalizer.Now().Format("2006-01-02"); repo.snapshotDate != want {
+		t.Errorf("expected snapshot date %s, got %s", want, repo.snapshotDate)
+	}
+}
+
+func TestRunDelaySnapshot_Error(t *testing.T) {
+	repo := &mockRepository{snapshotErr: errors.New("db down")}
+	syncer := newTestSyncer(&mockJiraClient{}, repo)
+
+	if err := syncer.RunDelaySnapshot(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -3,6 +3,8 @@ package batch
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
@@ -15,11 +17,21 @@ import (
 
 const defaultWorkerCount = 5
 
+// maxClosedSprintsPerBoard is the number of most recently closed sprints per board
+// whose issues are stored.
+const maxClosedSprintsPerBoard = 3
+
 // JiraClient defines the Jira API operations used by the syncer.
 // This interface allows the syncer to be tested without a real Jira instance.
+// Implementations must stop and return the context error when ctx is cancelled.
 type JiraClient interface {
-	GetAllProjects() ([]jiraclient.Project, error)
-	SearchIssues(opts jiraclient.IssueSearchOptions) ([]jiraclient.Issue, error)
+	GetAllProjectsContext(ctx context.Context) ([]jiraclient.Project, error)
+	SearchIssuesPagesContext(ctx context.Context, opts jiraclient.IssueSearchOptions, fn jiraclient.IssuePageFunc) error
+	GetIssueChangelogContext(ctx context.Context, issueIDOrKey string) ([]jiraclient.ChangelogHistory, error)
+	GetProjectVersionsContext(ctx context.Context, projectIDOrKey string) ([]jiraclient.Version, error)
+	GetProjectBoardsContext(ctx context.Context, projectIDOrKey string) ([]jiraclient.Board, error)
+	GetBoardSprintsContext(ctx context.Context, boardID int64) ([]jiraclient.Sprint, error)
+	GetSprintIssueIDsContext(ctx context.Context, sprintID int64) ([]string, error)
 }
 
 // Syncer orchestrates the Jira → DB synchronization process.
@@ -28,6 +40,9 @@ type Syncer struct {
 	repo        Repository
 	log         *zap.Logger
 	workerCount int
+	batchSize   int
+	changelog   bool
+	sprints     bool
 	recorder    metrics.Recorder
 }
 
@@ -42,10 +57,39 @@ func NewSyncer(jira JiraClient, repo Repository, log *zap.Logger, workerCount in
 		repo:        repo,
 		log:         log,
 		workerCount: workerCount,
+		batchSize:   defaultBatchSize,
 		recorder:    metrics.NoopRecorder{},
 	}
 }
 
+// SetBatchSize sets the maximum number of issues written by a single upsert.
+// Issues are streamed from Jira page by page and flushed in batches of this size,
+// which bounds the memory used by a sync. Values <= 0 restore the default (500),
+// and values above 2500 are capped.
+func (s *Syncer) SetBatchSize(n int) {
+	switch {
+	case n <= 0:
+		n = defaultBatchSize
+	case n > maxBatchSize:
+		n = maxBatchSize
+	}
+	s.batchSize = n
+}
+
+// SetChangelog enables or disables changelog ingestion. When enabled, issues are
+// searched with their changelog expanded and the status/due date transitions of
+// new or updated issues are stored in issue_history. Disabled by default.
+func (s *Syncer) SetChangelog(enabled bool) {
+	s.changelog = enabled
+}
+
+// SetSprints enables or disables sprint ingestion. When enabled, a full sync
+// stores the scrum boards of each project, their sprints and the issues of the
+// active, future and recently closed sprints. Disabled by default.
+func (s *Syncer) SetSprints(enabled bool) {
+	s.sprints = enabled
+}
+
 // SetRecorder sets the metrics recorder used to emit sync metrics.
 // The default recorder is a no-op; call this to enable CloudWatch EMF output.
 func (s *Syncer) SetRecorder(r metrics.Recorder) {
@@ -53,9 +97,11 @@ func (s *Syncer) SetRecorder(r metrics.Recorder) {
 }
 
 // RunFullSync fetches all Jira projects and their issues, then upserts them into the DB.
-// It records execution details in the sync_logs table.
+// It records execution details in the sync_logs table, and checkpoints each project
+// in sync_checkpoints so that an interrupted run can be continued by RunResumeSync.
+// If ctx is cancelled, in-flight work is aborted and the sync log is still
+// finalized with status ABORTED.
 func (s *Syncer) RunFullSync(ctx context.Context) error {
-	start := time.Now()
 	s.log.Info("full sync started")
 
 	logID, err := s.repo.StartSyncLog(ctx, "FULL")
@@ -63,24 +109,80 @@ func (s *Syncer) RunFullSync(ctx context.Context) error {
 		return fmt.Errorf("start sync log: %w", err)
 	}
 
-	projectsSynced, issuesSynced, syncErr := s.runSync(ctx)
+	return s.executeFullSync(ctx, logID, nil)
+}
+
+// RunResumeSync continues the most recent FULL sync that is still RUNNING (e.g. because
+// the task was killed) or was ABORTED, skipping completed projects and resuming
+// in-progress projects from their saved page cursor. If there is no unfinished sync,
+// a new full sync is started.
+func (s *Syncer) RunResumeSync(ctx context.Context) error {
+	logID, err := s.repo.GetUnfinishedSyncLogID(ctx, "FULL")
+	if err != nil {
+		return fmt.Errorf("get unfinished sync log: %w", err)
+	}
+	if logID == 0 {
+		s.log.Info("no unfinished full sync found, starting a new one")
+		return s.RunFullSync(ctx)
+	}
 
-	// sync_logs の更新は sync 失敗でも必ず行う
+	checkpoints, err := s.repo.GetCheckpoints(ctx, logID)
+	if err != nil {
+		return fmt.Errorf("get checkpoints: %w", err)
+	}
+	if err := s.repo.ReopenSyncLog(ctx, logID); err != nil {
+		return fmt.Errorf("reopen sync log: %w", err)
+	}
+
+	s.log.Info("resuming full sync",
+		zap.Int64("sync_log_id", logID),
+		zap.Int("checkpoints", len(checkpoints)),
+	)
+	return s.executeFullSync(ctx, logID, checkpoints)
+}
+
+// executeFullSync runs a full sync for the given sync log and finalizes it.
+// checkpoints may be nil for a fresh run.
+func (s *Syncer) executeFullSync(ctx context.Context, logID int64, checkpoints map[string]SyncCheckpoint) error {
+	start := time.Now()
+
+	projectsSynced, issuesSynced, results, syncErr := s.runSync(ctx, logID, checkpoints)
+
+	projectsFailed := 0
+	for _, r := range results {
+		if r.Status == "FAILURE" {
+			projectsFailed++
+		}
+	}
+
+	// sync_logs の更新は sync 失敗・中断でも必ず行う
 	status := "SUCCESS"
 	errMsg := ""
-	if syncErr != nil {
+	switch {
+	case ctx.Err() != nil:
+		// 中断時はプロジェクトの失敗もキャンセルによるものなので ABORTED として記録する
+		status = "ABORTED"
+		syncErr = fmt.Errorf("full sync aborted: %w", ctx.Err())
+		errMsg = syncErr.Error()
+	case syncErr != nil:
 		status = "FAILURE"
 		errMsg = syncErr.Error()
+	case projectsFailed > 0:
+		// 一部プロジェクトの失敗は PARTIAL として記録し、どのプロジェクトが古いままかを残す
+		status = "PARTIAL"
+		errMsg = fmt.Sprintf("%d of %d projects failed to sync", projectsFailed, len(results))
 	}
 
-	if finishErr := s.repo.FinishSyncLog(ctx, logID, status, projectsSynced, issuesSynced, errMsg); finishErr != nil {
+	if finishErr := s.repo.FinishSyncLog(context.WithoutCancel(ctx), logID, status, projectsSynced, issuesSynced, errMsg); finishErr != nil {
 		s.log.Error("failed to finish sync log", zap.Error(finishErr))
 	}
 
 	duration := time.Since(start)
 	s.log.Info("full sync finished",
+		zap.Int64("sync_log_id", logID),
 		zap.String("status", status),
 		zap.Int("projects_synced", projectsSynced),
+		zap.Int("projects_failed", projectsFailed),
 		zap.Int("issues_synced", issuesSynced),
 		zap.Duration("duration", duration),
 	)
@@ -90,6 +192,7 @@ func (s *Syncer) RunFullSync(ctx context.Context) error {
 		Success:        syncErr == nil,
 		Duration:       duration,
 		ProjectsSynced: projectsSynced,
+		ProjectsFailed: projectsFailed,
 		IssuesSynced:   issuesSynced,
 	})
 
@@ -107,17 +210,22 @@ func (s *Syncer) RunDeltaSync(ctx context.Context) error {
 		return fmt.Errorf("start sync log: %w", err)
 	}
 
-	issuesSynced, syncErr := s.runDeltaSync(ctx)
+	issuesSynced, syncErr := s.runDeltaSync(ctx, logID)
 
-	// sync_logs の更新は sync 失敗でも必ず行う
+	// sync_logs の更新は sync 失敗・中断でも必ず行う
 	status := "SUCCESS"
 	errMsg := ""
-	if syncErr != nil {
+	switch {
+	case ctx.Err() != nil:
+		status = "ABORTED"
+		syncErr = fmt.Errorf("delta sync aborted: %w", ctx.Err())
+		errMsg = syncErr.Error()
+	case syncErr != nil:
 		status = "FAILURE"
 		errMsg = syncErr.Error()
 	}
 
-	if finishErr := s.repo.FinishSyncLog(ctx, logID, status, 0, issuesSynced, errMsg); finishErr != nil {
+	if finishErr := s.repo.FinishSyncLog(context.WithoutCancel(ctx), logID, status, 0, issuesSynced, errMsg); finishErr != nil {
 		s.log.Error("failed to finish sync log", zap.Error(finishErr))
 	}
 
@@ -138,8 +246,9 @@ func (s *Syncer) RunDeltaSync(ctx context.Context) error {
 	return syncErr
 }
 
-// runDeltaSync fetches issues updated since the last successful DELTA sync and upserts them.
-func (s *Syncer) runDeltaSync(ctx context.Context) (issuesSynced int, err error) {
+// runDeltaSync streams issues updated since the last successful DELTA sync and
+// upserts them in batches.
+func (s *Syncer) runDeltaSync(ctx context.Context, logID int64) (issuesSynced int, err error) {
 	// 1. 前回成功した DELTA sync の実行時刻を取得
 	lastSync, err := s.repo.GetLastSuccessfulSyncTime(ctx, "DELTA")
 	if err != nil {
@@ -155,51 +264,92 @@ func (s *Syncer) runDeltaSync(ctx context.Context) (issuesSynced int, err error)
 		s.log.Info("no previous delta sync found, using 1-hour fallback")
 	}
 
-	// 2. JQL でプロジェクト横断の差分チケットを一括取得
+	// 2. プロジェクト横断で差分チケットを取得する JQL を組み立てる
 	jql := fmt.Sprintf(`updated >= "%s" ORDER BY updated ASC`, since.Format("2006/01/02 15:04"))
 	s.log.Info("fetching delta issues",
 		zap.String("since", since.Format(time.RFC3339)),
 		zap.String("jql", jql),
 	)
 
-	issues, err := s.jira.SearchIssues(jiraclient.IssueSearchOptions{JQL: jql})
+	// 3. チケットに対応する project_id と遅延判定ポリシー・カスタムフィールドの対応を解決
+	projectIDMap, err := s.repo.GetProjectIDMap(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get project id map: %w", err)
+	}
+	settings, err := loadIssueSettings(ctx, s.repo)
 	if err != nil {
-		return 0, fmt.Errorf("search delta issues: %w", err)
+		return 0, err
 	}
-	s.log.Info("fetched delta issues", zap.Int("count", len(issues)))
 
-	if len(issues) == 0 {
-		return 0, nil
+	// 4. ページ単位で取得し、正規化してバッチごとに DB に upsert
+	progress := newSyncProgress(s.repo, s.log, logID, 0)
+	batcher := s.newIssueBatcher(projectIDMap, settings)
+	batcher.onFlush = func(ctx context.Context, upserted int, _ *pageCursor) error {
+		progress.add(ctx, upserted)
+		return nil
 	}
 
-	// 3. チケットに対応する project_id を解決
-	projectIDMap, err := s.repo.GetProjectIDMap(ctx)
+	err = s.jira.SearchIssuesPagesContext(ctx, s.searchOptions(jql, jiraclient.PageCursor{}, settings.fields), func(issues []jiraclient.Issue, next jiraclient.PageCursor) error {
+		return batcher.addPage(ctx, issues, next)
+	})
 	if err != nil {
-		return 0, fmt.Errorf("get project id map: %w", err)
+		return batcher.upserted, fmt.Errorf("sync delta issues: %w", err)
 	}
+	if err := batcher.close(ctx); err != nil {
+		return batcher.upserted, err
+	}
+	s.log.Info("synced delta issues", zap.Int("count", batcher.upserted))
+
+	return batcher.upserted, nil
+}
 
-	// 4. チケットを正規化して DB に upsert
-	now := normalizer.Now()
-	dbIssues := make([]normalizer.DBIssue, len(issues))
-	for i, issue := range issues {
-		dbIssues[i] = normalizer.ConvertIssue(issue, now)
+// loadIssueSettings loads the delay policies and the custom field mapping applied
+// to the issues of a sync.
+func loadIssueSettings(ctx context.Context, repo Repository) (issueSettings, error) {
+	policies, err := repo.GetDelayPolicies(ctx)
+	if err != nil {
+		return issueSettings{}, fmt.Errorf("get delay policies: %w", err)
 	}
-	issuesSynced, err = s.repo.UpsertIssues(ctx, dbIssues, projectIDMap)
+	fields, err := repo.GetFieldMapping(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("upsert issues: %w", err)
+		return issueSettings{}, fmt.Errorf("get field mapping: %w", err)
 	}
+	return issueSettings{policies: policies, fields: fields}, nil
+}
+
+// newIssueBatcher returns a batcher that applies the given settings and also
+// records issue history when changelog ingestion is enabled.
+func (s *Syncer) newIssueBatcher(projectIDMap map[string]int64, settings issueSettings) *issueBatcher {
+	b := newIssueBatcher(s.repo, projectIDMap, s.batchSize)
+	b.policies = settings.policies
+	b.fields = settings.fields
+	b.log = s.log
+	if s.changelog {
+		b.fetchChangelog = s.jira.GetIssueChangelogContext
+	}
+	return b
+}
 
-	return issuesSynced, nil
+// searchOptions builds the issue search options for jql starting at cursor,
+// requesting the Jira fields of the custom field mapping as well.
+func (s *Syncer) searchOptions(jql string, cursor jiraclient.PageCursor, fields normalizer.FieldMapping) jiraclient.IssueSearchOptions {
+	opts := jiraclient.IssueSearchOptions{JQL: jql, Cursor: cursor, ExtraFields: fields.JiraFields()}
+	if s.changelog {
+		opts.Expand = []string{jiraclient.ExpandChangelog}
+	}
+	return opts
 }
 
 // runSync is the core sync logic, separated for testability.
-// Returns the number of projects and issues synced, plus any error.
-func (s *Syncer) runSync(ctx context.Context) (projectsSynced, issuesSynced int, err error) {
+// Projects whose checkpoint is marked completed are skipped; the others are fetched
+// and upserted page by page. Returns the number of projects and issues synced
+// (including progress made before a resume), the per-project results, plus any error.
+func (s *Syncer) runSync(ctx context.Context, logID int64, checkpoints map[string]SyncCheckpoint) (projectsSynced, issuesSynced int, results []ProjectSyncResult, err error) {
 	// 1. プロジェクト一覧を取得
 	s.log.Info("fetching projects from Jira")
-	jiraProjects, err := s.jira.GetAllProjects()
+	jiraProjects, err := s.jira.GetAllProjectsContext(ctx)
 	if err != nil {
-		return 0, 0, fmt.Errorf("get projects: %w", err)
+		return 0, 0, nil, fmt.Errorf("get projects: %w", err)
 	}
 	s.log.Info("fetched projects", zap.Int("count", len(jiraProjects)))
 
@@ -210,43 +360,260 @@ func (s *Syncer) runSync(ctx context.Context) (projectsSynced, issuesSynced int,
 	}
 	projectsSynced, err = s.repo.UpsertProjects(ctx, dbProjects)
 	if err != nil {
-		return 0, 0, fmt.Errorf("upsert projects: %w", err)
+		return 0, 0, nil, fmt.Errorf("upsert projects: %w", err)
 	}
 
-	// 3. jira_project_id → DB id のマップを取得
+	// 3. jira_project_id → DB id のマップと、遅延判定ポリシー・カスタムフィールドの対応を取得
 	projectIDMap, err := s.repo.GetProjectIDMap(ctx)
 	if err != nil {
-		return projectsSynced, 0, fmt.Errorf("get project id map: %w", err)
+		return projectsSynced, 0, nil, fmt.Errorf("get project id map: %w", err)
+	}
+	settings, err := loadIssueSettings(ctx, s.repo)
+	if err != nil {
+		return projectsSynced, 0, nil, err
 	}
 
-	// 4. プロジェクトごとのチケット取得を並列化（worker pool）
-	allIssues := s.fetchIssuesParallel(ctx, jiraProjects)
-	s.log.Info("fetched issues", zap.Int("count", len(allIssues)))
+	// 4. 完了済みのプロジェクトを除外し、残りを並列に取り込む（worker pool）
+	var pending []jiraclient.Project
+	resumedIssues := 0
+	for _, p := range jiraProjects {
+		cp, ok := checkpoints[p.ID]
+		if !ok {
+			pending = append(pending, p)
+			continue
+		}
+		resumedIssues += cp.IssuesSynced
+		if cp.Completed {
+			issuesSynced += cp.IssuesSynced
+			continue
+		}
+		pending = append(pending, p)
+	}
+	if skipped := len(jiraProjects) - len(pending); skipped > 0 {
+		s.log.Info("skipping projects completed before resume", zap.Int("count", skipped))
+	}
+
+	progress := newSyncProgress(s.repo, s.log, logID, resumedIssues)
+	results = s.syncProjectsParallel(ctx, logID, pending, checkpoints, projectIDMap, settings, progress)
+	for _, r := range results {
+		issuesSynced += r.IssuesSynced
+	}
+	s.log.Info("synced issues", zap.Int("count", issuesSynced))
+
+	// 5. Jira から消えたプロジェクトを論理削除（チケットはプロジェクト単位で syncProject が処理済み）
+	if err := s.reconcileDeletedProjects(ctx, jiraProjects); err != nil {
+		return projectsSynced, issuesSynced, results, err
+	}
+
+	return projectsSynced, issuesSynced, results, nil
+}
 
-	// 5. チケットを正規化して DB に upsert
-	now := normalizer.Now()
-	dbIssues := make([]normalizer.DBIssue, len(allIssues))
-	for i, issue := range allIssues {
-		dbIssues[i] = normalizer.ConvertIssue(issue, now)
+// syncVersions replaces the stored versions (releases) of a project with those in
+// Jira. Failures are logged as warnings and do not fail the project sync, since
+// its issues can still be synced.
+func (s *Syncer) syncVersions(ctx context.Context, p jiraclient.Project, projectIDMap map[string]int64) {
+	projectID, ok := projectIDMap[p.ID]
+	if !ok {
+		return
 	}
-	issuesSynced, err = s.repo.UpsertIssues(ctx, dbIssues, projectIDMap)
+	versions, err := s.jira.GetProjectVersionsContext(ctx, p.ID)
 	if err != nil {
-		return projectsSynced, 0, fmt.Errorf("upsert issues: %w", err)
+		s.log.Warn("failed to fetch project versions", zap.String("project_key", p.Key), zap.Error(err))
+		return
 	}
 
-	return projectsSynced, issuesSynced, nil
+	dbVersions := make([]normalizer.DBVersion, len(versions))
+	for i, v := range versions {
+		dbVersions[i] = normalizer.ConvertVersion(v)
+	}
+	if _, err := s.repo.ReplaceProjectVersions(ctx, projectID, dbVersions); err != nil {
+		s.log.Warn("failed to store project versions", zap.String("project_key", p.Key), zap.Error(err))
+	}
 }
 
-// fetchIssuesParallel fetches issues for all projects concurrently using a worker pool.
-// Errors from individual projects are logged as warnings; processing continues.
-func (s *Syncer) fetchIssuesParallel(ctx context.Context, projects []jiraclient.Project) []jiraclient.Issue {
-	type result struct {
-		issues []jiraclient.Issue
+// syncSprints stores the scrum boards of a project, their sprints and the issues
+// of each sprint. The issues of a closed sprint no longer change, so they are
+// fetched only for the most recently closed sprints that were not stored as
+// closed yet. Failures are logged as warnings and do not fail the project sync.
+func (s *Syncer) syncSprints(ctx context.Context, p jiraclient.Project, projectIDMap map[string]int64) {
+	projectID, ok := projectIDMap[p.ID]
+	if !ok {
+		return
+	}
+	boards, err := s.jira.GetProjectBoardsContext(ctx, p.ID)
+	if err != nil {
+		s.log.Warn("failed to fetch project boards", zap.String("project_key", p.Key), zap.Error(err))
+		return
+	}
+
+	// カンバンボードにはスプリントがない。他プロジェクトに所属するボードはそのプロジェクトの同期で取り込む
+	var scrumBoards []jiraclient.Board
+	dbBoards := make([]normalizer.DBBoard, 0, len(boards))
+	for _, b := range boards {
+		if b.Type != "scrum" {
+			continue
+		}
+		if b.Location != nil && b.Location.ProjectID != 0 && strconv.FormatInt(b.Location.ProjectID, 10) != p.ID {
+			continue
+		}
+		scrumBoards = append(scrumBoards, b)
+		dbBoards = append(dbBoards, normalizer.ConvertBoard(b))
+	}
+	boardIDMap, err := s.repo.ReplaceProjectBoards(ctx, projectID, dbBoards)
+	if err != nil {
+		s.log.Warn("failed to store project boards", zap.String("project_key", p.Key), zap.Error(err))
+		return
 	}
 
+	for _, b := range scrumBoards {
+		if err := s.syncBoardSprints(ctx, b, boardIDMap[b.ID]); err != nil {
+			s.log.Warn("failed to sync board sprints",
+				zap.String("project_key", p.Key), zap.Int64("board_id", b.ID), zap.Error(err))
+		}
+	}
+}
+
+// syncBoardSprints stores the sprints of a board and the issues of its active,
+// future and last maxClosedSprintsPerBoard closed sprints.
+func (s *Syncer) syncBoardSprints(ctx context.Context, b jiraclient.Board, boardID int64) error {
+	sprints, err := s.jira.GetBoardSprintsContext(ctx, b.ID)
+	if err != nil {
+		return fmt.Errorf("fetch sprints: %w", err)
+	}
+	dbSprints := make([]normalizer.DBSprint, len(sprints))
+	var closed []normalizer.DBSprint
+	for i, sp := range sprints {
+		dbSprints[i] = normalizer.ConvertSprint(sp)
+		if sp.State == "closed" {
+			closed = append(closed, dbSprints[i])
+		}
+	}
+
+	// 取り込み済みの状態を上書きする前に、完了後にチケットを取り込み済みのスプリントを確認する
+	closedIDs := make([]int64, len(closed))
+	for i, sp := range closed {
+		closedIDs[i] = sp.JiraSprintID
+	}
+	synced, err := s.repo.GetIssuesSyncedClosedSprintIDs(ctx, closedIDs)
+	if err != nil {
+		return err
+	}
+	if _, err := s.repo.ReplaceBoardSprints(ctx, boardID, dbSprints); err != nil {
+		return err
+	}
+
+	// 完了日の新しい順に maxClosedSprintsPerBoard 件の完了スプリントのみチケットを取り込む
+	sort.SliceStable(closed, func(i, j int) bool {
+		return sprintCompletedAfter(closed[i], closed[j])
+	})
+	if len(closed) > maxClosedSprintsPerBoard {
+		closed = closed[:maxClosedSprintsPerBoard]
+	}
+	var targets []int64
+	for _, sp := range dbSprints {
+		if sp.State != "closed" {
+			targets = append(targets, sp.JiraSprintID)
+		}
+	}
+	for _, sp := range closed {
+		if !synced[sp.JiraSprintID] {
+			targets = append(targets, sp.JiraSprintID)
+		}
+	}
+
+	for _, id := range targets {
+		issueIDs, err := s.jira.GetSprintIssueIDsContext(ctx, id)
+		if err != nil {
+			return fmt.Errorf("fetch issues of sprint %d: %w", id, err)
+		}
+		if _, err := s.repo.ReplaceSprintIssues(ctx, id, issueIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sprintCompletedAfter reports whether closed sprint a was completed after b.
+// Sprints without a complete date fall back to their end date.
+func sprintCompletedAfter(a, b normalizer.DBSprint) bool {
+	at, bt := a.CompleteDate, b.CompleteDate
+	if at == nil {
+		at = a.EndDate
+	}
+	if bt == nil {
+		bt = b.EndDate
+	}
+	switch {
+	case at == nil:
+		return false
+	case bt == nil:
+		return true
+	}
+	return at.After(*bt)
+}
+
+// reconcileDeletedProjects soft-deletes projects (and their issues) missing from jiraProjects.
+func (s *Syncer) reconcileDeletedProjects(ctx context.Context, jiraProjects []jiraclient.Project) error {
+	// 0 件は権限不足や API 障害の可能性が高いため、全件削除は行わない
+	if len(jiraProjects) == 0 {
+		s.log.Warn("no projects returned from Jira, skipping project reconciliation")
+		return nil
+	}
+
+	jiraProjectIDs := make([]string, len(jiraProjects))
+	for i, p := range jiraProjects {
+		jiraProjectIDs[i] = p.ID
+	}
+	n, err := s.repo.MarkMissingProjectsDeleted(ctx, jiraProjectIDs)
+	if err != nil {
+		return fmt.Errorf("mark missing projects deleted: %w", err)
+	}
+	if n > 0 {
+		s.log.Info("soft-deleted projects missing from Jira", zap.Int("count", n))
+	}
+	return nil
+}
+
+// reconcileDeletedIssues soft-deletes the stored issues of a project that were
+// not returned by a search started at syncStart. Issues written after syncStart
+// (e.g. created or moved into the project by a webhook while the project was
+// being fetched) are kept.
+func (s *Syncer) reconcileDeletedIssues(ctx context.Context, p jiraclient.Project, projectID int64, issueIDs []string, syncStart time.Time) error {
+	// 保存済みのチケットがあるのに 0 件の場合は権限不足や API 障害の可能性が高いため、全件削除は行わない
+	if len(issueIDs) == 0 {
+		stored, err := s.repo.CountProjectIssues(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("count project issues: %w", err)
+		}
+		if stored > 0 {
+			s.log.Warn("no issues returned from Jira, skipping issue reconciliation",
+				zap.String("project_key", p.Key),
+				zap.Int("stored_issues", stored),
+			)
+		}
+		return nil
+	}
+
+	n, err := s.repo.MarkMissingIssuesDeleted(ctx, projectID, issueIDs, syncStart)
+	if err != nil {
+		return fmt.Errorf("mark missing issues deleted: %w", err)
+	}
+	if n > 0 {
+		s.log.Info("soft-deleted issues missing from Jira",
+			zap.String("project_key", p.Key),
+			zap.Int("count", n),
+		)
+	}
+	return nil
+}
+
+// syncProjectsParallel syncs the issues of all given projects concurrently using a
+// worker pool. Errors from individual projects are logged as warnings and returned in
+// the corresponding ProjectSyncResult; processing of the other projects continues.
+func (s *Syncer) syncProjectsParallel(ctx context.Context, logID int64, projects []jiraclient.Project, checkpoints map[string]SyncCheckpoint, projectIDMap map[string]int64, settings issueSettings, progress *syncProgress) []ProjectSyncResult {
 	// semaphore で同時実行数を制限する
 	sem := make(chan struct{}, s.workerCount)
-	resultCh := make(chan result, len(projects))
+	resultCh := make(chan ProjectSyncResult, len(projects))
 
 	var wg sync.WaitGroup
 	for _, p := range projects {
@@ -257,24 +624,7 @@ func (s *Syncer) fetchIssuesParallel(ctx context.Context, projects []jiraclient.
 			sem <- struct{}{}        // acquire
 			defer func() { <-sem }() // release
 
-			// context がキャンセルされていたらスキップ
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-
-			jql := fmt.Sprintf("project = %s ORDER BY updated ASC", p.Key)
-			issues, err := s.jira.SearchIssues(jiraclient.IssueSearchOptions{JQL: jql})
-			if err != nil {
-				// 1プロジェクトの失敗は警告扱いとし、他プロジェクトの処理を継続する
-				s.log.Warn("failed to fetch issues for project",
-					zap.String("project_key", p.Key),
-					zap.Error(err),
-				)
-				return
-			}
-			resultCh <- result{issues: issues}
+			resultCh <- s.syncProject(ctx, logID, p, checkpoints[p.ID], projectIDMap, settings, progress)
 		}()
 	}
 
@@ -284,9 +634,94 @@ func (s *Syncer) fetchIssuesParallel(ctx context.Context, projects []jiraclient.
 		close(resultCh)
 	}()
 
-	var all []jiraclient.Issue
+	results := make([]ProjectSyncResult, 0, len(projects))
 	for r := range resultCh {
-		all = append(all, r.issues...)
+		results = append(results, r)
+	}
+	return results
+}
+
+// syncProject syncs the versions of one project, then streams its issues page by
+// page starting from the checkpoint cursor, upserts them in batches and saves a
+// checkpoint whenever a page has been fully written. When every page was fetched in
+// this run, issues missing from Jira are soft-deleted. The final result is recorded
+// in sync_project_results.
+func (s *Syncer) syncProject(ctx context.Context, logID int64, p jiraclient.Project, cp SyncCheckpoint, projectIDMap map[string]int64, settings issueSettings, progress *syncProgress) ProjectSyncResult {
+	start := time.Now()
+	cp.JiraProjectID = p.ID
+	cursor := jiraclient.PageCursor{StartAt: cp.NextStartAt, Token: cp.NextPageToken}
+	resumed := !cursor.IsZero()
+
+	base := cp.IssuesSynced // 再開前に upsert 済みの件数
+
+	var issueIDs []string
+	batcher := s.newIssueBatcher(projectIDMap, settings)
+	batcher.onFlush = func(ctx context.Context, upserted int, page *pageCursor) error {
+		progress.add(ctx, upserted)
+		if page == nil {
+			return nil
+		}
+		cp.NextStartAt = page.next.StartAt
+		cp.NextPageToken = page.next.Token
+		cp.IssuesSynced = base + page.end
+		return s.repo.SaveCheckpoint(ctx, logID, cp)
+	}
+
+	err := ctx.Err() // context がキャンセルされていたらスキップ（失敗として記録する）
+	if err == nil {
+		s.syncVersions(ctx, p, projectIDMap)
+		if s.sprints {
+			s.syncSprints(ctx, p, projectIDMap)
+		}
+
+		// startAt ページングでは取得中に更新されたチケットが末尾へ移動して後続がずれ、
+		// 一部を取りこぼして削除判定で誤って論理削除してしまうため、更新で変わらない順序で取得する
+		jql := fmt.Sprintf("project = %s ORDER BY created ASC, key ASC", p.Key)
+		err = s.jira.SearchIssuesPagesContext(ctx, s.searchOptions(jql, cursor, settings.fields), func(issues []jiraclient.Issue, next jiraclient.PageCursor) error {
+			for _, issue := range issues {
+				issueIDs = append(issueIDs, issue.ID)
+			}
+			return batcher.addPage(ctx, issues, next)
+		})
+	}
+	if err == nil {
+		err = batcher.close(ctx)
 	}
-	return all
+	cp.IssuesSynced = base + batcher.upserted
+
+	result := ProjectSyncResult{
+		JiraProjectID: p.ID,
+		ProjectKey:    p.Key,
+		Status:        "SUCCESS",
+		IssuesSynced:  cp.IssuesSynced,
+		Duration:      time.Since(start),
+	}
+	if err != nil {
+		// 1プロジェクトの失敗は警告扱いとし、他プロジェクトの処理を継続する
+		s.log.Warn("failed to sync issues for project",
+			zap.String("project_key", p.Key),
+			zap.Error(err),
+		)
+		result.Status = "FAILURE"
+		result.ErrorMessage = err.Error()
+	} else {
+		// 途中のページから再開した場合は全チケット ID が揃わないため削除判定を行わない
+		if projectID, ok := projectIDMap[p.ID]; ok && !resumed {
+			if err := s.reconcileDeletedIssues(ctx, p, projectID, issueIDs, start); err != nil {
+				s.log.Warn("failed to reconcile deleted issues", zap.String("project_key", p.Key), zap.Error(err))
+			}
+		}
+
+		// 取り込みは完了しているため、直後に中断されても完了を記録する
+		cp.Completed = true
+		if err := s.repo.SaveCheckpoint(context.WithoutCancel(ctx), logID, cp); err != nil {
+			s.log.Warn("failed to save checkpoint", zap.String("project_key", p.Key), zap.Error(err))
+		}
+	}
+
+	if err := s.repo.RecordProjectResults(context.WithoutCancel(ctx), logID, []ProjectSyncResult{result}); err != nil {
+		s.log.Error("failed to record project sync result", zap.String("project_key", p.Key), zap.Error(err))
+	}
+
+	return result
 }
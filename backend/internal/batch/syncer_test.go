@@ -2,7 +2,13 @@ package batch
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -22,30 +28,160 @@ type mockJiraClient struct {
 	projectsErr error
 	issues      []jiraclient.Issue
 	issuesErr   error
-	// searchCallCount は SearchIssues が呼ばれた回数
+	// searchCallCount は SearchIssuesPages が呼ばれた回数
 	searchCallCount int64
+
+	mu sync.Mutex
+	// onSearch は検索のたびに呼ばれる（キャンセルのテスト用）
+	onSearch func()
+
+	// cursors は SearchIssuesPages に渡された JQL ごとの開始カーソル
+	cursors map[string]jiraclient.PageCursor
+	// expands は SearchIssuesPages に渡された expand
+	expands []string
+	// extraFields は SearchIssuesPages に渡された追加フィールド
+	extraFields []string
+
+	// changelogs は GetIssueChangelog が返す issue ID ごとの履歴
+	changelogs     map[string][]jiraclient.ChangelogHistory
+	changelogErr   error
+	changelogCalls []string
+
+	// versions は GetProjectVersions が返す jira_project_id ごとのバージョン
+	versions    map[string][]jiraclient.Version
+	versionsErr error
+
+	// boards / sprints / sprintIssues はアジャイル API が返すボード・スプリント・スプリントのチケット
+	boards            map[string][]jiraclient.Board
+	boardsErr         error
+	sprints           map[int64][]jiraclient.Sprint
+	sprintIssues      map[int64][]string
+	sprintIssuesCalls []int64
 }
 
-func (m *mockJiraClient) GetAllProjects() ([]jiraclient.Project, error) {
+func (m *mockJiraClient) GetAllProjectsContext(_ context.Context) ([]jiraclient.Project, error) {
 	return m.projects, m.projectsErr
 }
 
-func (m *mockJiraClient) SearchIssues(_ jiraclient.IssueSearchOptions) ([]jiraclient.Issue, error) {
+func (m *mockJiraClient) SearchIssuesPagesContext(ctx context.Context, opts jiraclient.IssueSearchOptions, fn jiraclient.IssuePageFunc) error {
 	atomic.AddInt64(&m.searchCallCount, 1)
-	return m.issues, m.issuesErr
+	if m.onSearch != nil {
+		m.onSearch()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	if m.cursors == nil {
+		m.cursors = make(map[string]jiraclient.PageCursor)
+	}
+	m.cursors[opts.JQL] = opts.Cursor
+	m.expands = opts.Expand
+	m.extraFields = opts.ExtraFields
+	m.mu.Unlock()
+
+	if m.issuesErr != nil {
+		return m.issuesErr
+	}
+	if len(m.issues) == 0 {
+		return nil
+	}
+	return fn(m.issues, jiraclient.PageCursor{StartAt: opts.Cursor.StartAt + len(m.issues)})
+}
+
+func (m *mockJiraClient) GetIssueChangelogContext(_ context.Context, issueIDOrKey string) ([]jiraclient.ChangelogHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.changelogCalls = append(m.changelogCalls, issueIDOrKey)
+	return m.changelogs[issueIDOrKey], m.changelogErr
+}
+
+func (m *mockJiraClient) GetProjectVersionsContext(_ context.Context, projectIDOrKey string) ([]jiraclient.Version, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.versions[projectIDOrKey], m.versionsErr
+}
+
+func (m *mockJiraClient) GetProjectBoardsContext(_ context.Context, projectIDOrKey string) ([]jiraclient.Board, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.boards[projectIDOrKey], m.boardsErr
+}
+
+func (m *mockJiraClient) GetBoardSprintsContext(_ context.Context, boardID int64) ([]jiraclient.Sprint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sprints[boardID], nil
+}
+
+func (m *mockJiraClient) GetSprintIssueIDsContext(_ context.Context, sprintID int64) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sprintIssuesCalls = append(m.sprintIssuesCalls, sprintID)
+	return m.sprintIssues[sprintID], nil
+}
+
+// offsetJiraClient は startAt でページングする Jira（旧検索 API・Data Center）を模す。
+// 検索のたびに JQL の ORDER BY でチケットを並べ直すため、取得中の更新による順序の変化を再現できる。
+// issues は作成順（= キー順）で持つ。
+type offsetJiraClient struct {
+	*mockJiraClient
+	pageSize int
+	// afterPage は各ページを返した後に呼ばれる（取得中のチケット更新を模す）
+	afterPage func()
+	// failErr は startAt が failAt 以上のページで返すエラー（中断を模す）
+	failAt  int
+	failErr error
+}
+
+func (c *offsetJiraClient) SearchIssuesPagesContext(ctx context.Context, opts jiraclient.IssueSearchOptions, fn jiraclient.IssuePageFunc) error {
+	for startAt := opts.Cursor.StartAt; ; {
+		if c.failErr != nil && startAt >= c.failAt {
+			return c.failErr
+		}
+		ordered := append([]jiraclient.Issue(nil), c.issues...)
+		if strings.Contains(opts.JQL, "ORDER BY updated") {
+			sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Fields.Updated < ordered[j].Fields.Updated })
+		}
+		if startAt >= len(ordered) {
+			return nil
+		}
+		end := min(startAt+c.pageSize, len(ordered))
+		if err := fn(ordered[startAt:end], jiraclient.PageCursor{StartAt: end}); err != nil {
+			return err
+		}
+		if c.afterPage != nil {
+			c.afterPage()
+		}
+		startAt = end
+	}
+}
+
+// touch は id のチケットを更新したことにする（更新日時順の末尾へ移動する）
+func (c *offsetJiraClient) touch(id, updated string) {
+	for i := range c.issues {
+		if c.issues[i].ID == id {
+			c.issues[i].Fields.Updated = updated
+		}
+	}
 }
 
 // ----------------------------------------------------------------
 // Mock Repository
 // ----------------------------------------------------------------
 
+// mockRepository はプロジェクト単位の並列処理から呼ばれるため mu で保護する
 type mockRepository struct {
+	mu sync.Mutex
+
 	upsertProjectsCount int
 	upsertIssuesCount   int
 	projectIDMap        map[string]int64
 	syncLogID           int64
 	finishedStatus      string
 	finishedErrMsg      string
+	finishedIssues      int
+	finishCtxErr        error
 
 	upsertProjectsErr error
 	upsertIssuesErr   error
@@ -54,32 +190,338 @@ type mockRepository struct {
 	finishLogErr      error
 	lastSyncTime      *time.Time
 	lastSyncTimeErr   error
+
+	// 論理削除の呼び出し記録
+	keptProjectIDs     []string
+	markProjectsCalled bool
+	keptIssueIDs       map[int64][]string
+	reconcileStart     time.Time
+	markProjectsErr    error
+	// storedIssues は CountProjectIssues が返す project_id ごとの保存済みチケット数
+	storedIssues map[int64]int
+
+	projectResults []ProjectSyncResult
+
+	// チェックポイント
+	startLogCalled bool
+	reopened       bool
+	unfinishedLog  int64
+	checkpoints    map[string]SyncCheckpoint
+	savedCPs       map[string]SyncCheckpoint
+
+	upsertBatches   []int
+	upsertedIssues  []normalizer.DBIssue
+	progressUpdates []int
+
+	// staleIssues に含まれるチケットは保存済みの方が新しいものとして upsert しない
+	staleIssues map[string]bool
+	// 個別に論理削除したプロジェクト・チケット
+	deletedProjects []string
+	deletedIssues   map[string]time.Time
+
+	// リンクを置き換えたチケット
+	linkedIssues    []string
+	replaceLinksErr error
+
+	// プロジェクトごとに置き換えたバージョン
+	projectVersions map[int64][]normalizer.DBVersion
+	versionsErr     error
+
+	// ボード・スプリント（ボードの DB id は jira_board_id + 1000 とする）
+	projectBoards       map[int64][]normalizer.DBBoard
+	boardSprints        map[int64][]normalizer.DBSprint
+	syncedClosedSprints map[int64]bool
+	sprintIssues        map[int64][]string
+
+	// 遅延判定ポリシー（jira_project_id ごと）
+	delayPolicies    map[string]normalizer.DelayPolicy
+	delayPoliciesErr error
+
+	// カスタムフィールドの対応
+	fieldMapping    normalizer.FieldMapping
+	fieldMappingErr error
+
+	// 遅延スナップショット
+	snapshotDate string
+	snapshotErr  error
+
+	// 遅延ステータスの再計算
+	startedSyncType string
+	openIssues      []OpenIssue
+	openIssuesErr   error
+	delayUpdates    []DelayStatusUpdate
+
+	// 履歴: unchangedIssues に含まれないチケットは新規・更新扱い
+	unchangedIssues map[string]bool
+	history         []normalizer.DBIssueHistory
+	// insertHistoryErr は UpsertIssuesWithHistory を（チケットも保存せずに）失敗させる
+	insertHistoryErr error
 }
 
 func (m *mockRepository) UpsertProjects(_ context.Context, projects []normalizer.DBProject) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.upsertProjectsCount = len(projects)
 	return len(projects), m.upsertProjectsErr
 }
 
 func (m *mockRepository) UpsertIssues(_ context.Context, issues []normalizer.DBIssue, _ map[string]int64) (int, error) {
-	m.upsertIssuesCount = len(issues)
-	return len(issues), m.upsertIssuesErr
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var fresh []normalizer.DBIssue
+	for _, issue := range issues {
+		if !m.staleIssues[issue.JiraIssueID] {
+			fresh = append(fresh, issue)
+		}
+	}
+	m.upsertIssuesCount = len(fresh)
+	m.upsertBatches = append(m.upsertBatches, len(fresh))
+	m.upsertedIssues = append(m.upsertedIssues, fresh...)
+	return len(fresh), m.upsertIssuesErr
+}
+
+func (m *mockRepository) ReplaceIssueLinks(_ context.Context, issues []normalizer.DBIssue) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, issue := range issues {
+		n += len(issue.Links)
+		m.linkedIssues = append(m.linkedIssues, issue.JiraIssueID)
+	}
+	return n, m.replaceLinksErr
+}
+
+func (m *mockRepository) ReplaceProjectVersions(_ context.Context, projectID int64, versions []normalizer.DBVersion) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.versionsErr != nil {
+		return 0, m.versionsErr
+	}
+	if m.projectVersions == nil {
+		m.projectVersions = make(map[int64][]normalizer.DBVersion)
+	}
+	m.projectVersions[projectID] = versions
+	return len(versions), nil
+}
+
+func (m *mockRepository) ReplaceProjectBoards(_ context.Context, projectID int64, boards []normalizer.DBBoard) (map[int64]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.projectBoards == nil {
+		m.projectBoards = make(map[int64][]normalizer.DBBoard)
+	}
+	m.projectBoards[projectID] = boards
+	ids := make(map[int64]int64, len(boards))
+	for _, b := range boards {
+		ids[b.JiraBoardID] = b.JiraBoardID + 1000
+	}
+	return ids, nil
+}
+
+func (m *mockRepository) ReplaceBoardSprints(_ context.Context, boardID int64, sprints []normalizer.DBSprint) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.boardSprints == nil {
+		m.boardSprints = make(map[int64][]normalizer.DBSprint)
+	}
+	m.boardSprints[boardID] = sprints
+	return len(sprints), nil
+}
+
+func (m *mockRepository) GetIssuesSyncedClosedSprintIDs(_ context.Context, jiraSprintIDs []int64) (map[int64]bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	synced := make(map[int64]bool)
+	for _, id := range jiraSprintIDs {
+		if m.syncedClosedSprints[id] {
+			synced[id] = true
+		}
+	}
+	return synced, nil
+}
+
+func (m *mockRepository) ReplaceSprintIssues(_ context.Context, jiraSprintID int64, jiraIssueIDs []string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sprintIssues == nil {
+		m.sprintIssues = make(map[int64][]string)
+	}
+	m.sprintIssues[jiraSprintID] = jiraIssueIDs
+	return len(jiraIssueIDs), nil
 }
 
 func (m *mockRepository) GetProjectIDMap(_ context.Context) (map[string]int64, error) {
 	return m.projectIDMap, m.getProjectMapErr
 }
 
-func (m *mockRepository) StartSyncLog(_ context.Context, _ string) (int64, error) {
+func (m *mockRepository) GetDelayPolicies(_ context.Context) (map[string]normalizer.DelayPolicy, error) {
+	return m.delayPolicies, m.delayPoliciesErr
+}
+
+func (m *mockRepository) GetProjectDelayPolicy(_ context.Context, jiraProjectID string) (int64, normalizer.DelayPolicy, bool, error) {
+	if m.getProjectMapErr != nil {
+		return 0, normalizer.DelayPolicy{}, false, m.getProjectMapErr
+	}
+	if m.delayPoliciesErr != nil {
+		return 0, normalizer.DelayPolicy{}, false, m.delayPoliciesErr
+	}
+	id, ok := m.projectIDMap[jiraProjectID]
+	if !ok {
+		return 0, normalizer.DelayPolicy{}, false, nil
+	}
+	policy, ok := m.delayPolicies[jiraProjectID]
+	if !ok {
+		policy = normalizer.DefaultDelayPolicy
+	}
+	return id, policy, true, nil
+}
+
+func (m *mockRepository) GetFieldMapping(_ context.Context) (normalizer.FieldMapping, error) {
+	return m.fieldMapping, m.fieldMappingErr
+}
+
+func (m *mockRepository) MarkMissingProjectsDeleted(_ context.Context, jiraProjectIDs []string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.markProjectsCalled = true
+	m.keptProjectIDs = jiraProjectIDs
+	return 0, m.markProjectsErr
+}
+
+func (m *mockRepository) MarkMissingIssuesDeleted(_ context.Context, projectID int64, jiraIssueIDs []string, syncStart time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.keptIssueIDs == nil {
+		m.keptIssueIDs = make(map[int64][]string)
+	}
+	m.keptIssueIDs[projectID] = jiraIssueIDs
+	m.reconcileStart = syncStart
+	return 0, nil
+}
+
+func (m *mockRepository) CountProjectIssues(_ context.Context, projectID int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.storedIssues[projectID], nil
+}
+
+func (m *mockRepository) MarkProjectDeleted(_ context.Context, jiraProjectID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deletedProjects = append(m.deletedProjects, jiraProjectID)
+	return m.projectIDMap[jiraProjectID] != 0, nil
+}
+
+func (m *mockRepository) MarkIssueDeleted(_ context.Context, jiraIssueID string, deletedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.staleIssues[jiraIssueID] {
+		return false, nil
+	}
+	if m.deletedIssues == nil {
+		m.deletedIssues = make(map[string]time.Time)
+	}
+	m.deletedIssues[jiraIssueID] = deletedAt
+	return true, nil
+}
+
+func (m *mockRepository) FindChangedIssues(_ context.Context, issues []normalizer.DBIssue) (map[string]bool, error) {
+	changed := make(map[string]bool)
+	for _, issue := range issues {
+		if !m.unchangedIssues[issue.JiraIssueID] {
+			changed[issue.JiraIssueID] = true
+		}
+	}
+	return changed, nil
+}
+
+func (m *mockRepository) InsertIssueHistory(_ context.Context, history []normalizer.DBIssueHistory) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.history = append(m.history, history...)
+	return len(history), nil
+}
+
+func (m *mockRepository) UpsertIssuesWithHistory(ctx context.Context, issues []normalizer.DBIssue, projectIDMap map[string]int64, history []normalizer.DBIssueHistory) (int, error) {
+	m.mu.Lock()
+	err := m.insertHistoryErr
+	m.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	n, err := m.UpsertIssues(ctx, issues, projectIDMap)
+	if err != nil {
+		return 0, err
+	}
+	m.InsertIssueHistory(ctx, history)
+	return n, nil
+}
+
+func (m *mockRepository) RecordProjectResults(_ context.Context, _ int64, results []ProjectSyncResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.projectResults = append(m.projectResults, results...)
+	return nil
+}
+
+func (m *mockRepository) SaveCheckpoint(_ context.Context, _ int64, cp SyncCheckpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.savedCPs == nil {
+		m.savedCPs = make(map[string]SyncCheckpoint)
+	}
+	m.savedCPs[cp.JiraProjectID] = cp
+	return nil
+}
+
+func (m *mockRepository) GetCheckpoints(_ context.Context, _ int64) (map[string]SyncCheckpoint, error) {
+	return m.checkpoints, nil
+}
+
+func (m *mockRepository) GetUnfinishedSyncLogID(_ context.Context, _ string) (int64, error) {
+	return m.unfinishedLog, nil
+}
+
+func (m *mockRepository) ReopenSyncLog(_ context.Context, _ int64) error {
+	m.reopened = true
+	return nil
+}
+
+func (m *mockRepository) UpdateSyncProgress(_ context.Context, _ int64, issuesSynced int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.progressUpdates = append(m.progressUpdates, issuesSynced)
+	return nil
+}
+
+func (m *mockRepository) StartSyncLog(_ context.Context, syncType string) (int64, error) {
+	m.startLogCalled = true
+	m.startedSyncType = syncType
 	return m.syncLogID, m.startLogErr
 }
 
-func (m *mockRepository) FinishSyncLog(_ context.Context, _ int64, status string, _, _ int, errMsg string) error {
+func (m *mockRepository) FinishSyncLog(ctx context.Context, _ int64, status string, _, issuesSynced int, errMsg string) error {
+	m.finishCtxErr = ctx.Err()
 	m.finishedStatus = status
 	m.finishedErrMsg = errMsg
+	m.finishedIssues = issuesSynced
 	return m.finishLogErr
 }
 
+func (m *mockRepository) RecordDelaySnapshots(_ context.Context, date string) (int, int, error) {
+	m.snapshotDate = date
+	return len(m.projectIDMap), 0, m.snapshotErr
+}
+
+func (m *mockRepository) ListOpenIssues(_ context.Context) ([]OpenIssue, error) {
+	return m.openIssues, m.openIssuesErr
+}
+
+func (m *mockRepository) UpdateDelayStatuses(_ context.Context, updates []DelayStatusUpdate) (int, error) {
+	m.delayUpdates = append(m.delayUpdates, updates...)
+	return len(updates), nil
+}
+
 func (m *mockRepository) GetLastSuccessfulSyncTime(_ context.Context, _ string) (*time.Time, error) {
 	return m.lastSyncTime, m.lastSyncTimeErr
 }
@@ -189,7 +631,7 @@ func TestRunFullSync_UpsertProjectsError(t *testing.T) {
 }
 
 func TestRunFullSync_IssuesFetchErrorContinues(t *testing.T) {
-	// チケット取得エラーは警告扱いで処理継続し SUCCESS になる
+	// チケット取得エラーは警告扱いで処理継続し PARTIAL になる
 	jira := &mockJiraClient{
 		projects:  []jiraclient.Project{makeProject("1", "P"), makeProject("2", "Q")},
 		issuesErr: errors.New("project not found"),
@@ -204,8 +646,40 @@ func TestRunFullSync_IssuesFetchErrorContinues(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error when issue fetch fails per project, got: %v", err)
 	}
-	if repo.finishedStatus != "SUCCESS" {
-		t.Errorf("expected SUCCESS despite issue fetch errors, got %s", repo.finishedStatus)
+	if repo.finishedStatus != "PARTIAL" {
+		t.Errorf("expected PARTIAL when issue fetch fails, got %s", repo.finishedStatus)
+	}
+	if repo.finishedErrMsg == "" {
+		t.Error("expected non-empty error message for partial sync")
+	}
+	if len(repo.projectResults) != 2 {
+		t.Fatalf("expected 2 project results, got %d", len(repo.projectResults))
+	}
+	for _, r := range repo.projectResults {
+		if r.Status != "FAILURE" || r.ErrorMessage == "" {
+			t.Errorf("expected FAILURE with error message, got %+v", r)
+		}
+	}
+}
+
+func TestRunFullSync_RecordsProjectResults(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10"), makeIssue("2", "PROJ-2", "10")},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.projectResults) != 1 {
+		t.Fatalf("expected 1 project result, got %d", len(repo.projectResults))
+	}
+	got := repo.projectResults[0]
+	if got.ProjectKey != "PROJ" || got.Status != "SUCCESS" || got.IssuesSynced != 2 {
+		t.Errorf("unexpected project result: %+v", got)
 	}
 }
 
@@ -249,6 +723,380 @@ func TestRunFullSync_SyncLogAlwaysFinished(t *testing.T) {
 	}
 }
 
+func TestRunFullSync_ReconcilesDeleted(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10"), makeIssue("2", "PROJ-2", "10")},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 7}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.keptProjectIDs) != 1 || repo.keptProjectIDs[0] != "10" {
+		t.Errorf("expected kept project ids [10], got %v", repo.keptProjectIDs)
+	}
+	if got := repo.keptIssueIDs[7]; len(got) != 2 {
+		t.Errorf("expected 2 kept issue ids for project 7, got %v", got)
+	}
+}
+
+func TestRunFullSync_IssueUpdatedDuringSyncIsNotDeleted(t *testing.T) {
+	// 1 ページ目を取得した後に PROJ-1 が更新されても、後続のチケットを取りこぼさない
+	jira := &offsetJiraClient{mockJiraClient: &mockJiraClient{projects: []jiraclient.Project{makeProject("10", "PROJ")}}, pageSize: 1}
+	for i, id := range []string{"1", "2", "3"} {
+		issue := makeIssue(id, "PROJ-"+id, "10")
+		issue.Fields.Updated = fmt.Sprintf("2026-01-0%dT10:00:00.000+0900", i+1)
+		jira.issues = append(jira.issues, issue)
+	}
+	touched := false
+	jira.afterPage = func() {
+		if !touched {
+			touched = true
+			jira.touch("1", "2026-01-10T10:00:00.000+0900")
+		}
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 7}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := repo.keptIssueIDs[7], []string{"1", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected kept issue ids %v, got %v", want, got)
+	}
+}
+
+func TestRunFullSync_ReconcilesIssuesWrittenBeforeSyncStart(t *testing.T) {
+	// 同期中に Webhook で保存されたチケットを削除しないよう、同期開始時刻を渡す
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 7}}
+
+	before := time.Now()
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.reconcileStart.Before(before) || repo.reconcileStart.After(time.Now()) {
+		t.Errorf("expected the sync start time, got %v", repo.reconcileStart)
+	}
+}
+
+func TestRunFullSync_NoIssuesSkipsReconciliationOfStoredIssues(t *testing.T) {
+	// 保存済みのチケットがあるのに 0 件の場合は権限不足や API 障害とみなし、全件削除しない
+	jira := &mockJiraClient{projects: []jiraclient.Project{makeProject("10", "PROJ")}}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 7}, storedIssues: map[int64]int{7: 3}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.keptIssueIDs) != 0 {
+		t.Errorf("expected no issue reconciliation, got %v", repo.keptIssueIDs)
+	}
+}
+
+func TestRunFullSync_FetchErrorSkipsIssueReconciliation(t *testing.T) {
+	// 取得に失敗したプロジェクトのチケットは削除判定しない
+	jira := &mockJiraClient{
+		projects:  []jiraclient.Project{makeProject("1", "P")},
+		issuesErr: errors.New("project not found"),
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"1": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.keptIssueIDs) != 0 {
+		t.Errorf("expected no issue reconciliation, got %v", repo.keptIssueIDs)
+	}
+}
+
+func TestRunFullSync_NoProjectsSkipsReconciliation(t *testing.T) {
+	jira := &mockJiraClient{projects: []jiraclient.Project{}}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.markProjectsCalled {
+		t.Error("project reconciliation must be skipped when Jira returns no projects")
+	}
+}
+
+func TestRunFullSync_ReconcileError(t *testing.T) {
+	jira := &mockJiraClient{projects: []jiraclient.Project{makeProject("1", "P")}}
+	repo := &mockRepository{
+		syncLogID:       1,
+		projectIDMap:    map[string]int64{"1": 1},
+		markProjectsErr: errors.New("db write error"),
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err == nil {
+		t.Fatal("expected error from reconciliation, got nil")
+	}
+	if repo.finishedStatus != "FAILURE" {
+		t.Errorf("expected FAILURE status, got %s", repo.finishedStatus)
+	}
+}
+
+func TestRunFullSync_SavesCheckpoints(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10"), makeIssue("2", "PROJ-2", "10")},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cp, ok := repo.savedCPs["10"]
+	if !ok {
+		t.Fatal("expected checkpoint for project 10")
+	}
+	if !cp.Completed || cp.NextStartAt != 2 || cp.IssuesSynced != 2 {
+		t.Errorf("unexpected checkpoint: %+v", cp)
+	}
+}
+
+func TestRunFullSync_UpsertsInBatchesAndReportsProgress(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   makeIssuePage("10", 0, 5),
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	syncer.SetBatchSize(2)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []int{2, 2, 1}; !reflect.DeepEqual(repo.upsertBatches, want) {
+		t.Errorf("expected batches %v, got %v", want, repo.upsertBatches)
+	}
+	if want := []int{2, 4, 5}; !reflect.DeepEqual(repo.progressUpdates, want) {
+		t.Errorf("expected progress %v, got %v", want, repo.progressUpdates)
+	}
+	if repo.finishedIssues != 5 {
+		t.Errorf("expected 5 issues synced, got %d", repo.finishedIssues)
+	}
+}
+
+func TestSetBatchSize_Bounds(t *testing.T) {
+	syncer := newTestSyncer(&mockJiraClient{}, &mockRepository{})
+
+	syncer.SetBatchSize(0)
+	if syncer.batchSize != defaultBatchSize {
+		t.Errorf("expected default %d, got %d", defaultBatchSize, syncer.batchSize)
+	}
+	syncer.SetBatchSize(100000)
+	if syncer.batchSize != maxBatchSize {
+		t.Errorf("expected cap %d, got %d", maxBatchSize, syncer.batchSize)
+	}
+}
+
+// ----------------------------------------------------------------
+// Resume Sync Tests
+// ----------------------------------------------------------------
+
+func TestRunResumeSync_NoUnfinishedStartsNewSync(t *testing.T) {
+	jira := &mockJiraClient{projects: []jiraclient.Project{makeProject("10", "PROJ")}}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunResumeSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !repo.startLogCalled {
+		t.Error("expected a new sync log to be started")
+	}
+	if repo.finishedStatus != "SUCCESS" {
+		t.Errorf("expected SUCCESS, got %s", repo.finishedStatus)
+	}
+}
+
+func TestRunResumeSync_SkipsCompletedAndResumesCursor(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("1", "DONE"), makeProject("2", "HALF")},
+		issues:   []jiraclient.Issue{makeIssue("i9", "HALF-9", "2")},
+	}
+	repo := &mockRepository{
+		unfinishedLog: 77,
+		projectIDMap:  map[string]int64{"1": 1, "2": 2},
+		checkpoints: map[string]SyncCheckpoint{
+			"1": {JiraProjectID: "1", NextStartAt: 300, IssuesSynced: 300, Completed: true},
+			"2": {JiraProjectID: "2", NextStartAt: 100, IssuesSynced: 100},
+		},
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunResumeSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if repo.startLogCalled {
+		t.Error("resume must not start a new sync log")
+	}
+	if !repo.reopened {
+		t.Error("expected the unfinished sync log to be reopened")
+	}
+	if got := atomic.LoadInt64(&jira.searchCallCount); got != 1 {
+		t.Errorf("expected only the unfinished project to be fetched, got %d calls", got)
+	}
+	if got := jira.cursors["project = HALF ORDER BY created ASC, key ASC"]; got.StartAt != 100 {
+		t.Errorf("expected resume from startAt=100, got %+v", got)
+	}
+	// 途中から再開したプロジェクトは削除判定を行わない
+	if _, ok := repo.keptIssueIDs[2]; ok {
+		t.Error("issue reconciliation must be skipped for a project resumed mid-way")
+	}
+	// 300 (完了済み) + 100 (前回分) + 1 (今回分)
+	if repo.finishedIssues != 401 {
+		t.Errorf("expected 401 issues in sync log, got %d", repo.finishedIssues)
+	}
+	if repo.finishedStatus != "SUCCESS" {
+		t.Errorf("expected SUCCESS, got %s", repo.finishedStatus)
+	}
+}
+
+func TestRunResumeSync_IssueUpdatedBeforeResumeIsNotSkipped(t *testing.T) {
+	jira := &offsetJiraClient{mockJiraClient: &mockJiraClient{projects: []jiraclient.Project{makeProject("2", "HALF")}}, pageSize: 1}
+	for i, id := range []string{"1", "2", "3"} {
+		issue := makeIssue(id, "HALF-"+id, "2")
+		issue.Fields.Updated = fmt.Sprintf("2026-01-0%dT10:00:00.000+0900", i+1)
+		jira.issues = append(jira.issues, issue)
+	}
+
+	// 1 回目: 1 ページ目を取り込んだ後に中断される
+	jira.failAt, jira.failErr = 1, errors.New("connection reset")
+	first := &mockRepository{syncLogID: 77, projectIDMap: map[string]int64{"2": 2}}
+	syncer := newTestSyncer(jira, first)
+	syncer.SetBatchSize(1) // ページごとにチェックポイントを保存させる
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cp := first.savedCPs["2"]
+	if cp.Completed || cp.NextStartAt != 1 {
+		t.Fatalf("expected an unfinished checkpoint at startAt=1, got %+v", cp)
+	}
+
+	// 再開までの間に取り込み済みの HALF-1 が更新され、更新日時順では末尾へ移動する
+	jira.touch("1", "2026-01-10T10:00:00.000+0900")
+	jira.failErr = nil
+	second := &mockRepository{unfinishedLog: 77, projectIDMap: map[string]int64{"2": 2}, checkpoints: first.savedCPs}
+	syncer = newTestSyncer(jira, second)
+	syncer.SetBatchSize(1)
+	if err := syncer.RunResumeSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, issue := range append(first.upsertedIssues, second.upsertedIssues...) {
+		got = append(got, issue.JiraIssueID)
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected issues %v to be ingested, got %v", want, got)
+	}
+}
+
+func TestRunResumeSync_ResumesFromPageToken(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("2", "HALF")},
+		issues:   []jiraclient.Issue{makeIssue("i9", "HALF-9", "2")},
+	}
+	repo := &mockRepository{
+		unfinishedLog: 77,
+		projectIDMap:  map[string]int64{"2": 2},
+		checkpoints: map[string]SyncCheckpoint{
+			"2": {JiraProjectID: "2", NextPageToken: "tok-abc", IssuesSynced: 100},
+		},
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunResumeSync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := jira.cursors["project = HALF ORDER BY created ASC, key ASC"]; got.Token != "tok-abc" {
+		t.Errorf("expected resume from token tok-abc, got %+v", got)
+	}
+	if _, ok := repo.keptIssueIDs[2]; ok {
+		t.Error("issue reconciliation must be skipped for a project resumed mid-way")
+	}
+}
+
+// ----------------------------------------------------------------
+// Cancellation Tests
+// ----------------------------------------------------------------
+
+func TestRunFullSync_CancelledRecordsAborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+		onSearch: cancel, // チケット取得中に SIGTERM を受けた想定
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	err := syncer.RunFullSync(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if repo.finishedStatus != "ABORTED" {
+		t.Errorf("expected ABORTED, got %s", repo.finishedStatus)
+	}
+	// キャンセル後も sync_logs の更新はキャンセルされていない context で行う
+	if repo.finishCtxErr != nil {
+		t.Errorf("sync log must be finalized with a live context, got %v", repo.finishCtxErr)
+	}
+	if _, ok := repo.savedCPs["10"]; ok {
+		t.Error("an aborted project must not be checkpointed as completed")
+	}
+	if len(repo.keptIssueIDs) != 0 {
+		t.Error("issue reconciliation must be skipped for an aborted project")
+	}
+}
+
+func TestRunDeltaSync_CancelledRecordsAborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jira := &mockJiraClient{
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+		onSearch: cancel,
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	err := syncer.RunDeltaSync(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if repo.finishedStatus != "ABORTED" {
+		t.Errorf("expected ABORTED, got %s", repo.finishedStatus)
+	}
+	if repo.finishCtxErr != nil {
+		t.Errorf("sync log must be finalized with a live context, got %v", repo.finishCtxErr)
+	}
+}
+
 func TestNewSyncer_DefaultWorkerCount(t *testing.T) {
 	s := NewSyncer(nil, nil, zap.NewNop(), 0)
 	if s.workerCount != defaultWorkerCount {
@@ -256,6 +1104,247 @@ func TestNewSyncer_DefaultWorkerCount(t *testing.T) {
 	}
 }
 
+func TestRunFullSync_ChangelogEnabled(t *testing.T) {
+	issue := makeIssue("1", "PROJ-1", "10")
+	issue.Changelog = &jiraclient.Changelog{Total: 1, Histories: []jiraclient.ChangelogHistory{statusHistory("11", "To Do", "Done")}}
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{issue},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	syncer.SetChangelog(true)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if want := []string{jiraclient.ExpandChangelog}; !reflect.DeepEqual(jira.expands, want) {
+		t.Errorf("expected expand %v, got %v", want, jira.expands)
+	}
+	if len(repo.history) != 1 || repo.history[0].JiraHistoryID != "11" {
+		t.Errorf("expected history 11 to be recorded, got %+v", repo.history)
+	}
+}
+
+func TestRunFullSync_SyncsProjectVersions(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+		versions: map[string][]jiraclient.Version{"10": {{ID: "100", Name: "v1.0", ReleaseDate: "2026-03-31"}}},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	got := repo.projectVersions[1]
+	if len(got) != 1 || got[0].JiraVersionID != "100" || got[0].ReleaseDate == nil || *got[0].ReleaseDate != "2026-03-31" {
+		t.Errorf("unexpected versions: %+v", got)
+	}
+}
+
+func TestRunFullSync_VersionsErrorContinues(t *testing.T) {
+	jira := &mockJiraClient{
+		projects:    []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:      []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+		versionsErr: errors.New("forbidden"),
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if repo.finishedStatus != "SUCCESS" {
+		t.Errorf("expected SUCCESS status, got %s", repo.finishedStatus)
+	}
+	if repo.upsertIssuesCount != 1 {
+		t.Errorf("expected issues to be synced, got %d", repo.upsertIssuesCount)
+	}
+	if repo.projectVersions != nil {
+		t.Errorf("expected no versions to be stored, got %+v", repo.projectVersions)
+	}
+}
+
+func TestRunFullSync_SyncsSprints(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+		boards: map[string][]jiraclient.Board{"10": {
+			{ID: 1, Name: "PROJ board", Type: "scrum", Location: &jiraclient.BoardLocation{ProjectID: 10}},
+			{ID: 2, Name: "PROJ kanban", Type: "kanban"},
+			{ID: 3, Name: "OTHER board", Type: "scrum", Location: &jiraclient.BoardLocation{ProjectID: 20}},
+		}},
+		sprints: map[int64][]jiraclient.Sprint{1: {
+			{ID: 11, State: "closed", CompleteDate: "2026-01-05T09:00:00.000+09:00"},
+			{ID: 12, State: "closed", CompleteDate: "2026-01-19T09:00:00.000+09:00"},
+			{ID: 13, State: "closed", CompleteDate: "2026-02-02T09:00:00.000+09:00"},
+			{ID: 14, State: "closed", CompleteDate: "2026-02-16T09:00:00.000+09:00"},
+			{ID: 15, State: "active", StartDate: "2026-02-16T09:00:00.000+09:00", EndDate: "2026-03-02T09:00:00.000+09:00"},
+			{ID: 16, State: "future"},
+		}},
+		sprintIssues: map[int64][]string{15: {"1", "2"}},
+	}
+	// スプリント 14 は完了後にチケットを取り込み済み
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}, syncedClosedSprints: map[int64]bool{14: true}}
+
+	syncer := newTestSyncer(jira, repo)
+	syncer.SetSprints(true)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if boards := repo.projectBoards[1]; len(boards) != 1 || boards[0].JiraBoardID != 1 {
+		t.Errorf("expected only the scrum board of the project, got %+v", boards)
+	}
+	if got := len(repo.boardSprints[1001]); got != 6 {
+		t.Errorf("expected 6 sprints to be stored, got %d", got)
+	}
+	// アクティブ・未来のスプリントと、直近 3 件のうち未取り込みの完了スプリント（13, 12）
+	sort.Slice(jira.sprintIssuesCalls, func(i, j int) bool { return jira.sprintIssuesCalls[i] < jira.sprintIssuesCalls[j] })
+	if want := []int64{12, 13, 15, 16}; !reflect.DeepEqual(jira.sprintIssuesCalls, want) {
+		t.Errorf("expected issues of sprints %v to be fetched, got %v", want, jira.sprintIssuesCalls)
+	}
+	if got := repo.sprintIssues[15]; !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("unexpected issues of sprint 15: %v", got)
+	}
+}
+
+func TestRunFullSync_SprintsDisabledByDefault(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		boards:   map[string][]jiraclient.Board{"10": {{ID: 1, Type: "scrum"}}},
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if repo.projectBoards != nil {
+		t.Errorf("expected no boards to be stored, got %+v", repo.projectBoards)
+	}
+}
+
+func TestRunFullSync_BoardsErrorContinues(t *testing.T) {
+	jira := &mockJiraClient{
+		projects:  []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:    []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")},
+		boardsErr: errors.New("jira software not licensed"),
+	}
+	repo := &mockRepository{syncLogID: 1, projectIDMap: map[string]int64{"10": 1}}
+
+	syncer := newTestSyncer(jira, repo)
+	syncer.SetSprints(true)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if repo.finishedStatus != "SUCCESS" || repo.upsertIssuesCount != 1 {
+		t.Errorf("expected issues to be synced, got status %s and %d issues", repo.finishedStatus, repo.upsertIssuesCount)
+	}
+}
+
+func TestRunFullSync_AppliesDelayPolicies(t *testing.T) {
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{makeIssue("1", "PROJ-1", "10"), makeIssue("2", "OTHER-1", "20")},
+	}
+	// 10 は期限未設定を GREEN とするポリシー、20 はポリシーなし（デフォルト）
+	repo := &mockRepository{
+		syncLogID:     1,
+		projectIDMap:  map[string]int64{"10": 1, "20": 2},
+		delayPolicies: map[string]normalizer.DelayPolicy{"10": {YellowDays: 7, NoDueDateStatus: "GREEN"}},
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	got := map[string]string{}
+	for _, issue := range repo.upsertedIssues {
+		got[issue.JiraIssueID] = issue.DelayStatus
+	}
+	if want := map[string]string{"1": "GREEN", "2": "YELLOW"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected delay statuses %v, got %v", want, got)
+	}
+}
+
+func TestRunFullSync_DelayPoliciesError(t *testing.T) {
+	jira := &mockJiraClient{projects: []jiraclient.Project{makeProject("10", "PROJ")}}
+	repo := &mockRepository{
+		syncLogID:        1,
+		projectIDMap:     map[string]int64{"10": 1},
+		delayPoliciesErr: errors.New("db error"),
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if repo.finishedStatus != "FAILURE" {
+		t.Errorf("expected FAILURE status, got %s", repo.finishedStatus)
+	}
+	if atomic.LoadInt64(&jira.searchCallCount) != 0 {
+		t.Error("issues must not be fetched without delay policies")
+	}
+}
+
+func TestRunFullSync_AppliesFieldMapping(t *testing.T) {
+	issue := makeIssue("1", "PROJ-1", "10")
+	issue.Fields.Extra = map[string]json.RawMessage{
+		"customfield_10016": json.RawMessage(`3`),
+		"labels":            json.RawMessage(`["backend"]`),
+	}
+	jira := &mockJiraClient{
+		projects: []jiraclient.Project{makeProject("10", "PROJ")},
+		issues:   []jiraclient.Issue{issue},
+	}
+	repo := &mockRepository{
+		syncLogID:    1,
+		projectIDMap: map[string]int64{"10": 1},
+		fieldMapping: normalizer.FieldMapping{
+			normalizer.ColumnStoryPoints: "customfield_10016",
+			normalizer.ColumnLabels:      "labels",
+		},
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunFullSync(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if want := []string{"customfield_10016", "labels"}; !reflect.DeepEqual(jira.extraFields, want) {
+		t.Errorf("expected extra fields %v, got %v", want, jira.extraFields)
+	}
+	if len(repo.upsertedIssues) != 1 {
+		t.Fatalf("expected 1 upserted issue, got %d", len(repo.upsertedIssues))
+	}
+	got := repo.upsertedIssues[0]
+	if got.StoryPoints == nil || *got.StoryPoints != 3 {
+		t.Errorf("expected story points 3, got %v", got.StoryPoints)
+	}
+	if !reflect.DeepEqual(got.Labels, []string{"backend"}) {
+		t.Errorf("expected labels [backend], got %v", got.Labels)
+	}
+}
+
+func TestRunDeltaSync_FieldMappingError(t *testing.T) {
+	jira := &mockJiraClient{issues: []jiraclient.Issue{makeIssue("1", "PROJ-1", "10")}}
+	repo := &mockRepository{
+		syncLogID:       1,
+		projectIDMap:    map[string]int64{"10": 1},
+		fieldMappingErr: errors.New("db error"),
+	}
+
+	syncer := newTestSyncer(jira, repo)
+	if err := syncer.RunDeltaSync(context.Background()); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if atomic.LoadInt64(&jira.searchCallCount) != 0 {
+		t.Error("issues must not be fetched without the field mapping")
+	}
+}
+
 // ----------------------------------------------------------------
 // Delta Sync Tests
 // ----------------------------------------------------------------
@@ -0,0 +1,169 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+// ErrInvalidWebhookEvent is returned by WebhookProcessor.Process when an event
+// lacks the issue or project it is about.
+var ErrInvalidWebhookEvent = errors.New("invalid webhook event")
+
+// WebhookResult is the outcome of processing a webhook event.
+type WebhookResult string
+
+const (
+	// WebhookApplied means the event changed the stored data.
+	WebhookApplied WebhookResult = "applied"
+	// WebhookSkipped means the event was older than the stored data, or was about
+	// an issue or project that is not stored.
+	WebhookSkipped WebhookResult = "skipped"
+	// WebhookIgnored means the event type is not handled.
+	WebhookIgnored WebhookResult = "ignored"
+)
+
+// WebhookProcessor applies Jira webhook events to the DB, normalizing issues the
+// same way as the syncer. Events may arrive out of order or more than once: an
+// issue event is applied only if the issue's updated time is not older than the
+// stored one.
+type WebhookProcessor struct {
+	repo Repository
+	log  *zap.Logger
+}
+
+// NewWebhookProcessor creates a new WebhookProcessor.
+func NewWebhookProcessor(repo Repository, log *zap.Logger) *WebhookProcessor {
+	return &WebhookProcessor{repo: repo, log: log}
+}
+
+// Process applies event. It returns ErrInvalidWebhookEvent (wrapped) for
+// malformed events.
+func (p *WebhookProcessor) Process(ctx context.Context, event jiraclient.WebhookEvent) (WebhookResult, error) {
+	switch event.WebhookEvent {
+	case jiraclient.WebhookIssueCreated, jiraclient.WebhookIssueUpdated:
+		if event.Issue == nil {
+			return "", fmt.Errorf("%w: %s without issue", ErrInvalidWebhookEvent, event.WebhookEvent)
+		}
+		return p.upsertIssue(ctx, event)
+	case jiraclient.WebhookIssueDeleted:
+		if event.Issue == nil {
+			return "", fmt.Errorf("%w: %s without issue", ErrInvalidWebhookEvent, event.WebhookEvent)
+		}
+		return p.deleteIssue(ctx, event)
+	case jiraclient.WebhookProjectCreated, jiraclient.WebhookProjectUpdated,
+		jiraclient.WebhookProjectRestoredDeleted, jiraclient.WebhookProjectRestoredArchive:
+		if event.Project == nil {
+			return "", fmt.Errorf("%w: %s without project", ErrInvalidWebhookEvent, event.WebhookEvent)
+		}
+		return p.upsertProject(ctx, event.Project.ToProject())
+	case jiraclient.WebhookProjectDeleted, jiraclient.WebhookProjectSoftDeleted, jiraclient.WebhookProjectArchived:
+		if event.Project == nil {
+			return "", fmt.Errorf("%w: %s without project", ErrInvalidWebhookEvent, event.WebhookEvent)
+		}
+		return p.deleteProject(ctx, event.Project.ID.String())
+	default:
+		return WebhookIgnored, nil
+	}
+}
+
+// upsertIssue stores the issue of a created/updated event, its links, and the
+// status/due date transition of its changelog.
+func (p *WebhookProcessor) upsertIssue(ctx context.Context, event jiraclient.WebhookEvent) (WebhookResult, error) {
+	issue := *event.Issue
+	// イベントごとに全プロジェクトの設定を読み込まないよう、対象プロジェクトの分だけ解決する
+	projectID, policy, ok, err := p.repo.GetProjectDelayPolicy(ctx, issue.Fields.Project.ID)
+	if err != nil {
+		return "", fmt.Errorf("get project delay policy: %w", err)
+	}
+	// 未取り込みのプロジェクトのチケットは次回の同期でプロジェクトごと取り込む
+	if !ok {
+		p.log.Debug("skipping webhook issue of unknown project",
+			zap.String("issue_key", issue.Key), zap.String("jira_project_id", issue.Fields.Project.ID))
+		return WebhookSkipped, nil
+	}
+	fields, err := p.repo.GetFieldMapping(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get field mapping: %w", err)
+	}
+
+	di := normalizer.ConvertIssueWithPolicy(issue, normalizer.Now(), policy)
+	fields.Apply(&di, issue.Fields)
+
+	projectIDMap := map[string]int64{issue.Fields.Project.ID: projectID}
+	n, err := p.repo.UpsertIssues(ctx, []normalizer.DBIssue{di}, projectIDMap)
+	if err != nil {
+		return "", fmt.Errorf("upsert issues: %w", err)
+	}
+	if n == 0 {
+		// 保存済みの内容の方が新しい（イベントの到着順が前後した）
+		return WebhookSkipped, nil
+	}
+	if _, err := p.repo.ReplaceIssueLinks(ctx, []normalizer.DBIssue{di}); err != nil {
+		return "", fmt.Errorf("replace issue links: %w", err)
+	}
+
+	if event.Changelog != nil {
+		h := *event.Changelog
+		if h.Created == "" {
+			h.Created = issue.Fields.Updated
+		}
+		if h.Author == nil {
+			h.Author = event.User
+		}
+		history := normalizer.ConvertChangelog(di.JiraIssueID, []jiraclient.ChangelogHistory{h})
+		if len(history) > 0 {
+			if _, err := p.repo.InsertIssueHistory(ctx, history); err != nil {
+				return "", fmt.Errorf("insert issue history: %w", err)
+			}
+		}
+	}
+	return WebhookApplied, nil
+}
+
+// deleteIssue soft-deletes the issue of a deleted event as of the event time.
+func (p *WebhookProcessor) deleteIssue(ctx context.Context, event jiraclient.WebhookEvent) (WebhookResult, error) {
+	di := normalizer.ConvertIssue(*event.Issue, normalizer.Now())
+	// last_updated_at と同じタイムゾーンで比較するため、チケットの更新日時のロケーションに揃える
+	deletedAt := di.LastUpdatedAt
+	if event.Timestamp > 0 {
+		if t := time.UnixMilli(event.Timestamp).In(deletedAt.Location()); t.After(deletedAt) {
+			deletedAt = t
+		}
+	}
+
+	deleted, err := p.repo.MarkIssueDeleted(ctx, di.JiraIssueID, deletedAt)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return WebhookSkipped, nil
+	}
+	return WebhookApplied, nil
+}
+
+// upsertProject stores the project of a created/updated/restored event.
+func (p *WebhookProcessor) upsertProject(ctx context.Context, project jiraclient.Project) (WebhookResult, error) {
+	if _, err := p.repo.UpsertProjects(ctx, []normalizer.DBProject{normalizer.ConvertProject(project)}); err != nil {
+		return "", fmt.Errorf("upsert projects: %w", err)
+	}
+	return WebhookApplied, nil
+}
+
+// deleteProject soft-deletes the project of a deleted/archived event and its issues.
+func (p *WebhookProcessor) deleteProject(ctx context.Context, jiraProjectID string) (WebhookResult, error) {
+	deleted, err := p.repo.MarkProjectDeleted(ctx, jiraProjectID)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return WebhookSkipped, nil
+	}
+	return WebhookApplied, nil
+}
@@ -0,0 +1,162 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+func newTestWebhookProcessor(repo Repository) *WebhookProcessor {
+	return NewWebhookProcessor(repo, zap.NewNop())
+}
+
+func TestWebhookProcessor_IssueUpdated(t *testing.T) {
+	repo := &mockRepository{projectIDMap: map[string]int64{"10": 1}}
+	issue := makeIssue("1", "PROJ-1", "10")
+	issue.Fields.Updated = "2026-02-24T10:00:00.000+0900"
+
+	got, err := newTestWebhookProcessor(repo).Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookIssueUpdated,
+		User:         &jiraclient.User{AccountID: "a1"},
+		Issue:        &issue,
+		Changelog: &jiraclient.ChangelogHistory{ID: "500", Items: []jiraclient.ChangelogItem{
+			{Field: "status", FromString: "To Do", ToString: "In Progress"},
+		}},
+	})
+
+	if err != nil || got != WebhookApplied {
+		t.Fatalf("expected applied, got %s (%v)", got, err)
+	}
+	if len(repo.upsertedIssues) != 1 || repo.upsertedIssues[0].JiraIssueID != "1" {
+		t.Errorf("expected issue 1 to be upserted, got %+v", repo.upsertedIssues)
+	}
+	if len(repo.linkedIssues) != 1 {
+		t.Errorf("expected the links of issue 1 to be replaced, got %v", repo.linkedIssues)
+	}
+	// 変更日時はチケットの更新日時、変更者はイベントのユーザー
+	if len(repo.history) != 1 || repo.history[0].JiraHistoryID != "500" || repo.history[0].AuthorAccountID != "a1" ||
+		!repo.history[0].ChangedAt.Equal(time.Date(2026, 2, 24, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected history: %+v", repo.history)
+	}
+}
+
+func TestWebhookProcessor_StaleIssueSkipped(t *testing.T) {
+	repo := &mockRepository{projectIDMap: map[string]int64{"10": 1}, staleIssues: map[string]bool{"1": true}}
+	issue := makeIssue("1", "PROJ-1", "10")
+
+	got, err := newTestWebhookProcessor(repo).Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookIssueUpdated,
+		Issue:        &issue,
+		Changelog:    &jiraclient.ChangelogHistory{ID: "500", Items: []jiraclient.ChangelogItem{{Field: "status"}}},
+	})
+
+	if err != nil || got != WebhookSkipped {
+		t.Fatalf("expected skipped, got %s (%v)", got, err)
+	}
+	if len(repo.linkedIssues) != 0 || len(repo.history) != 0 {
+		t.Errorf("expected no links or history for a stale event, got %v %+v", repo.linkedIssues, repo.history)
+	}
+}
+
+func TestWebhookProcessor_UsesProjectDelayPolicy(t *testing.T) {
+	repo := &mockRepository{
+		projectIDMap:  map[string]int64{"10": 1, "20": 2},
+		delayPolicies: map[string]normalizer.DelayPolicy{"20": {YellowDays: 3, NoDueDateStatus: "RED"}},
+	}
+	// 期日なしのチケットはプロジェクト 20 のポリシーで RED になる
+	issue := makeIssue("1", "OTHER-1", "20")
+
+	got, err := newTestWebhookProcessor(repo).Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookIssueCreated,
+		Issue:        &issue,
+	})
+
+	if err != nil || got != WebhookApplied {
+		t.Fatalf("expected applied, got %s (%v)", got, err)
+	}
+	if len(repo.upsertedIssues) != 1 || repo.upsertedIssues[0].DelayStatus != "RED" {
+		t.Errorf("expected the issue to be upserted as RED, got %+v", repo.upsertedIssues)
+	}
+}
+
+func TestWebhookProcessor_UnknownProjectSkipped(t *testing.T) {
+	repo := &mockRepository{projectIDMap: map[string]int64{"10": 1}}
+	issue := makeIssue("1", "OTHER-1", "20")
+
+	got, err := newTestWebhookProcessor(repo).Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookIssueCreated,
+		Issue:        &issue,
+	})
+
+	if err != nil || got != WebhookSkipped {
+		t.Fatalf("expected skipped, got %s (%v)", got, err)
+	}
+	if len(repo.upsertedIssues) != 0 {
+		t.Errorf("expected no issues to be upserted, got %+v", repo.upsertedIssues)
+	}
+}
+
+func TestWebhookProcessor_IssueDeleted(t *testing.T) {
+	repo := &mockRepository{}
+	issue := makeIssue("1", "PROJ-1", "10")
+	issue.Fields.Updated = "2026-02-24T10:00:00.000+0900"
+
+	got, err := newTestWebhookProcessor(repo).Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookIssueDeleted,
+		Timestamp:    time.Date(2026, 2, 24, 3, 0, 0, 0, time.UTC).UnixMilli(),
+		Issue:        &issue,
+	})
+
+	if err != nil || got != WebhookApplied {
+		t.Fatalf("expected applied, got %s (%v)", got, err)
+	}
+	// 削除日時はイベントの日時（チケットの更新日時と同じオフセット）
+	deletedAt, ok := repo.deletedIssues["1"]
+	if !ok || !deletedAt.Equal(time.Date(2026, 2, 24, 3, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected deletion: %v", repo.deletedIssues)
+	}
+	if _, offset := deletedAt.Zone(); offset != 9*60*60 {
+		t.Errorf("expected the offset of the issue's updated time, got %d", offset)
+	}
+}
+
+func TestWebhookProcessor_Projects(t *testing.T) {
+	repo := &mockRepository{projectIDMap: map[string]int64{"10": 1}}
+	p := newTestWebhookProcessor(repo)
+
+	got, err := p.Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookProjectCreated,
+		Project:      &jiraclient.WebhookProject{ID: "20", Key: "NEW", Name: "New"},
+	})
+	if err != nil || got != WebhookApplied || repo.upsertProjectsCount != 1 {
+		t.Errorf("expected the project to be upserted, got %s (%v)", got, err)
+	}
+
+	got, err = p.Process(context.Background(), jiraclient.WebhookEvent{
+		WebhookEvent: jiraclient.WebhookProjectArchived,
+		Project:      &jiraclient.WebhookProject{ID: "10", Key: "PROJ"},
+	})
+	if err != nil || got != WebhookApplied || len(repo.deletedProjects) != 1 || repo.deletedProjects[0] != "10" {
+		t.Errorf("expected project 10 to be deleted, got %s (%v) %v", got, err, repo.deletedProjects)
+	}
+}
+
+func TestWebhookProcessor_IgnoredAndInvalid(t *testing.T) {
+	p := newTestWebhookProcessor(&mockRepository{})
+
+	got, err := p.Process(context.Background(), jiraclient.WebhookEvent{WebhookEvent: "comment_created"})
+	if err != nil || got != WebhookIgnored {
+		t.Errorf("expected ignored, got %s (%v)", got, err)
+	}
+
+	_, err = p.Process(context.Background(), jiraclient.WebhookEvent{WebhookEvent: jiraclient.WebhookIssueUpdated})
+	if !errors.Is(err, ErrInvalidWebhookEvent) {
+		t.Errorf("expected ErrInvalidWebhookEvent, got %v", err)
+	}
+}
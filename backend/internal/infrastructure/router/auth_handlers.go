@@ -1,7 +1,11 @@
 package router
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
@@ -15,10 +19,15 @@ type loginRequest struct {
 }
 
 type loginResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
-	User        userInfo `json:"user"`
+	AccessToken  string   `json:"access_token"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int      `json:"expires_in"`
+	RefreshToken string   `json:"refresh_token"`
+	User         userInfo `json:"user"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type userInfo struct {
@@ -27,8 +36,148 @@ type userInfo struct {
 	Role  string `json:"role"`
 }
 
+// Errors of rotateRefreshToken.
+var (
+	errRefreshTokenInvalid = errors.New("invalid refresh token")
+	errRefreshTokenRevoked = errors.New("refresh token has been revoked")
+	errRefreshTokenExpired = errors.New("refresh token has expired")
+	errAccountDisabled     = errors.New("account is disabled")
+)
+
+// dbUserChecker rejects tokens of users that are deactivated or deleted and
+// reports the current role of the others.
+type dbUserChecker struct {
+	db *sqlx.DB
+}
+
+// CheckUser implements auth.UserChecker.
+func (u dbUserChecker) CheckUser(ctx context.Context, userID int64) (string, error) {
+	var user struct {
+		Role     string `db:"role"`
+		IsActive bool   `db:"is_active"`
+	}
+	err := u.db.QueryRowxContext(ctx, `SELECT role, is_active FROM users WHERE id = $1`, userID).StructScan(&user)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !user.IsActive) {
+		return "", auth.ErrUserRevoked
+	}
+	if err != nil {
+		return "", err
+	}
+	return user.Role, nil
+}
+
+// issueRefreshToken stores the hash of a new refresh token of the user and
+// returns the token with the ID of its row.
+func issueRefreshToken(q sqlx.Queryer, tm *auth.TokenManager, userID int64) (string, int64, error) {
+	token, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", 0, err
+	}
+	var id int64
+	err = q.QueryRowx(
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id`,
+		userID, hash, time.Now().Add(tm.RefreshTokenDuration()),
+	).Scan(&id)
+	if err != nil {
+		return "", 0, err
+	}
+	return token, id, nil
+}
+
+// rotateRefreshToken exchanges a refresh token for a new one and returns the
+// user it belongs to. The presented token is revoked and linked to its
+// successor. Presenting a token that was already rotated or revoked revokes
+// every refresh token of the user, since it may have been stolen.
+func rotateRefreshToken(db *sqlx.DB, tm *auth.TokenManager, token string) (userInfo, string, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return userInfo{}, "", err
+	}
+	defer tx.Rollback()
+
+	var current struct {
+		ID        int64      `db:"id"`
+		UserID    int64      `db:"user_id"`
+		ExpiresAt time.Time  `db:"expires_at"`
+		RevokedAt *time.Time `db:"revoked_at"`
+		Email     string     `db:"email"`
+		Role      string     `db:"role"`
+		IsActive  bool       `db:"is_active"`
+	}
+	err = tx.QueryRowx(`
+		SELECT rt.id, rt.user_id, rt.expires_at, rt.revoked_at, u.email, u.role, u.is_active
+		FROM refresh_tokens rt
+		JOIN users u ON u.id = rt.user_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt`,
+		auth.HashRefreshToken(token),
+	).StructScan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return userInfo{}, "", errRefreshTokenInvalid
+	}
+	if err != nil {
+		return userInfo{}, "", err
+	}
+
+	if current.RevokedAt != nil {
+		// 失効済みのトークンの再使用は漏えいの可能性があるため、ユーザーのセッションをすべて失効させる
+		if err := revokeRefreshTokens(tx, current.UserID); err != nil {
+			return userInfo{}, "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return userInfo{}, "", err
+		}
+		return userInfo{}, "", errRefreshTokenRevoked
+	}
+	if !current.ExpiresAt.After(time.Now()) {
+		return userInfo{}, "", errRefreshTokenExpired
+	}
+	if !current.IsActive {
+		return userInfo{}, "", errAccountDisabled
+	}
+
+	next, nextID, err := issueRefreshToken(tx, tm, current.UserID)
+	if err != nil {
+		return userInfo{}, "", err
+	}
+	if _, err := tx.Exec(
+		`UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = $1 WHERE id = $2`,
+		nextID, current.ID,
+	); err != nil {
+		return userInfo{}, "", err
+	}
+	if err := tx.Commit(); err != nil {
+		return userInfo{}, "", err
+	}
+	return userInfo{ID: current.UserID, Email: current.Email, Role: current.Role}, next, nil
+}
+
+// revokeRefreshTokens revokes every active refresh token of the user.
+func revokeRefreshTokens(e sqlx.Execer, userID int64) error {
+	_, err := e.Exec(`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+// respondTokens writes a new access token for user together with refreshToken.
+func respondTokens(c *gin.Context, tm *auth.TokenManager, user userInfo, refreshToken string) {
+	token, err := tm.GenerateAccessToken(user.ID, user.Email, user.Role)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse{
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tm.AccessTokenDuration().Seconds()),
+		RefreshToken: refreshToken,
+		User:         user,
+	})
+}
+
 // loginHandler handles POST /api/v1/auth/login.
-// Validates email/password and returns a JWT access token on success.
+// Validates email/password and returns a short-lived JWT access token and a
+// refresh token on success.
 func loginHandler(db *sqlx.DB, tm *auth.TokenManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var req loginRequest
@@ -70,18 +219,68 @@ func loginHandler(db *sqlx.DB, tm *auth.TokenManager) gin.HandlerFunc {
 			return
 		}
 
-		token, err := tm.GenerateAccessToken(user.ID, user.Email, user.Role)
+		// 期限切れのリフレッシュトークンはここで掃除する（失効済みでも期限内のものは再使用の検知に使う）
+		if _, err := db.Exec(`DELETE FROM refresh_tokens WHERE user_id = $1 AND expires_at < NOW()`, user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue refresh token"})
+			return
+		}
+		refreshToken, _, err := issueRefreshToken(db, tm, user.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate token"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to issue refresh token"})
 			return
 		}
 
-		c.JSON(http.StatusOK, loginResponse{
-			AccessToken: token,
-			TokenType:   "Bearer",
-			ExpiresIn:   86400, // 24 hours in seconds
-			User:        userInfo{ID: user.ID, Email: user.Email, Role: user.Role},
-		})
+		respondTokens(c, tm, userInfo{ID: user.ID, Email: user.Email, Role: user.Role}, refreshToken)
+	}
+}
+
+// refreshHandler handles POST /api/v1/auth/refresh.
+// Exchanges a refresh token for a new access token and a new refresh token;
+// the presented refresh token cannot be used again.
+func refreshHandler(db *sqlx.DB, tm *auth.TokenManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req refreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+			return
+		}
+
+		user, refreshToken, err := rotateRefreshToken(db, tm, req.RefreshToken)
+		switch {
+		case errors.Is(err, errRefreshTokenInvalid), errors.Is(err, errRefreshTokenRevoked),
+			errors.Is(err, errRefreshTokenExpired), errors.Is(err, errAccountDisabled):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
+			return
+		}
+
+		respondTokens(c, tm, user, refreshToken)
+	}
+}
+
+// logoutHandler handles POST /api/v1/auth/logout.
+// Revokes the refresh token of the session. Unknown or already revoked tokens
+// are accepted so that logging out is idempotent; the access token stays valid
+// until it expires.
+func logoutHandler(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req refreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+			return
+		}
+
+		_, err := db.Exec(
+			`UPDATE refresh_tokens SET revoked_at = NOW() WHERE token_hash = $1 AND revoked_at IS NULL`,
+			auth.HashRefreshToken(req.RefreshToken),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke refresh token"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "logged out"})
 	}
 }
 
@@ -2,10 +2,13 @@ package router
 
 import (
 	"bytes"
+	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
@@ -134,6 +137,14 @@ func TestLoginHandler_Success(t *testing.T) {
 	mock.ExpectQuery(`SELECT id, email, password_hash, role, is_active FROM users`).
 		WithArgs("admin@example.com").
 		WillReturnRows(rows)
+	mock.ExpectExec(`DELETE FROM refresh_tokens WHERE user_id = \$1 AND expires_at < NOW\(\)`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// リフレッシュトークンはハッシュのみを保存する
+	var storedHash string
+	mock.ExpectQuery(`INSERT INTO refresh_tokens`).
+		WithArgs(int64(1), capturedArg{&storedHash}, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(10))
 
 	body := bytes.NewBufferString(`{"email":"admin@example.com","password":"Password1!"}`)
 	w := httptest.NewRecorder()
@@ -150,6 +161,182 @@ func TestLoginHandler_Success(t *testing.T) {
 	assert.Equal(t, "Bearer", resp.TokenType)
 	assert.Equal(t, "admin@example.com", resp.User.Email)
 	assert.Equal(t, "admin", resp.User.Role)
+	assert.Equal(t, 900, resp.ExpiresIn)
+	assert.Equal(t, auth.HashRefreshToken(resp.RefreshToken), storedHash)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// capturedArg matches any string argument and stores it.
+type capturedArg struct{ dst *string }
+
+func (a capturedArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*a.dst = s
+	return ok
+}
+
+// refreshTokenColumns are the columns rotateRefreshToken selects.
+var refreshTokenColumns = []string{"id", "user_id", "expires_at", "revoked_at", "email", "role", "is_active"}
+
+func newRefreshContext(body string) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, w
+}
+
+// --- refreshHandler tests ---
+
+func TestRefreshHandler_MissingToken(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newRefreshContext(`{}`)
+
+	refreshHandler(db, newTestTokenManager())(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRefreshHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens rt\s+JOIN users u ON u.id = rt.user_id\s+WHERE rt.token_hash = \$1\s+FOR UPDATE OF rt`).
+		WithArgs(auth.HashRefreshToken("old-token")).
+		WillReturnRows(sqlmock.NewRows(refreshTokenColumns).
+			AddRow(5, 1, time.Now().Add(time.Hour), nil, "admin@example.com", "project_manager", true))
+	var storedHash string
+	mock.ExpectQuery(`INSERT INTO refresh_tokens`).
+		WithArgs(int64(1), capturedArg{&storedHash}, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+	// 使用したトークンは失効させ、後継のトークンを記録する
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\), replaced_by = \$1 WHERE id = \$2`).
+		WithArgs(int64(6), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	c, w := newRefreshContext(`{"refresh_token":"old-token"}`)
+	tm := newTestTokenManager()
+
+	refreshHandler(db, tm)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp loginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEqual(t, "old-token", resp.RefreshToken)
+	assert.Equal(t, auth.HashRefreshToken(resp.RefreshToken), storedHash)
+	// ロールは DB の最新の値でアクセストークンを発行する
+	claims, err := tm.ValidateAccessToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "project_manager", claims.Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshHandler_UnknownToken(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens rt`).WillReturnRows(sqlmock.NewRows(refreshTokenColumns))
+	mock.ExpectRollback()
+	c, w := newRefreshContext(`{"refresh_token":"unknown"}`)
+
+	refreshHandler(db, newTestTokenManager())(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid refresh token")
+}
+
+func TestRefreshHandler_ReusedTokenRevokesAllSessions(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens rt`).
+		WillReturnRows(sqlmock.NewRows(refreshTokenColumns).
+			AddRow(5, 1, time.Now().Add(time.Hour), time.Now().Add(-time.Minute), "admin@example.com", "admin", true))
+	// ローテーション済みのトークンが再使用された場合はユーザーの全トークンを失効させる
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	c, w := newRefreshContext(`{"refresh_token":"rotated"}`)
+
+	refreshHandler(db, newTestTokenManager())(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "refresh token has been revoked")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshHandler_ExpiredToken(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens rt`).
+		WillReturnRows(sqlmock.NewRows(refreshTokenColumns).
+			AddRow(5, 1, time.Now().Add(-time.Hour), nil, "admin@example.com", "admin", true))
+	mock.ExpectRollback()
+	c, w := newRefreshContext(`{"refresh_token":"expired"}`)
+
+	refreshHandler(db, newTestTokenManager())(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "refresh token has expired")
+}
+
+func TestRefreshHandler_DisabledAccount(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM refresh_tokens rt`).
+		WillReturnRows(sqlmock.NewRows(refreshTokenColumns).
+			AddRow(5, 1, time.Now().Add(time.Hour), nil, "admin@example.com", "admin", false))
+	mock.ExpectRollback()
+	c, w := newRefreshContext(`{"refresh_token":"token"}`)
+
+	refreshHandler(db, newTestTokenManager())(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "account is disabled")
+}
+
+// --- logoutHandler tests ---
+
+func TestLogoutHandler_RevokesToken(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE token_hash = \$1 AND revoked_at IS NULL`).
+		WithArgs(auth.HashRefreshToken("session-token")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c, w := newRefreshContext(`{"refresh_token":"session-token"}`)
+
+	logoutHandler(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLogoutHandler_MissingToken(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newRefreshContext(`{}`)
+
+	logoutHandler(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// --- dbUserChecker tests ---
+
+func TestDBUserChecker(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT role, is_active FROM users WHERE id = \$1`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "is_active"}).AddRow("viewer", true))
+	mock.ExpectQuery(`SELECT role, is_active FROM users WHERE id = \$1`).WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "is_active"}).AddRow("admin", false))
+	mock.ExpectQuery(`SELECT role, is_active FROM users WHERE id = \$1`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "is_active"}))
+	checker := dbUserChecker{db: db}
+
+	role, err := checker.CheckUser(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "viewer", role)
+	// 無効化されたユーザー・削除されたユーザー
+	_, err = checker.CheckUser(context.Background(), 2)
+	assert.ErrorIs(t, err, auth.ErrUserRevoked)
+	_, err = checker.CheckUser(context.Background(), 3)
+	assert.ErrorIs(t, err, auth.ErrUserRevoked)
 }
 
 // --- meHandler tests ---
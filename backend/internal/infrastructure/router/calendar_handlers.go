@@ -0,0 +1,496 @@
+package router
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/calendar"
+)
+
+// maxHolidayFileSize limits the size of an uploaded holiday list file.
+const maxHolidayFileSize = 1 << 20
+
+// CalendarRow represents a business calendar with usage counts.
+type CalendarRow struct {
+	ID                int64     `db:"id" json:"id"`
+	Name              string    `db:"name" json:"name"`
+	Description       *string   `db:"description" json:"description"`
+	IsDefault         bool      `db:"is_default" json:"is_default"`
+	HolidayCount      int       `db:"holiday_count" json:"holiday_count"`
+	OrganizationCount int       `db:"organization_count" json:"organization_count"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// HolidayRow represents a holiday of a business calendar.
+type HolidayRow struct {
+	Date string `db:"holiday_date" json:"date"`
+	Name string `db:"name" json:"name"`
+}
+
+type calendarRequest struct {
+	Name        string  `json:"name"        binding:"required,max=100"`
+	Description *string `json:"description"`
+	IsDefault   bool    `json:"is_default"`
+}
+
+type holidayRequest struct {
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"max=100"`
+}
+
+type assignOrgCalendarRequest struct {
+	BusinessCalendarID *int64 `json:"business_calendar_id"`
+}
+
+// calendarQuery is the shared SQL for fetching business calendars with usage counts.
+const calendarQuery = `
+	SELECT
+		bc.id,
+		bc.name,
+		bc.description,
+		bc.is_default,
+		(SELECT COUNT(*) FROM calendar_holidays h WHERE h.calendar_id = bc.id) AS holiday_count,
+		(SELECT COUNT(*) FROM organizations o WHERE o.business_calendar_id = bc.id) AS organization_count,
+		bc.created_at,
+		bc.updated_at
+	FROM business_calendars bc
+`
+
+// parseCalendarID parses the :id path parameter and reports a 400 on failure.
+func parseCalendarID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid calendar id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// calendarExists reports whether the calendar exists, writing a 404 or 500 otherwise.
+func calendarExists(c *gin.Context, db *sqlx.DB, id int64) bool {
+	var exists bool
+	if err := db.QueryRowx(`SELECT EXISTS(SELECT 1 FROM business_calendars WHERE id = $1)`, id).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch calendar"})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+		return false
+	}
+	return true
+}
+
+// calendarNameTaken reports whether another calendar (other than excludeID) uses the name.
+func calendarNameTaken(db *sqlx.DB, name string, excludeID int64) (bool, error) {
+	var taken bool
+	err := db.QueryRowx(`SELECT EXISTS(SELECT 1 FROM business_calendars WHERE name = $1 AND id <> $2)`, name, excludeID).Scan(&taken)
+	return taken, err
+}
+
+// saveCalendar inserts (id == 0) or updates a calendar. Making a calendar the
+// default clears the flag on the previous default in the same transaction.
+// Returns the calendar ID, or sql.ErrNoRows when the calendar to update is missing.
+func saveCalendar(db *sqlx.DB, id int64, req calendarRequest) (int64, error) {
+	tx, err := db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if req.IsDefault {
+		if _, err := tx.Exec(`UPDATE business_calendars SET is_default = FALSE WHERE is_default AND id <> $1`, id); err != nil {
+			return 0, err
+		}
+	}
+	if id == 0 {
+		err = tx.QueryRowx(
+			`INSERT INTO business_calendars (name, description, is_default) VALUES ($1, $2, $3) RETURNING id`,
+			req.Name, req.Description, req.IsDefault,
+		).Scan(&id)
+	} else {
+		err = tx.QueryRowx(
+			`UPDATE business_calendars SET name = $1, description = $2, is_default = $3 WHERE id = $4 RETURNING id`,
+			req.Name, req.Description, req.IsDefault, id,
+		).Scan(&id)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// listCalendarsHandlerWithDB handles GET /api/v1/settings/calendars.
+func listCalendarsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		calendars := make([]CalendarRow, 0)
+		if err := db.Select(&calendars, calendarQuery+` ORDER BY bc.is_default DESC, bc.name`); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch calendars"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": calendars})
+	}
+}
+
+// getCalendarHandlerWithDB handles GET /api/v1/settings/calendars/:id.
+func getCalendarHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+		var cal CalendarRow
+		if err := db.Get(&cal, calendarQuery+` WHERE bc.id = $1`, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+			return
+		}
+		c.JSON(http.StatusOK, cal)
+	}
+}
+
+// createCalendarHandlerWithDB handles POST /api/v1/settings/calendars.
+func createCalendarHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req calendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+			return
+		}
+
+		taken, err := calendarNameTaken(db, req.Name, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check calendar name"})
+			return
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "calendar name already exists"})
+			return
+		}
+
+		id, err := saveCalendar(db, 0, req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar"})
+			return
+		}
+
+		var cal CalendarRow
+		if err := db.Get(&cal, calendarQuery+` WHERE bc.id = $1`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch created calendar"})
+			return
+		}
+		c.JSON(http.StatusCreated, cal)
+	}
+}
+
+// updateCalendarHandlerWithDB handles PUT /api/v1/settings/calendars/:id.
+func updateCalendarHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+		var req calendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		req.Name = strings.TrimSpace(req.Name)
+		if req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+			return
+		}
+
+		taken, err := calendarNameTaken(db, req.Name, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check calendar name"})
+			return
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "calendar name already exists"})
+			return
+		}
+
+		if _, err := saveCalendar(db, id, req); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update calendar"})
+			return
+		}
+
+		var cal CalendarRow
+		if err := db.Get(&cal, calendarQuery+` WHERE bc.id = $1`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch updated calendar"})
+			return
+		}
+		c.JSON(http.StatusOK, cal)
+	}
+}
+
+// deleteCalendarHandlerWithDB handles DELETE /api/v1/settings/calendars/:id.
+// Organizations using the calendar fall back to their parent's calendar.
+func deleteCalendarHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+		result, err := db.Exec(`DELETE FROM business_calendars WHERE id = $1`, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete calendar"})
+			return
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "calendar deleted"})
+	}
+}
+
+// listHolidaysHandlerWithDB handles GET /api/v1/settings/calendars/:id/holidays.
+// The optional year query parameter limits the result to one year.
+func listHolidaysHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+
+		query := `SELECT TO_CHAR(holiday_date, 'YYYY-MM-DD') AS holiday_date, name
+		          FROM calendar_holidays WHERE calendar_id = $1`
+		args := []interface{}{id}
+		if y := c.Query("year"); y != "" {
+			year, err := strconv.Atoi(y)
+			if err != nil || year < 1 || year > 9999 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+				return
+			}
+			query += ` AND EXTRACT(YEAR FROM holiday_date) = $2`
+			args = append(args, year)
+		}
+
+		if !calendarExists(c, db, id) {
+			return
+		}
+		holidays := make([]HolidayRow, 0)
+		if err := db.Select(&holidays, query+` ORDER BY holiday_date`, args...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch holidays"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": holidays})
+	}
+}
+
+// addHolidayHandlerWithDB handles POST /api/v1/settings/calendars/:id/holidays.
+// Adding an existing date updates its name.
+func addHolidayHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+		var req holidayRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if _, err := time.Parse(calendar.DateLayout, req.Date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+			return
+		}
+
+		if !calendarExists(c, db, id) {
+			return
+		}
+		_, err := db.Exec(
+			`INSERT INTO calendar_holidays (calendar_id, holiday_date, name) VALUES ($1, $2, $3)
+			 ON CONFLICT (calendar_id, holiday_date) DO UPDATE SET name = EXCLUDED.name`,
+			id, req.Date, strings.TrimSpace(req.Name),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save holiday"})
+			return
+		}
+		c.JSON(http.StatusCreated, HolidayRow{Date: req.Date, Name: strings.TrimSpace(req.Name)})
+	}
+}
+
+// deleteHolidayHandlerWithDB handles DELETE /api/v1/settings/calendars/:id/holidays/:date.
+func deleteHolidayHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+		date := c.Param("date")
+		if _, err := time.Parse(calendar.DateLayout, date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be YYYY-MM-DD"})
+			return
+		}
+
+		result, err := db.Exec(`DELETE FROM calendar_holidays WHERE calendar_id = $1 AND holiday_date = $2`, id, date)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete holiday"})
+			return
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "holiday not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "holiday deleted"})
+	}
+}
+
+// importHolidaysHandlerWithDB handles POST /api/v1/settings/calendars/:id/holidays/import.
+// The holiday list is uploaded as the multipart field "file" (CSV of date and
+// name; see calendar.ParseHolidays). Imported dates are added or renamed; with
+// replace=true, the holidays of the calendar not in the file are removed.
+func importHolidaysHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseCalendarID(c)
+		if !ok {
+			return
+		}
+		replace := c.Query("replace") == "true"
+
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		if fh.Size > maxHolidayFileSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is too large"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+			return
+		}
+		defer f.Close()
+
+		holidays, err := calendar.ParseHolidays(f)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if len(holidays) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "no holidays found in file"})
+			return
+		}
+
+		if !calendarExists(c, db, id) {
+			return
+		}
+		imported, removed, err := importHolidays(db, id, holidays, replace)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import holidays"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"imported": imported, "removed": removed})
+	}
+}
+
+// importHolidays upserts holidays into the calendar in one transaction and, when
+// replace is set, deletes the calendar's other holidays.
+func importHolidays(db *sqlx.DB, calendarID int64, holidays []calendar.Holiday, replace bool) (imported, removed int, err error) {
+	dates := make([]string, len(holidays))
+	names := make([]string, len(holidays))
+	for i, h := range holidays {
+		dates[i] = h.Date
+		names[i] = h.Name
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if replace {
+		result, err := tx.Exec(
+			`DELETE FROM calendar_holidays WHERE calendar_id = $1 AND NOT (holiday_date = ANY($2::date[]))`,
+			calendarID, pq.Array(dates),
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+		n, _ := result.RowsAffected()
+		removed = int(n)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO calendar_holidays (calendar_id, holiday_date, name)
+		SELECT $1, h.holiday_date, h.name
+		FROM unnest($2::date[], $3::text[]) AS h(holiday_date, name)
+		ON CONFLICT (calendar_id, holiday_date) DO UPDATE SET name = EXCLUDED.name`,
+		calendarID, pq.Array(dates), pq.Array(names),
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(holidays), removed, tx.Commit()
+}
+
+// assignOrganizationCalendarHandlerWithDB handles PUT /api/v1/organizations/:id/calendar.
+// A null business_calendar_id makes the organization inherit its parent's calendar.
+func assignOrganizationCalendarHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization id"})
+			return
+		}
+		var req assignOrgCalendarRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.BusinessCalendarID != nil {
+			var exists bool
+			if err := db.QueryRowx(`SELECT EXISTS(SELECT 1 FROM business_calendars WHERE id = $1)`, *req.BusinessCalendarID).Scan(&exists); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch calendar"})
+				return
+			}
+			if !exists {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "calendar not found"})
+				return
+			}
+		}
+
+		result, err := db.Exec(`UPDATE organizations SET business_calendar_id = $1 WHERE id = $2`, req.BusinessCalendarID, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update organization"})
+			return
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+			return
+		}
+
+		var org OrganizationRow
+		if err := db.QueryRowx(orgQuery+` WHERE o.id = $1 GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at`, id).
+			StructScan(&org); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch updated organization"})
+			return
+		}
+		org.DelayStatus = orgDelayStatus(&org)
+		c.JSON(http.StatusOK, org)
+	}
+}
@@ -0,0 +1,223 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var calendarColumns = []string{
+	"id", "name", "description", "is_default", "holiday_count", "organization_count", "created_at", "updated_at",
+}
+
+func newHolidayImportContext(t *testing.T, target, content string) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "syukujitsu.csv")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, target, &body)
+	c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+	return c, w
+}
+
+// --- createCalendarHandlerWithDB tests ---
+
+func TestCreateCalendarHandler_MissingName(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/calendars", `{"name": "  "}`, nil)
+	createCalendarHandlerWithDB(db)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateCalendarHandler_DuplicateName(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM business_calendars WHERE name`).
+		WithArgs("日本", int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/calendars", `{"name": "日本"}`, nil)
+	createCalendarHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateCalendarHandler_DefaultReplacesPrevious(t *testing.T) {
+	db, mock := newTestDB(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM business_calendars WHERE name`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE business_calendars SET is_default = FALSE`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO business_calendars`).
+		WithArgs("日本", nil, true).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM business_calendars bc`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(calendarColumns).AddRow(2, "日本", nil, true, 0, 0, now, now))
+
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/calendars", `{"name": "日本", "is_default": true}`, nil)
+	createCalendarHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp CalendarRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.IsDefault)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- updateCalendarHandlerWithDB tests ---
+
+func TestUpdateCalendarHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM business_calendars WHERE name`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE business_calendars SET name`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/calendars/99", `{"name": "日本"}`,
+		gin.Params{{Key: "id", Value: "99"}})
+	updateCalendarHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- listHolidaysHandlerWithDB tests ---
+
+func TestListHolidaysHandler_InvalidYear(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodGet, "/settings/calendars/1/holidays?year=abc", "",
+		gin.Params{{Key: "id", Value: "1"}})
+	listHolidaysHandlerWithDB(db)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListHolidaysHandler_FiltersByYear(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM calendar_holidays WHERE calendar_id = \$1 AND EXTRACT\(YEAR`).
+		WithArgs(int64(1), 2026).
+		WillReturnRows(sqlmock.NewRows([]string{"holiday_date", "name"}).AddRow("2026-01-01", "元日"))
+
+	c, w := newDelayPolicyContext(http.MethodGet, "/settings/calendars/1/holidays?year=2026", "",
+		gin.Params{{Key: "id", Value: "1"}})
+	listHolidaysHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string][]HolidayRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []HolidayRow{{Date: "2026-01-01", Name: "元日"}}, resp["data"])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- addHolidayHandlerWithDB tests ---
+
+func TestAddHolidayHandler_InvalidDate(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/calendars/1/holidays", `{"date": "2026/1/1"}`,
+		gin.Params{{Key: "id", Value: "1"}})
+	addHolidayHandlerWithDB(db)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// --- deleteHolidayHandlerWithDB tests ---
+
+func TestDeleteHolidayHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectExec(`DELETE FROM calendar_holidays`).
+		WithArgs(int64(1), "2026-05-06").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	c, w := newDelayPolicyContext(http.MethodDelete, "/settings/calendars/1/holidays/2026-05-06", "",
+		gin.Params{{Key: "id", Value: "1"}, {Key: "date", Value: "2026-05-06"}})
+	deleteHolidayHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- importHolidaysHandlerWithDB tests ---
+
+func TestImportHolidaysHandler_InvalidFile(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newHolidayImportContext(t, "/settings/calendars/1/holidays/import", "2026/1/1,元日\n2026/13/1,不正\n")
+	importHolidaysHandlerWithDB(db)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "line 2")
+}
+
+func TestImportHolidaysHandler_Replace(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM calendar_holidays WHERE calendar_id = \$1 AND NOT`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO calendar_holidays`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	c, w := newHolidayImportContext(t, "/settings/calendars/1/holidays/import?replace=true",
+		"国民の祝日・休日月日,国民の祝日・休日名称\n2026/1/1,元日\n2026/1/12,成人の日\n")
+	importHolidaysHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp["imported"])
+	assert.Equal(t, 3, resp["removed"])
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- assignOrganizationCalendarHandlerWithDB tests ---
+
+func TestAssignOrganizationCalendarHandler_CalendarNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/organizations/1/calendar", `{"business_calendar_id": 9}`,
+		gin.Params{{Key: "id", Value: "1"}})
+	assignOrganizationCalendarHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignOrganizationCalendarHandler_OrganizationNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectExec(`UPDATE organizations SET business_calendar_id`).
+		WithArgs(nil, int64(99)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/organizations/99/calendar", `{"business_calendar_id": null}`,
+		gin.Params{{Key: "id", Value: "99"}})
+	assignOrganizationCalendarHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
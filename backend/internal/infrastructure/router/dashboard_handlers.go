@@ -1,11 +1,15 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
 )
 
 // DashboardOrg holds per-organization stats for the dashboard summary.
@@ -47,7 +51,8 @@ const projectStatusCTE = `
 				ELSE 'GREEN'
 			END AS delay_status
 		FROM projects p
-		LEFT JOIN issues i ON i.project_id = p.id
+		LEFT JOIN issues i ON i.project_id = p.id AND i.deleted_at IS NULL
+		WHERE p.deleted_at IS NULL
 		GROUP BY p.id, p.organization_id
 	)
 `
@@ -90,7 +95,9 @@ func getDashboardSummaryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 				COUNT(*) FILTER (WHERE delay_status = 'RED')           AS red,
 				COUNT(*) FILTER (WHERE delay_status = 'YELLOW')        AS yellow,
 				COUNT(*) FILTER (WHERE delay_status = 'GREEN')         AS green
-			FROM issues
+			FROM issues i
+			JOIN projects p ON i.project_id = p.id
+			WHERE i.deleted_at IS NULL AND p.deleted_at IS NULL
 		`).StructScan(&ic)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue counts"})
@@ -155,6 +162,11 @@ type ProjectSummaryResponse struct {
 		GreenCount  int `json:"green_count"`
 		OpenCount   int `json:"open_count"`
 		TotalCount  int `json:"total_count"`
+		// SlippedCount is the number of open issues whose due date was moved later
+		// than the original due date.
+		SlippedCount int `json:"slipped_count"`
+		// PostponeCount is the total number of due date postponements of open issues.
+		PostponeCount int `json:"postpone_count"`
 	} `json:"summary"`
 }
 
@@ -185,18 +197,25 @@ func getProjectSummaryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 				COALESCE(COUNT(CASE WHEN i.delay_status = 'YELLOW' THEN 1 END), 0) AS yellow_count,
 				COALESCE(COUNT(CASE WHEN i.delay_status = 'GREEN'  THEN 1 END), 0) AS green_count,
 				COALESCE(COUNT(CASE WHEN i.status_category != 'Done' THEN 1 END), 0) AS open_count,
-				COALESCE(COUNT(i.id), 0) AS total_count
+				COALESCE(COUNT(i.id), 0) AS total_count,
+				COUNT(CASE WHEN i.slipped AND i.status_category != 'Done' THEN 1 END) AS slipped_count,
+				COALESCE(SUM(CASE WHEN i.status_category != 'Done' THEN i.postpone_count END), 0) AS postpone_count
 			FROM projects p
-			LEFT JOIN issues i ON p.id = i.project_id
-			WHERE p.id = $1
+			LEFT JOIN issues i ON p.id = i.project_id AND i.deleted_at IS NULL
+			WHERE p.id = $1 AND p.deleted_at IS NULL
 			GROUP BY p.id, p.jira_project_id, p.key, p.name, p.lead_account_id, p.lead_email,
 			         p.organization_id, p.is_active, p.created_at, p.updated_at
 		`
-		var project ProjectRow
-		if err := db.Get(&project, projectQuery, id); err != nil {
+		var row struct {
+			ProjectRow
+			SlippedCount  int `db:"slipped_count"`
+			PostponeCount int `db:"postpone_count"`
+		}
+		if err := db.Get(&row, projectQuery, id); err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
 			return
 		}
+		project := row.ProjectRow
 		switch {
 		case project.RedCount > 0:
 			project.DelayStatus = "RED"
@@ -207,29 +226,9 @@ func getProjectSummaryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		}
 
 		// Fetch top delayed issues (RED and YELLOW), sorted by due_date ASC NULLS LAST
-		issuesQuery := `
-			SELECT
-				i.id,
-				i.jira_issue_id,
-				i.jira_issue_key,
-				i.project_id,
-				p.key  AS project_key,
-				p.name AS project_name,
-				i.summary,
-				i.status,
-				i.status_category,
-				TO_CHAR(i.due_date, 'YYYY-MM-DD') AS due_date,
-				i.assignee_name,
-				i.assignee_account_id,
-				i.delay_status,
-				i.priority,
-				i.issue_type,
-				i.last_updated_at,
-				i.created_at,
-				i.updated_at
-			FROM issues i
-			JOIN projects p ON i.project_id = p.id
+		issuesQuery := issueQuery + `
 			WHERE i.project_id = $1
+			  AND i.deleted_at IS NULL
 			  AND i.delay_status IN ('RED', 'YELLOW')
 			ORDER BY
 				CASE i.delay_status WHEN 'RED' THEN 0 ELSE 1 END,
@@ -250,6 +249,8 @@ func getProjectSummaryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		resp.Summary.GreenCount = project.GreenCount
 		resp.Summary.OpenCount = project.OpenCount
 		resp.Summary.TotalCount = project.TotalCount
+		resp.Summary.SlippedCount = row.SlippedCount
+		resp.Summary.PostponeCount = row.PostponeCount
 
 		c.JSON(http.StatusOK, resp)
 	}
@@ -319,8 +320,8 @@ func getOrganizationSummaryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 				p.created_at,
 				p.updated_at
 			FROM projects p
-			LEFT JOIN issues i ON i.project_id = p.id
-			WHERE p.organization_id = $1
+			LEFT JOIN issues i ON i.project_id = p.id AND i.deleted_at IS NULL
+			WHERE p.organization_id = $1 AND p.deleted_at IS NULL
 			GROUP BY p.id, p.jira_project_id, p.key, p.name, p.lead_account_id, p.lead_email,
 			         p.organization_id, p.created_at, p.updated_at
 			ORDER BY red_count DESC, yellow_count DESC, p.name ASC
@@ -348,3 +349,125 @@ func getOrganizationSummaryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		})
 	}
 }
+
+// TrendPoint is one point of the delay trend time series.
+type TrendPoint struct {
+	Date           string `db:"date"            json:"date"`
+	RedIssues      int    `db:"red_issues"      json:"red_issues"`
+	YellowIssues   int    `db:"yellow_issues"   json:"yellow_issues"`
+	GreenIssues    int    `db:"green_issues"    json:"green_issues"`
+	TotalIssues    int    `db:"total_issues"    json:"total_issues"`
+	RedProjects    int    `db:"red_projects"    json:"red_projects"`
+	YellowProjects int    `db:"yellow_projects" json:"yellow_projects"`
+	GreenProjects  int    `db:"green_projects"  json:"green_projects"`
+	TotalProjects  int    `db:"total_projects"  json:"total_projects"`
+}
+
+// TrendsResponse is the response body for GET /dashboard/trends.
+type TrendsResponse struct {
+	OrganizationID *int64       `json:"organization_id"`
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	Granularity    string       `json:"granularity"`
+	Data           []TrendPoint `json:"data"`
+}
+
+// defaultTrendDays is the period returned when from is omitted.
+const defaultTrendDays = 30
+
+// getDashboardTrendsHandlerWithDB returns the delay trend built from the daily
+// delay_snapshots. Without organization_id, the snapshots of all projects are
+// summed; with it, the snapshots of that organization (including its child
+// organizations) are used. With granularity=week, each week is represented by
+// its latest snapshot and dated by the Monday of the week.
+func getDashboardTrendsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granularity := c.DefaultQuery("granularity", "day")
+		if granularity != "day" && granularity != "week" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be day or week"})
+			return
+		}
+
+		// 日付はスナップショットと同じく REPORT_TIMEZONE の暦日として扱う
+		now := normalizer.Now()
+		to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if v := c.Query("to"); v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+				return
+			}
+			to = t
+		}
+		from := to.AddDate(0, 0, -(defaultTrendDays - 1))
+		if v := c.Query("from"); v != "" {
+			t, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+				return
+			}
+			from = t
+		}
+		if from.After(to) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+			return
+		}
+
+		resp := TrendsResponse{
+			From:        from.Format("2006-01-02"),
+			To:          to.Format("2006-01-02"),
+			Granularity: granularity,
+			Data:        make([]TrendPoint, 0),
+		}
+
+		// 組織指定時は組織単位、未指定時はプロジェクト単位のスナップショットを合算する
+		scope := "project_id IS NOT NULL"
+		args := []interface{}{resp.From, resp.To}
+		if v := c.Query("organization_id"); v != "" {
+			orgID, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization_id"})
+				return
+			}
+			resp.OrganizationID = &orgID
+			scope = "organization_id = $3"
+			args = append(args, orgID)
+		}
+
+		period := "snapshot_date"
+		if granularity == "week" {
+			period = "date_trunc('week', snapshot_date)::date"
+		}
+
+		query := fmt.Sprintf(`
+			WITH daily AS (
+				SELECT
+					snapshot_date,
+					SUM(red_issues)      AS red_issues,
+					SUM(yellow_issues)   AS yellow_issues,
+					SUM(green_issues)    AS green_issues,
+					SUM(total_issues)    AS total_issues,
+					SUM(red_projects)    AS red_projects,
+					SUM(yellow_projects) AS yellow_projects,
+					SUM(green_projects)  AS green_projects,
+					SUM(total_projects)  AS total_projects
+				FROM delay_snapshots
+				WHERE %s AND snapshot_date BETWEEN $1 AND $2
+				GROUP BY snapshot_date
+			)
+			SELECT DISTINCT ON (%s)
+				TO_CHAR(%s, 'YYYY-MM-DD') AS date,
+				red_issues, yellow_issues, green_issues, total_issues,
+				red_projects, yellow_projects, green_projects, total_projects
+			FROM daily
+			ORDER BY %s, snapshot_date DESC
+		`, scope, period, period, period)
+
+		if err := db.Select(&resp.Data, query, args...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch trends"})
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -213,6 +213,37 @@ func TestGetProjectSummaryHandler_Success(t *testing.T) {
 	assert.Equal(t, "RED", resp.DelayedIssues[0].DelayStatus)
 }
 
+func TestGetProjectSummaryHandler_SlipCounts(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := getProjectSummaryHandlerWithDB(db)
+
+	now := time.Now()
+	mock.ExpectQuery(`slipped_count`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "jira_project_id", "key", "name", "is_active", "created_at", "updated_at",
+			"red_count", "yellow_count", "green_count", "open_count", "total_count",
+			"slipped_count", "postpone_count",
+		}).AddRow(1, "JIRA-1", "PROJ", "Test Project", true, now, now, 0, 1, 2, 3, 3, 2, 5))
+	mock.ExpectQuery(`SELECT`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/dashboard/projects/1", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp ProjectSummaryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Summary.SlippedCount)
+	assert.Equal(t, 5, resp.Summary.PostponeCount)
+	assert.Equal(t, "YELLOW", resp.Project.DelayStatus)
+}
+
 func TestGetDashboardSummaryHandler_DBError(t *testing.T) {
 	db, mock := newTestDB(t)
 	mock.ExpectQuery(`WITH project_stats`).WillReturnError(sqlmock.ErrCancelled)
@@ -226,3 +257,88 @@ func TestGetDashboardSummaryHandler_DBError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+// --- getDashboardTrendsHandlerWithDB tests ---
+
+func TestGetDashboardTrendsHandler_InvalidParams(t *testing.T) {
+	cases := map[string]string{
+		"granularity": "/dashboard/trends?granularity=month",
+		"from":        "/dashboard/trends?from=2026-13-01",
+		"to":          "/dashboard/trends?to=yesterday",
+		"range":       "/dashboard/trends?from=2026-02-10&to=2026-02-01",
+		"org":         "/dashboard/trends?organization_id=abc",
+	}
+	for name, url := range cases {
+		t.Run(name, func(t *testing.T) {
+			db, _ := newTestDB(t)
+			handler := getDashboardTrendsHandlerWithDB(db)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, url, nil)
+
+			handler(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestGetDashboardTrendsHandler_OrganizationWeekly(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := getDashboardTrendsHandlerWithDB(db)
+
+	cols := []string{
+		"date", "red_issues", "yellow_issues", "green_issues", "total_issues",
+		"red_projects", "yellow_projects", "green_projects", "total_projects",
+	}
+	mock.ExpectQuery(`organization_id = \$3.*date_trunc\('week', snapshot_date\)`).
+		WithArgs("2026-02-01", "2026-02-28", int64(3)).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow("2026-02-02", 5, 3, 10, 18, 2, 1, 1, 4).
+			AddRow("2026-02-09", 3, 4, 12, 19, 1, 2, 1, 4))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/dashboard/trends?organization_id=3&from=2026-02-01&to=2026-02-28&granularity=week", nil)
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp TrendsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.OrganizationID)
+	assert.Equal(t, int64(3), *resp.OrganizationID)
+	assert.Equal(t, "week", resp.Granularity)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "2026-02-09", resp.Data[1].Date)
+	assert.Equal(t, 3, resp.Data[1].RedIssues)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDashboardTrendsHandler_DefaultsToLast30DaysOfAllProjects(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := getDashboardTrendsHandlerWithDB(db)
+
+	mock.ExpectQuery(`project_id IS NOT NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"date"}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/dashboard/trends", nil)
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp TrendsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	from, err := time.Parse("2006-01-02", resp.From)
+	require.NoError(t, err)
+	to, err := time.Parse("2006-01-02", resp.To)
+	require.NoError(t, err)
+	assert.Equal(t, 29*24*time.Hour, to.Sub(from))
+	assert.Nil(t, resp.OrganizationID)
+	assert.NotNil(t, resp.Data)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
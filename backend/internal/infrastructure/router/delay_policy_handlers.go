@@ -0,0 +1,268 @@
+package router
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// Delay policy scopes. A policy applies to every project (global), to the projects
+// of an organization and its descendants, or to a single project.
+const (
+	delayPolicyScopeGlobal       = "global"
+	delayPolicyScopeOrganization = "organization"
+	delayPolicyScopeProject      = "project"
+)
+
+// DelayPolicyRow represents a row in the delay_policies table.
+// NULL settings are inherited from the parent organization or the global default.
+type DelayPolicyRow struct {
+	ID               int64     `db:"id" json:"id"`
+	Scope            string    `db:"-" json:"scope"`
+	OrganizationID   *int64    `db:"organization_id" json:"organization_id"`
+	OrganizationName *string   `db:"organization_name" json:"organization_name"`
+	ProjectID        *int64    `db:"project_id" json:"project_id"`
+	ProjectKey       *string   `db:"project_key" json:"project_key"`
+	YellowDays       *int      `db:"yellow_days" json:"yellow_days"`
+	NoDueDateStatus  *string   `db:"no_due_date_status" json:"no_due_date_status"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (r *DelayPolicyRow) setScope() {
+	switch {
+	case r.OrganizationID != nil:
+		r.Scope = delayPolicyScopeOrganization
+	case r.ProjectID != nil:
+		r.Scope = delayPolicyScopeProject
+	default:
+		r.Scope = delayPolicyScopeGlobal
+	}
+}
+
+// delayPolicySettings are the settings of a delay policy. A nil value inherits the
+// setting from the parent organization or the global default.
+type delayPolicySettings struct {
+	YellowDays      *int    `json:"yellow_days"        binding:"omitempty,min=0,max=365"`
+	NoDueDateStatus *string `json:"no_due_date_status" binding:"omitempty,oneof=RED YELLOW GREEN"`
+}
+
+type createDelayPolicyRequest struct {
+	OrganizationID *int64 `json:"organization_id"`
+	ProjectID      *int64 `json:"project_id"`
+	delayPolicySettings
+}
+
+// delayPolicyQuery is the shared SQL for fetching delay policies with their target names.
+const delayPolicyQuery = `
+	SELECT
+		dp.id,
+		dp.organization_id,
+		o.name AS organization_name,
+		dp.project_id,
+		p.key AS project_key,
+		dp.yellow_days,
+		dp.no_due_date_status,
+		dp.created_at,
+		dp.updated_at
+	FROM delay_policies dp
+	LEFT JOIN organizations o ON o.id = dp.organization_id
+	LEFT JOIN projects p ON p.id = dp.project_id
+`
+
+// fetchDelayPolicy returns the delay policy with the given ID.
+func fetchDelayPolicy(db *sqlx.DB, id int64) (DelayPolicyRow, error) {
+	var row DelayPolicyRow
+	if err := db.Get(&row, delayPolicyQuery+` WHERE dp.id = $1`, id); err != nil {
+		return row, err
+	}
+	row.setScope()
+	return row, nil
+}
+
+// listDelayPoliciesHandlerWithDB handles GET /api/v1/settings/delay-policies.
+// Returns the global default first, then organization policies in tree order,
+// then project policies.
+func listDelayPoliciesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies := make([]DelayPolicyRow, 0)
+		query := delayPolicyQuery + `
+			ORDER BY dp.project_id IS NOT NULL, dp.organization_id IS NOT NULL, o.path, p.key`
+		if err := db.Select(&policies, query); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch delay policies"})
+			return
+		}
+		for i := range policies {
+			policies[i].setScope()
+		}
+		c.JSON(http.StatusOK, gin.H{"data": policies})
+	}
+}
+
+// getDelayPolicyHandlerWithDB handles GET /api/v1/settings/delay-policies/:id.
+func getDelayPolicyHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delay policy id"})
+			return
+		}
+
+		policy, err := fetchDelayPolicy(db, id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "delay policy not found"})
+			return
+		}
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// createDelayPolicyHandlerWithDB handles POST /api/v1/settings/delay-policies.
+// Creates the policy of an organization or a project; exactly one of
+// organization_id and project_id must be given. The global default always
+// exists and can only be updated.
+func createDelayPolicyHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req createDelayPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if (req.OrganizationID == nil) == (req.ProjectID == nil) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of organization_id or project_id is required"})
+			return
+		}
+
+		// 対象の存在確認と、既存ポリシーの重複確認
+		var targetExists, policyExists bool
+		var err error
+		if req.OrganizationID != nil {
+			err = db.QueryRowx(
+				`SELECT EXISTS(SELECT 1 FROM organizations WHERE id = $1),
+				        EXISTS(SELECT 1 FROM delay_policies WHERE organization_id = $1)`,
+				*req.OrganizationID,
+			).Scan(&targetExists, &policyExists)
+		} else {
+			err = db.QueryRowx(
+				`SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NULL),
+				        EXISTS(SELECT 1 FROM delay_policies WHERE project_id = $1)`,
+				*req.ProjectID,
+			).Scan(&targetExists, &policyExists)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create delay policy"})
+			return
+		}
+		if !targetExists {
+			if req.OrganizationID != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "organization not found"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "project not found"})
+			}
+			return
+		}
+		if policyExists {
+			c.JSON(http.StatusConflict, gin.H{"error": "delay policy already exists for this target"})
+			return
+		}
+
+		var newID int64
+		err = db.QueryRowx(
+			`INSERT INTO delay_policies (organization_id, project_id, yellow_days, no_due_date_status)
+			 VALUES ($1, $2, $3, $4)
+			 RETURNING id`,
+			req.OrganizationID, req.ProjectID, req.YellowDays, req.NoDueDateStatus,
+		).Scan(&newID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create delay policy"})
+			return
+		}
+
+		policy, err := fetchDelayPolicy(db, newID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch created delay policy"})
+			return
+		}
+		c.JSON(http.StatusCreated, policy)
+	}
+}
+
+// updateDelayPolicyHandlerWithDB handles PUT /api/v1/settings/delay-policies/:id.
+// Replaces the settings of the policy; omitted settings are reset to inherit.
+// The target of a policy cannot be changed.
+func updateDelayPolicyHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delay policy id"})
+			return
+		}
+
+		var req delayPolicySettings
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := db.Exec(
+			`UPDATE delay_policies SET yellow_days = $1, no_due_date_status = $2 WHERE id = $3`,
+			req.YellowDays, req.NoDueDateStatus, id,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update delay policy"})
+			return
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "delay policy not found"})
+			return
+		}
+
+		policy, err := fetchDelayPolicy(db, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch updated delay policy"})
+			return
+		}
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// deleteDelayPolicyHandlerWithDB handles DELETE /api/v1/settings/delay-policies/:id.
+// The affected projects fall back to the inherited policy. The global default
+// cannot be deleted.
+func deleteDelayPolicyHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delay policy id"})
+			return
+		}
+
+		var global bool
+		err = db.QueryRowx(
+			`SELECT organization_id IS NULL AND project_id IS NULL FROM delay_policies WHERE id = $1`, id,
+		).Scan(&global)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "delay policy not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete delay policy"})
+			return
+		}
+		if global {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "the global delay policy cannot be deleted"})
+			return
+		}
+
+		if _, err := db.Exec(`DELETE FROM delay_policies WHERE id = $1`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete delay policy"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "delay policy deleted"})
+	}
+}
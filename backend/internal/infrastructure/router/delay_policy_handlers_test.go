@@ -0,0 +1,202 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var delayPolicyColumns = []string{
+	"id", "organization_id", "organization_name", "project_id", "project_key",
+	"yellow_days", "no_due_date_status", "created_at", "updated_at",
+}
+
+func newDelayPolicyContext(method, target, body string, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = params
+	return c, w
+}
+
+// --- listDelayPoliciesHandlerWithDB tests ---
+
+func TestListDelayPoliciesHandler_SetsScope(t *testing.T) {
+	db, mock := newTestDB(t)
+	now := time.Now()
+	rows := sqlmock.NewRows(delayPolicyColumns).
+		AddRow(1, nil, nil, nil, nil, 3, "YELLOW", now, now).
+		AddRow(2, 5, "Hardware", nil, nil, 7, nil, now, now).
+		AddRow(3, nil, nil, 9, "SUP", nil, "GREEN", now, now)
+	mock.ExpectQuery(`FROM delay_policies dp`).WillReturnRows(rows)
+
+	c, w := newDelayPolicyContext(http.MethodGet, "/settings/delay-policies", "", nil)
+	listDelayPoliciesHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string][]DelayPolicyRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp["data"], 3)
+	assert.Equal(t, "global", resp["data"][0].Scope)
+	assert.Equal(t, "organization", resp["data"][1].Scope)
+	assert.Equal(t, "project", resp["data"][2].Scope)
+	assert.Nil(t, resp["data"][1].NoDueDateStatus)
+}
+
+// --- createDelayPolicyHandlerWithDB tests ---
+
+func TestCreateDelayPolicyHandler_RequiresExactlyOneTarget(t *testing.T) {
+	db, _ := newTestDB(t)
+	for _, body := range []string{`{"yellow_days": 7}`, `{"organization_id": 1, "project_id": 2}`} {
+		c, w := newDelayPolicyContext(http.MethodPost, "/settings/delay-policies", body, nil)
+		createDelayPolicyHandlerWithDB(db)(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestCreateDelayPolicyHandler_InvalidSettings(t *testing.T) {
+	db, _ := newTestDB(t)
+	for _, body := range []string{
+		`{"organization_id": 1, "yellow_days": -1}`,
+		`{"organization_id": 1, "no_due_date_status": "BLUE"}`,
+	} {
+		c, w := newDelayPolicyContext(http.MethodPost, "/settings/delay-policies", body, nil)
+		createDelayPolicyHandlerWithDB(db)(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestCreateDelayPolicyHandler_OrganizationNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM organizations WHERE id`).
+		WithArgs(int64(99)).
+		WillReturnRows(sqlmock.NewRows([]string{"target", "policy"}).AddRow(false, false))
+
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/delay-policies", `{"organization_id": 99, "yellow_days": 7}`, nil)
+	createDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateDelayPolicyHandler_Duplicate(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM projects WHERE id`).
+		WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"target", "policy"}).AddRow(true, true))
+
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/delay-policies", `{"project_id": 9, "no_due_date_status": "GREEN"}`, nil)
+	createDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCreateDelayPolicyHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM organizations WHERE id`).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"target", "policy"}).AddRow(true, false))
+	mock.ExpectQuery(`INSERT INTO delay_policies`).
+		WithArgs(int64(5), nil, 7, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectQuery(`FROM delay_policies dp`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(delayPolicyColumns).AddRow(2, 5, "Hardware", nil, nil, 7, nil, now, now))
+
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/delay-policies", `{"organization_id": 5, "yellow_days": 7}`, nil)
+	createDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var resp DelayPolicyRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "organization", resp.Scope)
+	require.NotNil(t, resp.YellowDays)
+	assert.Equal(t, 7, *resp.YellowDays)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- updateDelayPolicyHandlerWithDB tests ---
+
+func TestUpdateDelayPolicyHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectExec(`UPDATE delay_policies`).
+		WithArgs(nil, "GREEN", int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/delay-policies/42", `{"no_due_date_status": "GREEN"}`, gin.Params{{Key: "id", Value: "42"}})
+	updateDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdateDelayPolicyHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	now := time.Now()
+	mock.ExpectExec(`UPDATE delay_policies`).
+		WithArgs(5, "RED", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM delay_policies dp`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(delayPolicyColumns).AddRow(1, nil, nil, nil, nil, 5, "RED", now, now))
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/delay-policies/1", `{"yellow_days": 5, "no_due_date_status": "RED"}`, gin.Params{{Key: "id", Value: "1"}})
+	updateDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp DelayPolicyRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "global", resp.Scope)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- deleteDelayPolicyHandlerWithDB tests ---
+
+func TestDeleteDelayPolicyHandler_GlobalRefused(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM delay_policies WHERE id`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"global"}).AddRow(true))
+
+	c, w := newDelayPolicyContext(http.MethodDelete, "/settings/delay-policies/1", "", gin.Params{{Key: "id", Value: "1"}})
+	deleteDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDelayPolicyHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM delay_policies WHERE id`).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"global"}))
+
+	c, w := newDelayPolicyContext(http.MethodDelete, "/settings/delay-policies/7", "", gin.Params{{Key: "id", Value: "7"}})
+	deleteDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeleteDelayPolicyHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM delay_policies WHERE id`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"global"}).AddRow(false))
+	mock.ExpectExec(`DELETE FROM delay_policies`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	c, w := newDelayPolicyContext(http.MethodDelete, "/settings/delay-policies/2", "", gin.Params{{Key: "id", Value: "2"}})
+	deleteDelayPolicyHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
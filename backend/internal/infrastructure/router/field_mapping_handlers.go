@@ -0,0 +1,149 @@
+package router
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+// jiraFieldIDPattern matches Jira field IDs such as "customfield_10015" or "labels".
+var jiraFieldIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,255}$`)
+
+// FieldMappingRow is the Jira field a canonical issue column is read from.
+// JiraFieldID is nil for columns that are not mapped.
+type FieldMappingRow struct {
+	Column      string     `db:"column_name" json:"column"`
+	JiraFieldID *string    `db:"jira_field_id" json:"jira_field_id"`
+	UpdatedAt   *time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type updateFieldMappingRequest struct {
+	JiraFieldID string `json:"jira_field_id" binding:"required"`
+}
+
+// listFieldMappingsHandlerWithDB handles GET /api/v1/settings/jira-connections/:id/field-mappings.
+// Returns every mappable column of the connection, including the unmapped ones.
+// Mappings are per connection because custom field IDs are assigned per site.
+func listFieldMappingsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		connectionID, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		if !jiraConnectionExists(c, db, connectionID) {
+			return
+		}
+
+		var mapped []FieldMappingRow
+		if err := db.Select(&mapped, `SELECT column_name, jira_field_id, updated_at FROM jira_field_mappings WHERE connection_id = $1`, connectionID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch field mappings"})
+			return
+		}
+		byColumn := make(map[string]FieldMappingRow, len(mapped))
+		for _, m := range mapped {
+			byColumn[m.Column] = m
+		}
+
+		mappings := make([]FieldMappingRow, len(normalizer.MappableColumns))
+		for i, column := range normalizer.MappableColumns {
+			mappings[i] = FieldMappingRow{Column: column}
+			if m, ok := byColumn[column]; ok {
+				mappings[i] = m
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"data": mappings})
+	}
+}
+
+// updateFieldMappingHandlerWithDB handles PUT /api/v1/settings/jira-connections/:id/field-mappings/:column.
+// Maps the column to the given Jira field of the connection's site. The mapping
+// takes effect from the next sync of the connection.
+func updateFieldMappingHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		connectionID, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		column := c.Param("column")
+		if !normalizer.IsMappableColumn(column) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown column: " + column})
+			return
+		}
+
+		var req updateFieldMappingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fieldID := strings.TrimSpace(req.JiraFieldID)
+		if !jiraFieldIDPattern.MatchString(fieldID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid jira_field_id"})
+			return
+		}
+		if !jiraConnectionExists(c, db, connectionID) {
+			return
+		}
+
+		var row FieldMappingRow
+		err := db.QueryRowx(`
+			INSERT INTO jira_field_mappings (connection_id, column_name, jira_field_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (connection_id, column_name) DO UPDATE SET jira_field_id = EXCLUDED.jira_field_id
+			RETURNING column_name, jira_field_id, updated_at`,
+			connectionID, column, fieldID,
+		).StructScan(&row)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update field mapping"})
+			return
+		}
+		c.JSON(http.StatusOK, row)
+	}
+}
+
+// deleteFieldMappingHandlerWithDB handles DELETE /api/v1/settings/jira-connections/:id/field-mappings/:column.
+// The column is left empty from the next sync of the connection.
+func deleteFieldMappingHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		connectionID, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		column := c.Param("column")
+		if !normalizer.IsMappableColumn(column) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown column: " + column})
+			return
+		}
+
+		result, err := db.Exec(`DELETE FROM jira_field_mappings WHERE connection_id = $1 AND column_name = $2`, connectionID, column)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete field mapping"})
+			return
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "field mapping not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "field mapping deleted"})
+	}
+}
+
+// jiraConnectionExists reports whether the Jira connection exists, writing a
+// 404 or 500 otherwise.
+func jiraConnectionExists(c *gin.Context, db *sqlx.DB, id int64) bool {
+	var exists bool
+	if err := db.Get(&exists, `SELECT EXISTS (SELECT 1 FROM jira_connections WHERE id = $1)`, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch jira connection"})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "jira connection not found"})
+		return false
+	}
+	return true
+}
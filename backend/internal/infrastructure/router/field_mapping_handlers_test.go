@@ -0,0 +1,127 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// --- listFieldMappingsHandlerWithDB tests ---
+
+// fieldMappingParams returns the path parameters of a field mapping of connection 1.
+func fieldMappingParams(column string) gin.Params {
+	return gin.Params{{Key: "id", Value: "1"}, {Key: "column", Value: column}}
+}
+
+// expectJiraConnectionExists expects the existence check of connection 1.
+func expectJiraConnectionExists(mock sqlmock.Sqlmock, exists bool) {
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM jira_connections WHERE id = \$1\)`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+func TestListFieldMappingsHandler_IncludesUnmappedColumns(t *testing.T) {
+	db, mock := newTestDB(t)
+	now := time.Now()
+	expectJiraConnectionExists(mock, true)
+	mock.ExpectQuery(`FROM jira_field_mappings WHERE connection_id = \$1`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"column_name", "jira_field_id", "updated_at"}).
+			AddRow("labels", "labels", now).
+			AddRow("start_date", "customfield_10015", now))
+
+	c, w := newDelayPolicyContext(http.MethodGet, "/settings/jira-connections/1/field-mappings", "", jiraConnectionParams)
+	listFieldMappingsHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp map[string][]FieldMappingRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	rows := resp["data"]
+	require.Len(t, rows, 6)
+	assert.Equal(t, "start_date", rows[0].Column)
+	require.NotNil(t, rows[0].JiraFieldID)
+	assert.Equal(t, "customfield_10015", *rows[0].JiraFieldID)
+	assert.Equal(t, "story_points", rows[1].Column)
+	assert.Nil(t, rows[1].JiraFieldID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListFieldMappingsHandler_ConnectionNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	expectJiraConnectionExists(mock, false)
+
+	c, w := newDelayPolicyContext(http.MethodGet, "/settings/jira-connections/1/field-mappings", "", jiraConnectionParams)
+	listFieldMappingsHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- updateFieldMappingHandlerWithDB tests ---
+
+func TestUpdateFieldMappingHandler_UnknownColumn(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/jira-connections/1/field-mappings/summary", `{"jira_field_id": "summary"}`,
+		fieldMappingParams("summary"))
+	updateFieldMappingHandlerWithDB(db)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateFieldMappingHandler_InvalidFieldID(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/jira-connections/1/field-mappings/start_date", `{"jira_field_id": "custom field"}`,
+		fieldMappingParams("start_date"))
+	updateFieldMappingHandlerWithDB(db)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdateFieldMappingHandler_Upserts(t *testing.T) {
+	db, mock := newTestDB(t)
+	expectJiraConnectionExists(mock, true)
+	mock.ExpectQuery(`INSERT INTO jira_field_mappings[\s\S]*ON CONFLICT \(connection_id, column_name\)`).
+		WithArgs(int64(1), "story_points", "customfield_10016").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name", "jira_field_id", "updated_at"}).
+			AddRow("story_points", "customfield_10016", time.Now()))
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/jira-connections/1/field-mappings/story_points", `{"jira_field_id": " customfield_10016 "}`,
+		fieldMappingParams("story_points"))
+	updateFieldMappingHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp FieldMappingRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "story_points", resp.Column)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateFieldMappingHandler_ConnectionNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	expectJiraConnectionExists(mock, false)
+
+	c, w := newDelayPolicyContext(http.MethodPut, "/settings/jira-connections/1/field-mappings/story_points", `{"jira_field_id": "customfield_10016"}`,
+		fieldMappingParams("story_points"))
+	updateFieldMappingHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- deleteFieldMappingHandlerWithDB tests ---
+
+func TestDeleteFieldMappingHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectExec(`DELETE FROM jira_field_mappings WHERE connection_id = \$1 AND column_name = \$2`).
+		WithArgs(int64(1), "epic_key").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	c, w := newDelayPolicyContext(http.MethodDelete, "/settings/jira-connections/1/field-mappings/epic_key", "",
+		fieldMappingParams("epic_key"))
+	deleteFieldMappingHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,11 +10,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // IssueRow represents a ticket with its project info.
 type IssueRow struct {
 	ID               int64      `db:"id" json:"id"`
+	ConnectionID     int64      `db:"connection_id" json:"connection_id"`
 	JiraIssueID      string     `db:"jira_issue_id" json:"jira_issue_id"`
 	JiraIssueKey     string     `db:"jira_issue_key" json:"jira_issue_key"`
 	ProjectID        int64      `db:"project_id" json:"project_id"`
@@ -24,6 +26,10 @@ type IssueRow struct {
 	Status           string     `db:"status" json:"status"`
 	StatusCategory   string     `db:"status_category" json:"status_category"`
 	DueDate          *string    `db:"due_date" json:"due_date"`
+	OriginalDueDate  *string    `db:"original_due_date" json:"original_due_date"`
+	PostponeCount    int        `db:"postpone_count" json:"postpone_count"`
+	Slipped          bool       `db:"slipped" json:"slipped"`
+	SlipDays         int        `db:"slip_days" json:"slip_days"`
 	AssigneeName     *string    `db:"assignee_name" json:"assignee_name"`
 	AssigneeAccountID *string   `db:"assignee_account_id" json:"assignee_account_id"`
 	DelayStatus      string     `db:"delay_status" json:"delay_status"`
@@ -32,8 +38,56 @@ type IssueRow struct {
 	LastUpdatedAt    time.Time  `db:"last_updated_at" json:"last_updated_at"`
 	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+	StartDate        *string    `db:"start_date" json:"start_date"`
+	StoryPoints      *float64   `db:"story_points" json:"story_points"`
+	EpicKey          *string    `db:"epic_key" json:"epic_key"`
+	SprintName       *string    `db:"sprint_name" json:"sprint_name"`
+	Labels           pq.StringArray `db:"labels" json:"labels"`
+	Components       pq.StringArray `db:"components" json:"components"`
+	ParentID         *int64     `db:"parent_id" json:"parent_id"`
+	ParentIssueKey   *string    `db:"parent_issue_key" json:"parent_issue_key"`
 }
 
+// issueQuery is the shared SQL for fetching issues with their project info.
+const issueQuery = `
+	SELECT
+		i.id,
+		i.connection_id,
+		i.jira_issue_id,
+		i.jira_issue_key,
+		i.project_id,
+		p.key  AS project_key,
+		p.name AS project_name,
+		i.summary,
+		i.status,
+		i.status_category,
+		TO_CHAR(i.due_date, 'YYYY-MM-DD') AS due_date,
+		TO_CHAR(i.original_due_date, 'YYYY-MM-DD') AS original_due_date,
+		i.postpone_count,
+		i.slipped,
+		COALESCE(GREATEST(i.due_date - i.original_due_date, 0), 0) AS slip_days,
+		i.assignee_name,
+		i.assignee_account_id,
+		i.delay_status,
+		i.priority,
+		i.issue_type,
+		i.last_updated_at,
+		i.created_at,
+		i.updated_at,
+		TO_CHAR(i.start_date, 'YYYY-MM-DD') AS start_date,
+		i.story_points,
+		i.epic_key,
+		i.sprint_name,
+		i.labels,
+		i.components,
+		(SELECT pi.id FROM issues pi
+			WHERE pi.connection_id = i.connection_id AND pi.jira_issue_id = i.parent_jira_issue_id
+				AND pi.deleted_at IS NULL) AS parent_id,
+		i.parent_issue_key
+	FROM issues i
+	JOIN projects p ON i.project_id = p.id
+`
+
 // IssueListResponse is the response body for GET /issues.
 type IssueListResponse struct {
 	Data       []IssueRow     `json:"data"`
@@ -62,7 +116,8 @@ func listIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		}
 
 		// --- Build WHERE conditions ---
-		var conditions []string
+		// 論理削除済みのチケット・プロジェクトは常に除外する
+		conditions := []string{"i.deleted_at IS NULL", "p.deleted_at IS NULL"}
 		var args []interface{}
 		idx := 1
 
@@ -92,11 +147,10 @@ func listIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			args = append(args, "%"+assigneeName+"%")
 			idx++
 		}
+		conditions, args, idx = appendSlipConditions(c, conditions, args, idx)
+		conditions, args, idx = appendCustomFieldConditions(c, conditions, args, idx)
 
-		whereClause := ""
-		if len(conditions) > 0 {
-			whereClause = "WHERE " + strings.Join(conditions, " AND ")
-		}
+		whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 		// --- ORDER BY clause ---
 		validSortCols := map[string]string{
@@ -104,6 +158,8 @@ func listIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			"last_updated_at": "i.last_updated_at",
 			"jira_issue_key":  "i.jira_issue_key",
 			"delay_status":    "i.delay_status",
+			"start_date":      "i.start_date",
+			"story_points":    "i.story_points",
 		}
 		sortCol, ok := validSortCols[sortParam]
 		if !ok {
@@ -136,28 +192,7 @@ func listIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 		// --- Main data query ---
 		offset := (page - 1) * perPage
-		mainQuery := fmt.Sprintf(`
-			SELECT
-				i.id,
-				i.jira_issue_id,
-				i.jira_issue_key,
-				i.project_id,
-				p.key  AS project_key,
-				p.name AS project_name,
-				i.summary,
-				i.status,
-				i.status_category,
-				TO_CHAR(i.due_date, 'YYYY-MM-DD') AS due_date,
-				i.assignee_name,
-				i.assignee_account_id,
-				i.delay_status,
-				i.priority,
-				i.issue_type,
-				i.last_updated_at,
-				i.created_at,
-				i.updated_at
-			FROM issues i
-			JOIN projects p ON i.project_id = p.id
+		mainQuery := issueQuery + fmt.Sprintf(`
 			%s
 			ORDER BY %s
 			LIMIT $%d OFFSET $%d
@@ -188,6 +223,68 @@ func listIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 	}
 }
 
+// appendSlipConditions adds the due date slip filters shared by the issue list
+// endpoints: slipped=true|false and min_slip_days=N (due date moved at least N
+// days later than the original due date). Invalid values are ignored.
+func appendSlipConditions(c *gin.Context, conditions []string, args []interface{}, idx int) ([]string, []interface{}, int) {
+	switch c.Query("slipped") {
+	case "true":
+		conditions = append(conditions, "i.slipped")
+	case "false":
+		conditions = append(conditions, "NOT i.slipped")
+	}
+	if minSlipDays, err := strconv.Atoi(c.Query("min_slip_days")); err == nil && minSlipDays > 0 {
+		conditions = append(conditions, fmt.Sprintf("i.due_date - i.original_due_date >= $%d", idx))
+		args = append(args, minSlipDays)
+		idx++
+	}
+	return conditions, args, idx
+}
+
+// appendCustomFieldConditions adds the filters on the columns filled from custom
+// Jira fields: epic_key, sprint_name, label and component (exact match),
+// start_date_from / start_date_to ("YYYY-MM-DD", inclusive) and
+// min_story_points / max_story_points. Invalid values are ignored.
+func appendCustomFieldConditions(c *gin.Context, conditions []string, args []interface{}, idx int) ([]string, []interface{}, int) {
+	filters := []struct {
+		param string
+		cond  string
+		parse func(string) (interface{}, bool)
+	}{
+		{"epic_key", "i.epic_key = $%d", parseNonEmpty},
+		{"sprint_name", "i.sprint_name = $%d", parseNonEmpty},
+		// GIN インデックスを使うため ANY ではなく @> で絞り込む
+		{"label", "i.labels @> ARRAY[$%d::text]", parseNonEmpty},
+		{"component", "i.components @> ARRAY[$%d::text]", parseNonEmpty},
+		{"start_date_from", "i.start_date >= $%d", parseDate},
+		{"start_date_to", "i.start_date <= $%d", parseDate},
+		{"min_story_points", "i.story_points >= $%d", parseNumber},
+		{"max_story_points", "i.story_points <= $%d", parseNumber},
+	}
+	for _, f := range filters {
+		if v, ok := f.parse(c.Query(f.param)); ok {
+			conditions = append(conditions, fmt.Sprintf(f.cond, idx))
+			args = append(args, v)
+			idx++
+		}
+	}
+	return conditions, args, idx
+}
+
+func parseNonEmpty(s string) (interface{}, bool) {
+	return s, s != ""
+}
+
+func parseDate(s string) (interface{}, bool) {
+	_, err := time.Parse("2006-01-02", s)
+	return s, err == nil
+}
+
+func parseNumber(s string) (interface{}, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
 // listProjectIssuesHandlerWithDB returns a Gin handler for listing issues of a specific project.
 // It accepts the same query parameters as listIssuesHandlerWithDB, but the project_id is fixed
 // to the path parameter :id.
@@ -218,7 +315,7 @@ func listProjectIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		}
 
 		// --- Build WHERE conditions (project_id is always required) ---
-		conditions := []string{"i.project_id = $1"}
+		conditions := []string{"i.project_id = $1", "i.deleted_at IS NULL", "p.deleted_at IS NULL"}
 		args := []interface{}{projectID}
 		idx := 2
 
@@ -240,6 +337,8 @@ func listProjectIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			args = append(args, "%"+assigneeName+"%")
 			idx++
 		}
+		conditions, args, idx = appendSlipConditions(c, conditions, args, idx)
+		conditions, args, idx = appendCustomFieldConditions(c, conditions, args, idx)
 
 		whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
@@ -249,6 +348,8 @@ func listProjectIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			"last_updated_at": "i.last_updated_at",
 			"jira_issue_key":  "i.jira_issue_key",
 			"delay_status":    "i.delay_status",
+			"start_date":      "i.start_date",
+			"story_points":    "i.story_points",
 		}
 		sortCol, ok := validSortCols[sortParam]
 		if !ok {
@@ -280,28 +381,7 @@ func listProjectIssuesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 		// --- Main data query ---
 		offset := (page - 1) * perPage
-		mainQuery := fmt.Sprintf(`
-			SELECT
-				i.id,
-				i.jira_issue_id,
-				i.jira_issue_key,
-				i.project_id,
-				p.key  AS project_key,
-				p.name AS project_name,
-				i.summary,
-				i.status,
-				i.status_category,
-				TO_CHAR(i.due_date, 'YYYY-MM-DD') AS due_date,
-				i.assignee_name,
-				i.assignee_account_id,
-				i.delay_status,
-				i.priority,
-				i.issue_type,
-				i.last_updated_at,
-				i.created_at,
-				i.updated_at
-			FROM issues i
-			JOIN projects p ON i.project_id = p.id
+		mainQuery := issueQuery + fmt.Sprintf(`
 			%s
 			ORDER BY %s
 			LIMIT $%d OFFSET $%d
@@ -342,29 +422,8 @@ func getIssueHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			return
 		}
 
-		query := `
-			SELECT
-				i.id,
-				i.jira_issue_id,
-				i.jira_issue_key,
-				i.project_id,
-				p.key  AS project_key,
-				p.name AS project_name,
-				i.summary,
-				i.status,
-				i.status_category,
-				TO_CHAR(i.due_date, 'YYYY-MM-DD') AS due_date,
-				i.assignee_name,
-				i.assignee_account_id,
-				i.delay_status,
-				i.priority,
-				i.issue_type,
-				i.last_updated_at,
-				i.created_at,
-				i.updated_at
-			FROM issues i
-			JOIN projects p ON i.project_id = p.id
-			WHERE i.id = $1
+		query := issueQuery + `
+			WHERE i.id = $1 AND i.deleted_at IS NULL AND p.deleted_at IS NULL
 		`
 
 		var issue IssueRow
@@ -376,3 +435,64 @@ func getIssueHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		c.JSON(http.StatusOK, issue)
 	}
 }
+
+// IssueHistoryRow is a status or due date transition of an issue.
+type IssueHistoryRow struct {
+	ID              int64     `db:"id" json:"id"`
+	Field           string    `db:"field" json:"field"`
+	FromValue       *string   `db:"from_value" json:"from_value"`
+	ToValue         *string   `db:"to_value" json:"to_value"`
+	AuthorAccountID *string   `db:"author_account_id" json:"author_account_id"`
+	AuthorName      *string   `db:"author_name" json:"author_name"`
+	ChangedAt       time.Time `db:"changed_at" json:"changed_at"`
+}
+
+// getIssueHistoryHandlerWithDB handles GET /api/v1/issues/:id/history.
+// Returns the status/due date transitions of the issue in chronological order.
+// The optional field query parameter ("status" or "duedate") narrows the result.
+func getIssueHistoryHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issue id"})
+			return
+		}
+
+		field := c.Query("field")
+		if field != "" && field != "status" && field != "duedate" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "field must be status or duedate"})
+			return
+		}
+
+		var exists bool
+		err = db.Get(&exists, `
+			SELECT EXISTS (
+				SELECT 1 FROM issues i
+				JOIN projects p ON i.project_id = p.id
+				WHERE i.id = $1 AND i.deleted_at IS NULL AND p.deleted_at IS NULL
+			)`, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue"})
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "issue not found"})
+			return
+		}
+
+		history := make([]IssueHistoryRow, 0)
+		err = db.Select(&history, `
+			SELECT id, field, from_value, to_value, author_account_id, author_name, changed_at
+			FROM issue_history
+			WHERE issue_id = $1 AND ($2 = '' OR field = $2)
+			ORDER BY changed_at, id`,
+			id, field,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": history})
+	}
+}
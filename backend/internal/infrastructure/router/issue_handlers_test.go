@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
@@ -78,6 +79,100 @@ func TestListIssuesHandler_DefaultParams(t *testing.T) {
 	assert.Equal(t, 25, resp.Pagination.PerPage)
 }
 
+func TestListIssuesHandler_SlipFilters(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := listIssuesHandlerWithDB(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\).*i\.slipped AND i\.due_date - i\.original_due_date >= \$1`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT`).
+		WithArgs(7, 25, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "jira_issue_id", "jira_issue_key", "project_id",
+			"project_key", "project_name", "summary", "status",
+			"status_category", "due_date", "original_due_date", "postpone_count", "slipped", "slip_days",
+			"delay_status", "last_updated_at", "created_at", "updated_at",
+		}).AddRow(1, "10001", "PROJ-1", 1, "PROJ", "Project", "Fix bug", "In Progress",
+			"In Progress", "2026-03-20", "2026-03-01", 2, true, 19,
+			"YELLOW", time.Now(), time.Now(), time.Now()))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/issues?slipped=true&min_slip_days=7", nil)
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IssueListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.True(t, resp.Data[0].Slipped)
+	assert.Equal(t, 19, resp.Data[0].SlipDays)
+	assert.Equal(t, 2, resp.Data[0].PostponeCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListIssuesHandler_InvalidMinSlipDaysIgnored(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := listIssuesHandlerWithDB(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+		WithArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT`).
+		WithArgs(25, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/issues?min_slip_days=abc", nil)
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListIssuesHandler_CustomFieldFilters(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := listIssuesHandlerWithDB(db)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\).*i\.epic_key = \$1 AND i\.labels @> ARRAY\[\$2::text\] AND i\.start_date >= \$3 AND i\.story_points >= \$4`).
+		WithArgs("PROJ-10", "backend", "2026-03-01", 3.0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT.*ORDER BY i\.story_points DESC`).
+		WithArgs("PROJ-10", "backend", "2026-03-01", 3.0, 25, 0).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "jira_issue_id", "jira_issue_key", "project_id",
+			"project_key", "project_name", "summary", "status",
+			"status_category", "delay_status", "last_updated_at", "created_at", "updated_at",
+			"start_date", "story_points", "epic_key", "sprint_name", "labels", "components",
+		}).AddRow(1, "10001", "PROJ-1", 1, "PROJ", "Project", "Fix bug", "In Progress",
+			"In Progress", "GREEN", time.Now(), time.Now(), time.Now(),
+			"2026-03-02", "5.00", "PROJ-10", "Sprint 2", "{backend,urgent}", "{}"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	// start_date_to と max_story_points は不正な値のため無視される
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/issues?epic_key=PROJ-10&label=backend&start_date_from=2026-03-01&start_date_to=3/31&min_story_points=3&max_story_points=x&sort=story_points&order=desc", nil)
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp IssueListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	issue := resp.Data[0]
+	require.NotNil(t, issue.StoryPoints)
+	assert.Equal(t, 5.0, *issue.StoryPoints)
+	assert.Equal(t, "Sprint 2", *issue.SprintName)
+	assert.Equal(t, []string{"backend", "urgent"}, []string(issue.Labels))
+	assert.Empty(t, issue.Components)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestListProjectIssuesHandler_InvalidID(t *testing.T) {
 	db, _ := newTestDB(t)
 	handler := listProjectIssuesHandlerWithDB(db)
@@ -185,3 +280,80 @@ func TestListProjectIssuesHandler_DBError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+// --- getIssueHistoryHandlerWithDB tests ---
+
+func TestGetIssueHistoryHandler_InvalidID(t *testing.T) {
+	db, _ := newTestDB(t)
+	handler := getIssueHistoryHandlerWithDB(db)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/issues/abc/history", nil)
+	c.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetIssueHistoryHandler_InvalidField(t *testing.T) {
+	db, _ := newTestDB(t)
+	handler := getIssueHistoryHandlerWithDB(db)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/issues/1/history?field=summary", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetIssueHistoryHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := getIssueHistoryHandlerWithDB(db)
+
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/issues/999/history", nil)
+	c.Params = gin.Params{{Key: "id", Value: "999"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIssueHistoryHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := getIssueHistoryHandlerWithDB(db)
+
+	changedAt := time.Date(2026, 2, 10, 0, 30, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM issue_history`).WithArgs(int64(1), "duedate").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "field", "from_value", "to_value", "author_account_id", "author_name", "changed_at"}).
+			AddRow(1, "duedate", "2026-02-20", "2026-03-01", "acc-1", "Alice", changedAt))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/issues/1/history?field=duedate", nil)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []IssueHistoryRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "duedate", resp.Data[0].Field)
+	assert.Equal(t, "2026-03-01", *resp.Data[0].ToValue)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
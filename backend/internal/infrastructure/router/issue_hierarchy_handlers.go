@@ -0,0 +1,307 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// maxHierarchyDepth bounds the descendant search of the roll-up, so that a
+// cyclic parent chain in Jira data cannot make the query run forever.
+const maxHierarchyDepth = 5
+
+// childIssueCondition matches the direct children of an issue given its
+// jira_issue_id ($1), jira_issue_key ($2) and Jira connection ($3): issues whose
+// parent is the issue, and issues without a parent that link to it as their epic
+// (Epic Link field).
+const childIssueCondition = `
+	i.connection_id = $3
+	AND (i.parent_jira_issue_id = $1 OR (i.parent_jira_issue_id IS NULL AND i.epic_key = $2))
+	AND i.deleted_at IS NULL AND p.deleted_at IS NULL`
+
+// epicCondition matches epics: issues of the Epic type, and issues that other
+// issues refer to through the Epic Link field.
+const epicCondition = `
+	(LOWER(i.issue_type) IN ('epic', 'エピック') OR EXISTS (
+		SELECT 1 FROM issues c
+		WHERE c.connection_id = i.connection_id AND c.epic_key = i.jira_issue_key AND c.deleted_at IS NULL
+	))`
+
+// issueRollupQuery counts the descendants (children, grandchildren, ...) of the
+// issues with the IDs in $1, grouped by issue.
+var issueRollupQuery = fmt.Sprintf(`
+	WITH RECURSIVE tree AS (
+		SELECT r.id AS root_id, c.id, c.connection_id, c.jira_issue_id, 1 AS depth
+		FROM issues r
+		JOIN issues c ON c.connection_id = r.connection_id
+			AND (c.parent_jira_issue_id = r.jira_issue_id
+				OR (c.parent_jira_issue_id IS NULL AND c.epic_key = r.jira_issue_key))
+		WHERE r.id = ANY($1) AND c.deleted_at IS NULL
+		UNION
+		SELECT t.root_id, c.id, c.connection_id, c.jira_issue_id, t.depth + 1
+		FROM tree t
+		JOIN issues c ON c.connection_id = t.connection_id AND c.parent_jira_issue_id = t.jira_issue_id
+		WHERE c.deleted_at IS NULL AND t.depth < %d
+	), descendants AS (
+		SELECT DISTINCT root_id, id FROM tree
+	)
+	SELECT
+		d.root_id,
+		COUNT(*)                                                                 AS total_count,
+		COUNT(*) FILTER (WHERE i.status_category = 'Done')                       AS done_count,
+		COUNT(*) FILTER (WHERE i.status_category <> 'Done' AND i.delay_status = 'RED')    AS red_count,
+		COUNT(*) FILTER (WHERE i.status_category <> 'Done' AND i.delay_status = 'YELLOW') AS yellow_count,
+		COUNT(*) FILTER (WHERE i.status_category <> 'Done' AND i.delay_status = 'GREEN')  AS green_count
+	FROM descendants d
+	JOIN issues i ON i.id = d.id
+	GROUP BY d.root_id`, maxHierarchyDepth)
+
+// IssueRollup is the progress and effective delay status of an issue derived
+// from all of its descendants.
+type IssueRollup struct {
+	TotalCount  int `db:"total_count" json:"total_count"`
+	DoneCount   int `db:"done_count" json:"done_count"`
+	RedCount    int `db:"red_count" json:"red_count"`       // 未完了の RED の子孫チケット数
+	YellowCount int `db:"yellow_count" json:"yellow_count"` // 未完了の YELLOW の子孫チケット数
+	GreenCount  int `db:"green_count" json:"green_count"`   // 未完了の GREEN の子孫チケット数
+	// ProgressPercent is the percentage of descendants that are done. An issue
+	// without descendants is 100 when it is done itself, else 0.
+	ProgressPercent int `json:"progress_percent"`
+	// DelayStatus is the worst of the issue's own delay status and those of its
+	// open descendants.
+	DelayStatus string `json:"delay_status"`
+}
+
+// EpicRow is an epic with the roll-up of its descendants.
+type EpicRow struct {
+	IssueRow
+	Rollup IssueRollup `json:"rollup"`
+}
+
+// EpicListResponse is the response body for GET /epics.
+type EpicListResponse struct {
+	Data       []EpicRow      `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// IssueLinkRow is a link from an issue to another issue.
+type IssueLinkRow struct {
+	ID             int64   `db:"id" json:"id"`
+	LinkType       string  `db:"link_type" json:"link_type"`
+	Direction      string  `db:"direction" json:"direction"`
+	Description    *string `db:"description" json:"description"`
+	LinkedIssueKey string  `db:"linked_issue_key" json:"linked_issue_key"`
+	// LinkedIssueID is the ID of the linked issue, nil when it is not synced.
+	LinkedIssueID *int64 `db:"linked_issue_id" json:"linked_issue_id"`
+}
+
+// fetchRollups returns the roll-up of each of the given issues keyed by issue ID.
+func fetchRollups(db *sqlx.DB, issues []IssueRow) (map[int64]IssueRollup, error) {
+	ids := make([]int64, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+
+	rows, err := db.Queryx(issueRollupQuery, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]IssueRollup)
+	for rows.Next() {
+		var r struct {
+			RootID int64 `db:"root_id"`
+			IssueRollup
+		}
+		if err := rows.StructScan(&r); err != nil {
+			return nil, err
+		}
+		counts[r.RootID] = r.IssueRollup
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rollups := make(map[int64]IssueRollup, len(issues))
+	for _, issue := range issues {
+		rollups[issue.ID] = completeRollup(issue, counts[issue.ID])
+	}
+	return rollups, nil
+}
+
+// completeRollup derives the progress and effective delay status of issue from
+// the counts of its descendants.
+func completeRollup(issue IssueRow, r IssueRollup) IssueRollup {
+	switch {
+	case r.TotalCount > 0:
+		r.ProgressPercent = int(math.Round(float64(r.DoneCount) * 100 / float64(r.TotalCount)))
+	case issue.StatusCategory == "Done":
+		r.ProgressPercent = 100
+	}
+
+	switch {
+	case issue.DelayStatus == "RED" || r.RedCount > 0:
+		r.DelayStatus = "RED"
+	case issue.DelayStatus == "YELLOW" || r.YellowCount > 0:
+		r.DelayStatus = "YELLOW"
+	default:
+		r.DelayStatus = "GREEN"
+	}
+	return r
+}
+
+// getIssueChildrenHandlerWithDB handles GET /api/v1/issues/:id/children.
+// Returns the direct children of the issue (subtasks, or the issues of an epic)
+// and the roll-up of all of its descendants.
+func getIssueChildrenHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issue id"})
+			return
+		}
+
+		var issue IssueRow
+		if err := db.Get(&issue, issueQuery+` WHERE i.id = $1 AND i.deleted_at IS NULL AND p.deleted_at IS NULL`, id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "issue not found"})
+			return
+		}
+
+		children := make([]IssueRow, 0)
+		query := issueQuery + ` WHERE ` + childIssueCondition + ` ORDER BY i.jira_issue_key`
+		if err := db.Select(&children, query, issue.JiraIssueID, issue.JiraIssueKey, issue.ConnectionID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch child issues"})
+			return
+		}
+
+		rollups, err := fetchRollups(db, []IssueRow{issue})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue roll-up"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": children, "rollup": rollups[issue.ID]})
+	}
+}
+
+// getIssueLinksHandlerWithDB handles GET /api/v1/issues/:id/links.
+// Returns the issue links of the issue ("blocks", "is blocked by", ...).
+func getIssueLinksHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issue id"})
+			return
+		}
+
+		var exists bool
+		err = db.Get(&exists, `
+			SELECT EXISTS (
+				SELECT 1 FROM issues i
+				JOIN projects p ON i.project_id = p.id
+				WHERE i.id = $1 AND i.deleted_at IS NULL AND p.deleted_at IS NULL
+			)`, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue"})
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "issue not found"})
+			return
+		}
+
+		links := make([]IssueLinkRow, 0)
+		err = db.Select(&links, `
+			SELECT l.id, l.link_type, l.direction, l.description, l.linked_issue_key, li.id AS linked_issue_id
+			FROM issue_links l
+			JOIN issues i ON i.id = l.issue_id
+			LEFT JOIN issues li ON li.connection_id = i.connection_id
+				AND li.jira_issue_id = l.linked_jira_issue_id AND li.deleted_at IS NULL
+			WHERE l.issue_id = $1
+			ORDER BY l.link_type, l.direction, l.linked_issue_key`,
+			id,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch issue links"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": links})
+	}
+}
+
+// listEpicsHandlerWithDB handles GET /api/v1/epics.
+// Returns the epics with the roll-up of their descendants, optionally narrowed
+// to one project (project_id), ordered by due date.
+func listEpicsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			page = 1
+		}
+		perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "25"))
+		if err != nil || perPage < 1 || perPage > 100 {
+			perPage = 25
+		}
+
+		conditions := []string{"i.deleted_at IS NULL", "p.deleted_at IS NULL", epicCondition}
+		var args []interface{}
+		idx := 1
+		if pid, err := strconv.ParseInt(c.Query("project_id"), 10, 64); err == nil {
+			conditions = append(conditions, fmt.Sprintf("i.project_id = $%d", idx))
+			args = append(args, pid)
+			idx++
+		}
+		whereClause := "WHERE " + strings.Join(conditions, " AND ")
+
+		var total int
+		countQuery := `SELECT COUNT(*) FROM issues i JOIN projects p ON i.project_id = p.id ` + whereClause
+		if err := db.QueryRowx(countQuery, args...).Scan(&total); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count epics"})
+			return
+		}
+
+		epics := make([]IssueRow, 0)
+		mainQuery := issueQuery + fmt.Sprintf(`
+			%s
+			ORDER BY i.due_date ASC NULLS LAST, i.jira_issue_key
+			LIMIT $%d OFFSET $%d
+		`, whereClause, idx, idx+1)
+		if err := db.Select(&epics, mainQuery, append(args, perPage, (page-1)*perPage)...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch epics"})
+			return
+		}
+
+		rollups, err := fetchRollups(db, epics)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch epic roll-ups"})
+			return
+		}
+		data := make([]EpicRow, len(epics))
+		for i, epic := range epics {
+			data[i] = EpicRow{IssueRow: epic, Rollup: rollups[epic.ID]}
+		}
+
+		totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+		if totalPages == 0 {
+			totalPages = 1
+		}
+
+		c.JSON(http.StatusOK, EpicListResponse{
+			Data: data,
+			Pagination: PaginationMeta{
+				Page:       page,
+				PerPage:    perPage,
+				Total:      total,
+				TotalPages: totalPages,
+			},
+		})
+	}
+}
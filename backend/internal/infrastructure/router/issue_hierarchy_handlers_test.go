@@ -0,0 +1,164 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hierarchyIssueColumns = []string{
+	"id", "jira_issue_id", "jira_issue_key", "project_id", "summary",
+	"status_category", "delay_status", "parent_id", "parent_issue_key", "connection_id",
+}
+
+var rollupColumns = []string{
+	"root_id", "total_count", "done_count", "red_count", "yellow_count", "green_count",
+}
+
+func TestCompleteRollup(t *testing.T) {
+	tests := []struct {
+		name         string
+		issue        IssueRow
+		counts       IssueRollup
+		wantProgress int
+		wantStatus   string
+	}{
+		{"progress from children", IssueRow{DelayStatus: "GREEN"}, IssueRollup{TotalCount: 3, DoneCount: 2, GreenCount: 1}, 67, "GREEN"},
+		{"red child makes epic red", IssueRow{DelayStatus: "GREEN"}, IssueRollup{TotalCount: 2, RedCount: 1, YellowCount: 1}, 0, "RED"},
+		{"yellow child makes epic yellow", IssueRow{DelayStatus: "GREEN"}, IssueRollup{TotalCount: 2, DoneCount: 1, YellowCount: 1}, 50, "YELLOW"},
+		{"own status is kept when worse", IssueRow{DelayStatus: "RED"}, IssueRollup{TotalCount: 1, GreenCount: 1}, 0, "RED"},
+		{"done issue without children", IssueRow{DelayStatus: "GREEN", StatusCategory: "Done"}, IssueRollup{}, 100, "GREEN"},
+		{"open issue without children", IssueRow{DelayStatus: "YELLOW", StatusCategory: "To Do"}, IssueRollup{}, 0, "YELLOW"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := completeRollup(tt.issue, tt.counts)
+			assert.Equal(t, tt.wantProgress, got.ProgressPercent)
+			assert.Equal(t, tt.wantStatus, got.DelayStatus)
+		})
+	}
+}
+
+// --- getIssueChildrenHandlerWithDB tests ---
+
+func TestGetIssueChildrenHandler_InvalidID(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodGet, "/issues/abc/children", "", gin.Params{{Key: "id", Value: "abc"}})
+
+	getIssueChildrenHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetIssueChildrenHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT`).WithArgs(int64(999)).
+		WillReturnRows(sqlmock.NewRows(hierarchyIssueColumns))
+	c, w := newDelayPolicyContext(http.MethodGet, "/issues/999/children", "", gin.Params{{Key: "id", Value: "999"}})
+
+	getIssueChildrenHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetIssueChildrenHandler_ReturnsChildrenAndRollup(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT`).WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows(hierarchyIssueColumns).
+			AddRow(10, "10010", "PROJ-10", 1, "Epic", "In Progress", "GREEN", nil, nil, 2))
+	mock.ExpectQuery(`i\.connection_id = \$3\s+AND \(i\.parent_jira_issue_id = \$1 OR \(i\.parent_jira_issue_id IS NULL AND i\.epic_key = \$2\)\)`).
+		WithArgs("10010", "PROJ-10", int64(2)).
+		WillReturnRows(sqlmock.NewRows(hierarchyIssueColumns).
+			AddRow(11, "10011", "PROJ-11", 1, "Story", "Done", "GREEN", 10, "PROJ-10", 2).
+			AddRow(12, "10012", "PROJ-12", 1, "Story", "To Do", "RED", 10, "PROJ-10", 2))
+	mock.ExpectQuery(`WITH RECURSIVE tree`).
+		WillReturnRows(sqlmock.NewRows(rollupColumns).AddRow(10, 3, 1, 1, 0, 1))
+	c, w := newDelayPolicyContext(http.MethodGet, "/issues/10/children", "", gin.Params{{Key: "id", Value: "10"}})
+
+	getIssueChildrenHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data   []IssueRow  `json:"data"`
+		Rollup IssueRollup `json:"rollup"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "PROJ-11", resp.Data[0].JiraIssueKey)
+	require.NotNil(t, resp.Data[0].ParentID)
+	assert.Equal(t, int64(10), *resp.Data[0].ParentID)
+	assert.Equal(t, 3, resp.Rollup.TotalCount)
+	assert.Equal(t, 33, resp.Rollup.ProgressPercent)
+	assert.Equal(t, "RED", resp.Rollup.DelayStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- getIssueLinksHandlerWithDB tests ---
+
+func TestGetIssueLinksHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	c, w := newDelayPolicyContext(http.MethodGet, "/issues/999/links", "", gin.Params{{Key: "id", Value: "999"}})
+
+	getIssueLinksHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetIssueLinksHandler_ReturnsLinks(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(11)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM issue_links l`).WithArgs(int64(11)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "link_type", "direction", "description", "linked_issue_key", "linked_issue_id"}).
+			AddRow(1, "Blocks", "outward", "blocks", "PROJ-12", 12).
+			AddRow(2, "Blocks", "inward", "is blocked by", "OTHER-1", nil))
+	c, w := newDelayPolicyContext(http.MethodGet, "/issues/11/links", "", gin.Params{{Key: "id", Value: "11"}})
+
+	getIssueLinksHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []IssueLinkRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 2)
+	require.NotNil(t, resp.Data[0].LinkedIssueID)
+	assert.Equal(t, int64(12), *resp.Data[0].LinkedIssueID)
+	assert.Nil(t, resp.Data[1].LinkedIssueID)
+}
+
+// --- listEpicsHandlerWithDB tests ---
+
+func TestListEpicsHandler_WithRollup(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`LOWER\(i\.issue_type\) IN \('epic', 'エピック'\)`).WithArgs(int64(1), 25, 0).
+		WillReturnRows(sqlmock.NewRows(hierarchyIssueColumns).
+			AddRow(10, "10010", "PROJ-10", 1, "Epic A", "In Progress", "GREEN", nil, nil, 2).
+			AddRow(20, "10020", "PROJ-20", 1, "Epic B", "To Do", "GREEN", nil, nil, 2))
+	mock.ExpectQuery(`WITH RECURSIVE tree`).
+		WillReturnRows(sqlmock.NewRows(rollupColumns).AddRow(10, 4, 4, 0, 0, 0))
+	c, w := newDelayPolicyContext(http.MethodGet, "/epics?project_id=1", "", nil)
+
+	listEpicsHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp EpicListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Pagination.Total)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, 100, resp.Data[0].Rollup.ProgressPercent)
+	assert.Equal(t, "GREEN", resp.Data[0].Rollup.DelayStatus)
+	// 子チケットのないエピックは件数 0
+	assert.Equal(t, 0, resp.Data[1].Rollup.TotalCount)
+	assert.Equal(t, 0, resp.Data[1].Rollup.ProgressPercent)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
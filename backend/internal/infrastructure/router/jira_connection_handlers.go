@@ -0,0 +1,641 @@
+package router
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+// manualSyncTimeout bounds a sync started from the admin UI so that a hung Jira
+// request cannot keep the sync RUNNING (and block further syncs) forever.
+const manualSyncTimeout = 2 * time.Hour
+
+// JiraConnectionRow is a connection to a Jira site with the number of projects
+// synced from it. The API token is stored encrypted and never serialised.
+//
+// CredentialSource is the source syncs take the credentials from ("env",
+// "file" or "db"; empty when none has them). When it is not "db", the stored
+// credentials are shadowed by the environment or the credentials file.
+//
+// OAuthConnected reports whether OAuth tokens are stored for the connection;
+// CloudID is set once the consent flow has completed.
+//
+// APIFlavor is the REST API of the site: "cloud" (v3) or "datacenter" (v2, for
+// Jira Data Center / Server).
+//
+// WebhookSecretSet reports whether a webhook secret is stored; the secret itself
+// is never returned.
+type JiraConnectionRow struct {
+	ID               int64     `db:"id" json:"id"`
+	Name             string    `db:"name" json:"name"`
+	BaseURL          string    `db:"base_url" json:"base_url"`
+	APIFlavor        string    `db:"api_flavor" json:"api_flavor"`
+	AuthType         string    `db:"auth_type" json:"auth_type"`
+	Email            string    `db:"email" json:"email"`
+	APIToken         string    `db:"api_token" json:"-"`
+	APITokenMask     string    `json:"api_token_mask"` // "•••••<last4>"
+	CloudID          string    `db:"cloud_id" json:"cloud_id"`
+	OAuthConnected   bool      `db:"oauth_connected" json:"oauth_connected"`
+	WebhookSecretSet bool      `db:"webhook_secret_set" json:"webhook_secret_set"`
+	CredentialSource string    `json:"credential_source"`
+	Enabled          bool      `db:"enabled" json:"enabled"`
+	ProjectCount     int       `db:"project_count" json:"project_count"`
+	CreatedAt        time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type jiraConnectionRequest struct {
+	Name    string `json:"name"     binding:"required,max=100"`
+	BaseURL string `json:"base_url" binding:"required,url"`
+	// APIFlavor is "cloud" (default) or "datacenter". OAuth needs "cloud".
+	APIFlavor string `json:"api_flavor" binding:"omitempty,oneof=cloud datacenter"`
+	// AuthType is "basic" (default), "pat" or "oauth".
+	AuthType string `json:"auth_type" binding:"omitempty,oneof=basic pat oauth"`
+	// Email is required with basic authentication.
+	Email string `json:"email" binding:"omitempty,email"`
+	// APIToken (the personal access token with pat) is required on create unless
+	// the connection uses OAuth. On update an empty token keeps the stored one.
+	APIToken string `json:"api_token"`
+	// WebhookSecret is the secret Jira signs the webhooks of the connection with.
+	// On update an empty secret keeps the stored one.
+	WebhookSecret string `json:"webhook_secret" binding:"max=200"`
+	// Enabled defaults to true on create and is left unchanged on update when omitted.
+	Enabled *bool `json:"enabled"`
+}
+
+type testJiraConnectionRequest struct {
+	APIFlavor string `json:"api_flavor"`
+	AuthType  string `json:"auth_type"`
+	BaseURL   string `json:"base_url"`
+	Email     string `json:"email"`
+	APIToken  string `json:"api_token"`
+}
+
+// jiraConnectionQuery is the shared SQL for fetching Jira connections with their project counts.
+const jiraConnectionQuery = `
+	SELECT
+		jc.id,
+		jc.name,
+		jc.base_url,
+		jc.api_flavor,
+		jc.auth_type,
+		jc.email,
+		jc.api_token,
+		COALESCE(jc.cloud_id, '') AS cloud_id,
+		EXISTS(SELECT 1 FROM jira_oauth_tokens t WHERE t.connection_id = jc.id) AS oauth_connected,
+		jc.webhook_secret <> '' AS webhook_secret_set,
+		jc.enabled,
+		(SELECT COUNT(*) FROM projects p WHERE p.connection_id = jc.id AND p.deleted_at IS NULL) AS project_count,
+		jc.created_at,
+		jc.updated_at
+	FROM jira_connections jc
+`
+
+// maskToken returns "•••••<last4>" when the token is long enough, otherwise "•••••".
+func maskToken(token string) string {
+	if len(token) <= 4 {
+		return "•••••"
+	}
+	return "•••••" + token[len(token)-4:]
+}
+
+// parseJiraConnectionID parses the :id path parameter and reports a 400 on failure.
+func parseJiraConnectionID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid jira connection id"})
+		return 0, false
+	}
+	return id, true
+}
+
+// decryptJiraConnection decrypts the API token of conn and masks it.
+func decryptJiraConnection(cipher *secrets.TokenCipher, conn *JiraConnectionRow) error {
+	token, err := cipher.Decrypt(conn.APIToken)
+	if err != nil {
+		return err
+	}
+	conn.APIToken = token
+	conn.APITokenMask = maskToken(token)
+	return nil
+}
+
+// fetchJiraConnection returns the connection with its token decrypted and
+// masked, or sql.ErrNoRows when it does not exist.
+func fetchJiraConnection(db *sqlx.DB, cipher *secrets.TokenCipher, id int64) (JiraConnectionRow, error) {
+	var conn JiraConnectionRow
+	if err := db.Get(&conn, jiraConnectionQuery+` WHERE jc.id = $1`, id); err != nil {
+		return JiraConnectionRow{}, err
+	}
+	if err := decryptJiraConnection(cipher, &conn); err != nil {
+		return JiraConnectionRow{}, fmt.Errorf("decrypt api token: %w", err)
+	}
+	return conn, nil
+}
+
+// setCredentialSource sets the source creds resolves the credentials of conn
+// from, leaving it empty when no source has them.
+func setCredentialSource(ctx context.Context, creds secrets.CredentialProvider, conn *JiraConnectionRow) error {
+	resolved, err := creds.Credentials(ctx, conn.Name)
+	if errors.Is(err, secrets.ErrNoCredentials) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	conn.CredentialSource = resolved.Source
+	return nil
+}
+
+// resolveJiraCredentials resolves the credentials syncs use for conn, reporting
+// a 400 when no source has them and a 500 on failure.
+func resolveJiraCredentials(c *gin.Context, creds secrets.CredentialProvider, conn JiraConnectionRow) (secrets.JiraCredentials, bool) {
+	resolved, err := creds.Credentials(c.Request.Context(), conn.Name)
+	if errors.Is(err, secrets.ErrNoCredentials) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Jira connection not configured"})
+		return secrets.JiraCredentials{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve jira credentials"})
+		return secrets.JiraCredentials{}, false
+	}
+	return resolved, true
+}
+
+// respondFetchJiraConnectionError reports the error of fetchJiraConnection.
+func respondFetchJiraConnectionError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "jira connection not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch jira connection"})
+}
+
+// jiraConnectionNameTaken reports whether another connection (other than excludeID) uses the name.
+func jiraConnectionNameTaken(db *sqlx.DB, name string, excludeID int64) (bool, error) {
+	var taken bool
+	err := db.QueryRowx(`SELECT EXISTS(SELECT 1 FROM jira_connections WHERE name = $1 AND id <> $2)`, name, excludeID).Scan(&taken)
+	return taken, err
+}
+
+// bindJiraConnectionRequest binds and normalizes the request body, reporting a 400 on failure.
+func bindJiraConnectionRequest(c *gin.Context) (jiraConnectionRequest, bool) {
+	var req jiraConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be empty"})
+		return req, false
+	}
+	// BaseURL の末尾スラッシュを除去して統一
+	req.BaseURL = strings.TrimRight(req.BaseURL, "/")
+	if req.APIFlavor == "" {
+		req.APIFlavor = string(jiraclient.APIFlavorCloud)
+	}
+	if req.AuthType == "" {
+		req.AuthType = secrets.AuthTypeBasic
+	}
+	switch req.AuthType {
+	case secrets.AuthTypeBasic:
+		if req.Email == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "email is required with basic authentication"})
+			return req, false
+		}
+	case secrets.AuthTypeOAuth:
+		// Atlassian の OAuth 2.0 (3LO) は Jira Cloud のサイトにしか使えない
+		if req.APIFlavor != string(jiraclient.APIFlavorCloud) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "oauth authentication requires a Jira Cloud site"})
+			return req, false
+		}
+		// OAuth のトークンは同意フローで取得して jira_oauth_tokens に保存する
+		req.APIToken = ""
+	}
+	return req, true
+}
+
+// configureJiraClient builds the client configuration of the connection for
+// the API flavor of its site, reporting a 400 when the flavor or the
+// credentials cannot be used.
+func configureJiraClient(c *gin.Context, jiraAuth batch.JiraAuth, connectionID int64, apiFlavor string, creds secrets.JiraCredentials) (jiraclient.Config, bool) {
+	var cfg jiraclient.Config
+	flavor, err := jiraclient.ParseAPIFlavor(apiFlavor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return cfg, false
+	}
+	if flavor != jiraclient.APIFlavorCloud && creds.AuthType == secrets.AuthTypeOAuth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "oauth authentication requires a Jira Cloud site"})
+		return cfg, false
+	}
+	cfg.Flavor = flavor
+	err = jiraAuth.Configure(&cfg, connectionID, creds)
+	if errors.Is(err, jiraclient.ErrOAuthReauthorize) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Jira connection not authorized; run the OAuth consent flow"})
+		return cfg, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return cfg, false
+	}
+	return cfg, true
+}
+
+// pingJira verifies the credentials against Jira, writing the response.
+func pingJira(c *gin.Context, cfg jiraclient.Config) {
+	client := jiraclient.New(cfg)
+	if err := client.PingContext(c.Request.Context()); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Jira connection failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jira connection successful"})
+}
+
+// listJiraConnectionsHandlerWithDB handles GET /api/v1/settings/jira-connections.
+func listJiraConnectionsHandlerWithDB(db *sqlx.DB, cipher *secrets.TokenCipher, creds secrets.CredentialProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conns := make([]JiraConnectionRow, 0)
+		if err := db.Select(&conns, jiraConnectionQuery+` ORDER BY jc.id`); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch jira connections"})
+			return
+		}
+		for i := range conns {
+			if err := decryptJiraConnection(cipher, &conns[i]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt jira api token"})
+				return
+			}
+			if err := setCredentialSource(c.Request.Context(), creds, &conns[i]); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve jira credentials"})
+				return
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"data": conns})
+	}
+}
+
+// getJiraConnectionHandlerWithDB handles GET /api/v1/settings/jira-connections/:id.
+func getJiraConnectionHandlerWithDB(db *sqlx.DB, cipher *secrets.TokenCipher, creds secrets.CredentialProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		conn, err := fetchJiraConnection(db, cipher, id)
+		if err != nil {
+			respondFetchJiraConnectionError(c, err)
+			return
+		}
+		if err := setCredentialSource(c.Request.Context(), creds, &conn); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve jira credentials"})
+			return
+		}
+		c.JSON(http.StatusOK, conn)
+	}
+}
+
+// createJiraConnectionHandlerWithDB handles POST /api/v1/settings/jira-connections.
+// The API token and the webhook secret are encrypted with cipher before they are stored.
+// Its label and component fields are mapped to the standard Jira fields.
+func createJiraConnectionHandlerWithDB(db *sqlx.DB, cipher *secrets.TokenCipher, creds secrets.CredentialProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := bindJiraConnectionRequest(c)
+		if !ok {
+			return
+		}
+		if req.APIToken == "" && req.AuthType != secrets.AuthTypeOAuth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "api_token is required"})
+			return
+		}
+		enabled := req.Enabled == nil || *req.Enabled
+
+		taken, err := jiraConnectionNameTaken(db, req.Name, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check jira connection name"})
+			return
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "jira connection name already exists"})
+			return
+		}
+
+		token, err := cipher.Encrypt(req.APIToken)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt jira api token"})
+			return
+		}
+		webhookSecret, err := cipher.Encrypt(req.WebhookSecret)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt jira webhook secret"})
+			return
+		}
+
+		// ラベルとコンポーネントは Jira の標準フィールドのため、どのサイトでも同じ対応で初期設定する
+		var id int64
+		err = db.QueryRowx(`
+			WITH conn AS (
+				INSERT INTO jira_connections (name, base_url, api_flavor, auth_type, email, api_token, enabled, webhook_secret)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING id
+			), mappings AS (
+				INSERT INTO jira_field_mappings (connection_id, column_name, jira_field_id)
+				SELECT conn.id, f.name, f.name FROM conn, (VALUES ('labels'), ('components')) AS f(name)
+			)
+			SELECT id FROM conn`,
+			req.Name, req.BaseURL, req.APIFlavor, req.AuthType, req.Email, token, enabled, webhookSecret,
+		).Scan(&id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create jira connection"})
+			return
+		}
+
+		conn, err := fetchJiraConnection(db, cipher, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch created jira connection"})
+			return
+		}
+		if err := setCredentialSource(c.Request.Context(), creds, &conn); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve jira credentials"})
+			return
+		}
+		c.JSON(http.StatusCreated, conn)
+	}
+}
+
+// updateJiraConnectionHandlerWithDB handles PUT /api/v1/settings/jira-connections/:id.
+// An empty api_token or webhook_secret keeps the stored value; a new value is
+// encrypted with cipher.
+// Changing the site or the authentication type discards the OAuth authorization.
+func updateJiraConnectionHandlerWithDB(db *sqlx.DB, cipher *secrets.TokenCipher, creds secrets.CredentialProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		req, ok := bindJiraConnectionRequest(c)
+		if !ok {
+			return
+		}
+
+		taken, err := jiraConnectionNameTaken(db, req.Name, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check jira connection name"})
+			return
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "jira connection name already exists"})
+			return
+		}
+
+		token, err := cipher.Encrypt(req.APIToken)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt jira api token"})
+			return
+		}
+		webhookSecret, err := cipher.Encrypt(req.WebhookSecret)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt jira webhook secret"})
+			return
+		}
+
+		// OAuth 以外では保存済みのトークンを使わない。サイトが変わった場合は認可し直す
+		if req.AuthType == secrets.AuthTypeOAuth {
+			token = ""
+		}
+		err = db.QueryRowx(`
+			UPDATE jira_connections SET
+				name           = $1,
+				base_url       = $2,
+				auth_type      = $3,
+				email          = $4,
+				api_token      = CASE WHEN $3 = 'oauth' THEN '' ELSE COALESCE(NULLIF($5, ''), api_token) END,
+				enabled        = COALESCE($6, enabled),
+				cloud_id       = CASE WHEN $3 = 'oauth' AND base_url = $2 THEN cloud_id END,
+				api_flavor     = $7,
+				webhook_secret = COALESCE(NULLIF($9, ''), webhook_secret)
+			WHERE id = $8
+			RETURNING id`,
+			req.Name, req.BaseURL, req.AuthType, req.Email, token, req.Enabled, req.APIFlavor, id, webhookSecret,
+		).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "jira connection not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update jira connection"})
+			return
+		}
+		if req.AuthType != secrets.AuthTypeOAuth {
+			if _, err := db.Exec(`DELETE FROM jira_oauth_tokens WHERE connection_id = $1`, id); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete jira oauth tokens"})
+				return
+			}
+		}
+
+		conn, err := fetchJiraConnection(db, cipher, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch updated jira connection"})
+			return
+		}
+		if err := setCredentialSource(c.Request.Context(), creds, &conn); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve jira credentials"})
+			return
+		}
+		c.JSON(http.StatusOK, conn)
+	}
+}
+
+// deleteJiraConnectionHandlerWithDB handles DELETE /api/v1/settings/jira-connections/:id.
+// Connections that projects were synced from cannot be deleted; disable them instead.
+func deleteJiraConnectionHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+
+		// 論理削除済みのプロジェクトも接続を参照しているため含めて判定する
+		var inUse bool
+		if err := db.QueryRowx(`SELECT EXISTS(SELECT 1 FROM projects WHERE connection_id = $1)`, id).Scan(&inUse); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check jira connection usage"})
+			return
+		}
+		if inUse {
+			c.JSON(http.StatusConflict, gin.H{"error": "jira connection has synced projects; disable it instead"})
+			return
+		}
+
+		result, err := db.Exec(`DELETE FROM jira_connections WHERE id = $1`, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete jira connection"})
+			return
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "jira connection not found"})
+			return
+		}
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
+
+// testJiraConnectionHandler handles POST /api/v1/settings/jira-connections/test.
+// Verifies the credentials in the request body before a connection is saved.
+// OAuth connections can only be tested once saved and authorized.
+func testJiraConnectionHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req testJiraConnectionRequest
+		_ = c.ShouldBindJSON(&req)
+		creds := secrets.JiraCredentials{
+			BaseURL:  strings.TrimRight(req.BaseURL, "/"),
+			Email:    req.Email,
+			APIToken: req.APIToken,
+			AuthType: req.AuthType,
+		}
+		if creds.AuthType == "" {
+			creds.AuthType = secrets.AuthTypeBasic
+		}
+		switch {
+		case creds.AuthType == secrets.AuthTypeOAuth:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "save the connection and authorize it to test OAuth"})
+			return
+		case creds.AuthType != secrets.AuthTypeBasic && creds.AuthType != secrets.AuthTypePAT:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "auth_type must be basic, pat or oauth"})
+			return
+		case !creds.Complete():
+			c.JSON(http.StatusBadRequest, gin.H{"error": "base_url, email and api_token are required"})
+			return
+		}
+		cfg, ok := configureJiraClient(c, batch.JiraAuth{}, 0, req.APIFlavor, creds)
+		if !ok {
+			return
+		}
+		pingJira(c, cfg)
+	}
+}
+
+// testStoredJiraConnectionHandlerWithDB handles POST /api/v1/settings/jira-connections/:id/test.
+// Verifies the credentials syncs of the connection use (resolved by creds);
+// non-empty fields of the request body override them so that changes can be
+// tested before saving.
+func testStoredJiraConnectionHandlerWithDB(db *sqlx.DB, cipher *secrets.TokenCipher, creds secrets.CredentialProvider, jiraAuth batch.JiraAuth) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		var req testJiraConnectionRequest
+		_ = c.ShouldBindJSON(&req)
+
+		conn, err := fetchJiraConnection(db, cipher, id)
+		if err != nil {
+			respondFetchJiraConnectionError(c, err)
+			return
+		}
+		resolved, err := creds.Credentials(c.Request.Context(), conn.Name)
+		if err != nil && !errors.Is(err, secrets.ErrNoCredentials) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve jira credentials"})
+			return
+		}
+		if resolved.AuthType == "" {
+			resolved.AuthType = conn.AuthType
+		}
+		if req.AuthType != "" {
+			resolved.AuthType = req.AuthType
+		}
+		if req.BaseURL != "" {
+			resolved.BaseURL = strings.TrimRight(req.BaseURL, "/")
+		}
+		if req.Email != "" {
+			resolved.Email = req.Email
+		}
+		if req.APIToken != "" {
+			resolved.APIToken = req.APIToken
+		}
+		flavor := conn.APIFlavor
+		if req.APIFlavor != "" {
+			flavor = req.APIFlavor
+		}
+		if resolved.AuthType == secrets.AuthTypeOAuth && resolved.CloudID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Jira connection not authorized; run the OAuth consent flow"})
+			return
+		}
+		if !resolved.Complete() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Jira connection not configured"})
+			return
+		}
+		cfg, ok := configureJiraClient(c, jiraAuth, id, flavor, resolved)
+		if !ok {
+			return
+		}
+		pingJira(c, cfg)
+	}
+}
+
+// syncJiraConnectionHandlerWithDB handles POST /api/v1/settings/jira-connections/:id/sync.
+// Starts a full sync of the connection asynchronously, with the credentials
+// resolved by creds and the client and syncer options of the scheduled batch.
+// newRepo returns the repository of a connection.
+func syncJiraConnectionHandlerWithDB(db *sqlx.DB, cipher *secrets.TokenCipher, creds secrets.CredentialProvider, jiraAuth batch.JiraAuth, syncOpts batch.SyncOptions, newRepo func(connectionID int64) batch.Repository, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		conn, err := fetchJiraConnection(db, cipher, id)
+		if err != nil {
+			respondFetchJiraConnectionError(c, err)
+			return
+		}
+		if !conn.Enabled {
+			c.JSON(http.StatusConflict, gin.H{"error": "jira connection is disabled"})
+			return
+		}
+		resolved, ok := resolveJiraCredentials(c, creds, conn)
+		if !ok {
+			return
+		}
+		cfg, ok := configureJiraClient(c, jiraAuth, id, conn.APIFlavor, resolved)
+		if !ok {
+			return
+		}
+
+		// 同じ接続で RUNNING 状態のジョブがある場合はスキップ
+		var running int
+		if err := db.QueryRowx(`SELECT COUNT(*) FROM sync_logs WHERE connection_id = $1 AND status = 'RUNNING'`, id).Scan(&running); err == nil && running > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "sync is already running"})
+			return
+		}
+
+		connLog := log.With(zap.String("connection", conn.Name), zap.String("credential_source", resolved.Source))
+		// スケジュール実行と同じレート制限・変更履歴・スプリントの取り込みで同期する
+		syncer := syncOpts.NewConnectionSyncer(cfg, newRepo(id), connLog)
+
+		// フルシンクを非同期で実行（sync_log の管理は Syncer が担当）
+		// リクエストとは独立した context を使い、上限時間を超えたら中断して ABORTED として記録する
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), manualSyncTimeout)
+			defer cancel()
+			err := syncer.RunFullSync(ctx)
+			if err != nil {
+				connLog.Error("full sync failed", zap.Error(err))
+			}
+			broadcastSyncNotification(db, log, err)
+		}()
+
+		c.JSON(http.StatusAccepted, gin.H{"message": "sync started"})
+	}
+}
@@ -0,0 +1,212 @@
+package router
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/auth"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+// oauthStateTTL bounds the time between starting the consent flow and the callback.
+const oauthStateTTL = 10 * time.Minute
+
+// newOAuthState returns a random state for the consent flow.
+func newOAuthState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// authorizeJiraOAuthHandlerWithDB handles POST /api/v1/settings/jira-connections/:id/oauth/authorize.
+// Returns the Atlassian consent page URL; the browser opens it and Atlassian
+// redirects back to jiraOAuthCallbackHandler.
+func authorizeJiraOAuthHandlerWithDB(db *sqlx.DB, jiraAuth batch.JiraAuth) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, ok := parseJiraConnectionID(c)
+		if !ok {
+			return
+		}
+		if jiraAuth.OAuth == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Jira OAuth app not configured"})
+			return
+		}
+
+		var authType string
+		err := db.QueryRowx(`SELECT auth_type FROM jira_connections WHERE id = $1`, id).Scan(&authType)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "jira connection not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch jira connection"})
+			return
+		}
+		if authType != secrets.AuthTypeOAuth {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "jira connection does not use oauth"})
+			return
+		}
+
+		state, err := newOAuthState()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate oauth state"})
+			return
+		}
+		var createdBy *int64
+		if claims := auth.GetClaims(c); claims != nil {
+			createdBy = &claims.UserID
+		}
+		// 期限切れの state はここで掃除する
+		if _, err := db.Exec(`DELETE FROM jira_oauth_states WHERE expires_at < NOW()`); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save oauth state"})
+			return
+		}
+		_, err = db.Exec(`
+			INSERT INTO jira_oauth_states (state, connection_id, created_by, expires_at)
+			VALUES ($1, $2, $3, $4)`,
+			state, id, createdBy, time.Now().Add(oauthStateTTL),
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save oauth state"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"authorization_url": jiraAuth.OAuth.AuthCodeURL(state)})
+	}
+}
+
+// jiraOAuthCallbackHandler handles GET /api/v1/oauth/jira/callback.
+// Verifies the state, exchanges the authorization code for tokens, records the
+// Atlassian site of the connection and stores the tokens encrypted. When
+// resultURL is set the browser is redirected there with the result in the
+// query string; otherwise the result is returned as JSON.
+func jiraOAuthCallbackHandler(db *sqlx.DB, jiraAuth batch.JiraAuth, resultURL string, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		// state は一度だけ使えるよう、検証と同時に削除する
+		var connectionID int64
+		err := db.QueryRowx(`
+			DELETE FROM jira_oauth_states
+			WHERE state = $1 AND expires_at > NOW()
+			RETURNING connection_id`,
+			c.Query("state"),
+		).Scan(&connectionID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondJiraOAuthResult(c, resultURL, 0, http.StatusBadRequest, "invalid or expired oauth state")
+			return
+		}
+		if err != nil {
+			respondJiraOAuthResult(c, resultURL, 0, http.StatusInternalServerError, "failed to verify oauth state")
+			return
+		}
+
+		if denied := c.Query("error"); denied != "" {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusBadRequest, "authorization denied: "+denied)
+			return
+		}
+		code := c.Query("code")
+		if code == "" {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusBadRequest, "authorization code is missing")
+			return
+		}
+		if jiraAuth.OAuth == nil {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusServiceUnavailable, "Jira OAuth app not configured")
+			return
+		}
+
+		var baseURL string
+		err = db.QueryRowx(`SELECT base_url FROM jira_connections WHERE id = $1 AND auth_type = 'oauth'`, connectionID).Scan(&baseURL)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusNotFound, "jira oauth connection not found")
+			return
+		}
+		if err != nil {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusInternalServerError, "failed to fetch jira connection")
+			return
+		}
+
+		token, err := jiraAuth.OAuth.Exchange(ctx, code)
+		if err != nil {
+			log.Warn("jira oauth code exchange failed", zap.Int64("connection_id", connectionID), zap.Error(err))
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusBadGateway, "failed to exchange authorization code")
+			return
+		}
+		resources, err := jiraAuth.OAuth.AccessibleResources(ctx, token.AccessToken)
+		if err != nil {
+			log.Warn("jira oauth accessible resources failed", zap.Int64("connection_id", connectionID), zap.Error(err))
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusBadGateway, "failed to fetch authorized Jira sites")
+			return
+		}
+		// 同意したアカウントが複数サイトにアクセスできる場合も、接続の base_url のサイトだけを使う
+		cloudID := ""
+		for _, r := range resources {
+			if strings.EqualFold(strings.TrimRight(r.URL, "/"), baseURL) {
+				cloudID = r.ID
+				break
+			}
+		}
+		if cloudID == "" {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusBadRequest,
+				fmt.Sprintf("the authorized account has no access to %s", baseURL))
+			return
+		}
+
+		if err := batch.NewOAuthTokenStore(db, jiraAuth.Cipher, connectionID).Save(ctx, token); err != nil {
+			log.Error("failed to save jira oauth token", zap.Int64("connection_id", connectionID), zap.Error(err))
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusInternalServerError, "failed to save oauth token")
+			return
+		}
+		if _, err := db.Exec(`UPDATE jira_connections SET cloud_id = $1 WHERE id = $2`, cloudID, connectionID); err != nil {
+			respondJiraOAuthResult(c, resultURL, connectionID, http.StatusInternalServerError, "failed to update jira connection")
+			return
+		}
+
+		log.Info("jira oauth connection authorized", zap.Int64("connection_id", connectionID), zap.String("cloud_id", cloudID))
+		respondJiraOAuthResult(c, resultURL, connectionID, http.StatusOK, "")
+	}
+}
+
+// respondJiraOAuthResult reports the result of the consent flow: as a redirect
+// to resultURL with jira_oauth=success|error (and the message) when it is set,
+// otherwise as JSON with status.
+func respondJiraOAuthResult(c *gin.Context, resultURL string, connectionID int64, status int, errMsg string) {
+	if resultURL == "" {
+		if errMsg != "" {
+			c.JSON(status, gin.H{"error": errMsg})
+			return
+		}
+		c.JSON(status, gin.H{"message": "Jira connection authorized", "connection_id": connectionID})
+		return
+	}
+
+	q := url.Values{}
+	if errMsg != "" {
+		q.Set("jira_oauth", "error")
+		q.Set("message", errMsg)
+	} else {
+		q.Set("jira_oauth", "success")
+	}
+	if connectionID != 0 {
+		q.Set("connection_id", fmt.Sprint(connectionID))
+	}
+	sep := "?"
+	if strings.Contains(resultURL, "?") {
+		sep = "&"
+	}
+	c.Redirect(http.StatusFound, resultURL+sep+q.Encode())
+}
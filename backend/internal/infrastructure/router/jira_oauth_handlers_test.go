@@ -0,0 +1,212 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/config"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+// newOAuthJiraAuth returns a JiraAuth whose OAuth app talks to the fake
+// Atlassian endpoints of atlassian.
+func newOAuthJiraAuth(t *testing.T, atlassian *httptest.Server) batch.JiraAuth {
+	t.Helper()
+	jiraAuth := batch.NewJiraAuth(nil, testCipher, config.JiraOAuthConfig{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "https://pm.example.com/api/v1/oauth/jira/callback",
+	})
+	if atlassian != nil {
+		jiraAuth.OAuth.TokenURL = atlassian.URL + "/oauth/token"
+		jiraAuth.OAuth.APIURL = atlassian.URL
+	}
+	return jiraAuth
+}
+
+// newAtlassianServer fakes the token and accessible-resources endpoints.
+func newAtlassianServer(t *testing.T, siteURL string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["code"] != "good-code" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"invalid_grant","error_description":"bad code"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600,"scope":"read:jira-work"}`))
+		case "/oauth/token/accessible-resources":
+			w.Write([]byte(`[{"id":"cloud-1","url":"` + siteURL + `","name":"example"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// --- authorizeJiraOAuthHandlerWithDB tests ---
+
+func TestAuthorizeJiraOAuthHandler_NotConfigured(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/jira-connections/1/oauth/authorize", "", jiraConnectionParams)
+
+	authorizeJiraOAuthHandlerWithDB(db, testJiraAuth)(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestAuthorizeJiraOAuthHandler_NotOAuth(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT auth_type FROM jira_connections`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"auth_type"}).AddRow("basic"))
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/jira-connections/1/oauth/authorize", "", jiraConnectionParams)
+
+	authorizeJiraOAuthHandlerWithDB(db, newOAuthJiraAuth(t, nil))(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAuthorizeJiraOAuthHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT auth_type FROM jira_connections`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"auth_type"}))
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/jira-connections/1/oauth/authorize", "", jiraConnectionParams)
+
+	authorizeJiraOAuthHandlerWithDB(db, newOAuthJiraAuth(t, nil))(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAuthorizeJiraOAuthHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT auth_type FROM jira_connections`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"auth_type"}).AddRow("oauth"))
+	mock.ExpectExec(`DELETE FROM jira_oauth_states WHERE expires_at < NOW\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO jira_oauth_states`).
+		WithArgs(sqlmock.AnyArg(), int64(1), nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/jira-connections/1/oauth/authorize", "", jiraConnectionParams)
+
+	authorizeJiraOAuthHandlerWithDB(db, newOAuthJiraAuth(t, nil))(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	u, err := url.Parse(resp["authorization_url"])
+	require.NoError(t, err)
+	assert.Equal(t, "auth.atlassian.com", u.Host)
+	assert.Equal(t, "client", u.Query().Get("client_id"))
+	assert.Len(t, u.Query().Get("state"), 64)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// --- jiraOAuthCallbackHandler tests ---
+
+func TestJiraOAuthCallbackHandler_InvalidState(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`DELETE FROM jira_oauth_states`).WithArgs("unknown").
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id"}))
+	c, w := newDelayPolicyContext(http.MethodGet, "/oauth/jira/callback?state=unknown&code=good-code", "", nil)
+
+	jiraOAuthCallbackHandler(db, newOAuthJiraAuth(t, nil), "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid or expired oauth state")
+}
+
+func TestJiraOAuthCallbackHandler_Denied(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`DELETE FROM jira_oauth_states`).WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(1))
+	c, w := newDelayPolicyContext(http.MethodGet, "/oauth/jira/callback?state=s1&error=access_denied", "", nil)
+
+	jiraOAuthCallbackHandler(db, newOAuthJiraAuth(t, nil), "https://pm.example.com/settings", zap.NewNop())(c)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	u, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "error", u.Query().Get("jira_oauth"))
+	assert.Equal(t, "1", u.Query().Get("connection_id"))
+	assert.Contains(t, u.Query().Get("message"), "access_denied")
+}
+
+func TestJiraOAuthCallbackHandler_Success(t *testing.T) {
+	atlassian := newAtlassianServer(t, "https://example.atlassian.net")
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`DELETE FROM jira_oauth_states`).WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT base_url FROM jira_connections WHERE id = \$1 AND auth_type = 'oauth'`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"base_url"}).AddRow("https://example.atlassian.net"))
+	// トークンは暗号化して保存する
+	mock.ExpectExec(`INSERT INTO jira_oauth_tokens`).
+		WithArgs(int64(1), encryptedToken("access-1"), encryptedToken("refresh-1"), sqlmock.AnyArg(), "read:jira-work").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE jira_connections SET cloud_id = \$1 WHERE id = \$2`).
+		WithArgs("cloud-1", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c, w := newDelayPolicyContext(http.MethodGet, "/oauth/jira/callback?state=s1&code=good-code", "", nil)
+
+	jiraOAuthCallbackHandler(db, newOAuthJiraAuth(t, atlassian), "https://pm.example.com/settings?tab=jira", zap.NewNop())(c)
+
+	require.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://pm.example.com/settings?tab=jira&connection_id=1&jira_oauth=success", w.Header().Get("Location"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJiraOAuthCallbackHandler_SiteNotAuthorized(t *testing.T) {
+	atlassian := newAtlassianServer(t, "https://other.atlassian.net")
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`DELETE FROM jira_oauth_states`).WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT base_url FROM jira_connections`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"base_url"}).AddRow("https://example.atlassian.net"))
+	c, w := newDelayPolicyContext(http.MethodGet, "/oauth/jira/callback?state=s1&code=good-code", "", nil)
+
+	jiraOAuthCallbackHandler(db, newOAuthJiraAuth(t, atlassian), "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "no access to https://example.atlassian.net")
+	// トークンは保存しない
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJiraOAuthCallbackHandler_ExchangeFails(t *testing.T) {
+	atlassian := newAtlassianServer(t, "https://example.atlassian.net")
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`DELETE FROM jira_oauth_states`).WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"connection_id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT base_url FROM jira_connections`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"base_url"}).AddRow("https://example.atlassian.net"))
+	c, w := newDelayPolicyContext(http.MethodGet, "/oauth/jira/callback?state=s1&code=bad-code", "", nil)
+
+	jiraOAuthCallbackHandler(db, newOAuthJiraAuth(t, atlassian), "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+}
+
+// --- configureJiraClient tests ---
+
+func TestConfigureJiraClient_OAuthNotAuthorized(t *testing.T) {
+	c, w := newDelayPolicyContext(http.MethodPost, "/settings/jira-connections/1/sync", "", jiraConnectionParams)
+	creds := secrets.JiraCredentials{BaseURL: "https://example.atlassian.net", AuthType: secrets.AuthTypeOAuth}
+
+	_, ok := configureJiraClient(c, newOAuthJiraAuth(t, nil), 1, "cloud", creds)
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "OAuth consent flow")
+}
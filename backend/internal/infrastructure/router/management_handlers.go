@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
@@ -25,6 +26,10 @@ type assignProjectOrgRequest struct {
 	OrganizationID *int64 `json:"organization_id"`
 }
 
+type setOrgTimezoneRequest struct {
+	Timezone *string `json:"timezone"`
+}
+
 // createOrganizationHandlerWithDB handles POST /organizations.
 func createOrganizationHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -86,7 +91,7 @@ func createOrganizationHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 		// Return the created organization
 		var org OrganizationRow
-		if err := db.QueryRowx(orgQuery+` WHERE o.id = $1 GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.created_at, o.updated_at`, newID).
+		if err := db.QueryRowx(orgQuery+` WHERE o.id = $1 GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at`, newID).
 			StructScan(&org); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch created organization"})
 			return
@@ -129,7 +134,7 @@ func updateOrganizationHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		}
 
 		var org OrganizationRow
-		if err := db.QueryRowx(orgQuery+` WHERE o.id = $1 GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.created_at, o.updated_at`, id).
+		if err := db.QueryRowx(orgQuery+` WHERE o.id = $1 GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at`, id).
 			StructScan(&org); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch updated organization"})
 			return
@@ -162,7 +167,7 @@ func deleteOrganizationHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 		// Check for assigned projects
 		var projectCount int
-		if err := db.QueryRowx(`SELECT COUNT(*) FROM projects WHERE organization_id = $1`, id).Scan(&projectCount); err != nil {
+		if err := db.QueryRowx(`SELECT COUNT(*) FROM projects WHERE organization_id = $1 AND deleted_at IS NULL`, id).Scan(&projectCount); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check assigned projects"})
 			return
 		}
@@ -225,3 +230,51 @@ func assignProjectToOrganizationHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "project assigned successfully"})
 	}
 }
+
+// setOrganizationTimezoneHandlerWithDB handles PUT /organizations/:id/timezone.
+// The timezone is an IANA name such as "America/New_York"; null makes the
+// organization inherit its parent's timezone (or REPORT_TIMEZONE at the top).
+func setOrganizationTimezoneHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization id"})
+			return
+		}
+
+		var req setOrgTimezoneRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		if req.Timezone != nil {
+			tz := strings.TrimSpace(*req.Timezone)
+			// "Local" はサーバーのタイムゾーンに依存するため受け付けない
+			if _, err := time.LoadLocation(tz); err != nil || tz == "" || tz == "Local" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone"})
+				return
+			}
+			req.Timezone = &tz
+		}
+
+		result, err := db.Exec(`UPDATE organizations SET timezone = $1 WHERE id = $2`, req.Timezone, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update organization"})
+			return
+		}
+		rows, _ := result.RowsAffected()
+		if rows == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+			return
+		}
+
+		var org OrganizationRow
+		if err := db.QueryRowx(orgQuery+` WHERE o.id = $1 GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at`, id).
+			StructScan(&org); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch updated organization"})
+			return
+		}
+		org.DelayStatus = orgDelayStatus(&org)
+		c.JSON(http.StatusOK, org)
+	}
+}
@@ -484,3 +484,73 @@ func TestAssignProjectHandler_SuccessNullOrg(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+// --- setOrganizationTimezoneHandlerWithDB tests ---
+
+func TestSetOrganizationTimezoneHandler_InvalidTimezone(t *testing.T) {
+	db, _ := newTestDB(t)
+	handler := setOrganizationTimezoneHandlerWithDB(db)
+
+	for _, body := range []string{`{"timezone":"Mars/Olympus"}`, `{"timezone":""}`, `{"timezone":"Local"}`} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/organizations/1/timezone", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		handler(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestSetOrganizationTimezoneHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := setOrganizationTimezoneHandlerWithDB(db)
+
+	mock.ExpectExec(`UPDATE organizations SET timezone`).
+		WithArgs(nil, int64(99)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/organizations/99/timezone", bytes.NewBufferString(`{"timezone":null}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "99"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetOrganizationTimezoneHandler_Success(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := setOrganizationTimezoneHandlerWithDB(db)
+	now := time.Now()
+
+	mock.ExpectExec(`UPDATE organizations SET timezone`).
+		WithArgs("America/New_York", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM organizations o`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "parent_id", "path", "level", "business_calendar_id", "timezone", "created_at", "updated_at",
+			"total_projects", "red_projects", "yellow_projects", "green_projects",
+		}).AddRow(1, "US", nil, "/1/", 0, nil, "America/New_York", now, now, 0, 0, 0, 0))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/organizations/1/timezone", bytes.NewBufferString(`{"timezone":" America/New_York "}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp OrganizationRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Timezone)
+	assert.Equal(t, "America/New_York", *resp.Timezone)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -11,18 +11,20 @@ import (
 
 // OrganizationRow represents an organization with aggregated project delay stats.
 type OrganizationRow struct {
-	ID             int64     `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name"`
-	ParentID       *int64    `db:"parent_id" json:"parent_id"`
-	Path           string    `db:"path" json:"path"`
-	Level          int       `db:"level" json:"level"`
-	CreatedAt      time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
-	TotalProjects  int       `db:"total_projects" json:"total_projects"`
-	RedProjects    int       `db:"red_projects" json:"red_projects"`
-	YellowProjects int       `db:"yellow_projects" json:"yellow_projects"`
-	GreenProjects  int       `db:"green_projects" json:"green_projects"`
-	DelayStatus    string    `json:"delay_status"`
+	ID                 int64     `db:"id" json:"id"`
+	Name               string    `db:"name" json:"name"`
+	ParentID           *int64    `db:"parent_id" json:"parent_id"`
+	Path               string    `db:"path" json:"path"`
+	Level              int       `db:"level" json:"level"`
+	BusinessCalendarID *int64    `db:"business_calendar_id" json:"business_calendar_id"`
+	Timezone           *string   `db:"timezone" json:"timezone"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+	TotalProjects      int       `db:"total_projects" json:"total_projects"`
+	RedProjects        int       `db:"red_projects" json:"red_projects"`
+	YellowProjects     int       `db:"yellow_projects" json:"yellow_projects"`
+	GreenProjects      int       `db:"green_projects" json:"green_projects"`
+	DelayStatus        string    `json:"delay_status"`
 }
 
 // orgDelayStatus computes the delay status for an organization based on project counts.
@@ -45,6 +47,8 @@ const orgQuery = `
 		o.parent_id,
 		o.path,
 		o.level,
+		o.business_calendar_id,
+		o.timezone,
 		o.created_at,
 		o.updated_at,
 		COALESCE(COUNT(DISTINCT p.id), 0) AS total_projects,
@@ -58,13 +62,14 @@ const orgQuery = `
 			AND COALESCE(pds.yellow_count, 0) = 0
 			THEN p.id END), 0) AS green_projects
 	FROM organizations o
-	LEFT JOIN projects p ON o.id = p.organization_id
+	LEFT JOIN projects p ON o.id = p.organization_id AND p.deleted_at IS NULL
 	LEFT JOIN (
 		SELECT
 			project_id,
 			COUNT(CASE WHEN delay_status = 'RED'    THEN 1 END) AS red_count,
 			COUNT(CASE WHEN delay_status = 'YELLOW' THEN 1 END) AS yellow_count
 		FROM issues
+		WHERE deleted_at IS NULL
 		GROUP BY project_id
 	) pds ON p.id = pds.project_id
 `
@@ -73,7 +78,7 @@ const orgQuery = `
 func listOrganizationsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		query := orgQuery + `
-			GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.created_at, o.updated_at
+			GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at
 			ORDER BY o.path
 		`
 
@@ -103,7 +108,7 @@ func getOrganizationHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 		query := orgQuery + `
 			WHERE o.id = $1
-			GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.created_at, o.updated_at
+			GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at
 		`
 
 		var org OrganizationRow
@@ -129,7 +134,7 @@ func getChildOrganizationsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 		query := orgQuery + `
 			WHERE o.parent_id = $1
-			GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.created_at, o.updated_at
+			GROUP BY o.id, o.name, o.parent_id, o.path, o.level, o.business_calendar_id, o.timezone, o.created_at, o.updated_at
 			ORDER BY o.path
 		`
 
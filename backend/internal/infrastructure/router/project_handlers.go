@@ -65,7 +65,8 @@ func listProjectsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 		}
 
 		// --- Build WHERE conditions ---
-		var conditions []string
+		// 論理削除済みのプロジェクトは常に除外する
+		conditions := []string{"p.deleted_at IS NULL"}
 		var args []interface{}
 		argIdx := 1
 
@@ -86,10 +87,7 @@ func listProjectsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			}
 		}
 
-		whereClause := ""
-		if len(conditions) > 0 {
-			whereClause = "WHERE " + strings.Join(conditions, " AND ")
-		}
+		whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 		// --- ORDER BY clause (using subquery alias columns, no table prefix) ---
 		var orderBy string
@@ -122,7 +120,7 @@ func listProjectsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 				COALESCE(COUNT(CASE WHEN i.status_category != 'Done' THEN 1 END), 0) AS open_count,
 				COALESCE(COUNT(i.id), 0) AS total_count
 			FROM projects p
-			LEFT JOIN issues i ON p.id = i.project_id
+			LEFT JOIN issues i ON p.id = i.project_id AND i.deleted_at IS NULL
 			%s
 			GROUP BY p.id, p.jira_project_id, p.key, p.name, p.lead_account_id, p.lead_email,
 			         p.organization_id, p.is_active, p.created_at, p.updated_at
@@ -234,8 +232,8 @@ func getProjectHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 				COALESCE(COUNT(CASE WHEN i.status_category != 'Done' THEN 1 END), 0) AS open_count,
 				COALESCE(COUNT(i.id), 0) AS total_count
 			FROM projects p
-			LEFT JOIN issues i ON p.id = i.project_id
-			WHERE p.id = $1
+			LEFT JOIN issues i ON p.id = i.project_id AND i.deleted_at IS NULL
+			WHERE p.id = $1 AND p.deleted_at IS NULL
 			GROUP BY p.id, p.jira_project_id, p.key, p.name, p.lead_account_id, p.lead_email,
 			         p.organization_id, p.is_active, p.created_at, p.updated_at
 		`
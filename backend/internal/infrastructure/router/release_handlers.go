@@ -0,0 +1,136 @@
+package router
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+// ReleaseRow is a version (release) of a project with the counts of its issues
+// (the issues whose fixVersions include it).
+type ReleaseRow struct {
+	ID                   int64   `db:"id" json:"id"`
+	JiraVersionID        string  `db:"jira_version_id" json:"jira_version_id"`
+	Name                 string  `db:"name" json:"name"`
+	Description          *string `db:"description" json:"description"`
+	StartDate            *string `db:"start_date" json:"start_date"`
+	ReleaseDate          *string `db:"release_date" json:"release_date"`
+	Released             bool    `db:"released" json:"released"`
+	Archived             bool    `db:"archived" json:"archived"`
+	TotalCount           int     `db:"total_count" json:"total_count"`
+	OpenCount            int     `db:"open_count" json:"open_count"`
+	RedCount             int     `db:"red_count" json:"red_count"`                             // 未完了の RED のチケット数
+	YellowCount          int     `db:"yellow_count" json:"yellow_count"`                       // 未完了の YELLOW のチケット数
+	DueAfterReleaseCount int     `db:"due_after_release_count" json:"due_after_release_count"` // 期日がリリース日より後の未完了チケット数
+	// DelayStatus is computed from the release date and the open issues
+	// (see normalizer.CalcReleaseStatus).
+	DelayStatus string `json:"delay_status"`
+}
+
+// listProjectReleasesHandlerWithDB handles GET /api/v1/projects/:id/releases.
+// Returns the releases of the project ordered by release date, with their issue
+// counts and delay status. Archived releases are included with include_archived=true.
+func listProjectReleasesHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project id"})
+			return
+		}
+		includeArchived := c.Query("include_archived") == "true"
+
+		loc, ok := projectLocation(c, db, projectID)
+		if !ok {
+			return
+		}
+
+		releases := make([]ReleaseRow, 0)
+		err = db.Select(&releases, `
+			SELECT
+				v.id,
+				v.jira_version_id,
+				v.name,
+				v.description,
+				TO_CHAR(v.start_date, 'YYYY-MM-DD')   AS start_date,
+				TO_CHAR(v.release_date, 'YYYY-MM-DD') AS release_date,
+				v.released,
+				v.archived,
+				COUNT(i.id)                                                                  AS total_count,
+				COUNT(i.id) FILTER (WHERE i.status_category <> 'Done')                       AS open_count,
+				COUNT(i.id) FILTER (WHERE i.status_category <> 'Done' AND i.delay_status = 'RED')    AS red_count,
+				COUNT(i.id) FILTER (WHERE i.status_category <> 'Done' AND i.delay_status = 'YELLOW') AS yellow_count,
+				COUNT(i.id) FILTER (WHERE i.status_category <> 'Done' AND i.due_date > v.release_date) AS due_after_release_count
+			FROM project_versions v
+			LEFT JOIN issues i ON i.connection_id = v.connection_id
+				AND v.jira_version_id = ANY(i.fix_version_ids) AND i.deleted_at IS NULL
+			WHERE v.project_id = $1 AND (v.archived = FALSE OR $2)
+			GROUP BY v.id
+			ORDER BY v.release_date ASC NULLS LAST, v.name`,
+			projectID, includeArchived,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch releases"})
+			return
+		}
+
+		// リリース日の経過はプロジェクトのタイムゾーンの「今日」で判定する
+		now := time.Now().In(loc)
+		for i := range releases {
+			r := &releases[i]
+			r.DelayStatus = normalizer.CalcReleaseStatus(r.Released, r.ReleaseDate, normalizer.ReleaseIssueCounts{
+				Open:            r.OpenCount,
+				Red:             r.RedCount,
+				Yellow:          r.YellowCount,
+				DueAfterRelease: r.DueAfterReleaseCount,
+			}, now)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": releases})
+	}
+}
+
+// projectLocation returns the timezone the project's "today" is evaluated in:
+// the timezone of its organization or of the nearest ancestor that sets one,
+// else the reporting timezone — the same inheritance the batch uses for delay
+// statuses. It writes a 404 or 500 and returns false when the project does
+// not exist or the lookup fails.
+func projectLocation(c *gin.Context, db *sqlx.DB, projectID int64) (*time.Location, bool) {
+	var tz sql.NullString
+	err := db.Get(&tz, `
+		SELECT (
+			SELECT a.timezone
+			FROM organizations a
+			WHERE o.path LIKE a.path || '%' AND a.timezone IS NOT NULL
+			ORDER BY a.level DESC
+			LIMIT 1
+		) AS timezone
+		FROM projects p
+		LEFT JOIN organizations o ON o.id = p.organization_id
+		WHERE p.id = $1 AND p.deleted_at IS NULL`,
+		projectID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch project"})
+		return nil, false
+	}
+	if !tz.Valid {
+		return normalizer.Location(), true
+	}
+	loc, err := time.LoadLocation(tz.String)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid project timezone"})
+		return nil, false
+	}
+	return loc, true
+}
@@ -0,0 +1,102 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var releaseColumns = []string{
+	"id", "jira_version_id", "name", "description", "start_date", "release_date", "released", "archived",
+	"total_count", "open_count", "red_count", "yellow_count", "due_after_release_count",
+}
+
+func TestListProjectReleasesHandler_InvalidID(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/abc/releases", "", gin.Params{{Key: "id", Value: "abc"}})
+
+	listProjectReleasesHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListProjectReleasesHandler_ProjectNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM projects p`).WithArgs(int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone"}))
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/999/releases", "", gin.Params{{Key: "id", Value: "999"}})
+
+	listProjectReleasesHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListProjectReleasesHandler_ComputesDelayStatus(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM projects p`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone"}).AddRow(nil))
+	mock.ExpectQuery(`FROM project_versions v`).WithArgs(int64(1), true).
+		WillReturnRows(sqlmock.NewRows(releaseColumns).
+			AddRow(1, "100", "v1.0", nil, nil, "2000-01-31", true, false, 10, 1, 1, 0, 0).
+			AddRow(2, "101", "v1.1", nil, nil, "2000-02-28", false, false, 5, 2, 0, 0, 0).
+			AddRow(3, "102", "v2.0", nil, nil, "2999-12-31", false, false, 8, 3, 0, 1, 0).
+			AddRow(4, "103", "v3.0", nil, nil, nil, false, true, 0, 0, 0, 0, 0))
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/1/releases?include_archived=true", "", gin.Params{{Key: "id", Value: "1"}})
+
+	listProjectReleasesHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []ReleaseRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 4)
+	// リリース済み → GREEN、リリース日超過 → RED、YELLOW のチケットあり → YELLOW、未完了なし → GREEN
+	assert.Equal(t, "GREEN", resp.Data[0].DelayStatus)
+	assert.Equal(t, "RED", resp.Data[1].DelayStatus)
+	assert.Equal(t, "YELLOW", resp.Data[2].DelayStatus)
+	assert.Equal(t, "GREEN", resp.Data[3].DelayStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListProjectReleasesHandler_UsesOrganizationTimezone(t *testing.T) {
+	// UTC-11 の「今日」は UTC+14 では常に過去の日付になる
+	behind, err := time.LoadLocation("Pacific/Pago_Pago")
+	require.NoError(t, err)
+	releaseDate := time.Now().In(behind).Format("2006-01-02")
+
+	for _, tc := range []struct {
+		timezone string
+		want     string
+	}{
+		{"Pacific/Pago_Pago", "GREEN"},
+		{"Pacific/Kiritimati", "RED"},
+	} {
+		t.Run(tc.timezone, func(t *testing.T) {
+			db, mock := newTestDB(t)
+			mock.ExpectQuery(`a\.timezone IS NOT NULL`).WithArgs(int64(1)).
+				WillReturnRows(sqlmock.NewRows([]string{"timezone"}).AddRow(tc.timezone))
+			mock.ExpectQuery(`FROM project_versions v`).WithArgs(int64(1), false).
+				WillReturnRows(sqlmock.NewRows(releaseColumns).
+					AddRow(1, "100", "v1.0", nil, nil, releaseDate, false, false, 3, 1, 0, 0, 0))
+			c, w := newDelayPolicyContext(http.MethodGet, "/projects/1/releases", "", gin.Params{{Key: "id", Value: "1"}})
+
+			listProjectReleasesHandlerWithDB(db)(c)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp struct {
+				Data []ReleaseRow `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Len(t, resp.Data, 1)
+			assert.Equal(t, tc.want, resp.Data[0].DelayStatus)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
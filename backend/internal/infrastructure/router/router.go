@@ -8,19 +8,28 @@ import (
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/auth"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/config"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/logger"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
 )
 
-// NewRouter は新しいGinルーターを作成する
-func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger) *gin.Engine {
+// NewRouter は新しいGinルーターを作成する。cipher は DB に保存する Jira API トークン・Webhook シークレットの暗号化に、
+// creds は手動同期・接続テストで使う Jira 認証情報の解決に使う（バッチと同じ優先順位）。
+// syncOpts は手動同期の Jira クライアントと Syncer の設定（バッチと共通）
+func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger, cipher *secrets.TokenCipher, creds secrets.CredentialProvider, syncOpts batch.SyncOptions) *gin.Engine {
 	// Ginモードの設定
 	gin.SetMode(cfg.Server.GinMode)
 
 	r := gin.New()
 
 	tm := auth.NewTokenManager(cfg.Auth.JWTSecret)
+	tm.SetDurations(cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+
+	// Jira 接続の認証方式に応じたクライアント構成（バッチと共通）。OAuth アプリは JIRA_OAUTH_* で設定する
+	jiraAuth := batch.NewJiraAuth(db, cipher, cfg.JiraOAuth)
+	newRepo := func(connectionID int64) batch.Repository { return batch.NewRepository(db, connectionID) }
 
 	// 共通ミドルウェア
 	r.Use(gin.Recovery())
@@ -39,11 +48,23 @@ func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger) *gin.Engine
 		authGroup := v1.Group("/auth")
 		{
 			authGroup.POST("/login", loginHandler(db, tm))
+			// アクセストークンの期限切れ後に呼ぶため JWT は不要（リフレッシュトークンで検証する）
+			authGroup.POST("/refresh", refreshHandler(db, tm))
+			authGroup.POST("/logout", logoutHandler(db))
 		}
 
+		// Jira Webhook（JWT不要。接続ごとの共有シークレットによる署名で検証する）
+		// ID なしのパスは接続 ID 導入前に登録された Webhook のため default 接続として扱う
+		v1.POST("/webhooks/jira", jiraWebhookHandler(db, cipher, cfg.Auth.JiraWebhookSecret, log.Logger))
+		v1.POST("/webhooks/jira/:id", jiraWebhookHandler(db, cipher, cfg.Auth.JiraWebhookSecret, log.Logger))
+
+		// Jira OAuth 2.0 の同意フローのコールバック（JWT不要。state で検証する）
+		v1.GET("/oauth/jira/callback", jiraOAuthCallbackHandler(db, jiraAuth, cfg.JiraOAuth.ResultURL, log.Logger))
+
 		// 認証が必要なエンドポイント
 		protected := v1.Group("")
-		protected.Use(auth.Middleware(tm))
+		// 無効化・削除されたユーザーのトークンはアクセストークンの有効期限内でも拒否する
+		protected.Use(auth.Middleware(tm, dbUserChecker{db: db}))
 		{
 			// 認証ユーザー情報
 			protected.GET("/auth/me", meHandler())
@@ -58,6 +79,8 @@ func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger) *gin.Engine
 				organizations.POST("", auth.RequireRole("admin"), createOrganizationHandlerWithDB(db))
 				organizations.PUT("/:id", auth.RequireRole("admin"), updateOrganizationHandlerWithDB(db))
 				organizations.DELETE("/:id", auth.RequireRole("admin"), deleteOrganizationHandlerWithDB(db))
+				organizations.PUT("/:id/calendar", auth.RequireRole("admin"), assignOrganizationCalendarHandlerWithDB(db))
+				organizations.PUT("/:id/timezone", auth.RequireRole("admin"), setOrganizationTimezoneHandlerWithDB(db))
 			}
 
 			// プロジェクト管理
@@ -66,6 +89,9 @@ func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger) *gin.Engine
 				projects.GET("", listProjectsHandlerWithDB(db))
 				projects.GET("/:id", getProjectHandlerWithDB(db))
 				projects.GET("/:id/issues", listProjectIssuesHandlerWithDB(db))
+				projects.GET("/:id/releases", listProjectReleasesHandlerWithDB(db))
+				projects.GET("/:id/sprints", listProjectSprintsHandlerWithDB(db))
+				projects.GET("/:id/sprints/current", getCurrentSprintsHandlerWithDB(db))
 				// admin のみ書き込み可
 				projects.PUT("/:id", auth.RequireRole("admin"), updateProjectHandlerWithDB(db))
 				// admin + project_manager が組織割り当て可能
@@ -87,14 +113,37 @@ func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger) *gin.Engine
 			settings := protected.Group("/settings")
 			settings.Use(auth.RequireRole("admin"))
 			{
-				settings.GET("/jira", getJiraSettingsHandler(db))
-				settings.PUT("/jira", updateJiraSettingsHandler(db))
-				settings.POST("/jira/test", testJiraConnectionHandler(db))
-				settings.POST("/jira/sync", triggerSyncHandler(db, log.Logger))
+				settings.GET("/jira-connections", listJiraConnectionsHandlerWithDB(db, cipher, creds))
+				settings.POST("/jira-connections", createJiraConnectionHandlerWithDB(db, cipher, creds))
+				settings.POST("/jira-connections/test", testJiraConnectionHandler())
+				settings.GET("/jira-connections/:id", getJiraConnectionHandlerWithDB(db, cipher, creds))
+				settings.PUT("/jira-connections/:id", updateJiraConnectionHandlerWithDB(db, cipher, creds))
+				settings.DELETE("/jira-connections/:id", deleteJiraConnectionHandlerWithDB(db))
+				settings.POST("/jira-connections/:id/test", testStoredJiraConnectionHandlerWithDB(db, cipher, creds, jiraAuth))
+				settings.POST("/jira-connections/:id/sync", syncJiraConnectionHandlerWithDB(db, cipher, creds, jiraAuth, syncOpts, newRepo, log.Logger))
+				settings.POST("/jira-connections/:id/oauth/authorize", authorizeJiraOAuthHandlerWithDB(db, jiraAuth))
+				settings.GET("/jira-connections/:id/field-mappings", listFieldMappingsHandlerWithDB(db))
+				settings.PUT("/jira-connections/:id/field-mappings/:column", updateFieldMappingHandlerWithDB(db))
+				settings.DELETE("/jira-connections/:id/field-mappings/:column", deleteFieldMappingHandlerWithDB(db))
+				settings.GET("/delay-policies", listDelayPoliciesHandlerWithDB(db))
+				settings.POST("/delay-policies", createDelayPolicyHandlerWithDB(db))
+				settings.GET("/delay-policies/:id", getDelayPolicyHandlerWithDB(db))
+				settings.PUT("/delay-policies/:id", updateDelayPolicyHandlerWithDB(db))
+				settings.DELETE("/delay-policies/:id", deleteDelayPolicyHandlerWithDB(db))
+				settings.GET("/calendars", listCalendarsHandlerWithDB(db))
+				settings.POST("/calendars", createCalendarHandlerWithDB(db))
+				settings.GET("/calendars/:id", getCalendarHandlerWithDB(db))
+				settings.PUT("/calendars/:id", updateCalendarHandlerWithDB(db))
+				settings.DELETE("/calendars/:id", deleteCalendarHandlerWithDB(db))
+				settings.GET("/calendars/:id/holidays", listHolidaysHandlerWithDB(db))
+				settings.POST("/calendars/:id/holidays", addHolidayHandlerWithDB(db))
+				settings.POST("/calendars/:id/holidays/import", importHolidaysHandlerWithDB(db))
+				settings.DELETE("/calendars/:id/holidays/:date", deleteHolidayHandlerWithDB(db))
 			}
 
 			// 同期ログ (admin のみ)
 			protected.GET("/sync-logs", auth.RequireRole("admin"), listSyncLogsHandler(db))
+			protected.GET("/sync-logs/:id", auth.RequireRole("admin"), getSyncLogHandler(db))
 
 			// 通知
 			notifications := protected.Group("/notifications")
@@ -110,14 +159,21 @@ func NewRouter(cfg *config.Config, db *sqlx.DB, log *logger.Logger) *gin.Engine
 			{
 				issues.GET("", listIssuesHandlerWithDB(db))
 				issues.GET("/:id", getIssueHandlerWithDB(db))
+				issues.GET("/:id/history", getIssueHistoryHandlerWithDB(db))
+				issues.GET("/:id/children", getIssueChildrenHandlerWithDB(db))
+				issues.GET("/:id/links", getIssueLinksHandlerWithDB(db))
 			}
 
+			// エピック（子チケットからの進捗・遅延ロールアップ付き）
+			protected.GET("/epics", listEpicsHandlerWithDB(db))
+
 			// ダッシュボード（読み取り専用）
 			dashboard := protected.Group("/dashboard")
 			{
 				dashboard.GET("/summary", getDashboardSummaryHandlerWithDB(db))
 				dashboard.GET("/organizations/:id", getOrganizationSummaryHandlerWithDB(db))
 				dashboard.GET("/projects/:id", getProjectSummaryHandlerWithDB(db))
+				dashboard.GET("/trends", getDashboardTrendsHandlerWithDB(db))
 			}
 		}
 	}
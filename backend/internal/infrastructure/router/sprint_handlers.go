@@ -0,0 +1,197 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+// validSprintStates are the values accepted by the state filter of the sprint list.
+var validSprintStates = map[string]bool{"future": true, "active": true, "closed": true}
+
+// SprintRow is a sprint of one of a project's scrum boards with the counts of its
+// issues.
+type SprintRow struct {
+	ID           int64      `db:"id" json:"id"`
+	JiraSprintID int64      `db:"jira_sprint_id" json:"jira_sprint_id"`
+	BoardID      int64      `db:"board_id" json:"board_id"`
+	BoardName    string     `db:"board_name" json:"board_name"`
+	Name         string     `db:"name" json:"name"`
+	State        string     `db:"state" json:"state"`
+	Goal         *string    `db:"goal" json:"goal"`
+	StartDate    *time.Time `db:"start_date" json:"start_date"`
+	EndDate      *time.Time `db:"end_date" json:"end_date"`
+	CompleteDate *time.Time `db:"complete_date" json:"complete_date"`
+	TotalCount   int        `db:"total_count" json:"total_count"`
+	DoneCount    int        `db:"done_count" json:"done_count"`
+}
+
+// SprintHealth is the health of an active sprint: its commitment, scope changes
+// and progress.
+type SprintHealth struct {
+	SprintRow
+	CommittedCount     int `db:"committed_count" json:"committed_count"`           // スプリント開始時点のチケット数
+	AddedCount         int `db:"added_count" json:"added_count"`                   // スプリント開始後に追加されたチケット数
+	CommittedDoneCount int `db:"committed_done_count" json:"committed_done_count"` // 開始時点のチケットのうち完了した数
+	CarriedOverCount   int `db:"carried_over_count" json:"carried_over_count"`     // 完了済みの別スプリントから持ち越したチケット数
+	RedCount           int `db:"red_count" json:"red_count"`                       // 未完了の RED のチケット数
+	OpenCount          int `json:"open_count"`
+	// DaysRemaining is the number of calendar days until the end date in the
+	// project's timezone (0 once it has passed), or nil without an end date.
+	DaysRemaining   *int `json:"days_remaining"`
+	ProgressPercent int  `json:"progress_percent"`
+	// DelayStatus is computed from the elapsed time and the progress of the sprint
+	// (see normalizer.CalcSprintStatus).
+	DelayStatus string `json:"delay_status"`
+}
+
+// sprintIssueCountColumns counts the issues of sprint s joined as i. Issues that
+// were not synced yet are not counted.
+const sprintIssueCountColumns = `
+	COUNT(i.id)                                           AS total_count,
+	COUNT(i.id) FILTER (WHERE i.status_category = 'Done') AS done_count`
+
+// listProjectSprintsHandlerWithDB handles GET /api/v1/projects/:id/sprints.
+// Returns the sprints of the project's boards, newest first. The state query
+// parameter (future, active or closed) filters by sprint state.
+func listProjectSprintsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project id"})
+			return
+		}
+		state := c.Query("state")
+		if state != "" && !validSprintStates[state] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid state"})
+			return
+		}
+		if !projectExists(c, db, projectID) {
+			return
+		}
+
+		sprints := make([]SprintRow, 0)
+		err = db.Select(&sprints, `
+			SELECT
+				s.id, s.jira_sprint_id, s.board_id, b.name AS board_name, s.name, s.state, s.goal,
+				s.start_date, s.end_date, s.complete_date,`+sprintIssueCountColumns+`
+			FROM sprints s
+			JOIN jira_boards b ON b.id = s.board_id
+			LEFT JOIN sprint_issues si ON si.sprint_id = s.id
+			LEFT JOIN issues i ON i.connection_id = s.connection_id
+				AND i.jira_issue_id = si.jira_issue_id AND i.deleted_at IS NULL
+			WHERE b.project_id = $1 AND ($2 = '' OR s.state = $2)
+			GROUP BY s.id, b.name
+			ORDER BY s.start_date DESC NULLS FIRST, s.id DESC`,
+			projectID, state,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sprints"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": sprints})
+	}
+}
+
+// getCurrentSprintsHandlerWithDB handles GET /api/v1/projects/:id/sprints/current.
+// Returns the health of the active sprint of each of the project's boards.
+//
+// Issues first seen in the sprint at its first sync are counted as committed,
+// even if that sync ran after the sprint started; issues seen later and after the
+// start date are counted as added. An issue is carried over when it was also in
+// a closed sprint.
+func getCurrentSprintsHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project id"})
+			return
+		}
+		loc, ok := projectLocation(c, db, projectID)
+		if !ok {
+			return
+		}
+
+		sprints := make([]SprintHealth, 0)
+		err = db.Select(&sprints, `
+			SELECT
+				s.id, s.jira_sprint_id, s.board_id, b.name AS board_name, s.name, s.state, s.goal,
+				s.start_date, s.end_date, s.complete_date,`+sprintIssueCountColumns+`,
+				COUNT(i.id) FILTER (WHERE NOT m.added)                                       AS committed_count,
+				COUNT(i.id) FILTER (WHERE m.added)                                           AS added_count,
+				COUNT(i.id) FILTER (WHERE NOT m.added AND i.status_category = 'Done')        AS committed_done_count,
+				COUNT(i.id) FILTER (WHERE m.carried_over)                                    AS carried_over_count,
+				COUNT(i.id) FILTER (WHERE i.status_category <> 'Done' AND i.delay_status = 'RED') AS red_count
+			FROM sprints s
+			JOIN jira_boards b ON b.id = s.board_id
+			LEFT JOIN LATERAL (
+				SELECT
+					si.jira_issue_id,
+					COALESCE(si.first_seen_at > s.start_date AND si.first_seen_at > MIN(si.first_seen_at) OVER (), FALSE) AS added,
+					EXISTS (
+						SELECT 1 FROM sprint_issues ci
+						JOIN sprints cs ON cs.id = ci.sprint_id
+						WHERE ci.jira_issue_id = si.jira_issue_id AND cs.connection_id = s.connection_id
+							AND cs.id <> s.id AND cs.state = 'closed'
+					) AS carried_over
+				FROM sprint_issues si
+				WHERE si.sprint_id = s.id
+			) m ON TRUE
+			LEFT JOIN issues i ON i.connection_id = s.connection_id
+				AND i.jira_issue_id = m.jira_issue_id AND i.deleted_at IS NULL
+			WHERE b.project_id = $1 AND s.state = 'active'
+			GROUP BY s.id, b.name
+			ORDER BY s.start_date, s.id`,
+			projectID,
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch current sprints"})
+			return
+		}
+
+		// 残り日数と進捗はプロジェクトのタイムゾーンの「今日」で判定する
+		now := time.Now().In(loc)
+		for i := range sprints {
+			completeSprintHealth(&sprints[i], now)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": sprints})
+	}
+}
+
+// completeSprintHealth fills the fields of h computed from its counts and dates.
+func completeSprintHealth(h *SprintHealth, now time.Time) {
+	h.OpenCount = h.TotalCount - h.DoneCount
+	if h.TotalCount > 0 {
+		h.ProgressPercent = h.DoneCount * 100 / h.TotalCount
+	}
+	if h.EndDate != nil {
+		days := normalizer.SprintDaysRemaining(*h.EndDate, now)
+		h.DaysRemaining = &days
+	}
+	h.DelayStatus = normalizer.CalcSprintStatus(h.StartDate, h.EndDate, normalizer.SprintProgress{
+		Total: h.TotalCount,
+		Done:  h.DoneCount,
+		Red:   h.RedCount,
+	}, now)
+}
+
+// projectExists reports whether the project exists, writing a 404 or 500 otherwise.
+func projectExists(c *gin.Context, db *sqlx.DB, projectID int64) bool {
+	var exists bool
+	if err := db.Get(&exists, `SELECT EXISTS (SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NULL)`, projectID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch project"})
+		return false
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "project not found"})
+		return false
+	}
+	return true
+}
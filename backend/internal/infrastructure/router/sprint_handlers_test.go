@@ -0,0 +1,149 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/normalizer"
+)
+
+var sprintColumns = []string{
+	"id", "jira_sprint_id", "board_id", "board_name", "name", "state", "goal",
+	"start_date", "end_date", "complete_date", "total_count", "done_count",
+}
+
+var sprintHealthColumns = append(append([]string{}, sprintColumns...),
+	"committed_count", "added_count", "committed_done_count", "carried_over_count", "red_count",
+)
+
+func TestListProjectSprintsHandler_InvalidState(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/1/sprints?state=open", "", gin.Params{{Key: "id", Value: "1"}})
+
+	listProjectSprintsHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListProjectSprintsHandler_ProjectNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(999)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/999/sprints", "", gin.Params{{Key: "id", Value: "999"}})
+
+	listProjectSprintsHandlerWithDB(db)(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListProjectSprintsHandler_FiltersByState(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	start := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`FROM sprints s`).WithArgs(int64(1), "closed").
+		WillReturnRows(sqlmock.NewRows(sprintColumns).
+			AddRow(3, 13, 1, "PROJ board", "Sprint 3", "closed", nil, start, end, end, 8, 7))
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/1/sprints?state=closed", "", gin.Params{{Key: "id", Value: "1"}})
+
+	listProjectSprintsHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []SprintRow `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, int64(13), resp.Data[0].JiraSprintID)
+	assert.Equal(t, 7, resp.Data[0].DoneCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCurrentSprintsHandler_ComputesHealth(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM projects p`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone"}).AddRow(nil))
+	// 2 週間のスプリントの 1 週間目が終わった時点で 2/10 件のみ完了 → YELLOW
+	now := normalizer.Now()
+	start := now.Add(-7 * 24 * time.Hour)
+	end := now.Add(7 * 24 * time.Hour)
+	mock.ExpectQuery(`FROM sprints s`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(sprintHealthColumns).
+			AddRow(4, 14, 1, "PROJ board", "Sprint 4", "active", "Ship v1.1", start, end, nil, 10, 2, 8, 2, 1, 3, 0))
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/1/sprints/current", "", gin.Params{{Key: "id", Value: "1"}})
+
+	getCurrentSprintsHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []SprintHealth `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	got := resp.Data[0]
+	assert.Equal(t, 8, got.CommittedCount)
+	assert.Equal(t, 2, got.AddedCount)
+	assert.Equal(t, 3, got.CarriedOverCount)
+	assert.Equal(t, 8, got.OpenCount)
+	assert.Equal(t, 20, got.ProgressPercent)
+	require.NotNil(t, got.DaysRemaining)
+	assert.Equal(t, 7, *got.DaysRemaining)
+	assert.Equal(t, "YELLOW", got.DelayStatus)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCurrentSprintsHandler_UsesOrganizationTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`a\.timezone IS NOT NULL`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"timezone"}).AddRow("Pacific/Kiritimati"))
+	// 終了日はプロジェクトのタイムゾーンで 3 日後の 0 時（UTC では 2 日後の日中）
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day()+3, 0, 0, 0, 0, loc)
+	start := end.Add(-14 * 24 * time.Hour)
+	mock.ExpectQuery(`FROM sprints s`).WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(sprintHealthColumns).
+			AddRow(4, 14, 1, "PROJ board", "Sprint 4", "active", nil, start, end, nil, 10, 9, 10, 0, 9, 0, 0))
+	c, w := newDelayPolicyContext(http.MethodGet, "/projects/1/sprints/current", "", gin.Params{{Key: "id", Value: "1"}})
+
+	getCurrentSprintsHandlerWithDB(db)(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []SprintHealth `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	require.NotNil(t, resp.Data[0].DaysRemaining)
+	assert.Equal(t, 3, *resp.Data[0].DaysRemaining)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCompleteSprintHealth(t *testing.T) {
+	now := time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC)
+	past := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+
+	// 終了日を過ぎた未完了のスプリントは RED、残り日数は 0
+	h := SprintHealth{SprintRow: SprintRow{EndDate: &past, TotalCount: 4, DoneCount: 3}}
+	completeSprintHealth(&h, now)
+	assert.Equal(t, "RED", h.DelayStatus)
+	require.NotNil(t, h.DaysRemaining)
+	assert.Equal(t, 0, *h.DaysRemaining)
+	assert.Equal(t, 75, h.ProgressPercent)
+
+	// チケットも終了日もないスプリント
+	h = SprintHealth{}
+	completeSprintHealth(&h, now)
+	assert.Equal(t, "GREEN", h.DelayStatus)
+	assert.Nil(t, h.DaysRemaining)
+	assert.Equal(t, 0, h.ProgressPercent)
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -81,6 +83,8 @@ func createUserHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 // updateUserHandlerWithDB handles PUT /api/v1/users/:id.
 // Updates role and/or is_active for the specified user (admin only).
+// Deactivating a user or changing their role revokes their refresh tokens;
+// auth.Middleware rejects or re-authorizes their access tokens from the next request.
 func updateUserHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		idStr := c.Param("id")
@@ -104,19 +108,35 @@ func updateUserHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			}
 		}
 
-		// ロールと is_active を両方まとめて更新する
-		_, err = db.Exec(
-			`UPDATE users
-			 SET role      = COALESCE($1, role),
-			     is_active = COALESCE($2, is_active),
+		// ロールと is_active を両方まとめて更新し、変更前のロールを返す
+		var prevRole string
+		err = db.QueryRowx(
+			`UPDATE users u
+			 SET role      = COALESCE($1, u.role),
+			     is_active = COALESCE($2, u.is_active),
 			     updated_at = CURRENT_TIMESTAMP
-			 WHERE id = $3`,
+			 FROM users prev
+			 WHERE u.id = $3 AND prev.id = u.id
+			 RETURNING prev.role`,
 			req.Role, req.IsActive, targetID,
-		)
+		).Scan(&prevRole)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user"})
 			return
 		}
+		// 無効化・ロール変更前のセッションをリフレッシュで延長させない
+		deactivated := req.IsActive != nil && !*req.IsActive
+		roleChanged := req.Role != nil && *req.Role != prevRole
+		if deactivated || roleChanged {
+			if err := revokeRefreshTokens(db, targetID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke user sessions"})
+				return
+			}
+		}
 
 		var updated userListItem
 		err = db.QueryRowx(
@@ -134,6 +154,7 @@ func updateUserHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 
 // changePasswordHandlerWithDB handles PUT /api/v1/users/:id/password.
 // Resets the password for the specified user (admin only). Current password is not required.
+// The user's refresh tokens are revoked so that existing sessions must log in again.
 func changePasswordHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -164,12 +185,17 @@ func changePasswordHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
 			return
 		}
+		if err := revokeRefreshTokens(db, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke user sessions"})
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{"message": "password updated"})
 	}
 }
 
 // deleteUserHandlerWithDB handles DELETE /api/v1/users/:id.
 // Deletes the specified user (admin only). Self-deletion is prohibited.
+// The user's refresh tokens are deleted with the user (ON DELETE CASCADE).
 func deleteUserHandlerWithDB(db *sqlx.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		idStr := c.Param("id")
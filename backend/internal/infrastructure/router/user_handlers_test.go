@@ -187,8 +187,13 @@ func TestUpdateUserHandler_Success(t *testing.T) {
 	db, mock := newTestDB(t)
 	handler := updateUserHandlerWithDB(db)
 
-	mock.ExpectExec(`UPDATE users`).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`UPDATE users u(.|\n)*RETURNING prev.role`).
+		WithArgs("project_manager", nil, int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+	// ロールが変わった場合は既存のセッションを失効させる
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectQuery(`SELECT id, email, role, is_active FROM users`).
 		WithArgs(int64(2)).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "is_active"}).
@@ -208,6 +213,80 @@ func TestUpdateUserHandler_Success(t *testing.T) {
 	var resp userListItem
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	assert.Equal(t, "project_manager", resp.Role)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUserHandler_SameRoleKeepsSessions(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := updateUserHandlerWithDB(db)
+
+	mock.ExpectQuery(`UPDATE users u`).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+	mock.ExpectQuery(`SELECT id, email, role, is_active FROM users`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "is_active"}).
+			AddRow(2, "viewer@example.com", "viewer", true))
+
+	role := "viewer"
+	body, _ := json.Marshal(updateUserRequest{Role: &role})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "2"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateUserHandler_NotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := updateUserHandlerWithDB(db)
+
+	mock.ExpectQuery(`UPDATE users u`).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}))
+
+	inactive := false
+	body, _ := json.Marshal(updateUserRequest{IsActive: &inactive})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/9", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "9"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUpdateUserHandler_DeactivateRevokesSessions(t *testing.T) {
+	db, mock := newTestDB(t)
+	handler := updateUserHandlerWithDB(db)
+
+	mock.ExpectQuery(`UPDATE users u`).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("viewer"))
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, email, role, is_active FROM users`).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "is_active"}).
+			AddRow(2, "viewer@example.com", "viewer", false))
+
+	inactive := false
+	body, _ := json.Marshal(updateUserRequest{IsActive: &inactive})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/users/2", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "2"}}
+
+	handler(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestDeleteUserHandler_NotFound(t *testing.T) {
@@ -274,6 +353,10 @@ func TestChangePasswordHandler_Success(t *testing.T) {
 	// パスワードハッシュ更新が1行に影響する
 	mock.ExpectExec(`UPDATE users SET password_hash`).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	// 既存のセッションはログインし直させる
+	mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = NOW\(\) WHERE user_id = \$1 AND revoked_at IS NULL`).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
 
 	body := bytes.NewBufferString(`{"new_password":"newpass123"}`)
 	w := httptest.NewRecorder()
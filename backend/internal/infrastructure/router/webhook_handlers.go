@@ -0,0 +1,138 @@
+package router
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/internal/batch"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/secrets"
+)
+
+// maxWebhookBodyBytes limits the size of a webhook payload.
+const maxWebhookBodyBytes = 1 << 20
+
+// defaultWebhookConnection is the Jira connection that webhooks posted to
+// /api/v1/webhooks/jira (without a connection ID) belong to.
+const defaultWebhookConnection = secrets.DefaultEnvConnection
+
+// webhookConnection is the Jira connection a webhook is addressed to.
+type webhookConnection struct {
+	ID            int64  `db:"id"`
+	Name          string `db:"name"`
+	Enabled       bool   `db:"enabled"`
+	WebhookSecret string `db:"webhook_secret"`
+}
+
+// jiraWebhookHandler handles POST /api/v1/webhooks/jira/:id and its alias
+// POST /api/v1/webhooks/jira for the "default" connection.
+// Verifies the HMAC-SHA256 signature of the body with the webhook secret of the
+// connection (decrypted with cipher) and applies the issue/project event to the
+// DB as data of that connection. defaultSecret (JIRA_WEBHOOK_SECRET) is only
+// accepted for the "default" connection while it has no secret of its own.
+// Webhooks are rejected while the connection has no secret, and ignored for
+// disabled connections.
+func jiraWebhookHandler(db *sqlx.DB, cipher *secrets.TokenCipher, defaultSecret string, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := `SELECT id, name, enabled, webhook_secret FROM jira_connections WHERE name = $1`
+		var key any = defaultWebhookConnection
+		if param := c.Param("id"); param != "" {
+			connectionID, err := strconv.ParseInt(param, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid jira connection id"})
+				return
+			}
+			query = `SELECT id, name, enabled, webhook_secret FROM jira_connections WHERE id = $1`
+			key = connectionID
+		}
+
+		var conn webhookConnection
+		err := db.Get(&conn, query, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "jira connection not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch jira connection"})
+			return
+		}
+		secret, err := cipher.Decrypt(conn.WebhookSecret)
+		if err != nil {
+			log.Error("failed to decrypt jira webhook secret", zap.String("connection", conn.Name), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt webhook secret"})
+			return
+		}
+		// 他の接続のイベントを署名できないよう、共通のシークレットは default 接続にだけ使う
+		if secret == "" && conn.Name == defaultWebhookConnection {
+			secret = defaultSecret
+		}
+		if secret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "webhook secret not configured"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBodyBytes))
+		if err != nil {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "payload too large"})
+			return
+		}
+		if !validWebhookSignature(secret, body, c.GetHeader(jiraclient.WebhookSignatureHeader)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
+			return
+		}
+
+		var event jiraclient.WebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+			return
+		}
+
+		if !conn.Enabled {
+			// 無効化した接続のイベントは取り込まない（Jira に再送させないよう 200 を返す）
+			c.JSON(http.StatusOK, gin.H{"result": batch.WebhookIgnored})
+			return
+		}
+
+		processor := batch.NewWebhookProcessor(batch.NewRepository(db, conn.ID), log)
+		result, err := processor.Process(c.Request.Context(), event)
+		if errors.Is(err, batch.ErrInvalidWebhookEvent) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error("failed to process jira webhook", zap.String("event", event.WebhookEvent), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process webhook"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"result": result})
+	}
+}
+
+// validWebhookSignature reports whether header ("sha256=<hex>") is the
+// HMAC-SHA256 of body keyed with secret.
+func validWebhookSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,177 @@
+package router
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+func signWebhook(body string) string {
+	return signWebhookWith(testWebhookSecret, body)
+}
+
+func signWebhookWith(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookParams are the path parameters of a webhook for Jira connection 1.
+var webhookParams = gin.Params{{Key: "id", Value: "1"}}
+
+var webhookConnectionColumns = []string{"id", "name", "enabled", "webhook_secret"}
+
+// expectWebhookConnection expects the lookup of Jira connection 1, whose
+// webhook secret testWebhookSecret is stored encrypted.
+func expectWebhookConnection(mock sqlmock.Sqlmock, enabled bool) {
+	secret, _ := testCipher.Encrypt(testWebhookSecret)
+	mock.ExpectQuery(`SELECT id, name, enabled, webhook_secret FROM jira_connections WHERE id = \$1`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(webhookConnectionColumns).AddRow(1, "site-a", enabled, secret))
+}
+
+func TestJiraWebhookHandler_SecretNotConfigured(t *testing.T) {
+	db, mock := newTestDB(t)
+	mock.ExpectQuery(`FROM jira_connections WHERE id = \$1`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(webhookConnectionColumns).AddRow(1, "site-a", true, ""))
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", `{}`, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(`{}`))
+
+	// 共通のシークレットは default 以外の接続には使わない
+	jiraWebhookHandler(db, testCipher, testWebhookSecret, zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestJiraWebhookHandler_InvalidSignature(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"webhookEvent":"jira:issue_deleted","issue":{"id":"1"}}`
+	expectWebhookConnection(mock, true)
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(`{"webhookEvent":"other"}`))
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestJiraWebhookHandler_OtherConnectionSecretRejected(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"webhookEvent":"comment_created"}`
+	expectWebhookConnection(mock, true)
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhookWith("global-secret", body))
+
+	jiraWebhookHandler(db, testCipher, "global-secret", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestJiraWebhookHandler_DefaultAliasUsesGlobalSecret(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"webhookEvent":"comment_created"}`
+	mock.ExpectQuery(`FROM jira_connections WHERE name = \$1`).
+		WithArgs("default").
+		WillReturnRows(sqlmock.NewRows(webhookConnectionColumns).AddRow(1, "default", true, ""))
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira", body, nil)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhookWith("global-secret", body))
+
+	jiraWebhookHandler(db, testCipher, "global-secret", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":"ignored"}`, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJiraWebhookHandler_InvalidConnectionID(t *testing.T) {
+	db, _ := newTestDB(t)
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/abc", `{}`, gin.Params{{Key: "id", Value: "abc"}})
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJiraWebhookHandler_ConnectionNotFound(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"webhookEvent":"comment_created"}`
+	mock.ExpectQuery(`FROM jira_connections WHERE id = \$1`).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(webhookConnectionColumns))
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(body))
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestJiraWebhookHandler_DisabledConnection(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"timestamp":1771894800000,"webhookEvent":"jira:issue_deleted",
+		"issue":{"id":"1","key":"PROJ-1","fields":{"updated":"2026-02-24T09:00:00.000+0900","project":{"id":"10"}}}}`
+	expectWebhookConnection(mock, false)
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(body))
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":"ignored"}`, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJiraWebhookHandler_InvalidEvent(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"webhookEvent":"jira:issue_updated"}`
+	expectWebhookConnection(mock, true)
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(body))
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJiraWebhookHandler_IgnoresUnsupportedEvent(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"webhookEvent":"comment_created"}`
+	expectWebhookConnection(mock, true)
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(body))
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":"ignored"}`, w.Body.String())
+}
+
+func TestJiraWebhookHandler_IssueDeleted(t *testing.T) {
+	db, mock := newTestDB(t)
+	body := `{"timestamp":1771894800000,"webhookEvent":"jira:issue_deleted",
+		"issue":{"id":"1","key":"PROJ-1","fields":{"updated":"2026-02-24T09:00:00.000+0900","project":{"id":"10"}}}}`
+	expectWebhookConnection(mock, true)
+	mock.ExpectExec(`UPDATE issues SET deleted_at`).
+		WithArgs("1", sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c, w := newDelayPolicyContext(http.MethodPost, "/webhooks/jira/1", body, webhookParams)
+	c.Request.Header.Set(jiraclient.WebhookSignatureHeader, signWebhook(body))
+
+	jiraWebhookHandler(db, testCipher, "", zap.NewNop())(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"result":"applied"}`, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,8 +9,11 @@ import (
 )
 
 const (
-	// accessTokenDuration is the lifetime of an access token.
-	accessTokenDuration = 24 * time.Hour
+	// DefaultAccessTokenDuration is the default lifetime of an access token.
+	// Access tokens are short-lived; clients renew them with a refresh token.
+	DefaultAccessTokenDuration = 15 * time.Minute
+	// DefaultRefreshTokenDuration is the default lifetime of a refresh token.
+	DefaultRefreshTokenDuration = 30 * 24 * time.Hour
 )
 
 // Claims represents the JWT payload used by this application.
@@ -23,12 +26,40 @@ type Claims struct {
 
 // TokenManager handles JWT generation and validation.
 type TokenManager struct {
-	secret []byte
+	secret          []byte
+	accessDuration  time.Duration
+	refreshDuration time.Duration
 }
 
-// NewTokenManager creates a TokenManager with the given signing secret.
+// NewTokenManager creates a TokenManager with the given signing secret and the
+// default token lifetimes.
 func NewTokenManager(secret string) *TokenManager {
-	return &TokenManager{secret: []byte(secret)}
+	return &TokenManager{
+		secret:          []byte(secret),
+		accessDuration:  DefaultAccessTokenDuration,
+		refreshDuration: DefaultRefreshTokenDuration,
+	}
+}
+
+// SetDurations overrides the lifetimes of access and refresh tokens.
+// Non-positive values keep the current lifetime.
+func (m *TokenManager) SetDurations(access, refresh time.Duration) {
+	if access > 0 {
+		m.accessDuration = access
+	}
+	if refresh > 0 {
+		m.refreshDuration = refresh
+	}
+}
+
+// AccessTokenDuration returns the lifetime of the access tokens it issues.
+func (m *TokenManager) AccessTokenDuration() time.Duration {
+	return m.accessDuration
+}
+
+// RefreshTokenDuration returns the lifetime of the refresh tokens it issues.
+func (m *TokenManager) RefreshTokenDuration() time.Duration {
+	return m.refreshDuration
 }
 
 // GenerateAccessToken creates a signed JWT access token for the given user.
@@ -40,7 +71,7 @@ func (m *TokenManager) GenerateAccessToken(userID int64, email, role string) (st
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenDuration)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessDuration)),
 		},
 	}
 
@@ -77,6 +77,44 @@ func TestValidate_ExpiredToken(t *testing.T) {
 	}
 }
 
+func TestGenerateAccessToken_Duration(t *testing.T) {
+	tm := NewTokenManager("test-secret")
+	tm.SetDurations(5*time.Minute, 0)
+
+	token, err := tm.GenerateAccessToken(1, "user@example.com", "viewer")
+	if err != nil {
+		t.Fatalf("expected no error generating token, got: %v", err)
+	}
+	claims, err := tm.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("expected no error validating token, got: %v", err)
+	}
+	if got := claims.ExpiresAt.Sub(claims.IssuedAt.Time); got != 5*time.Minute {
+		t.Errorf("expected 5m lifetime, got %v", got)
+	}
+	// 0 以下は既定値のまま
+	if tm.RefreshTokenDuration() != DefaultRefreshTokenDuration {
+		t.Errorf("expected default refresh duration, got %v", tm.RefreshTokenDuration())
+	}
+}
+
+func TestGenerateRefreshToken(t *testing.T) {
+	token, hash, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(token) != 43 {
+		t.Errorf("expected 43-char token, got %d", len(token))
+	}
+	if hash != HashRefreshToken(token) || hash == token {
+		t.Errorf("expected hash of token, got %q", hash)
+	}
+	other, _, _ := GenerateRefreshToken()
+	if other == token {
+		t.Error("expected unique tokens")
+	}
+}
+
 func TestPassword_HashAndCheck(t *testing.T) {
 	hash, err := HashPassword("my-secure-password")
 	if err != nil {
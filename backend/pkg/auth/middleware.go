@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -9,9 +11,25 @@ import (
 
 const claimsKey = "claims"
 
+// ErrUserRevoked is returned by a UserChecker when the user a token was issued
+// to has been deactivated or deleted.
+var ErrUserRevoked = errors.New("user is deactivated or deleted")
+
+// UserChecker verifies that the user of a valid token may still use it.
+// CheckUser returns the current role of the user, or ErrUserRevoked when the
+// user has been deactivated or deleted.
+type UserChecker interface {
+	CheckUser(ctx context.Context, userID int64) (role string, err error)
+}
+
 // Middleware returns a Gin handler that validates the Bearer JWT token.
 // On success it stores the *Claims in the context under the key "claims".
-func Middleware(tm *TokenManager) gin.HandlerFunc {
+//
+// When users is non-nil every request also checks that the user is still
+// active and replaces the role in the claims with the user's current role, so
+// that deactivating, deleting or demoting a user takes effect immediately
+// rather than when the access token expires.
+func Middleware(tm *TokenManager, users UserChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -36,6 +54,20 @@ func Middleware(tm *TokenManager) gin.HandlerFunc {
 			return
 		}
 
+		if users != nil {
+			role, err := users.CheckUser(c.Request.Context(), claims.UserID)
+			if errors.Is(err, ErrUserRevoked) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to verify user"})
+				return
+			}
+			// トークン発行後にロールが変更されていても現在のロールで認可する
+			claims.Role = role
+		}
+
 		c.Set(claimsKey, claims)
 		c.Next()
 	}
@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -55,7 +57,7 @@ func TestGetClaims_Set(t *testing.T) {
 
 func TestMiddleware_MissingHeader(t *testing.T) {
 	tm := NewTokenManager("secret")
-	router := testRouter(Middleware(tm))
+	router := testRouter(Middleware(tm, nil))
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -69,7 +71,7 @@ func TestMiddleware_MissingHeader(t *testing.T) {
 
 func TestMiddleware_BadFormat(t *testing.T) {
 	tm := NewTokenManager("secret")
-	router := testRouter(Middleware(tm))
+	router := testRouter(Middleware(tm, nil))
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -84,7 +86,7 @@ func TestMiddleware_BadFormat(t *testing.T) {
 
 func TestMiddleware_InvalidToken(t *testing.T) {
 	tm := NewTokenManager("secret")
-	router := testRouter(Middleware(tm))
+	router := testRouter(Middleware(tm, nil))
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -99,7 +101,7 @@ func TestMiddleware_InvalidToken(t *testing.T) {
 
 func TestMiddleware_ExpiredToken(t *testing.T) {
 	tm := NewTokenManager("secret")
-	router := testRouter(Middleware(tm))
+	router := testRouter(Middleware(tm, nil))
 
 	past := time.Now().Add(-2 * time.Hour)
 	claims := Claims{
@@ -131,7 +133,7 @@ func TestMiddleware_ValidToken(t *testing.T) {
 
 	// Capture claims set by middleware for assertion
 	var gotClaims *Claims
-	router.GET("/", Middleware(tm), func(c *gin.Context) {
+	router.GET("/", Middleware(tm, nil), func(c *gin.Context) {
 		gotClaims = GetClaims(c)
 		c.Status(http.StatusOK)
 	})
@@ -151,6 +153,86 @@ func TestMiddleware_ValidToken(t *testing.T) {
 	assert.Equal(t, "admin", gotClaims.Role)
 }
 
+// stubUserChecker returns role and err for every user and records the checked IDs.
+type stubUserChecker struct {
+	role    string
+	err     error
+	checked []int64
+}
+
+func (s *stubUserChecker) CheckUser(_ context.Context, userID int64) (string, error) {
+	s.checked = append(s.checked, userID)
+	return s.role, s.err
+}
+
+func requestWithToken(t *testing.T, router *gin.Engine, tm *TokenManager) *httptest.ResponseRecorder {
+	t.Helper()
+	token, err := tm.GenerateAccessToken(7, "user@example.com", "viewer")
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ActiveUser(t *testing.T) {
+	tm := NewTokenManager("secret")
+	users := &stubUserChecker{role: "viewer"}
+	router := testRouter(Middleware(tm, users))
+
+	w := requestWithToken(t, router, tm)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []int64{7}, users.checked)
+}
+
+func TestMiddleware_RevokedUser(t *testing.T) {
+	tm := NewTokenManager("secret")
+	// 無効化・削除されたユーザーのトークンは有効期限内でも拒否する
+	router := testRouter(Middleware(tm, &stubUserChecker{err: ErrUserRevoked}))
+
+	w := requestWithToken(t, router, tm)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "token has been revoked", resp["error"])
+}
+
+func TestMiddleware_UsesCurrentRole(t *testing.T) {
+	tm := NewTokenManager("secret")
+	// viewer として発行したトークンでも、admin に昇格済みなら admin として扱う（降格も同様）
+	router := testRouter(Middleware(tm, &stubUserChecker{role: "admin"}), RequireRole("admin"))
+
+	w := requestWithToken(t, router, tm)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMiddleware_DemotedUserLosesRole(t *testing.T) {
+	tm := NewTokenManager("secret")
+	token, err := tm.GenerateAccessToken(7, "admin@example.com", "admin")
+	require.NoError(t, err)
+	router := testRouter(Middleware(tm, &stubUserChecker{role: "viewer"}), RequireRole("admin"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMiddleware_UserCheckError(t *testing.T) {
+	tm := NewTokenManager("secret")
+	router := testRouter(Middleware(tm, &stubUserChecker{err: errors.New("db down")}))
+
+	w := requestWithToken(t, router, tm)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 // --- RequireRole tests ---
 
 func TestRequireRole_NoClaims(t *testing.T) {
@@ -169,7 +251,7 @@ func TestRequireRole_NoClaims(t *testing.T) {
 
 func TestRequireRole_WrongRole(t *testing.T) {
 	tm := NewTokenManager("secret")
-	router := testRouter(Middleware(tm), RequireRole("admin"))
+	router := testRouter(Middleware(tm, nil), RequireRole("admin"))
 
 	// Generate a viewer token
 	token, err := tm.GenerateAccessToken(1, "viewer@example.com", "viewer")
@@ -188,7 +270,7 @@ func TestRequireRole_WrongRole(t *testing.T) {
 
 func TestRequireRole_AllowedRole(t *testing.T) {
 	tm := NewTokenManager("secret")
-	router := testRouter(Middleware(tm), RequireRole("admin", "project_manager"))
+	router := testRouter(Middleware(tm, nil), RequireRole("admin", "project_manager"))
 
 	token, err := tm.GenerateAccessToken(1, "pm@example.com", "project_manager")
 	require.NoError(t, err)
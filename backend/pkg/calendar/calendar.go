@@ -0,0 +1,53 @@
+// Package calendar provides business calendars used to count business days:
+// Saturdays and Sundays plus a list of holidays are non-business days.
+package calendar
+
+import "time"
+
+// DateLayout is the layout of holiday dates ("YYYY-MM-DD").
+const DateLayout = "2006-01-02"
+
+// Holiday is a non-business day of a calendar.
+type Holiday struct {
+	Date string // "YYYY-MM-DD"
+	Name string
+}
+
+// Calendar decides which days are business days. The zero value and a nil
+// *Calendar treat every weekday as a business day.
+type Calendar struct {
+	holidays map[string]bool
+}
+
+// New returns a calendar with the given holidays.
+func New(holidays []Holiday) *Calendar {
+	c := &Calendar{holidays: make(map[string]bool, len(holidays))}
+	for _, h := range holidays {
+		c.holidays[h.Date] = true
+	}
+	return c
+}
+
+// IsBusinessDay reports whether the date of t is neither a weekend nor a holiday.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	switch t.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	return !c.holidays[t.Format(DateLayout)]
+}
+
+// AddBusinessDays returns the n-th business day after t (n >= 0). With n = 0,
+// t itself is returned even when it is not a business day.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	for n > 0 {
+		t = t.AddDate(0, 0, 1)
+		if c.IsBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
@@ -0,0 +1,104 @@
+package calendar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(DateLayout, s)
+	return t
+}
+
+func TestIsBusinessDay(t *testing.T) {
+	c := New([]Holiday{{Date: "2026-02-23", Name: "天皇誕生日"}})
+	cases := map[string]bool{
+		"2026-02-20": true,  // 金
+		"2026-02-21": false, // 土
+		"2026-02-22": false, // 日
+		"2026-02-23": false, // 祝日（月）
+		"2026-02-24": true,  // 火
+	}
+	for d, want := range cases {
+		if got := c.IsBusinessDay(date(d)); got != want {
+			t.Errorf("IsBusinessDay(%s) = %v, want %v", d, got, want)
+		}
+	}
+}
+
+func TestNilCalendarSkipsWeekendsOnly(t *testing.T) {
+	var c *Calendar
+	if c.IsBusinessDay(date("2026-02-21")) {
+		t.Error("expected Saturday to be a non-business day")
+	}
+	if !c.IsBusinessDay(date("2026-02-23")) {
+		t.Error("expected Monday to be a business day without holidays")
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	c := New([]Holiday{{Date: "2026-02-23"}})
+	cases := []struct {
+		from string
+		n    int
+		want string
+	}{
+		{"2026-02-19", 0, "2026-02-19"},
+		{"2026-02-19", 1, "2026-02-20"}, // 木 → 金
+		{"2026-02-20", 1, "2026-02-24"}, // 金 → 土日・祝日を飛ばして火
+		{"2026-02-20", 3, "2026-02-26"},
+		{"2026-02-21", 0, "2026-02-21"}, // 0 日は休日でもそのまま
+	}
+	for _, tc := range cases {
+		if got := c.AddBusinessDays(date(tc.from), tc.n).Format(DateLayout); got != tc.want {
+			t.Errorf("AddBusinessDays(%s, %d) = %s, want %s", tc.from, tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestParseHolidays(t *testing.T) {
+	in := "国民の祝日・休日月日,国民の祝日・休日名称\n" +
+		"2026/1/1,元日\n" +
+		"\n" +
+		"# コメント\n" +
+		"2026-01-12, 成人の日\n" +
+		"2026/01/01,元日（重複）\n" +
+		"2026/2/11\n"
+	got, err := ParseHolidays(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Holiday{
+		{Date: "2026-01-01", Name: "元日（重複）"},
+		{Date: "2026-01-12", Name: "成人の日"},
+		{Date: "2026-02-11", Name: ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseHolidays_ShiftJIS(t *testing.T) {
+	sjis, err := japanese.ShiftJIS.NewEncoder().String("国民の祝日・休日月日,国民の祝日・休日名称\r\n2026/2/23,天皇誕生日\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseHolidays(strings.NewReader(sjis))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []Holiday{{Date: "2026-02-23", Name: "天皇誕生日"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParseHolidays_InvalidDate(t *testing.T) {
+	_, err := ParseHolidays(strings.NewReader("2026/1/1,元日\n2026/13/1,不正\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error on line 2, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// holidayDateLayouts are the date layouts accepted in holiday list files.
+// "2006/1/2" is the format of the Cabinet Office holiday list (syukujitsu.csv).
+var holidayDateLayouts = []string{DateLayout, "2006/1/2"}
+
+// ParseHolidays reads a holiday list file: CSV rows of date and (optional) name,
+// with dates in "YYYY-MM-DD" or "YYYY/M/D" format. A header row, blank lines and
+// lines starting with '#' are skipped. Files that are not valid UTF-8 are read as
+// Shift_JIS, so the Cabinet Office list can be imported as downloaded.
+// Dates are normalized to "YYYY-MM-DD"; a date listed twice keeps the last name.
+func ParseHolidays(r io.Reader) ([]Holiday, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read holiday list: %w", err)
+	}
+	if !utf8.Valid(data) {
+		if data, err = japanese.ShiftJIS.NewDecoder().Bytes(data); err != nil {
+			return nil, fmt.Errorf("decode holiday list: %w", err)
+		}
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+
+	var holidays []Holiday
+	index := make(map[string]int)
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse holiday list: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		date, ok := parseHolidayDate(rec[0])
+		if !ok {
+			// 先頭行は見出しとして読み飛ばす
+			if first {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: invalid date %q", line, rec[0])
+		}
+		var name string
+		if len(rec) > 1 {
+			name = strings.TrimSpace(rec[1])
+		}
+
+		if i, dup := index[date]; dup {
+			holidays[i].Name = name
+			continue
+		}
+		index[date] = len(holidays)
+		holidays = append(holidays, Holiday{Date: date, Name: name})
+	}
+	return holidays, nil
+}
+
+func parseHolidayDate(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range holidayDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(DateLayout), true
+		}
+	}
+	return "", false
+}
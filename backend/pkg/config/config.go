@@ -4,16 +4,28 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config はアプリケーション設定を保持する
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Log      LogConfig
-	Auth     AuthConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Log       LogConfig
+	Auth      AuthConfig
+	Report    ReportConfig
+	JiraOAuth JiraOAuthConfig
+}
+
+// ReportConfig は日付の集計・表示に関する設定
+type ReportConfig struct {
+	// Timezone は期日の判定や日次集計で「今日」を決めるタイムゾーン（IANA 名）。
+	// 組織ごとの設定（organizations.timezone）がある場合はそちらが優先される。
+	Timezone string
+	// Location は Timezone を読み込んだもの
+	Location *time.Location
 }
 
 // AuthConfig はAPI認証設定
@@ -22,6 +34,27 @@ type AuthConfig struct {
 	JWTSecret      string
 	// AllowedOrigins は CORS で許可するオリジンのカンマ区切りリスト。空の場合は全オリジン許可。
 	AllowedOrigins string
+	// JiraWebhookSecret は default 接続の Jira Webhook の署名検証に使う共有シークレット。
+	// 接続ごとのシークレット（jira_connections.webhook_secret）が未設定の場合にだけ使う。
+	JiraWebhookSecret string
+	// AccessTokenTTL はアクセストークン（JWT）の有効期間。期限後はリフレッシュトークンで更新する。
+	AccessTokenTTL time.Duration
+	// RefreshTokenTTL はリフレッシュトークンの有効期間。更新のたびに新しいトークンに置き換わる。
+	RefreshTokenTTL time.Duration
+}
+
+// JiraOAuthConfig は Jira 接続の OAuth 2.0 (3LO) で使う Atlassian のアプリ設定
+type JiraOAuthConfig struct {
+	// ClientID は developer console で作成したアプリのクライアント ID。空の場合は OAuth 接続を使えない。
+	ClientID string
+	// ClientSecret はアプリのシークレット
+	ClientSecret string
+	// RedirectURL はアプリに登録したコールバック URL（https://<API サーバー>/api/v1/oauth/jira/callback）
+	RedirectURL string
+	// Scopes は要求するスコープのスペース区切り。空の場合は jiraclient.DefaultOAuthScopes
+	Scopes string
+	// ResultURL は同意フローの完了後にリダイレクトするフロントエンドの URL。空の場合は JSON で結果を返す。
+	ResultURL string
 }
 
 // ServerConfig はサーバー設定
@@ -56,6 +89,21 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
+	timezone := getEnv("REPORT_TIMEZONE", "Asia/Tokyo")
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REPORT_TIMEZONE: %w", err)
+	}
+
+	accessTokenTTL, err := time.ParseDuration(getEnv("ACCESS_TOKEN_TTL", "15m"))
+	if err != nil || accessTokenTTL <= 0 {
+		return nil, fmt.Errorf("invalid ACCESS_TOKEN_TTL: %q", getEnv("ACCESS_TOKEN_TTL", ""))
+	}
+	refreshTokenTTL, err := time.ParseDuration(getEnv("REFRESH_TOKEN_TTL", "720h"))
+	if err != nil || refreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("invalid REFRESH_TOKEN_TTL: %q", getEnv("REFRESH_TOKEN_TTL", ""))
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:    getEnv("PORT", "8080"),
@@ -75,8 +123,22 @@ func Load() (*Config, error) {
 		},
 		Auth: AuthConfig{
 			// デフォルト値は開発用。本番環境では必ず JWT_SECRET 環境変数で上書きすること。
-			JWTSecret:      getEnv("JWT_SECRET", "dev-secret-change-in-production"),
-			AllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", ""),
+			JWTSecret:         getEnv("JWT_SECRET", "dev-secret-change-in-production"),
+			AllowedOrigins:    getEnv("CORS_ALLOWED_ORIGINS", ""),
+			JiraWebhookSecret: getEnv("JIRA_WEBHOOK_SECRET", ""),
+			AccessTokenTTL:    accessTokenTTL,
+			RefreshTokenTTL:   refreshTokenTTL,
+		},
+		Report: ReportConfig{
+			Timezone: timezone,
+			Location: location,
+		},
+		JiraOAuth: JiraOAuthConfig{
+			ClientID:     getEnv("JIRA_OAUTH_CLIENT_ID", ""),
+			ClientSecret: getEnv("JIRA_OAUTH_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("JIRA_OAUTH_REDIRECT_URL", ""),
+			Scopes:       getEnv("JIRA_OAUTH_SCOPES", ""),
+			ResultURL:    getEnv("JIRA_OAUTH_RESULT_URL", ""),
 		},
 	}
 
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,7 +17,9 @@ func TestLoad_Defaults(t *testing.T) {
 		"PORT", "GIN_MODE",
 		"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE",
 		"LOG_LEVEL", "LOG_FORMAT",
-		"JWT_SECRET", "CORS_ALLOWED_ORIGINS",
+		"JWT_SECRET", "CORS_ALLOWED_ORIGINS", "JIRA_WEBHOOK_SECRET",
+		"ACCESS_TOKEN_TTL", "REFRESH_TOKEN_TTL",
+		"REPORT_TIMEZONE",
 	} {
 		t.Setenv(key, "")
 	}
@@ -36,6 +39,11 @@ func TestLoad_Defaults(t *testing.T) {
 	assert.Equal(t, "json", cfg.Log.Format)
 	assert.Equal(t, "dev-secret-change-in-production", cfg.Auth.JWTSecret)
 	assert.Equal(t, "", cfg.Auth.AllowedOrigins)
+	assert.Equal(t, "", cfg.Auth.JiraWebhookSecret)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.AccessTokenTTL)
+	assert.Equal(t, 720*time.Hour, cfg.Auth.RefreshTokenTTL)
+	assert.Equal(t, "Asia/Tokyo", cfg.Report.Timezone)
+	assert.Equal(t, "Asia/Tokyo", cfg.Report.Location.String())
 }
 
 // TestLoad_EnvOverrides verifies that environment variables correctly
@@ -53,6 +61,10 @@ func TestLoad_EnvOverrides(t *testing.T) {
 	t.Setenv("LOG_FORMAT", "text")
 	t.Setenv("JWT_SECRET", "my-jwt-secret")
 	t.Setenv("CORS_ALLOWED_ORIGINS", "https://example.com")
+	t.Setenv("JIRA_WEBHOOK_SECRET", "webhook-secret")
+	t.Setenv("ACCESS_TOKEN_TTL", "5m")
+	t.Setenv("REFRESH_TOKEN_TTL", "168h")
+	t.Setenv("REPORT_TIMEZONE", "America/New_York")
 
 	cfg, err := Load()
 
@@ -69,6 +81,10 @@ func TestLoad_EnvOverrides(t *testing.T) {
 	assert.Equal(t, "text", cfg.Log.Format)
 	assert.Equal(t, "my-jwt-secret", cfg.Auth.JWTSecret)
 	assert.Equal(t, "https://example.com", cfg.Auth.AllowedOrigins)
+	assert.Equal(t, "webhook-secret", cfg.Auth.JiraWebhookSecret)
+	assert.Equal(t, 5*time.Minute, cfg.Auth.AccessTokenTTL)
+	assert.Equal(t, 168*time.Hour, cfg.Auth.RefreshTokenTTL)
+	assert.Equal(t, "America/New_York", cfg.Report.Location.String())
 }
 
 // TestLoad_InvalidDBPort verifies that Load() returns an error when DB_PORT
@@ -82,6 +98,28 @@ func TestLoad_InvalidDBPort(t *testing.T) {
 	assert.Contains(t, err.Error(), "DB_PORT")
 }
 
+// TestLoad_InvalidTimezone verifies that Load() returns an error when
+// REPORT_TIMEZONE is not a known IANA time zone.
+func TestLoad_InvalidTimezone(t *testing.T) {
+	t.Setenv("REPORT_TIMEZONE", "Mars/Olympus")
+
+	_, err := Load()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "REPORT_TIMEZONE")
+}
+
+// TestLoad_InvalidTokenTTL verifies that Load() returns an error when
+// ACCESS_TOKEN_TTL is not a positive duration.
+func TestLoad_InvalidTokenTTL(t *testing.T) {
+	t.Setenv("ACCESS_TOKEN_TTL", "-1m")
+
+	_, err := Load()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ACCESS_TOKEN_TTL")
+}
+
 // TestGetDSN verifies that GetDSN() formats the connection string correctly.
 func TestGetDSN(t *testing.T) {
 	db := &DatabaseConfig{
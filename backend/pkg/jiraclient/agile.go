@@ -0,0 +1,97 @@
+package jiraclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	boardPath = "/rest/agile/1.0/board"
+	// boardSprintPath is formatted with the board ID.
+	boardSprintPath = "/rest/agile/1.0/board/%d/sprint"
+	// sprintIssuePath is formatted with the sprint ID.
+	sprintIssuePath = "/rest/agile/1.0/sprint/%d/issue"
+	agilePageSize   = 50
+)
+
+// GetProjectBoardsContext fetches all agile boards (Scrum and Kanban) that show
+// issues of the given project, handling pagination automatically.
+// The Agile API is only available on sites with Jira Software.
+func (c *Client) GetProjectBoardsContext(ctx context.Context, projectIDOrKey string) ([]Board, error) {
+	var all []Board
+	startAt := 0
+
+	for {
+		var resp BoardPage
+		path := fmt.Sprintf("%s?projectKeyOrId=%s&startAt=%d&maxResults=%d",
+			boardPath, url.QueryEscape(projectIDOrKey), startAt, agilePageSize)
+
+		if err := c.get(ctx, path, &resp); err != nil {
+			return nil, fmt.Errorf("get boards of %s (startAt=%d): %w", projectIDOrKey, startAt, err)
+		}
+
+		all = append(all, resp.Values...)
+
+		if resp.IsLast || len(resp.Values) == 0 {
+			break
+		}
+		startAt += len(resp.Values)
+	}
+
+	return all, nil
+}
+
+// GetBoardSprintsContext fetches all sprints (future, active and closed) of a
+// Scrum board, handling pagination automatically.
+func (c *Client) GetBoardSprintsContext(ctx context.Context, boardID int64) ([]Sprint, error) {
+	var all []Sprint
+	startAt := 0
+
+	for {
+		var resp SprintPage
+		path := fmt.Sprintf(boardSprintPath+"?startAt=%d&maxResults=%d", boardID, startAt, agilePageSize)
+
+		if err := c.get(ctx, path, &resp); err != nil {
+			return nil, fmt.Errorf("get sprints of board %d (startAt=%d): %w", boardID, startAt, err)
+		}
+
+		all = append(all, resp.Values...)
+
+		if resp.IsLast || len(resp.Values) == 0 {
+			break
+		}
+		startAt += len(resp.Values)
+	}
+
+	return all, nil
+}
+
+// GetSprintIssueIDsContext fetches the IDs of all issues in a sprint, handling
+// pagination automatically. For a closed sprint, these are the issues that were
+// in the sprint when it was completed, including the ones that were not done.
+func (c *Client) GetSprintIssueIDsContext(ctx context.Context, sprintID int64) ([]string, error) {
+	var ids []string
+	startAt := 0
+
+	for {
+		var resp SprintIssuePage
+		// チケットの ID だけが必要なため、フィールドは最小限にする
+		path := fmt.Sprintf(sprintIssuePath+"?fields=status&startAt=%d&maxResults=%d", sprintID, startAt, issuePageSize)
+
+		if err := c.get(ctx, path, &resp); err != nil {
+			return nil, fmt.Errorf("get issues of sprint %d (startAt=%d): %w", sprintID, startAt, err)
+		}
+
+		for _, issue := range resp.Issues {
+			ids = append(ids, issue.ID)
+		}
+
+		if len(resp.Issues) == 0 || resp.StartAt+len(resp.Issues) >= resp.Total {
+			break
+		}
+		startAt += len(resp.Issues)
+	}
+
+	return ids, nil
+}
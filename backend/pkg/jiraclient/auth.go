@@ -0,0 +1,42 @@
+package jiraclient
+
+import (
+	"errors"
+	"net/http"
+)
+
+// errAuthenticate marks errors of Authenticator.Authenticate, which are not
+// retried.
+var errAuthenticate = errors.New("authenticate jira request")
+
+// Authenticator adds credentials to a Jira API request.
+type Authenticator interface {
+	// Authenticate sets the credentials of req, typically its Authorization
+	// header. The request context bounds any token refresh.
+	Authenticate(req *http.Request) error
+}
+
+// BasicAuth authenticates with an Atlassian account email and API token
+// (Jira Cloud).
+type BasicAuth struct {
+	Email    string
+	APIToken string
+}
+
+// Authenticate implements Authenticator.
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Email, a.APIToken)
+	return nil
+}
+
+// BearerAuth authenticates with a personal access token (Jira Data Center and
+// Server).
+type BearerAuth struct {
+	Token string
+}
+
+// Authenticate implements Authenticator.
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
@@ -0,0 +1,50 @@
+package jiraclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientUsesAuthenticator(t *testing.T) {
+	var capturedAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"accountId": "abc"})
+	}))
+	defer ts.Close()
+
+	client := New(Config{BaseURL: ts.URL, Auth: BearerAuth{Token: "pat-token"}})
+	if err := client.Ping(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if capturedAuth != "Bearer pat-token" {
+		t.Errorf("expected Bearer auth header, got: %s", capturedAuth)
+	}
+}
+
+type failingAuth struct{}
+
+func (failingAuth) Authenticate(*http.Request) error { return ErrOAuthReauthorize }
+
+func TestClientDoesNotRetryAuthenticationErrors(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	client.auth = failingAuth{}
+	err := client.Ping()
+
+	if !errors.Is(err, ErrOAuthReauthorize) {
+		t.Errorf("expected ErrOAuthReauthorize, got: %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no request, got %d", calls)
+	}
+}
@@ -1,11 +1,13 @@
-// Package jiraclient provides a Jira Cloud REST API v3 client with
-// rate-limit handling and exponential backoff retry logic.
+// Package jiraclient provides a Jira REST API client with rate-limit handling
+// and exponential backoff retry logic. It talks to Jira Cloud through REST API
+// v3 and to Jira Data Center / Server through REST API v2 (see APIFlavor).
 package jiraclient
 
 import (
 	"bytes"
-	"encoding/base64"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,78 +16,163 @@ import (
 
 // Config holds the configuration for the Jira API client.
 type Config struct {
-	// BaseURL is the Jira Cloud instance URL, e.g. https://yourcompany.atlassian.net
+	// BaseURL is the Jira instance URL, e.g. https://yourcompany.atlassian.net.
+	// With OAuth 2.0 it is the API gateway URL of the site; see OAuthConfig.SiteURL.
 	BaseURL string
+	// Flavor selects the REST API of the deployment. The zero value uses
+	// APIFlavorCloud.
+	Flavor APIFlavor
 	// Email is the Jira account email used for Basic authentication.
 	Email string
 	// APIToken is the Jira API token used for Basic authentication.
 	APIToken string
+	// Auth authenticates the requests. When nil, Basic authentication with Email
+	// and APIToken is used.
+	Auth Authenticator
+	// SearchAPI selects the issue search endpoint of Jira Cloud. The zero value
+	// uses SearchAPIEnhanced. Jira Data Center always uses its offset search.
+	SearchAPI SearchAPI
+	// RequestsPerSecond is the initial and maximum rate of the client-side rate
+	// limiter shared by all requests of the client. 0 disables the limiter.
+	RequestsPerSecond float64
+	// Burst is the number of requests the limiter allows at once. Defaults to 1.
+	Burst int
 }
 
-// Client is a Jira Cloud REST API v3 client.
+// SearchAPI identifies the issue search endpoint and its pagination style.
+type SearchAPI string
+
+const (
+	// SearchAPIEnhanced uses POST /rest/api/3/search/jql, paginated with
+	// nextPageToken and terminated by isLast. It does not report a total count.
+	SearchAPIEnhanced SearchAPI = "enhanced"
+	// SearchAPILegacy uses POST /rest/api/3/issue/search, paginated with
+	// startAt/total. Atlassian is deprecating this endpoint.
+	SearchAPILegacy SearchAPI = "legacy"
+)
+
+// ParseSearchAPI converts a configuration value into a SearchAPI.
+// An empty string selects SearchAPIEnhanced.
+func ParseSearchAPI(s string) (SearchAPI, error) {
+	switch SearchAPI(s) {
+	case "", SearchAPIEnhanced:
+		return SearchAPIEnhanced, nil
+	case SearchAPILegacy:
+		return SearchAPILegacy, nil
+	default:
+		return "", fmt.Errorf("unknown jira search API %q (want %q or %q)", s, SearchAPIEnhanced, SearchAPILegacy)
+	}
+}
+
+// Client is a Jira REST API client.
 type Client struct {
 	cfg        Config
 	httpClient *http.Client
-	authHeader string
+	auth       Authenticator
+	// limiter は全リクエストで共有するレートリミッター。nil の場合は制限しない。
+	limiter *RateLimiter
 	// sleepFn はリトライ前の待機に使用する。テストで差し替え可能。
-	sleepFn func(time.Duration)
+	// ctx がキャンセルされた場合は待機を中断してエラーを返す。
+	sleepFn func(ctx context.Context, d time.Duration) error
 }
 
 // New creates a new Jira API client with the given configuration.
 func New(cfg Config) *Client {
-	token := base64.StdEncoding.EncodeToString([]byte(cfg.Email + ":" + cfg.APIToken))
-	return &Client{
+	if cfg.Flavor == "" {
+		cfg.Flavor = APIFlavorCloud
+	}
+	if cfg.SearchAPI == "" {
+		cfg.SearchAPI = SearchAPIEnhanced
+	}
+	c := &Client{
 		cfg:        cfg,
 		httpClient: &http.Client{Timeout: 30 * time.Second},
-		authHeader: "Basic " + token,
-		sleepFn:    time.Sleep,
+		auth:       cfg.Auth,
+		sleepFn:    sleepContext,
+	}
+	if c.auth == nil {
+		c.auth = BasicAuth{Email: cfg.Email, APIToken: cfg.APIToken}
+	}
+	if cfg.RequestsPerSecond > 0 {
+		c.limiter = NewRateLimiter(cfg.RequestsPerSecond, cfg.Burst)
 	}
+	return c
 }
 
-// Ping calls GET /rest/api/3/myself and returns nil when the credentials are valid.
+const myselfPath = "/rest/api/3/myself"
+
+// Ping calls GET /rest/api/{3|2}/myself and returns nil when the credentials are valid.
 func (c *Client) Ping() error {
+	return c.PingContext(context.Background())
+}
+
+// PingContext is like Ping but aborts the request when ctx is cancelled.
+func (c *Client) PingContext(ctx context.Context) error {
 	var dest map[string]interface{}
-	return c.get("/rest/api/3/myself", &dest)
+	path := myselfPath
+	if c.isDataCenter() {
+		path = dcMyselfPath
+	}
+	return c.get(ctx, path, &dest)
 }
 
 // get performs an authenticated GET request to the given path and decodes the
 // JSON response body into dest. Retries are applied on transient errors.
-func (c *Client) get(path string, dest interface{}) error {
+func (c *Client) get(ctx context.Context, path string, dest interface{}) error {
 	url := c.cfg.BaseURL + path
-	return c.doWithRetry(http.MethodGet, url, nil, dest)
+	return c.doWithRetry(ctx, http.MethodGet, url, nil, dest)
 }
 
 // post performs an authenticated POST request with a JSON body and decodes the
 // JSON response body into dest. Retries are applied on transient errors.
-func (c *Client) post(path string, body interface{}, dest interface{}) error {
+func (c *Client) post(ctx context.Context, path string, body interface{}, dest interface{}) error {
 	url := c.cfg.BaseURL + path
-	return c.doWithRetry(http.MethodPost, url, body, dest)
+	return c.doWithRetry(ctx, http.MethodPost, url, body, dest)
 }
 
 // doWithRetry executes an HTTP request, retrying on transient failures using
 // exponential backoff and honouring Retry-After headers on 429 responses.
-func (c *Client) doWithRetry(method, url string, body interface{}, dest interface{}) error {
+// Cancellation of ctx aborts both in-flight requests and backoff waits, and the
+// context error is returned without further retries.
+func (c *Client) doWithRetry(ctx context.Context, method, url string, body interface{}, dest interface{}) error {
 	var lastErr error
+	var delay time.Duration
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
 			// リトライ前に待機する（前回のレスポンスがない場合は純粋なバックオフ）
-			c.sleepFn(retryAfterDelay(nil, attempt-1))
+			if err := c.sleepFn(ctx, delay); err != nil {
+				return err
+			}
+		}
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return err
+			}
 		}
 
-		resp, err := c.doOnce(method, url, body)
+		resp, err := c.doOnce(ctx, method, url, body)
 		if err != nil {
+			// キャンセル・タイムアウトはリトライしない
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			// 認証情報の取得失敗（OAuth の再認可が必要など）はリトライしても回復しない
+			if errors.Is(err, errAuthenticate) {
+				return err
+			}
 			lastErr = err
+			delay = retryAfterDelay(nil, attempt)
 			continue
 		}
+		if c.limiter != nil {
+			c.limiter.Observe(resp)
+		}
 
 		if retryableStatus(resp.StatusCode) {
 			// レート制限・一時的サーバーエラーは待機してリトライ
-			delay := retryAfterDelay(resp, attempt)
+			delay = retryAfterDelay(resp, attempt)
 			resp.Body.Close()
 			lastErr = fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
-			if attempt < maxRetries {
-				c.sleepFn(delay)
-			}
 			continue
 		}
 
@@ -105,7 +192,7 @@ func (c *Client) doWithRetry(method, url string, body interface{}, dest interfac
 }
 
 // doOnce executes a single HTTP request without retry logic.
-func (c *Client) doOnce(method, url string, body interface{}) (*http.Response, error) {
+func (c *Client) doOnce(ctx context.Context, method, url string, body interface{}) (*http.Response, error) {
 	var bodyReader io.Reader
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -115,11 +202,13 @@ func (c *Client) doOnce(method, url string, body interface{}) (*http.Response, e
 		bodyReader = bytes.NewReader(b)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
-	req.Header.Set("Authorization", c.authHeader)
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", errAuthenticate, err)
+	}
 	req.Header.Set("Accept", "application/json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -127,3 +216,15 @@ func (c *Client) doOnce(method, url string, body interface{}) (*http.Response, e
 
 	return c.httpClient.Do(req)
 }
+
+// sleepContext waits for d or until ctx is cancelled, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
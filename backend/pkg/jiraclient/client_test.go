@@ -1,9 +1,12 @@
 package jiraclient
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -11,12 +14,18 @@ import (
 // newTestClient creates a Client pointed at the given test server URL.
 // Sleep is replaced with a no-op to keep tests fast.
 func newTestClient(serverURL string) *Client {
+	return newTestClientWithSearchAPI(serverURL, "")
+}
+
+// newTestClientWithSearchAPI is newTestClient with an explicit issue search endpoint.
+func newTestClientWithSearchAPI(serverURL string, api SearchAPI) *Client {
 	c := New(Config{
-		BaseURL:  serverURL,
-		Email:    "test@example.com",
-		APIToken: "test-token",
+		BaseURL:   serverURL,
+		Email:     "test@example.com",
+		APIToken:  "test-token",
+		SearchAPI: api,
 	})
-	c.sleepFn = func(time.Duration) {}
+	c.sleepFn = func(context.Context, time.Duration) error { return nil }
 	return c
 }
 
@@ -150,7 +159,7 @@ func TestSearchIssues_SinglePage(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := newTestClient(ts.URL)
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPILegacy)
 	got, err := client.SearchIssues(IssueSearchOptions{JQL: "project = PROJ"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -189,7 +198,7 @@ func TestSearchIssues_Pagination(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := newTestClient(ts.URL)
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPILegacy)
 	got, err := client.SearchIssues(IssueSearchOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -214,7 +223,7 @@ func TestSearchIssuesUpdatedAfter(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	client := newTestClient(ts.URL)
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPILegacy)
 	_, err := client.SearchIssuesUpdatedAfter("2026-01-01T00:00:00")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -246,3 +255,449 @@ func TestClientSetsAuthorizationHeader(t *testing.T) {
 		t.Errorf("expected Basic auth header, got: %s", capturedAuth)
 	}
 }
+
+func TestSearchIssuesPages_ResumeFromStartAt(t *testing.T) {
+	// StartAt=2 から再開した場合、2件目以降のページのみ取得される
+	var startAts []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req IssueSearchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		startAts = append(startAts, req.StartAt)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(IssueSearchResponse{
+			Issues:  []Issue{{Key: "P-3"}},
+			StartAt: req.StartAt,
+			Total:   3,
+		})
+	}))
+	defer ts.Close()
+
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPILegacy)
+	var cursors []int
+	err := client.SearchIssuesPages(IssueSearchOptions{Cursor: PageCursor{StartAt: 2}}, func(issues []Issue, next PageCursor) error {
+		cursors = append(cursors, next.StartAt)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(startAts) != 1 || startAts[0] != 2 {
+		t.Errorf("expected a single request with startAt=2, got %v", startAts)
+	}
+	if len(cursors) != 1 || cursors[0] != 3 {
+		t.Errorf("expected next cursor 3, got %v", cursors)
+	}
+}
+
+func TestSearchIssuesPages_CallbackErrorStops(t *testing.T) {
+	callCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(IssueSearchResponse{
+			Issues: []Issue{{Key: "P-1"}},
+			Total:  10,
+		})
+	}))
+	defer ts.Close()
+
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPILegacy)
+	wantErr := errors.New("stop")
+	err := client.SearchIssuesPages(IssueSearchOptions{}, func([]Issue, PageCursor) error { return wantErr })
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 API call, got %d", callCount)
+	}
+}
+
+// --- Context tests ---
+
+func TestSearchIssuesContext_CancelledBeforeStart(t *testing.T) {
+	callCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		writeJSON(w, JQLSearchResponse{IsLast: true})
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := newTestClient(ts.URL)
+	_, err := client.SearchIssuesContext(ctx, IssueSearchOptions{JQL: "project = P"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if callCount != 0 {
+		t.Errorf("expected no API calls, got %d", callCount)
+	}
+}
+
+func TestGetAllProjectsContext_CancelsInFlightRequest(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// クライアントが切断するまでレスポンスを返さない
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := newTestClient(ts.URL)
+	start := time.Now()
+	_, err := client.GetAllProjectsContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request was not aborted promptly (%s)", elapsed)
+	}
+}
+
+func TestPingContext_CancelStopsBackoff(t *testing.T) {
+	callCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	// 実際の待機（1秒以上のバックオフ）を使い、キャンセルで中断されることを確認する
+	client := newTestClient(ts.URL)
+	client.sleepFn = sleepContext
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.PingContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("backoff was not interrupted (%s)", elapsed)
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 API call before cancellation, got %d", callCount)
+	}
+}
+
+func TestDoWithRetry_SleepsOncePerRetry(t *testing.T) {
+	callCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		if callCount < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, map[string]string{})
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	var sleeps []time.Duration
+	client.sleepFn = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if callCount != 3 {
+		t.Errorf("expected 3 calls, got %d", callCount)
+	}
+	// 2回の失敗に対して待機も2回（バックオフを二重に待たない）
+	if len(sleeps) != 2 {
+		t.Fatalf("expected 2 sleeps, got %v", sleeps)
+	}
+	if sleeps[0] < initialBackoff || sleeps[1] < 2*initialBackoff {
+		t.Errorf("expected exponential backoff, got %v", sleeps)
+	}
+}
+
+func TestGetProjectVersions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/project/10000/versions" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id":"1","projectId":10000,"name":"v1.0","released":true,"releaseDate":"2026-01-31"},
+			{"id":"2","projectId":10000,"name":"v1.1","archived":false,"startDate":"2026-02-01"}
+		]`))
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	got, err := client.GetProjectVersionsContext(context.Background(), "10000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Version{
+		{ID: "1", ProjectID: 10000, Name: "v1.0", Released: true, ReleaseDate: "2026-01-31"},
+		{ID: "2", ProjectID: 10000, Name: "v1.1", StartDate: "2026-02-01"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+// --- Agile tests ---
+
+func TestGetProjectBoards_Pagination(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board" || r.URL.Query().Get("projectKeyOrId") != "PROJ" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("startAt") == "0" {
+			writeJSON(w, BoardPage{Values: []Board{{ID: 1, Name: "Scrum", Type: "scrum"}}})
+			return
+		}
+		writeJSON(w, BoardPage{Values: []Board{{ID: 2, Name: "Kanban", Type: "kanban"}}, StartAt: 1, IsLast: true})
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	got, err := client.GetProjectBoardsContext(context.Background(), "PROJ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[1].Type != "kanban" {
+		t.Errorf("expected 2 boards, got %+v", got)
+	}
+}
+
+func TestGetBoardSprints(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board/1/sprint" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"isLast":true,"values":[
+			{"id":10,"name":"Sprint 1","state":"closed","startDate":"2026-01-05T09:00:00.000+09:00","endDate":"2026-01-19T09:00:00.000+09:00","completeDate":"2026-01-19T10:00:00.000+09:00","originBoardId":1},
+			{"id":11,"name":"Sprint 2","state":"active","goal":"Release v1.1","startDate":"2026-01-19T09:00:00.000+09:00","endDate":"2026-02-02T09:00:00.000+09:00","originBoardId":1}
+		]}`))
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	got, err := client.GetBoardSprintsContext(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[1].State != "active" || got[1].Goal != "Release v1.1" || got[0].CompleteDate == "" {
+		t.Errorf("unexpected sprints: %+v", got)
+	}
+}
+
+func TestGetSprintIssueIDs_Pagination(t *testing.T) {
+	var startAts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/sprint/11/issue" {
+			http.NotFound(w, r)
+			return
+		}
+		startAt := r.URL.Query().Get("startAt")
+		startAts = append(startAts, startAt)
+		if startAt == "0" {
+			writeJSON(w, SprintIssuePage{Issues: []Issue{{ID: "1"}, {ID: "2"}}, Total: 3})
+			return
+		}
+		writeJSON(w, SprintIssuePage{Issues: []Issue{{ID: "3"}}, StartAt: 2, Total: 3})
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	got, err := client.GetSprintIssueIDsContext(context.Background(), 11)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"1", "2", "3"}) {
+		t.Errorf("unexpected issue IDs: %v", got)
+	}
+	if len(startAts) != 2 {
+		t.Errorf("expected 2 requests, got %v", startAts)
+	}
+}
+
+// --- Changelog tests ---
+
+func TestGetIssueChangelog_Pagination(t *testing.T) {
+	var startAts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-1/changelog" {
+			http.NotFound(w, r)
+			return
+		}
+		startAt := r.URL.Query().Get("startAt")
+		startAts = append(startAts, startAt)
+		if startAt == "0" {
+			writeJSON(w, ChangelogPage{Values: []ChangelogHistory{{ID: "1"}, {ID: "2"}}, Total: 3})
+			return
+		}
+		writeJSON(w, ChangelogPage{Values: []ChangelogHistory{{ID: "3"}}, StartAt: 2, Total: 3, IsLast: true})
+	}))
+	defer ts.Close()
+
+	client := newTestClient(ts.URL)
+	got, err := client.GetIssueChangelogContext(context.Background(), "PROJ-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[2].ID != "3" {
+		t.Errorf("expected 3 histories, got %+v", got)
+	}
+	if len(startAts) != 2 || startAts[1] != "2" {
+		t.Errorf("expected requests at startAt 0 and 2, got %v", startAts)
+	}
+}
+
+func TestSearchIssues_ExpandChangelog(t *testing.T) {
+	var body JQLSearchRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		writeJSON(w, JQLSearchResponse{IsLast: true})
+	}))
+	defer ts.Close()
+
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPIEnhanced)
+	if _, err := client.SearchIssues(IssueSearchOptions{JQL: "project = PROJ", Expand: []string{ExpandChangelog}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.Expand != "changelog" {
+		t.Errorf("expected expand=changelog, got %q", body.Expand)
+	}
+}
+
+func TestSearchIssues_ExtraFields(t *testing.T) {
+	var body JQLSearchRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"isLast":true,"issues":[{"id":"1","key":"P-1","fields":{
+			"summary":"s","customfield_10016":5,"labels":["a","b"],"customfield_10020":null}}]}`))
+	}))
+	defer ts.Close()
+
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPIEnhanced)
+	got, err := client.SearchIssues(IssueSearchOptions{JQL: "project = PROJ", ExtraFields: []string{"customfield_10016", "labels", "parent"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := append(append([]string(nil), defaultIssueFields...), "customfield_10016", "labels"); !reflect.DeepEqual(body.Fields, want) {
+		t.Errorf("expected fields %v, got %v", want, body.Fields)
+	}
+	if len(defaultIssueFields) != 11 {
+		t.Errorf("defaultIssueFields must not be modified, got %v", defaultIssueFields)
+	}
+
+	f := got[0].Fields
+	if f.Summary != "s" {
+		t.Errorf("expected summary s, got %q", f.Summary)
+	}
+	if string(f.Extra["customfield_10016"]) != "5" || string(f.Extra["labels"]) != `["a","b"]` {
+		t.Errorf("unexpected extra fields: %v", f.Extra)
+	}
+	if _, ok := f.Extra["customfield_10020"]; ok {
+		t.Error("null fields must be omitted from Extra")
+	}
+	if _, ok := f.Extra["summary"]; ok {
+		t.Error("typed fields must not be duplicated in Extra")
+	}
+}
+
+func TestIssueFields_ParentAndLinks(t *testing.T) {
+	var f IssueFields
+	err := json.Unmarshal([]byte(`{
+		"parent":{"id":"100","key":"PROJ-100","fields":{"summary":"Epic"}},
+		"issuelinks":[
+			{"id":"1","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"id":"200","key":"PROJ-200"}},
+			{"id":"2","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"inwardIssue":{"id":"300","key":"PROJ-300"}}
+		]}`), &f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Parent == nil || f.Parent.Key != "PROJ-100" || f.Parent.ID != "100" {
+		t.Errorf("unexpected parent: %+v", f.Parent)
+	}
+	if len(f.IssueLinks) != 2 || f.IssueLinks[0].OutwardIssue == nil || f.IssueLinks[1].InwardIssue.Key != "PROJ-300" {
+		t.Errorf("unexpected issue links: %+v", f.IssueLinks)
+	}
+	// フィールドマッピングから参照できるよう parent は Extra にも残す
+	if _, ok := f.Extra["parent"]; !ok {
+		t.Error("expected parent to be kept in Extra")
+	}
+}
+
+func TestIssueFields_MarshalRoundTrip(t *testing.T) {
+	in := IssueFields{Summary: "s", Extra: map[string]json.RawMessage{"customfield_1": json.RawMessage(`"x"`)}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out IssueFields
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Summary != "s" || string(out.Extra["customfield_1"]) != `"x"` {
+		t.Errorf("unexpected round trip result: %+v", out)
+	}
+}
+
+func TestChangelog_IsComplete(t *testing.T) {
+	var nilLog *Changelog
+	if nilLog.IsComplete() {
+		t.Error("nil changelog must not be complete")
+	}
+	if !(&Changelog{Total: 1, Histories: []ChangelogHistory{{ID: "1"}}}).IsComplete() {
+		t.Error("expected complete changelog")
+	}
+	if (&Changelog{Total: 2, Histories: []ChangelogHistory{{ID: "1"}}}).IsComplete() {
+		t.Error("expected truncated changelog to be incomplete")
+	}
+}
+
+// --- Webhook tests ---
+
+func TestWebhookEvent_Unmarshal(t *testing.T) {
+	var issueEvent WebhookEvent
+	err := json.Unmarshal([]byte(`{
+		"timestamp":1771894800000,
+		"webhookEvent":"jira:issue_updated",
+		"issue":{"id":"1","key":"PROJ-1","fields":{"summary":"s","project":{"id":"10","key":"PROJ"}}},
+		"changelog":{"id":"500","items":[{"field":"status","fromString":"To Do","toString":"Done"}]}}`), &issueEvent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issueEvent.Issue == nil || issueEvent.Issue.Fields.Project.ID != "10" {
+		t.Errorf("unexpected issue: %+v", issueEvent.Issue)
+	}
+	if issueEvent.Changelog == nil || issueEvent.Changelog.ID != "500" || len(issueEvent.Changelog.Items) != 1 {
+		t.Errorf("unexpected changelog: %+v", issueEvent.Changelog)
+	}
+
+	// プロジェクトの ID は数値で送られる
+	var projectEvent WebhookEvent
+	err = json.Unmarshal([]byte(`{
+		"webhookEvent":"project_created",
+		"project":{"id":10000,"key":"NEW","name":"New","projectLead":{"accountId":"a1"}}}`), &projectEvent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := projectEvent.Project.ToProject()
+	if p.ID != "10000" || p.Key != "NEW" || p.Lead == nil || p.Lead.AccountID != "a1" {
+		t.Errorf("unexpected project: %+v", p)
+	}
+}
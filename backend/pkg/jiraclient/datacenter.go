@@ -0,0 +1,109 @@
+package jiraclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// APIFlavor identifies the Jira deployment and the REST API the client uses.
+type APIFlavor string
+
+const (
+	// APIFlavorCloud talks to Jira Cloud through REST API v3.
+	APIFlavorCloud APIFlavor = "cloud"
+	// APIFlavorDataCenter talks to Jira Data Center / Server through REST API v2.
+	// Issues are searched with POST /rest/api/2/search (startAt/total), projects
+	// are listed without pagination and changelogs are read from the issue.
+	APIFlavorDataCenter APIFlavor = "datacenter"
+)
+
+// ParseAPIFlavor converts a configuration value into an APIFlavor.
+// An empty string selects APIFlavorCloud.
+func ParseAPIFlavor(s string) (APIFlavor, error) {
+	switch APIFlavor(s) {
+	case "", APIFlavorCloud:
+		return APIFlavorCloud, nil
+	case APIFlavorDataCenter:
+		return APIFlavorDataCenter, nil
+	default:
+		return "", fmt.Errorf("unknown jira API flavor %q (want %q or %q)", s, APIFlavorCloud, APIFlavorDataCenter)
+	}
+}
+
+const (
+	dcMyselfPath  = "/rest/api/2/myself"
+	dcSearchPath  = "/rest/api/2/search"
+	dcProjectPath = "/rest/api/2/project"
+	// dcProjectVersionsPath is formatted with the project ID or key.
+	dcProjectVersionsPath = "/rest/api/2/project/%s/versions"
+	// dcIssuePath is formatted with the issue ID or key.
+	dcIssuePath = "/rest/api/2/issue/%s"
+)
+
+// isDataCenter reports whether the client talks to Jira Data Center / Server.
+func (c *Client) isDataCenter() bool {
+	return c.cfg.Flavor == APIFlavorDataCenter
+}
+
+// getAllProjectsDataCenter fetches all projects with GET /rest/api/2/project,
+// which returns them in a single array instead of pages.
+func (c *Client) getAllProjectsDataCenter(ctx context.Context) ([]Project, error) {
+	var projects []Project
+	if err := c.get(ctx, dcProjectPath+"?expand=lead", &projects); err != nil {
+		return nil, fmt.Errorf("get projects: %w", err)
+	}
+	return projects, nil
+}
+
+// searchIssuesDataCenter pages through POST /rest/api/2/search with
+// startAt/total. Data Center may return fewer issues per page than requested
+// (jira.search.views.default.max), so the offset advances by the page length.
+func (c *Client) searchIssuesDataCenter(ctx context.Context, jql string, fields, expand []string, startAt int, fn IssuePageFunc) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		req := IssueSearchRequest{
+			JQL:        jql,
+			StartAt:    startAt,
+			MaxResults: issuePageSize,
+			Fields:     fields,
+			Expand:     expand,
+		}
+
+		var resp IssueSearchResponse
+		if err := c.post(ctx, dcSearchPath, req, &resp); err != nil {
+			return fmt.Errorf("search issues (startAt=%d): %w", startAt, err)
+		}
+
+		next := startAt + len(resp.Issues)
+		if len(resp.Issues) > 0 {
+			if err := fn(resp.Issues, PageCursor{StartAt: next}); err != nil {
+				return err
+			}
+		}
+
+		if next >= resp.Total || len(resp.Issues) == 0 {
+			break
+		}
+		startAt = next
+	}
+
+	return nil
+}
+
+// getIssueChangelogDataCenter reads the change history of an issue from
+// GET /rest/api/2/issue/{issueIdOrKey}?expand=changelog. Data Center has no
+// paginated changelog endpoint but embeds the complete history in the issue.
+func (c *Client) getIssueChangelogDataCenter(ctx context.Context, issueIDOrKey string) ([]ChangelogHistory, error) {
+	var issue Issue
+	path := fmt.Sprintf(dcIssuePath+"?fields=updated&expand=changelog", url.PathEscape(issueIDOrKey))
+	if err := c.get(ctx, path, &issue); err != nil {
+		return nil, fmt.Errorf("get changelog of %s: %w", issueIDOrKey, err)
+	}
+	if issue.Changelog == nil {
+		return nil, nil
+	}
+	return issue.Changelog.Histories, nil
+}
@@ -0,0 +1,235 @@
+package jiraclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// dcFixture serves a response recorded from Jira Data Center.
+func dcFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "datacenter", name))
+	if err != nil {
+		t.Errorf("read fixture: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Write(body)
+}
+
+// newDataCenterServer serves the recorded Data Center fixtures on the REST API
+// v2 paths and rejects the Cloud (v3) paths.
+func newDataCenterServer(t *testing.T) (*httptest.Server, *[]IssueSearchRequest) {
+	t.Helper()
+	var searches []IssueSearchRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/2/myself":
+			dcFixture(t, w, "myself.json")
+		case "GET /rest/api/2/project":
+			if r.URL.Query().Get("expand") != "lead" {
+				t.Errorf("expected expand=lead, got %q", r.URL.RawQuery)
+			}
+			dcFixture(t, w, "project.json")
+		case "GET /rest/api/2/project/OPS/versions":
+			w.Write([]byte(`[{"self":"https://jira.example.com/rest/api/2/version/10200","id":"10200","name":"2026.04","archived":false,"released":false,"releaseDate":"2026-04-30","projectId":10000}]`))
+		case "POST /rest/api/2/search":
+			var req IssueSearchRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			searches = append(searches, req)
+			// Data Center は要求より少ない件数で返すことがある
+			if req.StartAt == 0 {
+				dcFixture(t, w, "search_page1.json")
+			} else {
+				dcFixture(t, w, "search_page2.json")
+			}
+		case "GET /rest/api/2/issue/OPS-1":
+			if r.URL.Query().Get("expand") != "changelog" {
+				t.Errorf("expected expand=changelog, got %q", r.URL.RawQuery)
+			}
+			dcFixture(t, w, "issue_changelog.json")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &searches
+}
+
+func newDataCenterClient(serverURL string) *Client {
+	c := New(Config{BaseURL: serverURL, Auth: BearerAuth{Token: "pat"}, Flavor: APIFlavorDataCenter})
+	c.sleepFn = func(context.Context, time.Duration) error { return nil }
+	return c
+}
+
+func TestParseAPIFlavor(t *testing.T) {
+	cases := map[string]APIFlavor{"": APIFlavorCloud, "cloud": APIFlavorCloud, "datacenter": APIFlavorDataCenter}
+	for in, want := range cases {
+		got, err := ParseAPIFlavor(in)
+		if err != nil || got != want {
+			t.Errorf("ParseAPIFlavor(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseAPIFlavor("server"); err == nil {
+		t.Error("expected error for unknown flavor")
+	}
+}
+
+func TestDataCenter_Ping(t *testing.T) {
+	ts, _ := newDataCenterServer(t)
+
+	if err := newDataCenterClient(ts.URL).Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDataCenter_GetAllProjects(t *testing.T) {
+	ts, _ := newDataCenterServer(t)
+
+	got, err := newDataCenterClient(ts.URL).GetAllProjects()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(got))
+	}
+	if got[0].Key != "OPS" || got[0].Lead == nil || got[0].Lead.Identifier() != "JIRAUSER10100" {
+		t.Errorf("unexpected first project: %+v", got[0])
+	}
+	// ユーザーキーの無い古いバージョンではユーザー名で識別する
+	if got[1].Lead.Identifier() != "suzuki" {
+		t.Errorf("expected lead identified by name, got %q", got[1].Lead.Identifier())
+	}
+}
+
+func TestDataCenter_GetProjectVersions(t *testing.T) {
+	ts, _ := newDataCenterServer(t)
+
+	got, err := newDataCenterClient(ts.URL).GetProjectVersionsContext(context.Background(), "OPS")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ReleaseDate != "2026-04-30" || got[0].ProjectID != 10000 {
+		t.Errorf("unexpected versions: %+v", got)
+	}
+}
+
+func TestDataCenter_SearchIssuesPages(t *testing.T) {
+	ts, searches := newDataCenterServer(t)
+	// SearchAPI は Data Center では使わない
+	client := newDataCenterClient(ts.URL)
+	client.cfg.SearchAPI = SearchAPIEnhanced
+
+	var keys []string
+	var cursors []PageCursor
+	err := client.SearchIssuesPages(IssueSearchOptions{JQL: "project = OPS", Expand: []string{ExpandChangelog}}, func(issues []Issue, next PageCursor) error {
+		for _, issue := range issues {
+			keys = append(keys, issue.Key)
+		}
+		cursors = append(cursors, next)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := []string{"OPS-1", "OPS-2", "OPS-3"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	// 1 ページ目が 2 件で返ったため、2 ページ目は startAt=2 から取得する
+	if len(*searches) != 2 || (*searches)[1].StartAt != 2 {
+		t.Fatalf("unexpected search requests: %+v", *searches)
+	}
+	if want := []string{"changelog"}; !reflect.DeepEqual((*searches)[0].Expand, want) {
+		t.Errorf("expand = %v, want %v", (*searches)[0].Expand, want)
+	}
+	if want := []PageCursor{{StartAt: 2}, {StartAt: 3}}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("cursors = %v, want %v", cursors, want)
+	}
+}
+
+func TestDataCenter_SearchIssuesResumeFromStartAt(t *testing.T) {
+	ts, searches := newDataCenterServer(t)
+
+	got, err := newDataCenterClient(ts.URL).SearchIssues(IssueSearchOptions{JQL: "project = OPS", Cursor: PageCursor{StartAt: 2}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].Key != "OPS-3" || len(*searches) != 1 {
+		t.Errorf("unexpected result: %d issues, %d requests", len(got), len(*searches))
+	}
+}
+
+func TestDataCenter_IssueDecoding(t *testing.T) {
+	ts, _ := newDataCenterServer(t)
+
+	got, err := newDataCenterClient(ts.URL).SearchIssues(IssueSearchOptions{JQL: "project = OPS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := got[0].Fields
+	if first.Status.StatusCategory != (IssueStatusCategory{ID: StatusCategoryIndeterminate, Key: "indeterminate", Name: "進行中"}) {
+		t.Errorf("unexpected status category: %+v", first.Status.StatusCategory)
+	}
+	if first.Assignee.Identifier() != "JIRAUSER10100" || first.Assignee.Name != "tanaka" {
+		t.Errorf("unexpected assignee: %+v", first.Assignee)
+	}
+	if string(first.Extra["customfield_10106"]) != "3.0" {
+		t.Errorf("expected custom field in Extra, got %s", first.Extra["customfield_10106"])
+	}
+	// key の無いステータスカテゴリーも ID は取得できる
+	if cat := got[1].Fields.Status.StatusCategory; cat.ID != StatusCategoryDone || cat.Key != "" {
+		t.Errorf("unexpected status category: %+v", cat)
+	}
+	if got[1].Fields.Assignee != nil || got[1].Fields.Priority != nil {
+		t.Errorf("expected unassigned issue without priority, got %+v", got[1].Fields)
+	}
+	third := got[2].Fields
+	if third.Parent == nil || third.Parent.Key != "OPS-1" || len(third.IssueLinks) != 1 || third.IssueLinks[0].InwardIssue.Key != "OPS-2" {
+		t.Errorf("unexpected parent or links: %+v", third)
+	}
+}
+
+func TestDataCenter_GetIssueChangelog(t *testing.T) {
+	ts, _ := newDataCenterServer(t)
+
+	got, err := newDataCenterClient(ts.URL).GetIssueChangelogContext(context.Background(), "OPS-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 histories, got %d", len(got))
+	}
+	if got[0].Items[0].Field != "duedate" || got[0].Items[0].To != "2026-03-31" || got[0].Author.Identifier() != "JIRAUSER10100" {
+		t.Errorf("unexpected history: %+v", got[0])
+	}
+}
+
+func TestUser_Identifier(t *testing.T) {
+	cases := []struct {
+		user *User
+		want string
+	}{
+		{nil, ""},
+		{&User{AccountID: "5b10a2844c20165700ede21g", Key: "k", Name: "n"}, "5b10a2844c20165700ede21g"},
+		{&User{Key: "JIRAUSER10100", Name: "tanaka"}, "JIRAUSER10100"},
+		{&User{Name: "suzuki"}, "suzuki"},
+	}
+	for _, tc := range cases {
+		if got := tc.user.Identifier(); got != tc.want {
+			t.Errorf("Identifier(%+v) = %q, want %q", tc.user, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package jiraclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeJira is an in-memory Jira that serves a fixed list of issues through both
+// the legacy (startAt/total) and the enhanced (nextPageToken/isLast) search
+// endpoints, using pageSize issues per page regardless of maxResults.
+type fakeJira struct {
+	issues   []Issue
+	pageSize int
+
+	mu       sync.Mutex
+	requests []fakeSearchRequest
+}
+
+// fakeSearchRequest records one search request received by fakeJira.
+type fakeSearchRequest struct {
+	Path    string
+	StartAt int
+	Token   string
+}
+
+func newFakeJira(t *testing.T, issueCount, pageSize int) (*fakeJira, *httptest.Server) {
+	t.Helper()
+	f := &fakeJira{pageSize: pageSize}
+	for i := 1; i <= issueCount; i++ {
+		f.issues = append(f.issues, Issue{ID: strconv.Itoa(10000 + i), Key: fmt.Sprintf("PROJ-%d", i)})
+	}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	return f, ts
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch r.URL.Path {
+	case issueSearchPath:
+		f.serveLegacy(w, r)
+	case jqlSearchPath:
+		f.serveEnhanced(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeJira) serveLegacy(w http.ResponseWriter, r *http.Request) {
+	var req IssueSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.record(fakeSearchRequest{Path: r.URL.Path, StartAt: req.StartAt})
+
+	writeJSON(w, IssueSearchResponse{
+		Issues:     f.page(req.StartAt),
+		StartAt:    req.StartAt,
+		MaxResults: f.pageSize,
+		Total:      len(f.issues),
+	})
+}
+
+func (f *fakeJira) serveEnhanced(w http.ResponseWriter, r *http.Request) {
+	var req JQLSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.record(fakeSearchRequest{Path: r.URL.Path, Token: req.NextPageToken})
+
+	// トークンは不透明な文字列として扱われるべきなので、オフセットをそのまま使わない
+	offset := 0
+	if req.NextPageToken != "" {
+		n, err := fmt.Sscanf(req.NextPageToken, "tok-%d", &offset)
+		if n != 1 || err != nil {
+			http.Error(w, "invalid nextPageToken", http.StatusBadRequest)
+			return
+		}
+	}
+
+	issues := f.page(offset)
+	resp := JQLSearchResponse{Issues: issues, IsLast: offset+len(issues) >= len(f.issues)}
+	if !resp.IsLast {
+		resp.NextPageToken = fmt.Sprintf("tok-%d", offset+len(issues))
+	}
+	writeJSON(w, resp)
+}
+
+func (f *fakeJira) page(offset int) []Issue {
+	if offset >= len(f.issues) {
+		return []Issue{}
+	}
+	end := offset + f.pageSize
+	if end > len(f.issues) {
+		end = len(f.issues)
+	}
+	return f.issues[offset:end]
+}
+
+func (f *fakeJira) record(r fakeSearchRequest) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// --- Pagination tests against fakeJira ---
+
+func TestSearchIssues_BothPaginationStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		api      SearchAPI
+		wantPath string
+	}{
+		{"enhanced", SearchAPIEnhanced, jqlSearchPath},
+		{"legacy", SearchAPILegacy, issueSearchPath},
+		{"default is enhanced", "", jqlSearchPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, ts := newFakeJira(t, 7, 3)
+			client := newTestClientWithSearchAPI(ts.URL, tt.api)
+
+			got, err := client.SearchIssues(IssueSearchOptions{JQL: "project = PROJ"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != 7 {
+				t.Fatalf("expected 7 issues, got %d", len(got))
+			}
+			if got[0].Key != "PROJ-1" || got[6].Key != "PROJ-7" {
+				t.Errorf("unexpected order: first=%s last=%s", got[0].Key, got[6].Key)
+			}
+			// 3件ずつ 3ページ
+			if len(fake.requests) != 3 {
+				t.Errorf("expected 3 requests, got %d", len(fake.requests))
+			}
+			for _, r := range fake.requests {
+				if r.Path != tt.wantPath {
+					t.Errorf("expected path %s, got %s", tt.wantPath, r.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchIssuesPages_EnhancedCursors(t *testing.T) {
+	_, ts := newFakeJira(t, 5, 2)
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPIEnhanced)
+
+	var cursors []PageCursor
+	err := client.SearchIssuesPages(IssueSearchOptions{JQL: "project = PROJ"}, func(_ []Issue, next PageCursor) error {
+		cursors = append(cursors, next)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 最終ページの次のカーソルは空
+	want := []PageCursor{{Token: "tok-2"}, {Token: "tok-4"}, {}}
+	if len(cursors) != len(want) {
+		t.Fatalf("expected %d cursors, got %v", len(want), cursors)
+	}
+	for i := range want {
+		if cursors[i] != want[i] {
+			t.Errorf("cursor %d: expected %+v, got %+v", i, want[i], cursors[i])
+		}
+	}
+}
+
+func TestSearchIssuesPages_EnhancedResumeFromToken(t *testing.T) {
+	fake, ts := newFakeJira(t, 5, 2)
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPIEnhanced)
+
+	got, err := client.SearchIssues(IssueSearchOptions{JQL: "project = PROJ", Cursor: PageCursor{Token: "tok-2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[0].Key != "PROJ-3" {
+		t.Errorf("expected PROJ-3..PROJ-5, got %d issues starting at %v", len(got), got)
+	}
+	if fake.requests[0].Token != "tok-2" {
+		t.Errorf("expected first request with token tok-2, got %q", fake.requests[0].Token)
+	}
+}
+
+func TestSearchIssuesPages_EnhancedStopsWithoutToken(t *testing.T) {
+	// isLast=false でもトークンが無ければ終了する（無限ループ防止）
+	callCount := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		writeJSON(w, JQLSearchResponse{Issues: []Issue{{Key: "P-1"}}, IsLast: false})
+	}))
+	defer ts.Close()
+
+	client := newTestClientWithSearchAPI(ts.URL, SearchAPIEnhanced)
+	got, err := client.SearchIssues(IssueSearchOptions{JQL: "project = P"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || callCount != 1 {
+		t.Errorf("expected 1 issue in 1 call, got %d issues in %d calls", len(got), callCount)
+	}
+}
+
+func TestSearchIssues_EmptyResult(t *testing.T) {
+	for _, api := range []SearchAPI{SearchAPIEnhanced, SearchAPILegacy} {
+		t.Run(string(api), func(t *testing.T) {
+			fake, ts := newFakeJira(t, 0, 2)
+			client := newTestClientWithSearchAPI(ts.URL, api)
+
+			got, err := client.SearchIssues(IssueSearchOptions{JQL: "project = PROJ"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != 0 || len(fake.requests) != 1 {
+				t.Errorf("expected 0 issues in 1 request, got %d issues in %d requests", len(got), len(fake.requests))
+			}
+		})
+	}
+}
+
+func TestParseSearchAPI(t *testing.T) {
+	for in, want := range map[string]SearchAPI{"": SearchAPIEnhanced, "enhanced": SearchAPIEnhanced, "legacy": SearchAPILegacy} {
+		got, err := ParseSearchAPI(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSearchAPI(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseSearchAPI("v2"); err == nil {
+		t.Error("expected error for unknown value")
+	}
+}
@@ -1,12 +1,26 @@
 package jiraclient
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
 
 const (
 	issueSearchPath = "/rest/api/3/issue/search"
+	jqlSearchPath   = "/rest/api/3/search/jql"
 	issuePageSize   = 100
+	// changelogPath is formatted with the issue ID or key.
+	changelogPath     = "/rest/api/3/issue/%s/changelog"
+	changelogPageSize = 100
 )
 
+// ExpandChangelog is the IssueSearchOptions.Expand value that embeds each
+// issue's change history in search results.
+const ExpandChangelog = "changelog"
+
 // defaultIssueFields is the list of fields requested from Jira for each issue.
 var defaultIssueFields = []string{
 	"summary",
@@ -17,46 +31,207 @@ var defaultIssueFields = []string{
 	"duedate",
 	"updated",
 	"project",
+	"parent",
+	"issuelinks",
+	"fixVersions",
 }
 
 // IssueSearchOptions contains optional filters for SearchIssues.
 type IssueSearchOptions struct {
 	// JQL is the Jira Query Language expression used to filter issues.
+	// The enhanced search endpoint rejects unbounded queries, so callers
+	// should always restrict it (e.g. by project or updated date).
 	JQL string
 	// Fields overrides the default list of fields to retrieve.
 	Fields []string
+	// ExtraFields are requested in addition to Fields (or the default fields),
+	// e.g. custom field IDs. Their values are returned in IssueFields.Extra.
+	ExtraFields []string
+	// Expand lists extra issue data to include, e.g. ExpandChangelog.
+	Expand []string
+	// Cursor is the position of the first page to fetch. Use a cursor received
+	// by an IssuePageFunc to resume an interrupted search; the zero value starts
+	// from the beginning.
+	Cursor PageCursor
+}
+
+// PageCursor identifies a page of issue search results. Only the field that
+// matches the client's SearchAPI is used.
+type PageCursor struct {
+	// StartAt is the offset of the page (SearchAPILegacy).
+	StartAt int
+	// Token is the nextPageToken of the page (SearchAPIEnhanced).
+	Token string
 }
 
+// IsZero reports whether c points at the first page.
+func (c PageCursor) IsZero() bool {
+	return c.StartAt == 0 && c.Token == ""
+}
+
+// IssuePageFunc is called by SearchIssuesPages for every page of issues.
+// next is the cursor of the page that follows, and can be stored to resume the
+// search. Returning an error stops the search and is returned as-is.
+type IssuePageFunc func(issues []Issue, next PageCursor) error
+
 // SearchIssues fetches all issues matching the given JQL query, handling pagination
-// automatically. If opts.JQL is empty, all issues are returned.
+// automatically.
 func (c *Client) SearchIssues(opts IssueSearchOptions) ([]Issue, error) {
+	return c.SearchIssuesContext(context.Background(), opts)
+}
+
+// SearchIssuesContext is like SearchIssues but stops fetching when ctx is cancelled.
+func (c *Client) SearchIssuesContext(ctx context.Context, opts IssueSearchOptions) ([]Issue, error) {
+	var all []Issue
+	err := c.SearchIssuesPagesContext(ctx, opts, func(issues []Issue, _ PageCursor) error {
+		all = append(all, issues...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// SearchIssuesPages fetches the issues matching the given JQL query page by page,
+// calling fn for each page instead of accumulating the results. The search starts
+// at opts.Cursor. The endpoint and pagination style follow Config.SearchAPI on
+// Jira Cloud; Jira Data Center is always paginated with startAt.
+func (c *Client) SearchIssuesPages(opts IssueSearchOptions, fn IssuePageFunc) error {
+	return c.SearchIssuesPagesContext(context.Background(), opts, fn)
+}
+
+// withExtraFields returns a copy of fields followed by the extra fields it does not contain yet.
+func withExtraFields(fields, extra []string) []string {
+	all := append([]string(nil), fields...)
+	for _, f := range extra {
+		if !slices.Contains(all, f) {
+			all = append(all, f)
+		}
+	}
+	return all
+}
+
+// SearchIssuesPagesContext is like SearchIssuesPages but stops fetching when ctx
+// is cancelled. fn is not called again once ctx is done.
+func (c *Client) SearchIssuesPagesContext(ctx context.Context, opts IssueSearchOptions, fn IssuePageFunc) error {
 	fields := opts.Fields
 	if len(fields) == 0 {
 		fields = defaultIssueFields
 	}
+	if len(opts.ExtraFields) > 0 {
+		fields = withExtraFields(fields, opts.ExtraFields)
+	}
 
-	var all []Issue
-	startAt := 0
+	if c.isDataCenter() {
+		return c.searchIssuesDataCenter(ctx, opts.JQL, fields, opts.Expand, opts.Cursor.StartAt, fn)
+	}
+	if c.cfg.SearchAPI == SearchAPILegacy {
+		return c.searchIssuesByOffset(ctx, opts.JQL, fields, opts.Expand, opts.Cursor.StartAt, fn)
+	}
+	return c.searchIssuesByToken(ctx, opts.JQL, fields, opts.Expand, opts.Cursor.Token, fn)
+}
 
+// searchIssuesByOffset pages through /rest/api/3/issue/search with startAt/total.
+func (c *Client) searchIssuesByOffset(ctx context.Context, jql string, fields, expand []string, startAt int, fn IssuePageFunc) error {
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		req := IssueSearchRequest{
-			JQL:        opts.JQL,
+			JQL:        jql,
 			StartAt:    startAt,
 			MaxResults: issuePageSize,
 			Fields:     fields,
+			Expand:     expand,
 		}
 
 		var resp IssueSearchResponse
-		if err := c.post(issueSearchPath, req, &resp); err != nil {
-			return nil, fmt.Errorf("search issues (startAt=%d): %w", startAt, err)
+		if err := c.post(ctx, issueSearchPath, req, &resp); err != nil {
+			return fmt.Errorf("search issues (startAt=%d): %w", startAt, err)
+		}
+
+		next := startAt + len(resp.Issues)
+		if len(resp.Issues) > 0 {
+			if err := fn(resp.Issues, PageCursor{StartAt: next}); err != nil {
+				return err
+			}
+		}
+
+		if next >= resp.Total || len(resp.Issues) == 0 {
+			break
+		}
+		startAt = next
+	}
+
+	return nil
+}
+
+// searchIssuesByToken pages through /rest/api/3/search/jql with nextPageToken
+// until the response reports isLast.
+func (c *Client) searchIssuesByToken(ctx context.Context, jql string, fields, expand []string, token string, fn IssuePageFunc) error {
+	for page := 0; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		req := JQLSearchRequest{
+			JQL:           jql,
+			NextPageToken: token,
+			MaxResults:    issuePageSize,
+			Fields:        fields,
+			Expand:        strings.Join(expand, ","),
+		}
+
+		var resp JQLSearchResponse
+		if err := c.post(ctx, jqlSearchPath, req, &resp); err != nil {
+			return fmt.Errorf("search issues (page=%d): %w", page, err)
+		}
+
+		// トークンがない場合も最終ページとみなす（同じページを無限に取得しないため）
+		last := resp.IsLast || resp.NextPageToken == ""
+		if len(resp.Issues) > 0 {
+			next := PageCursor{Token: resp.NextPageToken}
+			if last {
+				next.Token = ""
+			}
+			if err := fn(resp.Issues, next); err != nil {
+				return err
+			}
+		}
+
+		if last {
+			break
+		}
+		token = resp.NextPageToken
+	}
+
+	return nil
+}
+
+// GetIssueChangelogContext fetches the complete change history of an issue,
+// oldest first, handling pagination automatically.
+func (c *Client) GetIssueChangelogContext(ctx context.Context, issueIDOrKey string) ([]ChangelogHistory, error) {
+	if c.isDataCenter() {
+		return c.getIssueChangelogDataCenter(ctx, issueIDOrKey)
+	}
+	var all []ChangelogHistory
+	startAt := 0
+
+	for {
+		var resp ChangelogPage
+		path := fmt.Sprintf(changelogPath+"?startAt=%d&maxResults=%d",
+			url.PathEscape(issueIDOrKey), startAt, changelogPageSize)
+
+		if err := c.get(ctx, path, &resp); err != nil {
+			return nil, fmt.Errorf("get changelog of %s (startAt=%d): %w", issueIDOrKey, startAt, err)
 		}
 
-		all = append(all, resp.Issues...)
+		all = append(all, resp.Values...)
 
-		if startAt+len(resp.Issues) >= resp.Total || len(resp.Issues) == 0 {
+		if resp.IsLast || len(resp.Values) == 0 {
 			break
 		}
-		startAt += len(resp.Issues)
+		startAt += len(resp.Values)
 	}
 
 	return all, nil
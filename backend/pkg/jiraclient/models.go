@@ -1,5 +1,7 @@
 package jiraclient
 
+import "encoding/json"
+
 // Project represents a Jira project as returned by the project search API.
 type Project struct {
 	ID   string `json:"id"`
@@ -13,9 +15,28 @@ type User struct {
 	AccountID    string `json:"accountId"`
 	DisplayName  string `json:"displayName"`
 	EmailAddress string `json:"emailAddress"`
+	// Key and Name identify the user on Jira Data Center, which has no account IDs.
+	Key  string `json:"key,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Identifier returns the stable ID of the user: the account ID on Jira Cloud,
+// the user key on Jira Data Center (the username on versions without keys).
+func (u *User) Identifier() string {
+	switch {
+	case u == nil:
+		return ""
+	case u.AccountID != "":
+		return u.AccountID
+	case u.Key != "":
+		return u.Key
+	default:
+		return u.Name
+	}
 }
 
 // ProjectSearchResponse is the response from GET /rest/api/3/project/search.
+// Jira Data Center returns the projects as a plain array instead.
 type ProjectSearchResponse struct {
 	Values     []Project `json:"values"`
 	StartAt    int       `json:"startAt"`
@@ -29,6 +50,51 @@ type Issue struct {
 	ID     string      `json:"id"`
 	Key    string      `json:"key"`
 	Fields IssueFields `json:"fields"`
+	// Changelog is only present when the search requested expand=changelog.
+	Changelog *Changelog `json:"changelog,omitempty"`
+}
+
+// Changelog is the change history embedded in an issue by expand=changelog.
+// Jira may truncate it; when Total exceeds len(Histories), fetch the full
+// history with GetIssueChangelogContext.
+type Changelog struct {
+	StartAt    int                `json:"startAt"`
+	MaxResults int                `json:"maxResults"`
+	Total      int                `json:"total"`
+	Histories  []ChangelogHistory `json:"histories"`
+}
+
+// IsComplete reports whether the changelog contains every history entry.
+func (c *Changelog) IsComplete() bool {
+	return c != nil && c.StartAt == 0 && len(c.Histories) >= c.Total
+}
+
+// ChangelogHistory is a single change of an issue, possibly touching several fields.
+type ChangelogHistory struct {
+	ID      string          `json:"id"`
+	Author  *User           `json:"author,omitempty"`
+	Created string          `json:"created"` // e.g. "2026-01-15T10:30:00.000+0900"
+	Items   []ChangelogItem `json:"items"`
+}
+
+// ChangelogItem describes the change of one field.
+type ChangelogItem struct {
+	Field      string `json:"field"`
+	FieldID    string `json:"fieldId"`
+	FieldType  string `json:"fieldtype"`
+	From       string `json:"from"`
+	FromString string `json:"fromString"`
+	To         string `json:"to"`
+	ToString   string `json:"toString"`
+}
+
+// ChangelogPage is the response from GET /rest/api/3/issue/{issueIdOrKey}/changelog.
+type ChangelogPage struct {
+	Values     []ChangelogHistory `json:"values"`
+	StartAt    int                `json:"startAt"`
+	MaxResults int                `json:"maxResults"`
+	Total      int                `json:"total"`
+	IsLast     bool               `json:"isLast"`
 }
 
 // IssueFields contains the fields of a Jira issue.
@@ -41,6 +107,66 @@ type IssueFields struct {
 	DueDate        string          `json:"duedate"` // "YYYY-MM-DD" or ""
 	Updated        string          `json:"updated"`
 	Project        IssueProject    `json:"project"`
+	Parent         *IssueParent    `json:"parent,omitempty"`
+	IssueLinks     []IssueLink     `json:"issuelinks,omitempty"`
+	FixVersions    []IssueVersion  `json:"fixVersions,omitempty"`
+	// Extra holds the raw values of the requested fields that have no typed
+	// counterpart above (custom fields, labels, components, ...), keyed by field ID.
+	// Fields that Jira returned as null are omitted. parent, issuelinks and
+	// fixVersions are kept here as well, so that they can be read through a field mapping.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// issueFieldsAlias has the fields of IssueFields without its JSON methods.
+type issueFieldsAlias IssueFields
+
+// UnmarshalJSON decodes the typed fields and keeps every other non-null field in Extra.
+func (f *IssueFields) UnmarshalJSON(data []byte) error {
+	var typed issueFieldsAlias
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	*f = IssueFields(typed)
+	for id, raw := range all {
+		if _, known := typedIssueFields[id]; known || string(raw) == "null" {
+			continue
+		}
+		if f.Extra == nil {
+			f.Extra = make(map[string]json.RawMessage)
+		}
+		f.Extra[id] = raw
+	}
+	return nil
+}
+
+// MarshalJSON encodes the typed fields together with Extra.
+func (f IssueFields) MarshalJSON() ([]byte, error) {
+	typed, err := json.Marshal(issueFieldsAlias(f))
+	if err != nil || len(f.Extra) == 0 {
+		return typed, err
+	}
+	all := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(typed, &all); err != nil {
+		return nil, err
+	}
+	for id, raw := range f.Extra {
+		// 型付きのフィールドで出力済みの値を優先する
+		if _, set := all[id]; !set {
+			all[id] = raw
+		}
+	}
+	return json.Marshal(all)
+}
+
+// typedIssueFields are the field IDs decoded into the typed fields of IssueFields.
+var typedIssueFields = map[string]struct{}{
+	"summary": {}, "status": {}, "priority": {}, "issuetype": {},
+	"assignee": {}, "duedate": {}, "updated": {}, "project": {},
 }
 
 // IssueStatus represents the status of a Jira issue.
@@ -50,11 +176,22 @@ type IssueStatus struct {
 }
 
 // IssueStatusCategory represents the category of a Jira issue status.
+// ID is one of the fixed category IDs (StatusCategoryNew, ...); Key may be
+// missing or inconsistent on Jira Data Center.
 type IssueStatusCategory struct {
+	ID   int    `json:"id"`
 	Key  string `json:"key"`
 	Name string `json:"name"`
 }
 
+// Fixed IDs of the Jira status categories, shared by Jira Cloud and Data Center.
+const (
+	StatusCategoryUndefined     = 1 // "No Category"
+	StatusCategoryNew           = 2 // key "new" ("To Do")
+	StatusCategoryDone          = 3 // key "done"
+	StatusCategoryIndeterminate = 4 // key "indeterminate" ("In Progress")
+)
+
 // IssuePriority represents the priority of a Jira issue.
 type IssuePriority struct {
 	Name string `json:"name"`
@@ -71,18 +208,137 @@ type IssueProject struct {
 	Key string `json:"key"`
 }
 
-// IssueSearchRequest is the body for POST /rest/api/3/issue/search.
+// IssueParent is the parent of an issue: the epic of a story, or the issue of a subtask.
+type IssueParent struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// IssueLink is a link from an issue to another issue. Exactly one of InwardIssue
+// and OutwardIssue is set: OutwardIssue when the issue "blocks" the other one,
+// InwardIssue when it "is blocked by" it.
+type IssueLink struct {
+	ID           string        `json:"id"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *LinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *LinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// IssueLinkType describes a kind of issue link, e.g. Name "Blocks",
+// Inward "is blocked by", Outward "blocks".
+type IssueLinkType struct {
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+// LinkedIssue is the other issue of an issue link.
+type LinkedIssue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// IssueVersion is a version (release) an issue is assigned to through fixVersions.
+type IssueVersion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Version is a version (release) of a Jira project as returned by
+// GET /rest/api/3/project/{projectIdOrKey}/versions.
+type Version struct {
+	ID          string `json:"id"`
+	ProjectID   int64  `json:"projectId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Archived    bool   `json:"archived"`
+	Released    bool   `json:"released"`
+	StartDate   string `json:"startDate"`   // "YYYY-MM-DD" or ""
+	ReleaseDate string `json:"releaseDate"` // "YYYY-MM-DD" or ""
+}
+
+// Board is a Jira Software agile board.
+type Board struct {
+	ID       int64          `json:"id"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"` // "scrum" | "kanban" | "simple"
+	Location *BoardLocation `json:"location,omitempty"`
+}
+
+// BoardLocation is the project a board belongs to.
+type BoardLocation struct {
+	ProjectID  int64  `json:"projectId"`
+	ProjectKey string `json:"projectKey"`
+}
+
+// BoardPage is the response from GET /rest/agile/1.0/board.
+type BoardPage struct {
+	Values     []Board `json:"values"`
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	IsLast     bool    `json:"isLast"`
+}
+
+// Sprint is a sprint of a Scrum board. The dates are ISO 8601 date-times such
+// as "2026-01-05T09:00:00.000+09:00", or empty when not set.
+type Sprint struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"` // "future" | "active" | "closed"
+	Goal          string `json:"goal"`
+	StartDate     string `json:"startDate"`
+	EndDate       string `json:"endDate"`
+	CompleteDate  string `json:"completeDate"`
+	OriginBoardID int64  `json:"originBoardId"`
+}
+
+// SprintPage is the response from GET /rest/agile/1.0/board/{boardId}/sprint.
+type SprintPage struct {
+	Values     []Sprint `json:"values"`
+	StartAt    int      `json:"startAt"`
+	MaxResults int      `json:"maxResults"`
+	IsLast     bool     `json:"isLast"`
+}
+
+// SprintIssuePage is the response from GET /rest/agile/1.0/sprint/{sprintId}/issue.
+type SprintIssuePage struct {
+	Issues     []Issue `json:"issues"`
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+}
+
+// IssueSearchRequest is the body for POST /rest/api/3/issue/search and, on
+// Jira Data Center, POST /rest/api/2/search.
 type IssueSearchRequest struct {
 	JQL        string   `json:"jql"`
 	StartAt    int      `json:"startAt"`
 	MaxResults int      `json:"maxResults"`
 	Fields     []string `json:"fields"`
+	Expand     []string `json:"expand,omitempty"`
 }
 
-// IssueSearchResponse is the response from POST /rest/api/3/issue/search.
+// IssueSearchResponse is the response from POST /rest/api/3/issue/search and
+// POST /rest/api/2/search.
 type IssueSearchResponse struct {
 	Issues     []Issue `json:"issues"`
 	StartAt    int     `json:"startAt"`
 	MaxResults int     `json:"maxResults"`
 	Total      int     `json:"total"`
 }
+
+// JQLSearchRequest is the body for POST /rest/api/3/search/jql.
+type JQLSearchRequest struct {
+	JQL           string   `json:"jql"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+	MaxResults    int      `json:"maxResults"`
+	Fields        []string `json:"fields"`
+	Expand        string   `json:"expand,omitempty"` // comma-separated
+}
+
+// JQLSearchResponse is the response from POST /rest/api/3/search/jql.
+type JQLSearchResponse struct {
+	Issues        []Issue `json:"issues"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+}
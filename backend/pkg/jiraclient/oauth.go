@@ -0,0 +1,302 @@
+package jiraclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Atlassian OAuth 2.0 (3LO) endpoints.
+const (
+	DefaultOAuthAuthURL  = "https://auth.atlassian.com/authorize"
+	DefaultOAuthTokenURL = "https://auth.atlassian.com/oauth/token"
+	DefaultOAuthAPIURL   = "https://api.atlassian.com"
+)
+
+// DefaultOAuthScopes are the scopes requested when OAuthConfig.Scopes is empty:
+// read access to issues, projects, boards and sprints, and offline_access for
+// refresh tokens.
+var DefaultOAuthScopes = []string{
+	"read:jira-work",
+	"read:jira-user",
+	"read:board-scope:jira-software",
+	"read:sprint:jira-software",
+	"offline_access",
+}
+
+// oauthExpiryMargin refreshes access tokens this long before they expire, so
+// that a token does not expire while a request is in flight.
+const oauthExpiryMargin = time.Minute
+
+// ErrOAuthReauthorize is returned when the connection has no OAuth token or its
+// refresh token was rejected (revoked, expired or already rotated), so that an
+// administrator has to run the consent flow again.
+var ErrOAuthReauthorize = errors.New("jira oauth authorization required; reconnect the jira connection")
+
+// OAuthConfig is an Atlassian OAuth 2.0 (3LO) app.
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback URL registered for the app.
+	RedirectURL string
+	// Scopes defaults to DefaultOAuthScopes.
+	Scopes []string
+	// AuthURL, TokenURL and APIURL override the Atlassian endpoints.
+	AuthURL  string
+	TokenURL string
+	APIURL   string
+	// HTTPClient defaults to a client with a 30 second timeout.
+	HTTPClient *http.Client
+}
+
+// OAuthToken is an OAuth 2.0 access token with its refresh token.
+type OAuthToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	Scope        string
+}
+
+// Valid reports whether the access token can still be used at now.
+func (t OAuthToken) Valid(now time.Time) bool {
+	return t.AccessToken != "" && now.Add(oauthExpiryMargin).Before(t.ExpiresAt)
+}
+
+// AccessibleResource is a Jira site the OAuth token grants access to.
+type AccessibleResource struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (c *OAuthConfig) authURL() string {
+	if c.AuthURL != "" {
+		return c.AuthURL
+	}
+	return DefaultOAuthAuthURL
+}
+
+func (c *OAuthConfig) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	return DefaultOAuthTokenURL
+}
+
+func (c *OAuthConfig) apiURL() string {
+	if c.APIURL != "" {
+		return strings.TrimRight(c.APIURL, "/")
+	}
+	return DefaultOAuthAPIURL
+}
+
+func (c *OAuthConfig) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// AuthCodeURL returns the consent page URL that starts the authorization-code
+// flow. state is returned to the callback unchanged and must be verified there.
+func (c *OAuthConfig) AuthCodeURL(state string) string {
+	scopes := c.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultOAuthScopes
+	}
+	q := url.Values{
+		"audience":      {"api.atlassian.com"},
+		"client_id":     {c.ClientID},
+		"scope":         {strings.Join(scopes, " ")},
+		"redirect_uri":  {c.RedirectURL},
+		"state":         {state},
+		"response_type": {"code"},
+		"prompt":        {"consent"},
+	}
+	return c.authURL() + "?" + q.Encode()
+}
+
+// SiteURL returns the base URL for REST API requests to the Jira site cloudID
+// with an OAuth access token.
+func (c *OAuthConfig) SiteURL(cloudID string) string {
+	return c.apiURL() + "/ex/jira/" + cloudID
+}
+
+// Exchange exchanges the authorization code received by the callback for a token.
+func (c *OAuthConfig) Exchange(ctx context.Context, code string) (OAuthToken, error) {
+	return c.requestToken(ctx, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"code":          code,
+		"redirect_uri":  c.RedirectURL,
+	})
+}
+
+// Refresh obtains a new access token with refreshToken. Atlassian rotates
+// refresh tokens, so the returned token carries the refresh token to use next
+// time; the old one must be discarded. A rejected refresh token is reported as
+// ErrOAuthReauthorize.
+func (c *OAuthConfig) Refresh(ctx context.Context, refreshToken string) (OAuthToken, error) {
+	token, err := c.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	// ローテーションが無効なアプリでは新しいリフレッシュトークンが返らない
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+func (c *OAuthConfig) requestToken(ctx context.Context, params map[string]string) (OAuthToken, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return OAuthToken{}, fmt.Errorf("marshal token request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL(), bytes.NewReader(body))
+	if err != nil {
+		return OAuthToken{}, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return OAuthToken{}, fmt.Errorf("request oauth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tr tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tr); err != nil {
+		return OAuthToken{}, fmt.Errorf("oauth token endpoint: HTTP %d: decode response: %w", resp.StatusCode, err)
+	}
+	if tr.Error == "invalid_grant" {
+		return OAuthToken{}, fmt.Errorf("%w: %s", ErrOAuthReauthorize, tr.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		return OAuthToken{}, fmt.Errorf("oauth token endpoint: HTTP %d: %s %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+	}
+	return OAuthToken{
+		AccessToken:  tr.AccessToken,
+		RefreshToken: tr.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second),
+		Scope:        tr.Scope,
+	}, nil
+}
+
+// AccessibleResources returns the Jira sites accessToken grants access to.
+func (c *OAuthConfig) AccessibleResources(ctx context.Context, accessToken string) ([]AccessibleResource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL()+"/oauth/token/accessible-resources", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create accessible resources request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request accessible resources: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("accessible resources: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+	var resources []AccessibleResource
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return nil, fmt.Errorf("decode accessible resources: %w", err)
+	}
+	return resources, nil
+}
+
+// OAuthTokenStore persists the OAuth token of a connection. With rotating
+// refresh tokens every refresh invalidates the previous refresh token, so the
+// store must serialise refreshes across processes (the API server and the
+// batch may sync the same connection at once).
+type OAuthTokenStore interface {
+	// Load returns the stored token, or ErrOAuthReauthorize when there is none.
+	Load(ctx context.Context) (OAuthToken, error)
+	// Update calls refresh with the stored token while holding a lock shared by
+	// all processes, and stores the token it returns.
+	Update(ctx context.Context, refresh func(OAuthToken) (OAuthToken, error)) (OAuthToken, error)
+}
+
+// OAuthAuth authenticates with OAuth 2.0 access tokens, refreshing them from
+// the store when they expire. It is safe for concurrent use.
+type OAuthAuth struct {
+	config *OAuthConfig
+	store  OAuthTokenStore
+
+	mu    sync.Mutex
+	token OAuthToken
+	// now は現在時刻を返す。テストで差し替え可能。
+	now func() time.Time
+}
+
+// NewOAuthAuth returns an Authenticator for the token persisted in store.
+func NewOAuthAuth(config *OAuthConfig, store OAuthTokenStore) *OAuthAuth {
+	return &OAuthAuth{config: config, store: store, now: time.Now}
+}
+
+// Authenticate implements Authenticator.
+func (a *OAuthAuth) Authenticate(req *http.Request) error {
+	token, err := a.accessToken(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// accessToken returns a valid access token, refreshing it when needed.
+func (a *OAuthAuth) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token.Valid(a.now()) {
+		return a.token.AccessToken, nil
+	}
+	// 他のプロセスが更新済みの場合はリフレッシュせずにそのトークンを使う
+	token, err := a.store.Load(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !token.Valid(a.now()) {
+		token, err = a.store.Update(ctx, func(current OAuthToken) (OAuthToken, error) {
+			if current.Valid(a.now()) {
+				return current, nil
+			}
+			return a.config.Refresh(ctx, current.RefreshToken)
+		})
+		if err != nil {
+			return "", fmt.Errorf("refresh oauth token: %w", err)
+		}
+	}
+	a.token = token
+	return token.AccessToken, nil
+}
@@ -0,0 +1,194 @@
+package jiraclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newTokenServer returns a token endpoint that issues access-<n> and refresh-<n>
+// tokens and records the requests it received.
+func newTokenServer(t *testing.T, requests *[]map[string]string) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]string
+		json.NewDecoder(r.Body).Decode(&params)
+		*requests = append(*requests, params)
+		w.Header().Set("Content-Type", "application/json")
+		if params["refresh_token"] == "revoked" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "Unknown or invalid refresh token."})
+			return
+		}
+		n := len(*requests)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access-" + string(rune('0'+n)),
+			"refresh_token": "refresh-" + string(rune('0'+n)),
+			"expires_in":    3600,
+			"scope":         "read:jira-work offline_access",
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestOAuthConfig_AuthCodeURL(t *testing.T) {
+	c := &OAuthConfig{ClientID: "client", RedirectURL: "https://app.example.com/api/v1/oauth/jira/callback"}
+
+	u, err := url.Parse(c.AuthCodeURL("state-1"))
+	if err != nil {
+		t.Fatalf("expected valid URL, got: %v", err)
+	}
+	q := u.Query()
+	if u.Host != "auth.atlassian.com" || q.Get("state") != "state-1" || q.Get("client_id") != "client" {
+		t.Errorf("unexpected consent URL: %s", u)
+	}
+	if q.Get("audience") != "api.atlassian.com" || q.Get("response_type") != "code" {
+		t.Errorf("unexpected consent URL: %s", u)
+	}
+	if q.Get("scope") != "read:jira-work read:jira-user read:board-scope:jira-software read:sprint:jira-software offline_access" {
+		t.Errorf("expected default scopes, got: %s", q.Get("scope"))
+	}
+}
+
+func TestOAuthConfig_ExchangeAndRefresh(t *testing.T) {
+	var requests []map[string]string
+	ts := newTokenServer(t, &requests)
+	c := &OAuthConfig{ClientID: "client", ClientSecret: "secret", RedirectURL: "https://app.example.com/cb", TokenURL: ts.URL}
+
+	token, err := c.Exchange(context.Background(), "code-1")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || !token.Valid(time.Now()) {
+		t.Errorf("unexpected token: %+v", token)
+	}
+	if requests[0]["grant_type"] != "authorization_code" || requests[0]["code"] != "code-1" || requests[0]["redirect_uri"] != "https://app.example.com/cb" {
+		t.Errorf("unexpected exchange request: %v", requests[0])
+	}
+
+	// リフレッシュのたびに新しいリフレッシュトークンが発行される
+	token, err = c.Refresh(context.Background(), token.RefreshToken)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if token.RefreshToken != "refresh-2" {
+		t.Errorf("expected rotated refresh token, got: %s", token.RefreshToken)
+	}
+	if requests[1]["grant_type"] != "refresh_token" || requests[1]["refresh_token"] != "refresh-1" {
+		t.Errorf("unexpected refresh request: %v", requests[1])
+	}
+}
+
+func TestOAuthConfig_RefreshRejected(t *testing.T) {
+	var requests []map[string]string
+	ts := newTokenServer(t, &requests)
+	c := &OAuthConfig{ClientID: "client", TokenURL: ts.URL}
+
+	_, err := c.Refresh(context.Background(), "revoked")
+
+	if !errors.Is(err, ErrOAuthReauthorize) {
+		t.Errorf("expected ErrOAuthReauthorize, got: %v", err)
+	}
+}
+
+func TestOAuthConfig_AccessibleResources(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token/accessible-resources" || r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode([]AccessibleResource{{ID: "cloud-1", URL: "https://your-org.atlassian.net", Name: "your-org"}})
+	}))
+	defer ts.Close()
+	c := &OAuthConfig{APIURL: ts.URL}
+
+	resources, err := c.AccessibleResources(context.Background(), "access-1")
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(resources) != 1 || resources[0].ID != "cloud-1" {
+		t.Errorf("unexpected resources: %+v", resources)
+	}
+	if got := c.SiteURL("cloud-1"); got != ts.URL+"/ex/jira/cloud-1" {
+		t.Errorf("unexpected site URL: %s", got)
+	}
+}
+
+// memoryTokenStore is an OAuthTokenStore kept in memory.
+type memoryTokenStore struct {
+	token   OAuthToken
+	err     error
+	updates int
+}
+
+func (s *memoryTokenStore) Load(context.Context) (OAuthToken, error) {
+	return s.token, s.err
+}
+
+func (s *memoryTokenStore) Update(_ context.Context, refresh func(OAuthToken) (OAuthToken, error)) (OAuthToken, error) {
+	s.updates++
+	token, err := refresh(s.token)
+	if err != nil {
+		return OAuthToken{}, err
+	}
+	s.token = token
+	return token, nil
+}
+
+func TestOAuthAuth_RefreshesExpiredToken(t *testing.T) {
+	var requests []map[string]string
+	ts := newTokenServer(t, &requests)
+	store := &memoryTokenStore{token: OAuthToken{AccessToken: "expired", RefreshToken: "refresh-0", ExpiresAt: time.Now().Add(-time.Minute)}}
+	auth := NewOAuthAuth(&OAuthConfig{ClientID: "client", TokenURL: ts.URL}, store)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/rest/api/3/myself", nil)
+		if err := auth.Authenticate(req); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer access-1" {
+			t.Errorf("expected refreshed access token, got: %s", got)
+		}
+	}
+	// 2 回目はキャッシュしたトークンを使い、ローテーション後のリフレッシュトークンを保存する
+	if len(requests) != 1 || store.updates != 1 {
+		t.Errorf("expected one refresh, got %d requests and %d updates", len(requests), store.updates)
+	}
+	if store.token.RefreshToken != "refresh-1" {
+		t.Errorf("expected rotated refresh token to be stored, got: %s", store.token.RefreshToken)
+	}
+}
+
+func TestOAuthAuth_UsesTokenRefreshedByAnotherProcess(t *testing.T) {
+	store := &memoryTokenStore{token: OAuthToken{AccessToken: "fresh", RefreshToken: "refresh-9", ExpiresAt: time.Now().Add(time.Hour)}}
+	auth := NewOAuthAuth(&OAuthConfig{TokenURL: "http://127.0.0.1:0"}, store)
+	auth.token = OAuthToken{AccessToken: "stale", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	req := httptest.NewRequest(http.MethodGet, "/rest/api/3/myself", nil)
+	if err := auth.Authenticate(req); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer fresh" {
+		t.Errorf("expected stored access token, got: %s", got)
+	}
+	if store.updates != 0 {
+		t.Errorf("expected no refresh, got %d", store.updates)
+	}
+}
+
+func TestOAuthAuth_NotAuthorized(t *testing.T) {
+	auth := NewOAuthAuth(&OAuthConfig{}, &memoryTokenStore{err: ErrOAuthReauthorize})
+
+	err := auth.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !errors.Is(err, ErrOAuthReauthorize) {
+		t.Errorf("expected ErrOAuthReauthorize, got: %v", err)
+	}
+}
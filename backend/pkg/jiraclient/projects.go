@@ -1,15 +1,29 @@
 package jiraclient
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
 
 const (
 	projectSearchPath = "/rest/api/3/project/search"
 	projectPageSize   = 50
+	// projectVersionsPath is formatted with the project ID or key.
+	projectVersionsPath = "/rest/api/3/project/%s/versions"
 )
 
 // GetAllProjects fetches all Jira projects using paginated requests.
 // It returns the complete list of projects across all pages.
 func (c *Client) GetAllProjects() ([]Project, error) {
+	return c.GetAllProjectsContext(context.Background())
+}
+
+// GetAllProjectsContext is like GetAllProjects but stops fetching when ctx is cancelled.
+func (c *Client) GetAllProjectsContext(ctx context.Context) ([]Project, error) {
+	if c.isDataCenter() {
+		return c.getAllProjectsDataCenter(ctx)
+	}
 	var all []Project
 	startAt := 0
 
@@ -18,7 +32,7 @@ func (c *Client) GetAllProjects() ([]Project, error) {
 		path := fmt.Sprintf("%s?startAt=%d&maxResults=%d&expand=lead",
 			projectSearchPath, startAt, projectPageSize)
 
-		if err := c.get(path, &resp); err != nil {
+		if err := c.get(ctx, path, &resp); err != nil {
 			return nil, fmt.Errorf("get projects (startAt=%d): %w", startAt, err)
 		}
 
@@ -32,3 +46,18 @@ func (c *Client) GetAllProjects() ([]Project, error) {
 
 	return all, nil
 }
+
+// GetProjectVersionsContext fetches all versions (releases) of a Jira project,
+// including released and archived ones.
+func (c *Client) GetProjectVersionsContext(ctx context.Context, projectIDOrKey string) ([]Version, error) {
+	var versions []Version
+	pathFormat := projectVersionsPath
+	if c.isDataCenter() {
+		pathFormat = dcProjectVersionsPath
+	}
+	path := fmt.Sprintf(pathFormat, url.PathEscape(projectIDOrKey))
+	if err := c.get(ctx, path, &versions); err != nil {
+		return nil, fmt.Errorf("get project versions (%s): %w", projectIDOrKey, err)
+	}
+	return versions, nil
+}
@@ -0,0 +1,203 @@
+package jiraclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minRateFraction is the lowest rate the limiter slows down to, as a fraction
+	// of the configured rate.
+	minRateFraction = 0.1
+	// rateDecreaseFactor is applied to the current rate when Jira signals that the
+	// limit is near or has been hit (multiplicative decrease).
+	rateDecreaseFactor = 0.5
+	// rateIncreaseFraction of the configured rate is added back after every
+	// response that is not throttled (additive increase).
+	rateIncreaseFraction = 0.05
+)
+
+// RateLimiter is a token-bucket limiter shared by all requests of a Client.
+//
+// It starts at the configured rate and adapts to Jira's rate-limit headers:
+// the rate is halved when Jira answers 429 or reports X-RateLimit-NearLimit,
+// and recovers gradually while responses are not throttled. Retry-After pauses
+// every caller, and X-RateLimit-Remaining caps the tokens left in the bucket so
+// that concurrent workers do not overshoot the server's budget; when it reaches
+// zero, callers wait until X-RateLimit-Reset.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	maxRate float64 // 設定されたレート（req/s）。適応後もこれを超えない
+	rate    float64 // 現在のレート（req/s）
+	burst   float64
+	tokens  float64
+	last    time.Time // tokens を最後に補充した時刻
+	// pausedUntil までは Retry-After に従い全リクエストを止める
+	pausedUntil time.Time
+
+	now func() time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rps requests per second on average
+// with bursts of up to burst requests. burst < 1 is treated as 1, and rps <= 0
+// disables the rate (only Retry-After pauses apply).
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	l := &RateLimiter{
+		maxRate: rps,
+		rate:    rps,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		now:     time.Now,
+	}
+	l.last = l.now()
+	return l
+}
+
+// Rate returns the current (adapted) rate in requests per second.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Wait blocks until a request may be sent or ctx is cancelled.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		d := l.reserve()
+		if d <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0, or returns how long
+// to wait before trying again.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	l.refill(now)
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens += elapsed * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// Observe adapts the limiter to the rate-limit headers of a Jira response.
+func (l *RateLimiter) Observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.refill(now)
+
+	throttled := resp.StatusCode == http.StatusTooManyRequests ||
+		strings.EqualFold(resp.Header.Get("X-RateLimit-NearLimit"), "true")
+	if throttled {
+		l.rate *= rateDecreaseFactor
+		if floor := l.maxRate * minRateFraction; l.rate < floor {
+			l.rate = floor
+		}
+	} else if l.rate < l.maxRate {
+		l.rate += l.maxRate * rateIncreaseFraction
+		if l.rate > l.maxRate {
+			l.rate = l.maxRate
+		}
+	}
+
+	// サーバー側の残りトークン数を超えて送らないようにする
+	if v := resp.Header.Get("X-RateLimit-Remaining"); v != "" {
+		if remaining, err := strconv.ParseFloat(v, 64); err == nil {
+			if remaining < l.tokens {
+				l.tokens = remaining
+			}
+			// 使い切った場合はリセット時刻まで待つ。読めなければ Retry-After に従う
+			if remaining <= 0 {
+				if reset, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset")); ok {
+					l.pauseUntil(reset)
+				} else if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+					l.pauseUntil(now.Add(d))
+				}
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			l.pauseUntil(now.Add(d))
+		}
+	}
+}
+
+// pauseUntil stops all requests until t, keeping the later of two pauses.
+func (l *RateLimiter) pauseUntil(t time.Time) {
+	if t.After(l.pausedUntil) {
+		l.pausedUntil = t
+	}
+}
+
+// rateLimitResetLayouts are the accepted X-RateLimit-Reset formats. Jira sends
+// ISO-8601 timestamps, usually without seconds (e.g. "2024-05-01T10:15Z").
+var rateLimitResetLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header.
+func parseRateLimitReset(v string) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range rateLimitResetLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(v, 64)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
@@ -0,0 +1,195 @@
+package jiraclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for RateLimiter tests.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(rps float64, burst int) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewRateLimiter(rps, burst)
+	l.now = clock.now
+	l.last = clock.now()
+	return l, clock
+}
+
+func rateLimitResponse(status int, headers map[string]string) *http.Response {
+	h := http.Header{}
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return &http.Response{StatusCode: status, Header: h}
+}
+
+func TestRateLimiter_BurstThenRate(t *testing.T) {
+	l, clock := newTestLimiter(2, 2)
+
+	// バースト分は待たずに通る
+	if d := l.reserve(); d != 0 {
+		t.Fatalf("expected no wait, got %v", d)
+	}
+	if d := l.reserve(); d != 0 {
+		t.Fatalf("expected no wait, got %v", d)
+	}
+	// 3件目は 2 req/s なので 500ms 待つ
+	if d := l.reserve(); d != 500*time.Millisecond {
+		t.Fatalf("expected 500ms wait, got %v", d)
+	}
+	clock.advance(500 * time.Millisecond)
+	if d := l.reserve(); d != 0 {
+		t.Fatalf("expected no wait after refill, got %v", d)
+	}
+}
+
+func TestRateLimiter_429HalvesRateAndPauses(t *testing.T) {
+	l, clock := newTestLimiter(10, 5)
+
+	l.Observe(rateLimitResponse(http.StatusTooManyRequests, map[string]string{"Retry-After": "3"}))
+
+	if got := l.Rate(); got != 5 {
+		t.Errorf("expected rate 5 after 429, got %v", got)
+	}
+	// Retry-After の間は全員待つ
+	if d := l.reserve(); d != 3*time.Second {
+		t.Errorf("expected 3s pause, got %v", d)
+	}
+	clock.advance(3 * time.Second)
+	if d := l.reserve(); d != 0 {
+		t.Errorf("expected no wait after pause, got %v", d)
+	}
+}
+
+func TestRateLimiter_RecoversAndNeverExceedsConfiguredRate(t *testing.T) {
+	l, _ := newTestLimiter(10, 1)
+
+	l.Observe(rateLimitResponse(http.StatusOK, map[string]string{"X-RateLimit-NearLimit": "true"}))
+	if got := l.Rate(); got != 5 {
+		t.Fatalf("expected rate 5 when near limit, got %v", got)
+	}
+
+	for i := 0; i < 100; i++ {
+		l.Observe(rateLimitResponse(http.StatusOK, nil))
+	}
+	if got := l.Rate(); got != 10 {
+		t.Errorf("expected rate to recover to 10, got %v", got)
+	}
+}
+
+func TestRateLimiter_RateFloor(t *testing.T) {
+	l, _ := newTestLimiter(10, 1)
+	for i := 0; i < 20; i++ {
+		l.Observe(rateLimitResponse(http.StatusTooManyRequests, nil))
+	}
+	if got := l.Rate(); got != 1 {
+		t.Errorf("expected rate floor 1 (10%%), got %v", got)
+	}
+}
+
+func TestRateLimiter_RemainingCapsTokensAndWaitsForReset(t *testing.T) {
+	l, clock := newTestLimiter(10, 10)
+	reset := clock.now().Add(20 * time.Second)
+
+	l.Observe(rateLimitResponse(http.StatusOK, map[string]string{
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     reset.Format(time.RFC3339),
+	}))
+
+	if d := l.reserve(); d != 20*time.Second {
+		t.Errorf("expected to wait until reset (20s), got %v", d)
+	}
+}
+
+func TestRateLimiter_ResetWithoutSeconds(t *testing.T) {
+	l, clock := newTestLimiter(10, 10)
+	// Jira は秒を省いた ISO-8601 でリセット時刻を返す
+	reset := clock.now().Add(2 * time.Minute).Truncate(time.Minute)
+
+	l.Observe(rateLimitResponse(http.StatusOK, map[string]string{
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     reset.UTC().Format("2006-01-02T15:04Z"),
+	}))
+
+	if d, want := l.reserve(), reset.Sub(clock.now()); d != want {
+		t.Errorf("expected to wait until reset (%v), got %v", want, d)
+	}
+}
+
+func TestRateLimiter_UnparsableResetFallsBackToRetryAfter(t *testing.T) {
+	l, _ := newTestLimiter(10, 10)
+
+	l.Observe(rateLimitResponse(http.StatusOK, map[string]string{
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     "soon",
+		"Retry-After":           "15",
+	}))
+
+	if d := l.reserve(); d != 15*time.Second {
+		t.Errorf("expected to wait for Retry-After (15s), got %v", d)
+	}
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	want := time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)
+	for _, v := range []string{"2024-05-01T10:15Z", "2024-05-01T10:15:00Z", "2024-05-01T19:15+09:00"} {
+		got, ok := parseRateLimitReset(v)
+		if !ok || !got.Equal(want) {
+			t.Errorf("parseRateLimitReset(%q) = %v, %v; want %v", v, got, ok, want)
+		}
+	}
+	if _, ok := parseRateLimitReset("1714558500"); ok {
+		t.Error("expected an epoch timestamp to be rejected")
+	}
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	l := NewRateLimiter(0.001, 1)
+	l.reserve() // バーストを使い切る
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClient_RateLimiterSharedAcrossRequests(t *testing.T) {
+	var calls int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		w.Header().Set("X-RateLimit-NearLimit", "true")
+		writeJSON(w, map[string]string{})
+	}))
+	defer ts.Close()
+
+	c := New(Config{BaseURL: ts.URL, RequestsPerSecond: 100, Burst: 2})
+	if c.limiter == nil {
+		t.Fatal("expected limiter to be configured")
+	}
+	for i := 0; i < 3; i++ {
+		if err := c.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := c.limiter.Rate(); got >= 100 {
+		t.Errorf("expected rate to adapt below 100 after NearLimit responses, got %v", got)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestClient_NoLimiterByDefault(t *testing.T) {
+	if c := New(Config{BaseURL: "http://example.invalid"}); c.limiter != nil {
+		t.Error("expected no limiter when RequestsPerSecond is 0")
+	}
+}
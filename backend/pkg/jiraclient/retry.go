@@ -1,8 +1,8 @@
 package jiraclient
 
 import (
+	"math/rand/v2"
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -11,8 +11,15 @@ const (
 	initialBackoff = 1 * time.Second
 	maxBackoff     = 30 * time.Second
 	backoffFactor  = 2
+	// jitterFraction is the maximum extra delay added to a retry, as a fraction
+	// of the base delay, so that concurrent workers do not retry in lockstep.
+	jitterFraction = 0.2
 )
 
+// jitterFn returns a random number in [0, 1). Tests replace it to make delays
+// deterministic.
+var jitterFn = rand.Float64
+
 // retryableStatus returns true if the HTTP status code warrants a retry.
 func retryableStatus(statusCode int) bool {
 	switch statusCode {
@@ -27,19 +34,20 @@ func retryableStatus(statusCode int) bool {
 }
 
 // retryAfterDelay returns the duration to wait before retrying.
-// If the response contains a Retry-After header, that value is used.
-// Otherwise exponential backoff is applied based on the attempt number (0-indexed).
+// If a 429 response contains a Retry-After header, that value is used as is,
+// even when it exceeds maxBackoff. Otherwise exponential backoff is applied based
+// on the attempt number (0-indexed), capped at maxBackoff. Up to jitterFraction
+// of random delay is then added on top, so the wait is never shorter than
+// Retry-After or the backoff asks for.
 func retryAfterDelay(resp *http.Response, attempt int) time.Duration {
+	return withJitter(baseRetryDelay(resp, attempt))
+}
+
+func baseRetryDelay(resp *http.Response, attempt int) time.Duration {
 	// 429レスポンスの Retry-After ヘッダーを優先する
 	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
-		if v := resp.Header.Get("Retry-After"); v != "" {
-			if secs, err := strconv.ParseFloat(v, 64); err == nil {
-				d := time.Duration(secs * float64(time.Second))
-				if d > maxBackoff {
-					return maxBackoff
-				}
-				return d
-			}
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
 		}
 	}
 
@@ -53,3 +61,9 @@ func retryAfterDelay(resp *http.Response, attempt int) time.Duration {
 	}
 	return delay
 }
+
+// withJitter adds up to jitterFraction of d. It is applied after the backoff
+// cap so that workers that all reached maxBackoff still spread out.
+func withJitter(d time.Duration) time.Duration {
+	return d + time.Duration(float64(d)*jitterFraction*jitterFn())
+}
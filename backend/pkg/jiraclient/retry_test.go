@@ -22,7 +22,16 @@ func TestRetryableStatus(t *testing.T) {
 	}
 }
 
+// noJitter disables the random part of retry delays for the duration of a test.
+func noJitter(t *testing.T) {
+	t.Helper()
+	orig := jitterFn
+	jitterFn = func() float64 { return 0 }
+	t.Cleanup(func() { jitterFn = orig })
+}
+
 func TestRetryAfterDelay_Backoff(t *testing.T) {
+	noJitter(t)
 	// attempt=0: initialBackoff (1s)
 	// attempt=1: 2s
 	// attempt=2: 4s
@@ -44,14 +53,28 @@ func TestRetryAfterDelay_Backoff(t *testing.T) {
 }
 
 func TestRetryAfterDelay_MaxBackoff(t *testing.T) {
+	noJitter(t)
 	// 多数のリトライ後は maxBackoff (30s) を超えない
 	got := retryAfterDelay(nil, 10)
-	if got > maxBackoff {
-		t.Errorf("expected delay <= %v, got %v", maxBackoff, got)
+	if got != maxBackoff {
+		t.Errorf("expected delay %v, got %v", maxBackoff, got)
+	}
+}
+
+func TestRetryAfterDelay_JitterAboveMaxBackoff(t *testing.T) {
+	orig := jitterFn
+	t.Cleanup(func() { jitterFn = orig })
+
+	// maxBackoff に達した後もジッターで待機時間がばらける
+	jitterFn = func() float64 { return 0.5 }
+	got := retryAfterDelay(nil, 10)
+	if want := maxBackoff + maxBackoff/10; got != want {
+		t.Errorf("expected %v, got %v", want, got)
 	}
 }
 
 func TestRetryAfterDelay_RetryAfterHeader(t *testing.T) {
+	noJitter(t)
 	resp := &http.Response{
 		StatusCode: http.StatusTooManyRequests,
 		Header:     http.Header{"Retry-After": []string{"5"}},
@@ -62,14 +85,52 @@ func TestRetryAfterDelay_RetryAfterHeader(t *testing.T) {
 	}
 }
 
-func TestRetryAfterDelay_RetryAfterHeaderCappedAtMax(t *testing.T) {
-	// Retry-After が maxBackoff を超える場合は maxBackoff にキャップされる
+func TestRetryAfterDelay_RetryAfterHeaderNotCapped(t *testing.T) {
+	noJitter(t)
+	// Retry-After が maxBackoff を超えてもそれより早くはリトライしない
 	resp := &http.Response{
 		StatusCode: http.StatusTooManyRequests,
 		Header:     http.Header{"Retry-After": []string{"120"}},
 	}
 	got := retryAfterDelay(resp, 0)
-	if got != maxBackoff {
-		t.Errorf("expected %v (capped), got %v", maxBackoff, got)
+	if got != 120*time.Second {
+		t.Errorf("expected 120s from Retry-After header, got %v", got)
+	}
+}
+
+func TestRetryAfterDelay_Jitter(t *testing.T) {
+	orig := jitterFn
+	t.Cleanup(func() { jitterFn = orig })
+
+	// 最大ジッター（jitterFraction = 20%）
+	jitterFn = func() float64 { return 0.999999 }
+	got := retryAfterDelay(nil, 1)
+	if got <= 2*time.Second || got > 2400*time.Millisecond {
+		t.Errorf("expected 2s < delay <= 2.4s, got %v", got)
+	}
+
+	// Retry-After より短くなることはない
+	jitterFn = func() float64 { return 0 }
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{"3"}},
+	}
+	if got := retryAfterDelay(resp, 0); got != 3*time.Second {
+		t.Errorf("expected 3s, got %v", got)
+	}
+}
+
+func TestRetryAfterDelay_JitterSpreadsWorkers(t *testing.T) {
+	// 実際の乱数で複数回計算し、同じ値に揃わないことを確認する
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 20; i++ {
+		d := retryAfterDelay(nil, 0)
+		if d < initialBackoff || d > initialBackoff+initialBackoff/5 {
+			t.Fatalf("delay %v out of range", d)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Error("expected jittered delays to differ")
 	}
 }
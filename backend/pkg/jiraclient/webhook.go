@@ -0,0 +1,48 @@
+package jiraclient
+
+import "encoding/json"
+
+// Webhook event types (the webhookEvent field of a webhook payload).
+const (
+	WebhookIssueCreated           = "jira:issue_created"
+	WebhookIssueUpdated           = "jira:issue_updated"
+	WebhookIssueDeleted           = "jira:issue_deleted"
+	WebhookProjectCreated         = "project_created"
+	WebhookProjectUpdated         = "project_updated"
+	WebhookProjectDeleted         = "project_deleted"
+	WebhookProjectSoftDeleted     = "project_soft_deleted"
+	WebhookProjectRestoredDeleted = "project_restored_deleted"
+	WebhookProjectArchived        = "project_archived"
+	WebhookProjectRestoredArchive = "project_restored_archived"
+)
+
+// WebhookSignatureHeader is the header carrying the HMAC-SHA256 signature of a
+// webhook body ("sha256=<hex>") when the webhook is registered with a secret.
+const WebhookSignatureHeader = "X-Hub-Signature"
+
+// WebhookEvent is the payload of a Jira webhook. Issue events carry Issue with
+// the same fields as the search API, project events carry Project.
+type WebhookEvent struct {
+	Timestamp    int64  `json:"timestamp"` // milliseconds since the epoch
+	WebhookEvent string `json:"webhookEvent"`
+	User         *User  `json:"user,omitempty"`
+	Issue        *Issue `json:"issue,omitempty"`
+	// Changelog is the change of a jira:issue_updated event. Its Created is empty;
+	// the change happened at the issue's updated time.
+	Changelog *ChangelogHistory `json:"changelog,omitempty"`
+	Project   *WebhookProject   `json:"project,omitempty"`
+}
+
+// WebhookProject is the project of a project_* webhook event.
+type WebhookProject struct {
+	// ID is a JSON number in webhooks, unlike in the REST API.
+	ID          json.Number `json:"id"`
+	Key         string      `json:"key"`
+	Name        string      `json:"name"`
+	ProjectLead *User       `json:"projectLead,omitempty"`
+}
+
+// ToProject converts p to the Project returned by the REST API.
+func (p WebhookProject) ToProject() Project {
+	return Project{ID: p.ID.String(), Key: p.Key, Name: p.Name, Lead: p.ProjectLead}
+}
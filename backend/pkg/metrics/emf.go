@@ -42,6 +42,7 @@ func (r *EMFRecorder) RecordSync(m SyncResult) {
 						{"Name": "DurationSeconds", "Unit": "Seconds"},
 						{"Name": "IssuesSynced", "Unit": "Count"},
 						{"Name": "ProjectsSynced", "Unit": "Count"},
+						{"Name": "ProjectsFailed", "Unit": "Count"},
 					},
 				},
 			},
@@ -51,6 +52,7 @@ func (r *EMFRecorder) RecordSync(m SyncResult) {
 		"DurationSeconds": m.Duration.Seconds(),
 		"IssuesSynced":    m.IssuesSynced,
 		"ProjectsSynced":  m.ProjectsSynced,
+		"ProjectsFailed":  m.ProjectsFailed,
 	}
 
 	data, err := json.Marshal(entry)
@@ -17,6 +17,7 @@ func TestEMFRecorder_RecordSync_Success(t *testing.T) {
 		Success:        true,
 		Duration:       10 * time.Second,
 		ProjectsSynced: 3,
+		ProjectsFailed: 1,
 		IssuesSynced:   42,
 	})
 
@@ -46,6 +47,9 @@ func TestEMFRecorder_RecordSync_Success(t *testing.T) {
 	if got := m["ProjectsSynced"].(float64); got != 3 {
 		t.Errorf("expected ProjectsSynced=3, got %v", got)
 	}
+	if got := m["ProjectsFailed"].(float64); got != 1 {
+		t.Errorf("expected ProjectsFailed=1, got %v", got)
+	}
 }
 
 func TestEMFRecorder_RecordSync_Failure(t *testing.T) {
@@ -9,7 +9,7 @@ type Recorder interface {
 
 // SyncResult holds metrics for a single sync run.
 type SyncResult struct {
-	// SyncType is the type of sync: "FULL" or "DELTA".
+	// SyncType is the type of sync: "FULL", "DELTA" or "RECALC".
 	SyncType string
 	// Success is true when the sync completed without error.
 	Success bool
@@ -17,6 +17,8 @@ type SyncResult struct {
 	Duration time.Duration
 	// ProjectsSynced is the number of projects upserted (meaningful for FULL sync only).
 	ProjectsSynced int
+	// ProjectsFailed is the number of projects whose issues could not be fetched (FULL sync only).
+	ProjectsFailed int
 	// IssuesSynced is the number of issues upserted.
 	IssuesSynced int
 }
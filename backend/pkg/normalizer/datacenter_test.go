@@ -0,0 +1,91 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+// loadDataCenterIssues reads a search response recorded from Jira Data Center.
+func loadDataCenterIssues(t *testing.T) []jiraclient.Issue {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "datacenter_search.json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	var resp jiraclient.IssueSearchResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	return resp.Issues
+}
+
+func TestConvertIssue_DataCenter(t *testing.T) {
+	issues := loadDataCenterIssues(t)
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(issues))
+	}
+
+	got := ConvertIssue(issues[0], testNow)
+	if got.StatusCategory != "In Progress" || got.Status != "処理中" {
+		t.Errorf("unexpected status: %q / %q", got.Status, got.StatusCategory)
+	}
+	// Data Center には accountId が無いためユーザーキーで識別する
+	if got.AssigneeAccountID != "JIRAUSER10100" || got.AssigneeName != "田中 一郎" {
+		t.Errorf("unexpected assignee: %q / %q", got.AssigneeAccountID, got.AssigneeName)
+	}
+	if got.LastUpdatedAt.IsZero() || got.DueDate == nil || *got.DueDate != "2026-03-31" {
+		t.Errorf("unexpected dates: %v / %v", got.LastUpdatedAt, got.DueDate)
+	}
+	// 変更履歴は fieldId なしの形式で返る
+	if got.OriginalDueDate == nil || *got.OriginalDueDate != "2026-03-15" || got.PostponeCount != 1 || !got.Slipped {
+		t.Errorf("unexpected slip: original=%v count=%d slipped=%v", got.OriginalDueDate, got.PostponeCount, got.Slipped)
+	}
+	if len(got.FixVersionIDs) != 1 || got.FixVersionIDs[0] != "10200" {
+		t.Errorf("unexpected fix versions: %v", got.FixVersionIDs)
+	}
+
+	// statusCategory.key が無いチケットは ID で判定する
+	done := ConvertIssue(issues[1], testNow)
+	if done.StatusCategory != "Done" || done.DelayStatus != "GREEN" || done.AssigneeAccountID != "" {
+		t.Errorf("unexpected done issue: %+v", done)
+	}
+
+	// ユーザーキーの無いユーザーはユーザー名で識別する
+	sub := ConvertIssue(issues[2], testNow)
+	if sub.StatusCategory != "To Do" || sub.AssigneeAccountID != "suzuki" {
+		t.Errorf("unexpected subtask: %q / %q", sub.StatusCategory, sub.AssigneeAccountID)
+	}
+	if sub.ParentIssueKey == nil || *sub.ParentIssueKey != "OPS-1" || len(sub.Links) != 1 || sub.Links[0].Direction != "inward" {
+		t.Errorf("unexpected parent or links: %v / %+v", sub.ParentIssueKey, sub.Links)
+	}
+}
+
+func TestConvertChangelog_DataCenter(t *testing.T) {
+	issue := loadDataCenterIssues(t)[0]
+
+	got := ConvertChangelog(issue.ID, issue.Changelog.Histories)
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 changes, got %d", len(got))
+	}
+	if got[0].Field != HistoryFieldDueDate || got[0].AuthorAccountID != "JIRAUSER10100" {
+		t.Errorf("unexpected due date change: %+v", got[0])
+	}
+	if got[1].Field != HistoryFieldStatus || *got[1].FromValue != "オープン" || *got[1].ToValue != "処理中" {
+		t.Errorf("unexpected status change: %+v", got[1])
+	}
+}
+
+func TestConvertProject_DataCenterLead(t *testing.T) {
+	p := jiraclient.Project{ID: "10000", Key: "OPS", Lead: &jiraclient.User{Key: "JIRAUSER10100", Name: "tanaka", EmailAddress: "tanaka@example.com"}}
+
+	got := ConvertProject(p)
+
+	if got.LeadAccountID != "JIRAUSER10100" || got.LeadEmail != "tanaka@example.com" {
+		t.Errorf("unexpected lead: %+v", got)
+	}
+}
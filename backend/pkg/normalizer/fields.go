@@ -0,0 +1,235 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+// Canonical issue columns that can be filled from Jira fields through a FieldMapping.
+const (
+	ColumnStartDate   = "start_date"
+	ColumnStoryPoints = "story_points"
+	ColumnEpicKey     = "epic_key"
+	ColumnSprintName  = "sprint_name"
+	ColumnLabels      = "labels"
+	ColumnComponents  = "components"
+)
+
+// MappableColumns lists the canonical columns a Jira field can be mapped to.
+var MappableColumns = []string{
+	ColumnStartDate,
+	ColumnStoryPoints,
+	ColumnEpicKey,
+	ColumnSprintName,
+	ColumnLabels,
+	ColumnComponents,
+}
+
+// IsMappableColumn reports whether column is one of MappableColumns.
+func IsMappableColumn(column string) bool {
+	for _, c := range MappableColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldMapping maps canonical issue columns to the Jira field IDs they are read
+// from, e.g. {"start_date": "customfield_10015", "labels": "labels"}.
+// Columns without an entry are left empty.
+type FieldMapping map[string]string
+
+// JiraFields returns the distinct Jira field IDs of the mapping, sorted, to be
+// requested as extra fields of the issue search.
+func (m FieldMapping) JiraFields() []string {
+	seen := make(map[string]bool, len(m))
+	var fields []string
+	for _, id := range m {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			fields = append(fields, id)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Apply fills the mapped columns of di from the extra fields of the Jira issue.
+// Values that are missing or cannot be decoded leave the column empty.
+func (m FieldMapping) Apply(di *DBIssue, fields jiraclient.IssueFields) {
+	for column, id := range m {
+		raw, ok := fields.Extra[id]
+		if !ok {
+			continue
+		}
+		switch column {
+		case ColumnStartDate:
+			di.StartDate = decodeDate(raw)
+		case ColumnStoryPoints:
+			di.StoryPoints = decodeNumber(raw)
+		case ColumnEpicKey:
+			di.EpicKey = decodeKey(raw)
+		case ColumnSprintName:
+			di.SprintName = decodeSprintName(raw)
+		case ColumnLabels:
+			di.Labels = decodeNames(raw)
+		case ColumnComponents:
+			di.Components = decodeNames(raw)
+		}
+	}
+}
+
+// decodeDate reads a date ("2026-01-15") or date-time ("2026-01-15T10:30:00.000+0900")
+// field and returns its date part.
+func decodeDate(raw json.RawMessage) *string {
+	var s string
+	if json.Unmarshal(raw, &s) != nil || len(s) < len("2006-01-02") {
+		return nil
+	}
+	d := s[:len("2006-01-02")]
+	if _, err := time.Parse("2006-01-02", d); err != nil {
+		return nil
+	}
+	return &d
+}
+
+// decodeNumber reads a number field such as story points. Numeric strings are accepted.
+func decodeNumber(raw json.RawMessage) *float64 {
+	var f float64
+	if json.Unmarshal(raw, &f) == nil {
+		return &f
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return &f
+		}
+	}
+	return nil
+}
+
+// decodeKey reads an issue key: either a plain string (the "Epic Link" field)
+// or an issue object with a key (the "parent" field).
+func decodeKey(raw json.RawMessage) *string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return optionalString(strings.TrimSpace(s))
+	}
+	var issue struct {
+		Key string `json:"key"`
+	}
+	if json.Unmarshal(raw, &issue) == nil {
+		return optionalString(issue.Key)
+	}
+	return nil
+}
+
+// sprint is the part of a Jira sprint used to pick the sprint name.
+type sprint struct {
+	Name  string `json:"name"`
+	State string `json:"state"` // "active" | "future" | "closed"
+}
+
+// decodeSprintName reads the sprint field, which lists every sprint the issue
+// has been in. The active sprint is preferred, else the last one listed.
+// Older Jira versions return sprints as strings such as
+// "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,state=ACTIVE,name=Sprint 1,...]".
+func decodeSprintName(raw json.RawMessage) *string {
+	var sprints []sprint
+	var items []json.RawMessage
+	if json.Unmarshal(raw, &items) != nil {
+		items = []json.RawMessage{raw}
+	}
+	for _, item := range items {
+		var sp sprint
+		var s string
+		switch {
+		case json.Unmarshal(item, &s) == nil:
+			sp = parseLegacySprint(s)
+		case json.Unmarshal(item, &sp) != nil:
+			continue
+		}
+		if sp.Name != "" {
+			sprints = append(sprints, sp)
+		}
+	}
+	if len(sprints) == 0 {
+		return nil
+	}
+
+	name := sprints[len(sprints)-1].Name
+	for _, sp := range sprints {
+		if strings.EqualFold(sp.State, "active") {
+			name = sp.Name
+			break
+		}
+	}
+	return &name
+}
+
+// legacyAttrStart matches the start of each "key=" pair of a legacy sprint string.
+var legacyAttrStart = regexp.MustCompile(`(?:^|,)([A-Za-z]+)=`)
+
+// parseLegacySprint extracts the name and state of a sprint in the legacy string format.
+func parseLegacySprint(s string) sprint {
+	start := strings.Index(s, "[")
+	end := strings.LastIndex(s, "]")
+	if start < 0 || end <= start {
+		return sprint{}
+	}
+	attrs := s[start+1 : end]
+
+	// 値は次の ",key=" の直前まで（name にカンマが含まれる場合に備える）
+	var sp sprint
+	locs := legacyAttrStart.FindAllStringSubmatchIndex(attrs, -1)
+	for i, loc := range locs {
+		valueEnd := len(attrs)
+		if i+1 < len(locs) {
+			valueEnd = locs[i+1][0]
+		}
+		switch attrs[loc[2]:loc[3]] {
+		case "name":
+			sp.Name = attrs[loc[1]:valueEnd]
+		case "state":
+			sp.State = attrs[loc[1]:valueEnd]
+		}
+	}
+	return sp
+}
+
+// decodeNames reads a multi-value field (labels, components, multi-select custom
+// fields). Items may be strings or objects with a name or value.
+func decodeNames(raw json.RawMessage) []string {
+	var items []json.RawMessage
+	if json.Unmarshal(raw, &items) != nil {
+		items = []json.RawMessage{raw}
+	}
+	var names []string
+	for _, item := range items {
+		var s string
+		if json.Unmarshal(item, &s) != nil {
+			var obj struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			}
+			if json.Unmarshal(item, &obj) != nil {
+				continue
+			}
+			s = obj.Name
+			if s == "" {
+				s = obj.Value
+			}
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
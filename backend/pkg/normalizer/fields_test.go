@@ -0,0 +1,127 @@
+package normalizer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+func extraFields(fields map[string]string) jiraclient.IssueFields {
+	f := jiraclient.IssueFields{Extra: make(map[string]json.RawMessage)}
+	for id, raw := range fields {
+		f.Extra[id] = json.RawMessage(raw)
+	}
+	return f
+}
+
+func TestFieldMapping_JiraFields(t *testing.T) {
+	m := FieldMapping{
+		ColumnStartDate:  "customfield_10015",
+		ColumnLabels:     "labels",
+		ColumnComponents: "components",
+		ColumnEpicKey:    "",
+	}
+	want := []string{"components", "customfield_10015", "labels"}
+	if got := m.JiraFields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestFieldMapping_Apply(t *testing.T) {
+	m := FieldMapping{
+		ColumnStartDate:   "customfield_10015",
+		ColumnStoryPoints: "customfield_10016",
+		ColumnEpicKey:     "parent",
+		ColumnSprintName:  "customfield_10020",
+		ColumnLabels:      "labels",
+		ColumnComponents:  "components",
+	}
+	fields := extraFields(map[string]string{
+		"customfield_10015": `"2026-03-01"`,
+		"customfield_10016": `5.5`,
+		"parent":            `{"id":"10","key":"PROJ-10","fields":{"summary":"Epic"}}`,
+		"customfield_10020": `[{"id":1,"name":"Sprint 1","state":"closed"},{"id":2,"name":"Sprint 2","state":"active"},{"id":3,"name":"Sprint 3","state":"future"}]`,
+		"labels":            `["backend","urgent"]`,
+		"components":        `[{"id":"1","name":"API"},{"id":"2","name":"DB"}]`,
+	})
+
+	var di DBIssue
+	m.Apply(&di, fields)
+
+	if di.StartDate == nil || *di.StartDate != "2026-03-01" {
+		t.Errorf("unexpected start date: %v", di.StartDate)
+	}
+	if di.StoryPoints == nil || *di.StoryPoints != 5.5 {
+		t.Errorf("unexpected story points: %v", di.StoryPoints)
+	}
+	if di.EpicKey == nil || *di.EpicKey != "PROJ-10" {
+		t.Errorf("unexpected epic key: %v", di.EpicKey)
+	}
+	if di.SprintName == nil || *di.SprintName != "Sprint 2" {
+		t.Errorf("expected the active sprint, got %v", di.SprintName)
+	}
+	if !reflect.DeepEqual(di.Labels, []string{"backend", "urgent"}) {
+		t.Errorf("unexpected labels: %v", di.Labels)
+	}
+	if !reflect.DeepEqual(di.Components, []string{"API", "DB"}) {
+		t.Errorf("unexpected components: %v", di.Components)
+	}
+}
+
+func TestFieldMapping_ApplyAlternativeFormats(t *testing.T) {
+	m := FieldMapping{
+		ColumnStartDate:   "customfield_1",
+		ColumnStoryPoints: "customfield_2",
+		ColumnEpicKey:     "customfield_3",
+		ColumnSprintName:  "customfield_4",
+		ColumnLabels:      "customfield_5",
+	}
+	fields := extraFields(map[string]string{
+		"customfield_1": `"2026-03-01T09:00:00.000+0900"`,
+		"customfield_2": `"8"`,
+		"customfield_3": `"PROJ-1"`,
+		"customfield_4": `["com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,rapidViewId=1,state=CLOSED,name=Sprint 1, part 2,startDate=2026-01-01]"]`,
+		"customfield_5": `[{"id":"1","value":"Gold"}]`,
+	})
+
+	var di DBIssue
+	m.Apply(&di, fields)
+
+	if di.StartDate == nil || *di.StartDate != "2026-03-01" {
+		t.Errorf("unexpected start date: %v", di.StartDate)
+	}
+	if di.StoryPoints == nil || *di.StoryPoints != 8 {
+		t.Errorf("unexpected story points: %v", di.StoryPoints)
+	}
+	if di.EpicKey == nil || *di.EpicKey != "PROJ-1" {
+		t.Errorf("unexpected epic key: %v", di.EpicKey)
+	}
+	if di.SprintName == nil || *di.SprintName != "Sprint 1, part 2" {
+		t.Errorf("unexpected sprint name: %v", di.SprintName)
+	}
+	if !reflect.DeepEqual(di.Labels, []string{"Gold"}) {
+		t.Errorf("unexpected labels: %v", di.Labels)
+	}
+}
+
+func TestFieldMapping_ApplyInvalidValues(t *testing.T) {
+	m := FieldMapping{
+		ColumnStartDate:   "customfield_1",
+		ColumnStoryPoints: "customfield_2",
+		ColumnSprintName:  "customfield_3",
+	}
+	fields := extraFields(map[string]string{
+		"customfield_1": `"someday"`,
+		"customfield_2": `{"value":1}`,
+		"customfield_3": `[]`,
+	})
+
+	var di DBIssue
+	m.Apply(&di, fields)
+
+	if di.StartDate != nil || di.StoryPoints != nil || di.SprintName != nil {
+		t.Errorf("expected invalid values to be ignored, got %+v", di)
+	}
+}
@@ -11,6 +11,38 @@ type DBProject struct {
 	LeadEmail     string // empty string if no lead
 }
 
+// DBVersion is the normalized version (release) record of a project, ready to
+// be upserted into the project_versions table.
+type DBVersion struct {
+	JiraVersionID string
+	Name          string
+	Description   string  // empty string if not set
+	StartDate     *string // nil when not set; "YYYY-MM-DD" when set
+	ReleaseDate   *string // nil when not set; "YYYY-MM-DD" when set
+	Released      bool
+	Archived      bool
+}
+
+// DBBoard is the normalized agile board record, ready to be upserted into the
+// jira_boards table.
+type DBBoard struct {
+	JiraBoardID int64
+	Name        string
+	BoardType   string // "scrum" | "kanban" | "simple"
+}
+
+// DBSprint is the normalized sprint record, ready to be upserted into the
+// sprints table.
+type DBSprint struct {
+	JiraSprintID int64
+	Name         string
+	State        string // "future" | "active" | "closed"
+	Goal         string // empty string if not set
+	StartDate    *time.Time
+	EndDate      *time.Time
+	CompleteDate *time.Time
+}
+
 // DBIssue is the normalized issue record ready to be upserted into the DB.
 type DBIssue struct {
 	JiraIssueID       string
@@ -26,4 +58,51 @@ type DBIssue struct {
 	Priority          string    // empty string if not set
 	IssueType         string
 	LastUpdatedAt     time.Time
+	OriginalDueDate   *string // first due date ever set; nil when the issue never had one
+	PostponeCount     int     // number of times the due date was moved later
+	Slipped           bool    // true when DueDate is later than OriginalDueDate
+	// OriginalDueDateFromChangelog is true when OriginalDueDate and PostponeCount
+	// were derived from a complete changelog; they then replace the stored values,
+	// which otherwise win (see CalcDueDateSlip).
+	OriginalDueDateFromChangelog bool
+
+	// Parent issue (the epic of a story, or the issue of a subtask); nil for top-level issues.
+	ParentJiraIssueID *string
+	ParentIssueKey    *string
+	// Links are the issue links of the issue, e.g. "blocks" / "is blocked by".
+	Links []DBIssueLink
+	// FixVersionIDs are the Jira IDs of the versions (releases) in fixVersions.
+	FixVersionIDs []string
+
+	// Columns filled through a FieldMapping; empty when unmapped or not set in Jira.
+	StartDate   *string  // "YYYY-MM-DD"
+	StoryPoints *float64
+	EpicKey     *string
+	SprintName  *string
+	Labels      []string
+	Components  []string
+}
+
+// DBIssueLink is a normalized link from an issue to another issue, ready to be
+// stored in the issue_links table.
+type DBIssueLink struct {
+	JiraLinkID        string
+	LinkType          string // link type name, e.g. "Blocks"
+	Direction         string // "outward" | "inward"
+	Description       string // e.g. "blocks" (outward) or "is blocked by" (inward)
+	LinkedJiraIssueID string
+	LinkedIssueKey    string
+}
+
+// DBIssueHistory is a normalized status or due date transition of an issue,
+// ready to be inserted into the issue_history table.
+type DBIssueHistory struct {
+	JiraIssueID     string
+	JiraHistoryID   string  // Jira changelog history ID
+	Field           string  // "status" | "duedate"
+	FromValue       *string // nil when the field was empty
+	ToValue         *string // nil when the field was cleared
+	AuthorAccountID string  // empty string if unknown
+	AuthorName      string  // empty string if unknown
+	ChangedAt       time.Time
 }
@@ -1,18 +1,34 @@
 // Package normalizer converts Jira API responses into normalized DB records.
-// It implements the same status normalization and delay calculation logic
-// as the PostgreSQL triggers defined in the initial schema migration.
+// It implements the status normalization and the delay calculation, whose
+// thresholds come from the delay policy of each project.
 package normalizer
 
 import (
+	"sort"
 	"time"
 
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/calendar"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
 )
 
-// jst is the Japan Standard Time location used for date boundary calculations.
+// location is the reporting timezone used for date boundary calculations.
 // Jira due dates are plain dates (no time component), so we compare against
-// the current date in JST to avoid timezone-related off-by-one errors.
-var jst = mustLoadLocation("Asia/Tokyo")
+// the current date in this timezone to avoid timezone-related off-by-one errors.
+// It defaults to JST and is set from the configuration with SetLocation.
+var location = mustLoadLocation("Asia/Tokyo")
+
+// SetLocation sets the reporting timezone used by Now. A nil loc is ignored.
+// It is meant to be called once at startup, before any goroutine calls Now.
+func SetLocation(loc *time.Location) {
+	if loc != nil {
+		location = loc
+	}
+}
+
+// Location returns the reporting timezone.
+func Location() *time.Location {
+	return location
+}
 
 func mustLoadLocation(name string) *time.Location {
 	loc, err := time.LoadLocation(name)
@@ -37,37 +53,84 @@ func NormalizeStatusCategory(key string) string {
 	}
 }
 
-// CalcDelayStatus computes the delay status of an issue using the same logic as
-// the calculate_delay_status PostgreSQL trigger function.
+// NormalizeIssueStatusCategory maps the status category of an issue to one of
+// the three canonical values. The fixed category ID takes precedence over the
+// key, which Jira Data Center may omit or report inconsistently; the key is
+// used when the ID is missing or unknown (e.g. in hand-written payloads).
+func NormalizeIssueStatusCategory(c jiraclient.IssueStatusCategory) string {
+	switch c.ID {
+	case jiraclient.StatusCategoryDone:
+		return "Done"
+	case jiraclient.StatusCategoryIndeterminate:
+		return "In Progress"
+	case jiraclient.StatusCategoryNew, jiraclient.StatusCategoryUndefined:
+		return "To Do"
+	default:
+		return NormalizeStatusCategory(c.Key)
+	}
+}
+
+// DelayPolicy holds the thresholds used to compute the delay status of issues.
+type DelayPolicy struct {
+	// YellowDays is how many days before the due date an open issue turns YELLOW.
+	// They are business days when Calendar is set, calendar days otherwise.
+	YellowDays int
+	// NoDueDateStatus is the delay status of open issues without a due date.
+	NoDueDateStatus string
+	// Calendar, when set, excludes weekends and its holidays from YellowDays.
+	Calendar *calendar.Calendar
+	// Location, when set, overrides the timezone of now when deciding today's date.
+	Location *time.Location
+}
+
+// DefaultDelayPolicy is used when no delay policy is configured.
+var DefaultDelayPolicy = DelayPolicy{YellowDays: 3, NoDueDateStatus: "YELLOW"}
+
+// CalcDelayStatus computes the delay status of an issue with DefaultDelayPolicy.
 //
 //   - RED    : not Done AND due_date is in the past
 //   - YELLOW : not Done AND (due_date is nil OR due_date is within 3 days from now)
 //   - GREEN  : Done OR (due_date exists and is more than 3 days away)
 //
-// now should be the current time in the desired timezone (typically JST).
+// now should be the current time in the reporting timezone (see Now).
 func CalcDelayStatus(statusCategory string, dueDate *string, now time.Time) string {
+	return DefaultDelayPolicy.CalcDelayStatus(statusCategory, dueDate, now)
+}
+
+// CalcDelayStatus computes the delay status of an issue under the policy.
+// Open issues are RED when overdue, YELLOW when due within YellowDays (counted
+// in business days of Calendar if set), and NoDueDateStatus when they have no
+// due date; everything else is GREEN. Today's date is taken from now in the
+// policy's Location if set, in now's own timezone otherwise.
+func (p DelayPolicy) CalcDelayStatus(statusCategory string, dueDate *string, now time.Time) string {
 	if statusCategory == "Done" {
 		return "GREEN"
 	}
+	if p.Location != nil {
+		now = now.In(p.Location)
+	}
 
 	if dueDate == nil || *dueDate == "" {
-		// 期限未設定は注意扱い
-		return "YELLOW"
+		return p.NoDueDateStatus
 	}
 
 	due, err := time.ParseInLocation("2006-01-02", *dueDate, now.Location())
 	if err != nil {
 		// パースできない日付は期限未設定と同様に扱う
-		return "YELLOW"
+		return p.NoDueDateStatus
 	}
 
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	threeDay := today.AddDate(0, 0, 3)
+	yellowUntil := today.AddDate(0, 0, p.YellowDays)
+	if p.Calendar != nil {
+		// 土日・祝日を除いた営業日で数える
+		yellowUntil = p.Calendar.AddBusinessDays(today, p.YellowDays)
+	}
 
 	switch {
 	case due.Before(today):
 		return "RED"
-	case !due.After(threeDay): // today <= due <= today+3days
+	case !due.After(yellowUntil): // today <= due <= today+YellowDays
 		return "YELLOW"
 	default:
 		return "GREEN"
@@ -82,16 +145,35 @@ func ConvertProject(p jiraclient.Project) DBProject {
 		Name:          p.Name,
 	}
 	if p.Lead != nil {
-		dp.LeadAccountID = p.Lead.AccountID
+		dp.LeadAccountID = p.Lead.Identifier()
 		dp.LeadEmail = p.Lead.EmailAddress
 	}
 	return dp
 }
 
+// ConvertVersion converts a jiraclient.Version to a DBVersion.
+func ConvertVersion(v jiraclient.Version) DBVersion {
+	return DBVersion{
+		JiraVersionID: v.ID,
+		Name:          v.Name,
+		Description:   v.Description,
+		StartDate:     optionalString(v.StartDate),
+		ReleaseDate:   optionalString(v.ReleaseDate),
+		Released:      v.Released,
+		Archived:      v.Archived,
+	}
+}
+
 // ConvertIssue converts a jiraclient.Issue to a DBIssue.
-// now is used for delay status calculation and should typically be time.Now().In(jst).
+// now is used for delay status calculation and should typically be Now().
 func ConvertIssue(issue jiraclient.Issue, now time.Time) DBIssue {
-	statusCategory := NormalizeStatusCategory(issue.Fields.Status.StatusCategory.Key)
+	return ConvertIssueWithPolicy(issue, now, DefaultDelayPolicy)
+}
+
+// ConvertIssueWithPolicy is like ConvertIssue but computes the delay status
+// under the given delay policy.
+func ConvertIssueWithPolicy(issue jiraclient.Issue, now time.Time, policy DelayPolicy) DBIssue {
+	statusCategory := NormalizeIssueStatusCategory(issue.Fields.Status.StatusCategory)
 
 	var dueDate *string
 	if issue.Fields.DueDate != "" {
@@ -108,29 +190,184 @@ func ConvertIssue(issue jiraclient.Issue, now time.Time) DBIssue {
 		StatusCategory: statusCategory,
 		DueDate:        dueDate,
 		IssueType:      issue.Fields.IssueType.Name,
-		DelayStatus:    CalcDelayStatus(statusCategory, dueDate, now),
+		DelayStatus:    policy.CalcDelayStatus(statusCategory, dueDate, now),
 	}
 
 	if issue.Fields.Assignee != nil {
 		di.AssigneeName = issue.Fields.Assignee.DisplayName
-		di.AssigneeAccountID = issue.Fields.Assignee.AccountID
+		di.AssigneeAccountID = issue.Fields.Assignee.Identifier()
 	}
 
 	if issue.Fields.Priority != nil {
 		di.Priority = issue.Fields.Priority.Name
 	}
 
+	if p := issue.Fields.Parent; p != nil && p.ID != "" {
+		di.ParentJiraIssueID = &p.ID
+		di.ParentIssueKey = &p.Key
+	}
+	di.Links = ConvertIssueLinks(issue.Fields.IssueLinks)
+	for _, v := range issue.Fields.FixVersions {
+		if v.ID != "" {
+			di.FixVersionIDs = append(di.FixVersionIDs, v.ID)
+		}
+	}
+
 	if issue.Fields.Updated != "" {
 		if t, err := time.Parse(time.RFC3339, issue.Fields.Updated); err == nil {
 			di.LastUpdatedAt = t
+		} else if t, err := time.Parse(jiraChangelogTime, issue.Fields.Updated); err == nil {
+			// Jira は "2026-01-15T10:30:00.000+0900" 形式で返す
+			di.LastUpdatedAt = t
 		}
 	}
 
+	di.OriginalDueDate, di.PostponeCount = CalcDueDateSlip(issue.Changelog, dueDate)
+	di.OriginalDueDateFromChangelog = issue.Changelog.IsComplete()
+	di.Slipped = IsSlipped(di.OriginalDueDate, dueDate)
+
 	return di
 }
 
-// Now returns the current time in JST. Use this as the now argument when calling
-// ConvertIssue or CalcDelayStatus in production code.
+// ConvertIssueLinks normalizes the issue links of an issue. Links whose other
+// issue is unknown are skipped.
+func ConvertIssueLinks(links []jiraclient.IssueLink) []DBIssueLink {
+	var result []DBIssueLink
+	for _, l := range links {
+		dl := DBIssueLink{JiraLinkID: l.ID, LinkType: l.Type.Name}
+		switch {
+		case l.OutwardIssue != nil:
+			dl.Direction = "outward"
+			dl.Description = l.Type.Outward
+			dl.LinkedJiraIssueID = l.OutwardIssue.ID
+			dl.LinkedIssueKey = l.OutwardIssue.Key
+		case l.InwardIssue != nil:
+			dl.Direction = "inward"
+			dl.Description = l.Type.Inward
+			dl.LinkedJiraIssueID = l.InwardIssue.ID
+			dl.LinkedIssueKey = l.InwardIssue.Key
+		default:
+			continue
+		}
+		if dl.JiraLinkID == "" || dl.LinkedJiraIssueID == "" {
+			continue
+		}
+		result = append(result, dl)
+	}
+	return result
+}
+
+// CalcDueDateSlip derives the original due date of an issue and the number of
+// times its due date was postponed from the due date transitions in changelog.
+//
+// The original due date is the first due date ever set on the issue. When the
+// changelog is missing or truncated, or the due date was never changed, the
+// current due date is returned as the original and the count is 0; the DB keeps
+// the first original due date it has seen and counts later postponements itself.
+// Values derived from a complete changelog replace the stored ones.
+func CalcDueDateSlip(changelog *jiraclient.Changelog, dueDate *string) (original *string, postponeCount int) {
+	if !changelog.IsComplete() {
+		return dueDate, 0
+	}
+
+	changes := ConvertChangelog("", changelog.Histories)
+	// expand=changelog の並び順は保証されないため変更日時順に並べ替える
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+
+	original = dueDate
+	found := false
+	for _, ch := range changes {
+		if ch.Field != HistoryFieldDueDate {
+			continue
+		}
+		if !found {
+			// 最初の変更の変更前の値。未設定から設定された場合は設定後の値
+			if ch.FromValue != nil {
+				original, found = ch.FromValue, true
+			} else if ch.ToValue != nil {
+				original, found = ch.ToValue, true
+			}
+		}
+		// YYYY-MM-DD は文字列比較で前後を判定できる
+		if ch.FromValue != nil && ch.ToValue != nil && *ch.ToValue > *ch.FromValue {
+			postponeCount++
+		}
+	}
+	return original, postponeCount
+}
+
+// IsSlipped reports whether the due date has been moved later than the
+// original due date. Issues without a due date never count as slipped.
+func IsSlipped(original, dueDate *string) bool {
+	return original != nil && dueDate != nil && *dueDate > *original
+}
+
+// History fields tracked in issue_history.
+const (
+	HistoryFieldStatus  = "status"
+	HistoryFieldDueDate = "duedate"
+)
+
+// jiraChangelogTime is the timestamp layout used in Jira changelogs.
+const jiraChangelogTime = "2006-01-02T15:04:05.000-0700"
+
+// ConvertChangelog extracts the status and due date transitions from the change
+// histories of an issue. Other fields are ignored. Status values are the status
+// names (fromString/toString); due dates are "YYYY-MM-DD" (from/to).
+func ConvertChangelog(jiraIssueID string, histories []jiraclient.ChangelogHistory) []DBIssueHistory {
+	var out []DBIssueHistory
+	for _, h := range histories {
+		changedAt, err := time.Parse(jiraChangelogTime, h.Created)
+		if err != nil {
+			// RFC3339 形式で返る場合にも対応する
+			if changedAt, err = time.Parse(time.RFC3339, h.Created); err != nil {
+				continue
+			}
+		}
+
+		for _, item := range h.Items {
+			var from, to string
+			// fieldId が無い古い形式では field 名で判定する
+			field := item.FieldID
+			if field == "" {
+				field = item.Field
+			}
+			switch field {
+			case HistoryFieldStatus:
+				from, to = item.FromString, item.ToString
+			case HistoryFieldDueDate:
+				from, to = item.From, item.To
+			default:
+				continue
+			}
+
+			rec := DBIssueHistory{
+				JiraIssueID:   jiraIssueID,
+				JiraHistoryID: h.ID,
+				Field:         field,
+				FromValue:     optionalString(from),
+				ToValue:       optionalString(to),
+				ChangedAt:     changedAt,
+			}
+			if h.Author != nil {
+				rec.AuthorAccountID = h.Author.Identifier()
+				rec.AuthorName = h.Author.DisplayName
+			}
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// Now returns the current time in the reporting timezone. Use this as the now
+// argument when calling ConvertIssue or CalcDelayStatus in production code.
 func Now() time.Time {
-	return time.Now().In(jst)
+	return time.Now().In(location)
 }
@@ -1,9 +1,11 @@
 package normalizer
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/calendar"
 	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
 )
 
@@ -35,6 +37,27 @@ func TestNormalizeStatusCategory(t *testing.T) {
 	}
 }
 
+func TestNormalizeIssueStatusCategory(t *testing.T) {
+	cases := []struct {
+		category jiraclient.IssueStatusCategory
+		expected string
+	}{
+		{jiraclient.IssueStatusCategory{ID: 3, Key: "done"}, "Done"},
+		{jiraclient.IssueStatusCategory{ID: 4, Name: "進行中"}, "In Progress"}, // Data Center: key なし
+		{jiraclient.IssueStatusCategory{ID: 3, Key: "indeterminate"}, "Done"}, // ID を優先する
+		{jiraclient.IssueStatusCategory{ID: 2, Key: "new"}, "To Do"},
+		{jiraclient.IssueStatusCategory{ID: 1, Key: "undefined"}, "To Do"},
+		{jiraclient.IssueStatusCategory{Key: "done"}, "Done"}, // ID なし → key
+		{jiraclient.IssueStatusCategory{ID: 99}, "To Do"},
+	}
+	for _, tc := range cases {
+		got := NormalizeIssueStatusCategory(tc.category)
+		if got != tc.expected {
+			t.Errorf("NormalizeIssueStatusCategory(%+v) = %q, want %q", tc.category, got, tc.expected)
+		}
+	}
+}
+
 // ----------------------------------------------------------------
 // CalcDelayStatus — 境界値テスト
 // testNow = 2026-02-24
@@ -131,6 +154,89 @@ func TestCalcDelayStatus_InvalidDate(t *testing.T) {
 	}
 }
 
+func TestDelayPolicy_CalcDelayStatus(t *testing.T) {
+	// 7 日前から YELLOW、期限未設定は GREEN
+	policy := DelayPolicy{YellowDays: 7, NoDueDateStatus: "GREEN"}
+	cases := []struct {
+		status   string
+		dueDate  *string
+		expected string
+	}{
+		{"To Do", nil, "GREEN"},
+		{"To Do", strPtr("not-a-date"), "GREEN"},
+		{"To Do", strPtr("2026-02-23"), "RED"},
+		{"To Do", strPtr("2026-03-03"), "YELLOW"}, // ちょうど 7 日後
+		{"To Do", strPtr("2026-03-04"), "GREEN"},
+		{"Done", strPtr("2026-02-23"), "GREEN"},
+	}
+	for _, tc := range cases {
+		got := policy.CalcDelayStatus(tc.status, tc.dueDate, testNow)
+		if got != tc.expected {
+			t.Errorf("status=%s dueDate=%v: expected %s, got %s", tc.status, tc.dueDate, tc.expected, got)
+		}
+	}
+}
+
+func TestDelayPolicy_ZeroYellowDays(t *testing.T) {
+	// 0 日の場合は期日当日のみ YELLOW
+	policy := DelayPolicy{YellowDays: 0, NoDueDateStatus: "RED"}
+	if got := policy.CalcDelayStatus("To Do", strPtr("2026-02-24"), testNow); got != "YELLOW" {
+		t.Errorf("expected YELLOW on the due date, got %s", got)
+	}
+	if got := policy.CalcDelayStatus("To Do", strPtr("2026-02-25"), testNow); got != "GREEN" {
+		t.Errorf("expected GREEN the day before, got %s", got)
+	}
+	if got := policy.CalcDelayStatus("To Do", nil, testNow); got != "RED" {
+		t.Errorf("expected RED for no due date, got %s", got)
+	}
+}
+
+func TestDelayPolicy_BusinessDays(t *testing.T) {
+	// testNow = 2026-02-24（火）。3 営業日後は 2/27（金）、2/26（木）を祝日にすると 3/2（月）
+	policy := DelayPolicy{YellowDays: 3, NoDueDateStatus: "YELLOW", Calendar: calendar.New(nil)}
+	if got := policy.CalcDelayStatus("To Do", strPtr("2026-02-27"), testNow); got != "YELLOW" {
+		t.Errorf("expected YELLOW within 3 business days, got %s", got)
+	}
+	if got := policy.CalcDelayStatus("To Do", strPtr("2026-02-28"), testNow); got != "GREEN" {
+		t.Errorf("expected GREEN for the Saturday after the window, got %s", got)
+	}
+
+	policy.Calendar = calendar.New([]calendar.Holiday{{Date: "2026-02-26"}})
+	if got := policy.CalcDelayStatus("To Do", strPtr("2026-03-02"), testNow); got != "YELLOW" {
+		t.Errorf("expected the window to skip the holiday, got %s", got)
+	}
+}
+
+func TestDelayPolicy_Location(t *testing.T) {
+	// testNow は 2026-02-24 00:00 JST = 2026-02-23 10:00 ニューヨーク時間
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	policy := DelayPolicy{YellowDays: 0, NoDueDateStatus: "YELLOW", Location: ny}
+	if got := policy.CalcDelayStatus("To Do", strPtr("2026-02-23"), testNow); got != "YELLOW" {
+		t.Errorf("expected YELLOW for today in New York, got %s", got)
+	}
+	if got := DefaultDelayPolicy.CalcDelayStatus("To Do", strPtr("2026-02-23"), testNow); got != "RED" {
+		t.Errorf("expected RED for yesterday in JST, got %s", got)
+	}
+}
+
+func TestSetLocation(t *testing.T) {
+	orig := Location()
+	t.Cleanup(func() { SetLocation(orig) })
+
+	utc := time.UTC
+	SetLocation(utc)
+	if Now().Location() != utc {
+		t.Errorf("expected Now in UTC, got %s", Now().Location())
+	}
+	SetLocation(nil)
+	if Location() != utc {
+		t.Error("expected SetLocation(nil) to keep the current location")
+	}
+}
+
 // ----------------------------------------------------------------
 // ConvertProject
 // ----------------------------------------------------------------
@@ -261,6 +367,14 @@ func TestConvertIssue_EmptyDueDate(t *testing.T) {
 	}
 }
 
+func TestConvertIssueWithPolicy(t *testing.T) {
+	issue := makeTestIssue() // 期日 2026-02-28（4 日後）
+	got := ConvertIssueWithPolicy(issue, testNow, DelayPolicy{YellowDays: 7, NoDueDateStatus: "YELLOW"})
+	if got.DelayStatus != "YELLOW" {
+		t.Errorf("expected YELLOW within 7 days, got %s", got.DelayStatus)
+	}
+}
+
 func TestConvertIssue_LastUpdatedAt(t *testing.T) {
 	issue := makeTestIssue()
 	issue.Fields.Updated = "2026-02-20T10:00:00+09:00"
@@ -272,6 +386,17 @@ func TestConvertIssue_LastUpdatedAt(t *testing.T) {
 	}
 }
 
+func TestConvertIssue_LastUpdatedAtJiraFormat(t *testing.T) {
+	issue := makeTestIssue()
+	issue.Fields.Updated = "2026-02-20T10:00:00.000+0900"
+	got := ConvertIssue(issue, testNow)
+
+	expected := time.Date(2026, 2, 20, 1, 0, 0, 0, time.UTC)
+	if !got.LastUpdatedAt.Equal(expected) {
+		t.Errorf("LastUpdatedAt: expected %v, got %v", expected, got.LastUpdatedAt)
+	}
+}
+
 func TestConvertIssue_InvalidUpdatedAt(t *testing.T) {
 	issue := makeTestIssue()
 	issue.Fields.Updated = "not-a-timestamp"
@@ -282,3 +407,240 @@ func TestConvertIssue_InvalidUpdatedAt(t *testing.T) {
 		t.Errorf("expected zero LastUpdatedAt for invalid timestamp")
 	}
 }
+
+func TestConvertIssue_ParentAndLinks(t *testing.T) {
+	issue := makeTestIssue()
+	issue.Fields.Parent = &jiraclient.IssueParent{ID: "10000", Key: "PROJ-100"}
+	blocks := jiraclient.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}
+	issue.Fields.IssueLinks = []jiraclient.IssueLink{
+		{ID: "1", Type: blocks, OutwardIssue: &jiraclient.LinkedIssue{ID: "10002", Key: "PROJ-2"}},
+		{ID: "2", Type: blocks, InwardIssue: &jiraclient.LinkedIssue{ID: "10003", Key: "PROJ-3"}},
+		{ID: "3", Type: blocks}, // リンク先が無いものは無視される
+	}
+	got := ConvertIssue(issue, testNow)
+
+	if got.ParentJiraIssueID == nil || *got.ParentJiraIssueID != "10000" || *got.ParentIssueKey != "PROJ-100" {
+		t.Errorf("unexpected parent: %v %v", got.ParentJiraIssueID, got.ParentIssueKey)
+	}
+	want := []DBIssueLink{
+		{JiraLinkID: "1", LinkType: "Blocks", Direction: "outward", Description: "blocks", LinkedJiraIssueID: "10002", LinkedIssueKey: "PROJ-2"},
+		{JiraLinkID: "2", LinkType: "Blocks", Direction: "inward", Description: "is blocked by", LinkedJiraIssueID: "10003", LinkedIssueKey: "PROJ-3"},
+	}
+	if !reflect.DeepEqual(got.Links, want) {
+		t.Errorf("unexpected links: %+v", got.Links)
+	}
+}
+
+func TestConvertIssue_NoParent(t *testing.T) {
+	got := ConvertIssue(makeTestIssue(), testNow)
+	if got.ParentJiraIssueID != nil || got.ParentIssueKey != nil || got.Links != nil {
+		t.Errorf("expected no hierarchy, got %+v", got)
+	}
+}
+
+func TestConvertIssue_FixVersions(t *testing.T) {
+	issue := makeTestIssue()
+	issue.Fields.FixVersions = []jiraclient.IssueVersion{{ID: "20000", Name: "v1.0"}, {ID: "20001", Name: "v1.1"}}
+	got := ConvertIssue(issue, testNow)
+
+	if !reflect.DeepEqual(got.FixVersionIDs, []string{"20000", "20001"}) {
+		t.Errorf("unexpected fix versions: %v", got.FixVersionIDs)
+	}
+}
+
+func TestConvertVersion(t *testing.T) {
+	got := ConvertVersion(jiraclient.Version{ID: "20000", Name: "v1.0", Released: true, ReleaseDate: "2026-03-31"})
+	want := DBVersion{JiraVersionID: "20000", Name: "v1.0", ReleaseDate: strPtr("2026-03-31"), Released: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+// ----------------------------------------------------------------
+// ConvertChangelog
+// ----------------------------------------------------------------
+
+func TestConvertChangelog_StatusAndDueDateOnly(t *testing.T) {
+	histories := []jiraclient.ChangelogHistory{
+		{
+			ID:      "100",
+			Author:  &jiraclient.User{AccountID: "acc-1", DisplayName: "Alice"},
+			Created: "2026-02-10T09:30:00.000+0900",
+			Items: []jiraclient.ChangelogItem{
+				{Field: "status", FieldID: "status", From: "1", FromString: "To Do", To: "3", ToString: "In Progress"},
+				{Field: "summary", FieldID: "summary", FromString: "old", ToString: "new"},
+			},
+		},
+		{
+			ID:      "101",
+			Created: "2026-02-11T10:00:00.000+0900",
+			Items: []jiraclient.ChangelogItem{
+				{Field: "duedate", FieldID: "duedate", From: "2026-02-20", FromString: "20/Feb/26", To: "2026-03-01", ToString: "1/Mar/26"},
+			},
+		},
+	}
+
+	got := ConvertChangelog("10001", histories)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(got))
+	}
+
+	status := got[0]
+	if status.Field != HistoryFieldStatus || status.JiraHistoryID != "100" || status.JiraIssueID != "10001" {
+		t.Errorf("unexpected status record: %+v", status)
+	}
+	if *status.FromValue != "To Do" || *status.ToValue != "In Progress" {
+		t.Errorf("status values: expected To Do → In Progress, got %s → %s", *status.FromValue, *status.ToValue)
+	}
+	if status.AuthorAccountID != "acc-1" || status.AuthorName != "Alice" {
+		t.Errorf("unexpected author: %s / %s", status.AuthorAccountID, status.AuthorName)
+	}
+	if want := time.Date(2026, 2, 10, 0, 30, 0, 0, time.UTC); !status.ChangedAt.Equal(want) {
+		t.Errorf("ChangedAt: expected %v, got %v", want, status.ChangedAt)
+	}
+
+	// 期日は表示用文字列ではなく YYYY-MM-DD を使う
+	due := got[1]
+	if due.Field != HistoryFieldDueDate || *due.FromValue != "2026-02-20" || *due.ToValue != "2026-03-01" {
+		t.Errorf("unexpected duedate record: %+v", due)
+	}
+	if due.AuthorAccountID != "" {
+		t.Errorf("expected empty author for anonymous change, got %s", due.AuthorAccountID)
+	}
+}
+
+func TestConvertChangelog_ClearedDueDate(t *testing.T) {
+	histories := []jiraclient.ChangelogHistory{{
+		ID:      "200",
+		Created: "2026-02-12T10:00:00.000+0900",
+		Items:   []jiraclient.ChangelogItem{{Field: "duedate", From: "2026-02-20"}},
+	}}
+
+	got := ConvertChangelog("10001", histories)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(got))
+	}
+	if got[0].FromValue == nil || *got[0].FromValue != "2026-02-20" {
+		t.Errorf("expected from 2026-02-20, got %v", got[0].FromValue)
+	}
+	if got[0].ToValue != nil {
+		t.Errorf("expected nil to value for cleared due date, got %v", *got[0].ToValue)
+	}
+}
+
+func TestConvertChangelog_InvalidCreatedSkipped(t *testing.T) {
+	histories := []jiraclient.ChangelogHistory{{
+		ID:      "300",
+		Created: "not-a-timestamp",
+		Items:   []jiraclient.ChangelogItem{{Field: "status", FromString: "To Do", ToString: "Done"}},
+	}}
+	if got := ConvertChangelog("10001", histories); len(got) != 0 {
+		t.Errorf("expected no records for invalid timestamp, got %d", len(got))
+	}
+}
+
+// ----------------------------------------------------------------
+// CalcDueDateSlip / IsSlipped
+// ----------------------------------------------------------------
+
+func dueDateChange(id, created, from, to string) jiraclient.ChangelogHistory {
+	return jiraclient.ChangelogHistory{
+		ID:      id,
+		Created: created,
+		Items:   []jiraclient.ChangelogItem{{Field: "duedate", FieldID: "duedate", From: from, To: to}},
+	}
+}
+
+func TestCalcDueDateSlip_PostponedTwice(t *testing.T) {
+	// 並び順が新しい順でも変更日時で判定する
+	changelog := &jiraclient.Changelog{Total: 3, Histories: []jiraclient.ChangelogHistory{
+		dueDateChange("3", "2026-02-15T10:00:00.000+0900", "2026-03-10", "2026-03-20"),
+		dueDateChange("2", "2026-02-10T10:00:00.000+0900", "2026-03-15", "2026-03-10"),
+		dueDateChange("1", "2026-02-05T10:00:00.000+0900", "2026-03-01", "2026-03-15"),
+	}}
+
+	original, count := CalcDueDateSlip(changelog, strPtr("2026-03-20"))
+	if original == nil || *original != "2026-03-01" {
+		t.Errorf("expected original 2026-03-01, got %v", original)
+	}
+	// 前倒し（03-15 → 03-10）は延期に数えない
+	if count != 2 {
+		t.Errorf("expected 2 postponements, got %d", count)
+	}
+}
+
+func TestCalcDueDateSlip_FirstSetLater(t *testing.T) {
+	changelog := &jiraclient.Changelog{Total: 2, Histories: []jiraclient.ChangelogHistory{
+		dueDateChange("1", "2026-02-05T10:00:00.000+0900", "", "2026-03-01"),
+		dueDateChange("2", "2026-02-10T10:00:00.000+0900", "2026-03-01", "2026-03-08"),
+	}}
+
+	original, count := CalcDueDateSlip(changelog, strPtr("2026-03-08"))
+	if original == nil || *original != "2026-03-01" || count != 1 {
+		t.Errorf("expected original 2026-03-01 and 1 postponement, got %v, %d", original, count)
+	}
+}
+
+func TestCalcDueDateSlip_NoChangelogUsesCurrentDueDate(t *testing.T) {
+	truncated := &jiraclient.Changelog{Total: 5, Histories: []jiraclient.ChangelogHistory{
+		dueDateChange("1", "2026-02-05T10:00:00.000+0900", "2026-03-01", "2026-03-15"),
+	}}
+	for name, cl := range map[string]*jiraclient.Changelog{"nil": nil, "truncated": truncated} {
+		original, count := CalcDueDateSlip(cl, strPtr("2026-03-15"))
+		if original == nil || *original != "2026-03-15" || count != 0 {
+			t.Errorf("%s: expected current due date and 0, got %v, %d", name, original, count)
+		}
+	}
+}
+
+func TestIsSlipped(t *testing.T) {
+	cases := []struct {
+		original, due *string
+		want          bool
+	}{
+		{strPtr("2026-03-01"), strPtr("2026-03-02"), true},
+		{strPtr("2026-03-01"), strPtr("2026-03-01"), false},
+		{strPtr("2026-03-01"), strPtr("2026-02-20"), false},
+		{strPtr("2026-03-01"), nil, false},
+		{nil, strPtr("2026-03-01"), false},
+	}
+	for _, tc := range cases {
+		if got := IsSlipped(tc.original, tc.due); got != tc.want {
+			t.Errorf("IsSlipped(%v, %v) = %v, want %v", tc.original, tc.due, got, tc.want)
+		}
+	}
+}
+
+func TestConvertIssue_Slipped(t *testing.T) {
+	issue := makeTestIssue()
+	issue.Fields.DueDate = "2026-03-20"
+	issue.Changelog = &jiraclient.Changelog{Total: 1, Histories: []jiraclient.ChangelogHistory{
+		dueDateChange("1", "2026-02-05T10:00:00.000+0900", "2026-03-01", "2026-03-20"),
+	}}
+
+	got := ConvertIssue(issue, testNow)
+	if !got.Slipped || got.PostponeCount != 1 || *got.OriginalDueDate != "2026-03-01" {
+		t.Errorf("expected slipped issue with 1 postponement from 2026-03-01, got %+v", got)
+	}
+}
+
+func TestConvertIssue_OriginalDueDateFromChangelog(t *testing.T) {
+	issue := makeTestIssue()
+	issue.Fields.DueDate = "2026-03-20"
+
+	// changelog が無い・途中までの場合は保存済みの値を優先させる
+	if got := ConvertIssue(issue, testNow); got.OriginalDueDateFromChangelog {
+		t.Error("expected no changelog-derived original due date without a changelog")
+	}
+	issue.Changelog = &jiraclient.Changelog{Total: 2, Histories: []jiraclient.ChangelogHistory{
+		dueDateChange("1", "2026-02-05T10:00:00.000+0900", "2026-03-01", "2026-03-20"),
+	}}
+	if got := ConvertIssue(issue, testNow); got.OriginalDueDateFromChangelog {
+		t.Error("expected no changelog-derived original due date with a truncated changelog")
+	}
+
+	issue.Changelog.Total = 1
+	if got := ConvertIssue(issue, testNow); !got.OriginalDueDateFromChangelog {
+		t.Error("expected the original due date to be derived from the complete changelog")
+	}
+}
@@ -0,0 +1,46 @@
+package normalizer
+
+import "time"
+
+// ReleaseIssueCounts summarizes the open (not Done) issues of a release.
+type ReleaseIssueCounts struct {
+	Open   int
+	Red    int
+	Yellow int
+	// DueAfterRelease is the number of open issues due after the release date.
+	DueAfterRelease int
+}
+
+// CalcReleaseStatus computes the delay status of a release from its release
+// date and the delay statuses of its open issues.
+//
+//   - GREEN  : released, or no open issues left
+//   - RED    : the release date is in the past, or an open issue is RED
+//   - YELLOW : an open issue is YELLOW or is due after the release date
+//   - GREEN  : otherwise
+//
+// now should be the current time in the reporting timezone (see Now).
+func CalcReleaseStatus(released bool, releaseDate *string, counts ReleaseIssueCounts, now time.Time) string {
+	if released || counts.Open == 0 {
+		return "GREEN"
+	}
+
+	if releaseDate != nil {
+		// パースできないリリース日は未設定と同様に扱う
+		if due, err := time.ParseInLocation("2006-01-02", *releaseDate, now.Location()); err == nil {
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			if due.Before(today) {
+				return "RED"
+			}
+		}
+	}
+
+	switch {
+	case counts.Red > 0:
+		return "RED"
+	case counts.Yellow > 0 || counts.DueAfterRelease > 0:
+		return "YELLOW"
+	default:
+		return "GREEN"
+	}
+}
@@ -0,0 +1,31 @@
+package normalizer
+
+import "testing"
+
+func TestCalcReleaseStatus(t *testing.T) {
+	cases := []struct {
+		name        string
+		released    bool
+		releaseDate *string
+		counts      ReleaseIssueCounts
+		expected    string
+	}{
+		{"released", true, strPtr("2026-02-01"), ReleaseIssueCounts{Open: 3, Red: 3}, "GREEN"},
+		{"no open issues", false, strPtr("2026-02-01"), ReleaseIssueCounts{}, "GREEN"},
+		{"release date passed", false, strPtr("2026-02-23"), ReleaseIssueCounts{Open: 1}, "RED"},
+		{"release date is today", false, strPtr("2026-02-24"), ReleaseIssueCounts{Open: 1}, "GREEN"},
+		{"red issue", false, strPtr("2026-03-31"), ReleaseIssueCounts{Open: 2, Red: 1, Yellow: 1}, "RED"},
+		{"yellow issue", false, strPtr("2026-03-31"), ReleaseIssueCounts{Open: 2, Yellow: 1}, "YELLOW"},
+		{"issue due after release", false, strPtr("2026-03-31"), ReleaseIssueCounts{Open: 2, DueAfterRelease: 1}, "YELLOW"},
+		{"no release date", false, nil, ReleaseIssueCounts{Open: 2}, "GREEN"},
+		{"invalid release date", false, strPtr("someday"), ReleaseIssueCounts{Open: 2}, "GREEN"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := CalcReleaseStatus(tc.released, tc.releaseDate, tc.counts, testNow)
+			if got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
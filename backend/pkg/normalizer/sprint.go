@@ -0,0 +1,93 @@
+package normalizer
+
+import (
+	"time"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+// SprintBehindTolerance is how far (as a ratio of the sprint's issues) the done
+// issues of an active sprint may lag behind the elapsed time before the sprint
+// is YELLOW.
+const SprintBehindTolerance = 0.2
+
+// ConvertBoard converts a jiraclient.Board to a DBBoard.
+func ConvertBoard(b jiraclient.Board) DBBoard {
+	return DBBoard{JiraBoardID: b.ID, Name: b.Name, BoardType: b.Type}
+}
+
+// ConvertSprint converts a jiraclient.Sprint to a DBSprint. Dates that cannot be
+// parsed are left nil.
+func ConvertSprint(s jiraclient.Sprint) DBSprint {
+	return DBSprint{
+		JiraSprintID: s.ID,
+		Name:         s.Name,
+		State:        s.State,
+		Goal:         s.Goal,
+		StartDate:    parseSprintTime(s.StartDate),
+		EndDate:      parseSprintTime(s.EndDate),
+		CompleteDate: parseSprintTime(s.CompleteDate),
+	}
+}
+
+// parseSprintTime parses an agile API date-time ("2026-01-05T09:00:00.000+09:00").
+func parseSprintTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		if t, err = time.Parse(jiraChangelogTime, s); err != nil {
+			return nil
+		}
+	}
+	return &t
+}
+
+// SprintProgress summarizes the issues of a sprint.
+type SprintProgress struct {
+	Total int
+	Done  int
+	// Red is the number of open issues that are RED.
+	Red int
+}
+
+// CalcSprintStatus computes the delay status of an active sprint.
+//
+//   - GREEN  : every issue is done
+//   - RED    : the end date has passed, or an open issue is RED
+//   - YELLOW : the ratio of done issues lags behind the elapsed ratio of the
+//     sprint by more than SprintBehindTolerance
+//   - GREEN  : otherwise
+func CalcSprintStatus(start, end *time.Time, p SprintProgress, now time.Time) string {
+	if p.Done >= p.Total {
+		return "GREEN"
+	}
+	if end != nil && now.After(*end) {
+		return "RED"
+	}
+	if p.Red > 0 {
+		return "RED"
+	}
+	if start != nil && end != nil && end.After(*start) {
+		elapsed := float64(now.Sub(*start)) / float64(end.Sub(*start))
+		done := float64(p.Done) / float64(p.Total)
+		if done < elapsed-SprintBehindTolerance {
+			return "YELLOW"
+		}
+	}
+	return "GREEN"
+}
+
+// SprintDaysRemaining returns the number of calendar days from today to the end
+// date of a sprint in now's timezone, or 0 when the end date has passed.
+func SprintDaysRemaining(end time.Time, now time.Time) int {
+	end = end.In(now.Location())
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(endDay.Sub(today).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
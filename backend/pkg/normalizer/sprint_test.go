@@ -0,0 +1,75 @@
+package normalizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/m19cmjigen/sandbox-project-management/backend/pkg/jiraclient"
+)
+
+func TestConvertSprint(t *testing.T) {
+	got := ConvertSprint(jiraclient.Sprint{
+		ID:        11,
+		Name:      "Sprint 2",
+		State:     "active",
+		StartDate: "2026-02-16T09:00:00.000+09:00",
+		EndDate:   "2026-03-02T09:00:00.000+0900",
+	})
+	if got.JiraSprintID != 11 || got.State != "active" {
+		t.Errorf("unexpected sprint: %+v", got)
+	}
+	if got.StartDate == nil || !got.StartDate.Equal(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected start date: %v", got.StartDate)
+	}
+	if got.EndDate == nil || got.CompleteDate != nil {
+		t.Errorf("unexpected end/complete date: %v %v", got.EndDate, got.CompleteDate)
+	}
+}
+
+func TestCalcSprintStatus(t *testing.T) {
+	// testNow（2026-02-24）は 2/16〜3/2 のスプリントの経過率 4/7（約 57%）
+	start := time.Date(2026, 2, 16, 0, 0, 0, 0, testNow.Location())
+	end := time.Date(2026, 3, 2, 0, 0, 0, 0, testNow.Location())
+	past := time.Date(2026, 2, 23, 0, 0, 0, 0, testNow.Location())
+
+	cases := []struct {
+		name     string
+		end      *time.Time
+		progress SprintProgress
+		expected string
+	}{
+		{"all done", &past, SprintProgress{Total: 5, Done: 5}, "GREEN"},
+		{"empty sprint", &end, SprintProgress{}, "GREEN"},
+		{"end date passed", &past, SprintProgress{Total: 5, Done: 4}, "RED"},
+		{"red issue", &end, SprintProgress{Total: 10, Done: 6, Red: 1}, "RED"},
+		{"on track", &end, SprintProgress{Total: 10, Done: 5}, "GREEN"},
+		{"behind", &end, SprintProgress{Total: 10, Done: 3}, "YELLOW"},
+		{"no end date", nil, SprintProgress{Total: 10}, "GREEN"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := CalcSprintStatus(&start, tc.end, tc.progress, testNow)
+			if got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSprintDaysRemaining(t *testing.T) {
+	cases := []struct {
+		end      time.Time
+		expected int
+	}{
+		{time.Date(2026, 3, 2, 9, 0, 0, 0, testNow.Location()), 6},
+		{time.Date(2026, 2, 24, 18, 0, 0, 0, testNow.Location()), 0},
+		{time.Date(2026, 2, 20, 0, 0, 0, 0, testNow.Location()), 0},
+		// UTC の 2/25 15:00 は JST の 2/26
+		{time.Date(2026, 2, 25, 15, 0, 0, 0, time.UTC), 2},
+	}
+	for _, tc := range cases {
+		if got := SprintDaysRemaining(tc.end, testNow); got != tc.expected {
+			t.Errorf("SprintDaysRemaining(%v): expected %d, got %d", tc.end, tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// encryptedPrefix marks values encrypted by TokenCipher. Values without it are
+// treated as legacy plaintext.
+const encryptedPrefix = "enc:v1:"
+
+// keySize is the size of the key-encryption keys and data keys (AES-256).
+const keySize = 32
+
+// ErrNoEncryptionKey is returned when an encrypted value has to be decrypted but
+// no key is configured.
+var ErrNoEncryptionKey = errors.New("token encryption key not configured")
+
+// TokenCipher encrypts secrets stored in the database, such as Jira API tokens,
+// with envelope encryption: each value is encrypted with its own random data key
+// (AES-256-GCM), and the data key is encrypted with a key-encryption key
+// identified by a key ID.
+//
+// The encrypted form is
+//
+//	enc:v1:<key ID>:<encrypted data key>:<encrypted value>
+//
+// so that values encrypted with a retired key can still be decrypted as long as
+// the key is configured, and re-encrypted with the current key (key rotation).
+//
+// A TokenCipher without keys stores values as plaintext; see Enabled.
+type TokenCipher struct {
+	keys      map[string][]byte
+	currentID string
+}
+
+// NewTokenCipher returns a TokenCipher that encrypts with the key currentID and
+// decrypts with any of keys. Each key must be 32 bytes. With no keys, the
+// cipher is disabled.
+func NewTokenCipher(currentID string, keys map[string][]byte) (*TokenCipher, error) {
+	if len(keys) == 0 {
+		return &TokenCipher{}, nil
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid token encryption key id %q", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("token encryption key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+	}
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("token encryption key %q not found", currentID)
+	}
+	return &TokenCipher{keys: keys, currentID: currentID}, nil
+}
+
+// LoadTokenCipher reads the token encryption keys from environment variables.
+//
+// Expected environment variables:
+//
+//	TOKEN_ENCRYPTION_KEYS   — comma-separated "<key ID>:<base64 32-byte key>" pairs
+//	TOKEN_ENCRYPTION_KEY_ID — ID of the key to encrypt with (default: the first key)
+//
+// To rotate keys, add a new key, make it current with TOKEN_ENCRYPTION_KEY_ID,
+// run the reencrypt-tokens command, and then remove the old key.
+// When TOKEN_ENCRYPTION_KEYS is empty, the returned cipher is disabled.
+func LoadTokenCipher() (*TokenCipher, error) {
+	raw := strings.TrimSpace(os.Getenv("TOKEN_ENCRYPTION_KEYS"))
+	if raw == "" {
+		return &TokenCipher{}, nil
+	}
+
+	keys := make(map[string][]byte)
+	var firstID string
+	for _, pair := range strings.Split(raw, ",") {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid TOKEN_ENCRYPTION_KEYS entry: expected <key ID>:<base64 key>")
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode token encryption key %q: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("duplicate token encryption key id %q", id)
+		}
+		keys[id] = key
+		if firstID == "" {
+			firstID = id
+		}
+	}
+
+	currentID := os.Getenv("TOKEN_ENCRYPTION_KEY_ID")
+	if currentID == "" {
+		currentID = firstID
+	}
+	return NewTokenCipher(currentID, keys)
+}
+
+// Enabled reports whether keys are configured. A disabled cipher stores values
+// as plaintext and cannot decrypt encrypted values.
+func (c *TokenCipher) Enabled() bool {
+	return len(c.keys) > 0
+}
+
+// CurrentKeyID returns the ID of the key new values are encrypted with.
+func (c *TokenCipher) CurrentKeyID() string {
+	return c.currentID
+}
+
+// IsEncrypted reports whether value was produced by TokenCipher.Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// Encrypt encrypts plaintext with a new data key wrapped by the current key.
+// A disabled cipher returns plaintext unchanged, as does an empty plaintext.
+func (c *TokenCipher) Encrypt(plaintext string) (string, error) {
+	if !c.Enabled() || plaintext == "" {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+	// データキーの暗号化ではキー ID を追加認証データにし、別のキー ID への付け替えを検出する
+	wrappedKey, err := seal(c.keys[c.currentID], dataKey, []byte(c.currentID))
+	if err != nil {
+		return "", fmt.Errorf("encrypt data key: %w", err)
+	}
+	ciphertext, err := seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return "", fmt.Errorf("encrypt value: %w", err)
+	}
+
+	return encryptedPrefix + c.currentID + ":" +
+		base64.RawURLEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawURLEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt returns the plaintext of a value produced by Encrypt. Values that are
+// not encrypted (stored before encryption was enabled) are returned unchanged.
+func (c *TokenCipher) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if !c.Enabled() {
+		return "", ErrNoEncryptionKey
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+	keyID := parts[0]
+	key, ok := c.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("token encryption key %q not configured", keyID)
+	}
+	wrappedKey, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("decode data key: %w", err)
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("decode value: %w", err)
+	}
+
+	dataKey, err := open(key, wrappedKey, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("decrypt data key: %w", err)
+	}
+	plaintext, err := open(dataKey, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencrypt reports whether value should be re-encrypted: it is stored as
+// plaintext or encrypted with a key other than the current one.
+func (c *TokenCipher) NeedsReencrypt(value string) bool {
+	if !c.Enabled() || value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, encryptedPrefix+c.currentID+":")
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce.
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts a value produced by seal.
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,195 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, keySize)
+}
+
+func TestTokenCipher_RoundTrip(t *testing.T) {
+	c, err := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	encrypted, err := c.Encrypt("supersecrettoken1234")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !IsEncrypted(encrypted) || !strings.HasPrefix(encrypted, "enc:v1:k1:") {
+		t.Errorf("expected value encrypted with k1, got: %s", encrypted)
+	}
+	if strings.Contains(encrypted, "supersecrettoken1234") {
+		t.Error("encrypted value must not contain the plaintext")
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if decrypted != "supersecrettoken1234" {
+		t.Errorf("expected original token, got: %s", decrypted)
+	}
+}
+
+func TestTokenCipher_EncryptUsesRandomDataKey(t *testing.T) {
+	c, _ := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+
+	a, _ := c.Encrypt("token")
+	b, _ := c.Encrypt("token")
+	if a == b {
+		t.Error("expected different ciphertexts for the same plaintext")
+	}
+}
+
+func TestTokenCipher_DecryptPlaintextPassthrough(t *testing.T) {
+	c, _ := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+
+	got, err := c.Decrypt("legacy-plaintext-token")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if got != "legacy-plaintext-token" {
+		t.Errorf("expected plaintext unchanged, got: %s", got)
+	}
+}
+
+func TestTokenCipher_Rotation(t *testing.T) {
+	old, _ := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+	encrypted, _ := old.Encrypt("token")
+
+	rotated, err := NewTokenCipher("k2", map[string][]byte{"k1": testKey(1), "k2": testKey(2)})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !rotated.NeedsReencrypt(encrypted) {
+		t.Error("expected value encrypted with a retired key to need re-encryption")
+	}
+	got, err := rotated.Decrypt(encrypted)
+	if err != nil || got != "token" {
+		t.Fatalf("expected retired key to decrypt, got %q, %v", got, err)
+	}
+
+	reencrypted, _ := rotated.Encrypt(got)
+	if rotated.NeedsReencrypt(reencrypted) {
+		t.Error("expected value encrypted with the current key not to need re-encryption")
+	}
+	if !rotated.NeedsReencrypt("plaintext") {
+		t.Error("expected plaintext to need re-encryption")
+	}
+}
+
+func TestTokenCipher_DecryptErrors(t *testing.T) {
+	c, _ := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+	encrypted, _ := c.Encrypt("token")
+
+	other, _ := NewTokenCipher("k2", map[string][]byte{"k2": testKey(2)})
+	if _, err := other.Decrypt(encrypted); err == nil {
+		t.Error("expected error for an unknown key id")
+	}
+
+	// キー ID を付け替えた値は復号できない
+	swapped, _ := NewTokenCipher("k2", map[string][]byte{"k2": testKey(1)})
+	if _, err := swapped.Decrypt(strings.Replace(encrypted, "enc:v1:k1:", "enc:v1:k2:", 1)); err == nil {
+		t.Error("expected error for a value with a swapped key id")
+	}
+
+	if _, err := c.Decrypt(encrypted[:len(encrypted)-4]); err == nil {
+		t.Error("expected error for a tampered value")
+	}
+
+	disabled, _ := NewTokenCipher("", nil)
+	if _, err := disabled.Decrypt(encrypted); err != ErrNoEncryptionKey {
+		t.Errorf("expected ErrNoEncryptionKey, got: %v", err)
+	}
+}
+
+func TestTokenCipher_Disabled(t *testing.T) {
+	c, err := NewTokenCipher("", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.Enabled() {
+		t.Error("expected cipher without keys to be disabled")
+	}
+	got, _ := c.Encrypt("token")
+	if got != "token" {
+		t.Errorf("expected plaintext when disabled, got: %s", got)
+	}
+	if c.NeedsReencrypt("token") {
+		t.Error("expected nothing to re-encrypt when disabled")
+	}
+}
+
+func TestNewTokenCipher_InvalidKeys(t *testing.T) {
+	if _, err := NewTokenCipher("k1", map[string][]byte{"k1": []byte("short")}); err == nil {
+		t.Error("expected error for a short key")
+	}
+	if _, err := NewTokenCipher("k2", map[string][]byte{"k1": testKey(1)}); err == nil {
+		t.Error("expected error for an unknown current key")
+	}
+	if _, err := NewTokenCipher("a:b", map[string][]byte{"a:b": testKey(1)}); err == nil {
+		t.Error("expected error for a key id containing a colon")
+	}
+}
+
+func TestLoadTokenCipher(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	k2 := base64.StdEncoding.EncodeToString(testKey(2))
+
+	t.Setenv("TOKEN_ENCRYPTION_KEYS", "k1:"+k1+", k2:"+k2)
+	t.Setenv("TOKEN_ENCRYPTION_KEY_ID", "")
+	c, err := LoadTokenCipher()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.CurrentKeyID() != "k1" {
+		t.Errorf("expected first key to be current, got: %s", c.CurrentKeyID())
+	}
+
+	t.Setenv("TOKEN_ENCRYPTION_KEY_ID", "k2")
+	c, err = LoadTokenCipher()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.CurrentKeyID() != "k2" {
+		t.Errorf("expected k2 to be current, got: %s", c.CurrentKeyID())
+	}
+}
+
+func TestLoadTokenCipher_Unset(t *testing.T) {
+	t.Setenv("TOKEN_ENCRYPTION_KEYS", "")
+
+	c, err := LoadTokenCipher()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if c.Enabled() {
+		t.Error("expected disabled cipher when TOKEN_ENCRYPTION_KEYS is unset")
+	}
+}
+
+func TestLoadTokenCipher_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		keys string
+	}{
+		{"missing key id", base64.StdEncoding.EncodeToString(testKey(1))},
+		{"invalid base64", "k1:not-base64!"},
+		{"duplicate key id", "k1:" + base64.StdEncoding.EncodeToString(testKey(1)) + ",k1:" + base64.StdEncoding.EncodeToString(testKey(2))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TOKEN_ENCRYPTION_KEYS", tc.keys)
+			t.Setenv("TOKEN_ENCRYPTION_KEY_ID", "")
+			if _, err := LoadTokenCipher(); err == nil {
+				t.Errorf("expected error for %s, got nil", tc.name)
+			}
+		})
+	}
+}
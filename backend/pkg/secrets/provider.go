@@ -0,0 +1,231 @@
+package secrets
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNoCredentials is returned by a CredentialProvider that has no credentials
+// for the requested connection, so that the next provider in a chain is tried.
+var ErrNoCredentials = errors.New("jira credentials not configured")
+
+// Credential sources reported in JiraCredentials.Source.
+const (
+	SourceEnv  = "env"
+	SourceFile = "file"
+	SourceDB   = "db"
+)
+
+// DefaultEnvConnection is the connection that the JIRA_* environment variables
+// belong to unless JIRA_CONNECTION_NAME is set.
+const DefaultEnvConnection = "default"
+
+// defaultCredentialSources is the precedence of the credential sources unless
+// JIRA_CREDENTIAL_SOURCES is set.
+const defaultCredentialSources = SourceEnv + "," + SourceFile + "," + SourceDB
+
+// CredentialProvider resolves the credentials of a Jira connection by its name.
+type CredentialProvider interface {
+	// Credentials returns the complete credentials of the connection, or
+	// ErrNoCredentials when the provider has none for it.
+	Credentials(ctx context.Context, connection string) (JiraCredentials, error)
+}
+
+// EnvProvider provides the credentials in the JIRA_* environment variables (see
+// LoadJira) for the connection named Connection.
+type EnvProvider struct {
+	Connection string
+}
+
+// Credentials implements CredentialProvider. Partially set variables are an
+// error rather than ErrNoCredentials, so that a typo does not silently fall
+// back to another source.
+func (p EnvProvider) Credentials(_ context.Context, connection string) (JiraCredentials, error) {
+	if connection != p.Connection {
+		return JiraCredentials{}, ErrNoCredentials
+	}
+	if os.Getenv("JIRA_BASE_URL") == "" && os.Getenv("JIRA_EMAIL") == "" && os.Getenv("JIRA_API_TOKEN") == "" {
+		return JiraCredentials{}, ErrNoCredentials
+	}
+	creds, err := LoadJira()
+	if err != nil {
+		return JiraCredentials{}, err
+	}
+	creds.Source = SourceEnv
+	return creds, nil
+}
+
+// fileCredentials is an entry of the credentials file.
+type fileCredentials struct {
+	AuthType string `json:"auth_type"`
+	BaseURL  string `json:"base_url"`
+	Email    string `json:"email"`
+	APIToken string `json:"api_token"`
+}
+
+// FileProvider provides credentials from a JSON file keyed by connection name:
+//
+//	{"default": {"base_url": "https://your-org.atlassian.net", "email": "...", "api_token": "..."}}
+//
+// auth_type is "basic" (default) or "pat"; email is not used with "pat".
+//
+// The file is read on every call so that a rotated secret mounted as a file
+// takes effect without a restart. An empty Path provides no credentials.
+type FileProvider struct {
+	Path string
+}
+
+// Credentials implements CredentialProvider.
+func (p FileProvider) Credentials(_ context.Context, connection string) (JiraCredentials, error) {
+	if p.Path == "" {
+		return JiraCredentials{}, ErrNoCredentials
+	}
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return JiraCredentials{}, fmt.Errorf("read jira credentials file: %w", err)
+	}
+	var entries map[string]fileCredentials
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return JiraCredentials{}, fmt.Errorf("parse jira credentials file: %w", err)
+	}
+	entry, ok := entries[connection]
+	if !ok {
+		return JiraCredentials{}, ErrNoCredentials
+	}
+
+	creds := JiraCredentials{
+		BaseURL:  strings.TrimRight(entry.BaseURL, "/"),
+		Email:    entry.Email,
+		APIToken: entry.APIToken,
+		AuthType: entry.AuthType,
+		Source:   SourceFile,
+	}
+	if creds.AuthType == "" {
+		creds.AuthType = AuthTypeBasic
+	}
+	if creds.AuthType != AuthTypeBasic && creds.AuthType != AuthTypePAT {
+		return JiraCredentials{}, fmt.Errorf("jira credentials file: connection %q has unsupported auth_type %q (expected basic or pat)", connection, creds.AuthType)
+	}
+	var missing []string
+	if creds.BaseURL == "" {
+		missing = append(missing, "base_url")
+	}
+	if creds.Email == "" && creds.AuthType == AuthTypeBasic {
+		missing = append(missing, "email")
+	}
+	if creds.APIToken == "" {
+		missing = append(missing, "api_token")
+	}
+	if len(missing) > 0 {
+		return JiraCredentials{}, fmt.Errorf("jira credentials file: connection %q is missing %s", connection, strings.Join(missing, ", "))
+	}
+	return creds, nil
+}
+
+// DBProvider provides the credentials saved from the admin UI in the
+// jira_connections table, decrypting the API token with Cipher.
+type DBProvider struct {
+	DB     *sqlx.DB
+	Cipher *TokenCipher
+}
+
+// Credentials implements CredentialProvider. A connection without a complete
+// set of stored credentials provides none; an OAuth connection is complete once
+// the consent flow has recorded its site (cloud_id).
+func (p DBProvider) Credentials(ctx context.Context, connection string) (JiraCredentials, error) {
+	creds := JiraCredentials{Source: SourceDB}
+	err := p.DB.QueryRowxContext(ctx,
+		`SELECT base_url, email, api_token, auth_type, COALESCE(cloud_id, '') FROM jira_connections WHERE name = $1`, connection,
+	).Scan(&creds.BaseURL, &creds.Email, &creds.APIToken, &creds.AuthType, &creds.CloudID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return JiraCredentials{}, ErrNoCredentials
+	}
+	if err != nil {
+		return JiraCredentials{}, fmt.Errorf("fetch jira credentials of connection %q: %w", connection, err)
+	}
+
+	creds.APIToken, err = p.Cipher.Decrypt(creds.APIToken)
+	if err != nil {
+		return JiraCredentials{}, fmt.Errorf("decrypt api token of jira connection %q: %w", connection, err)
+	}
+	if !creds.Complete() {
+		return JiraCredentials{}, ErrNoCredentials
+	}
+	return creds, nil
+}
+
+// Complete reports whether c has everything its authentication type needs.
+func (c JiraCredentials) Complete() bool {
+	switch c.AuthType {
+	case AuthTypeOAuth:
+		return c.BaseURL != "" && c.CloudID != ""
+	case AuthTypePAT:
+		return c.BaseURL != "" && c.APIToken != ""
+	default:
+		return c.BaseURL != "" && c.Email != "" && c.APIToken != ""
+	}
+}
+
+// ChainProvider tries its providers in order and returns the credentials of
+// the first one that has them. Errors other than ErrNoCredentials stop the chain.
+type ChainProvider []CredentialProvider
+
+// Credentials implements CredentialProvider.
+func (c ChainProvider) Credentials(ctx context.Context, connection string) (JiraCredentials, error) {
+	for _, p := range c {
+		creds, err := p.Credentials(ctx, connection)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return creds, err
+	}
+	return JiraCredentials{}, ErrNoCredentials
+}
+
+// LoadCredentialProvider builds the credential chain shared by the batch and
+// the API, so that scheduled and manual syncs use the same credentials.
+//
+// Expected environment variables:
+//
+//	JIRA_CREDENTIAL_SOURCES — comma-separated sources in precedence order (default "env,file,db")
+//	JIRA_CREDENTIALS_FILE   — path of the credentials file read by the "file" source
+//	JIRA_CONNECTION_NAME    — connection the JIRA_* variables belong to (default "default")
+func LoadCredentialProvider(db *sqlx.DB, cipher *TokenCipher) (ChainProvider, error) {
+	sources := os.Getenv("JIRA_CREDENTIAL_SOURCES")
+	if strings.TrimSpace(sources) == "" {
+		sources = defaultCredentialSources
+	}
+	envConnection := os.Getenv("JIRA_CONNECTION_NAME")
+	if envConnection == "" {
+		envConnection = DefaultEnvConnection
+	}
+
+	var chain ChainProvider
+	seen := make(map[string]bool)
+	for _, source := range strings.Split(sources, ",") {
+		source = strings.TrimSpace(source)
+		if seen[source] {
+			return nil, fmt.Errorf("duplicate jira credential source %q", source)
+		}
+		seen[source] = true
+
+		switch source {
+		case SourceEnv:
+			chain = append(chain, EnvProvider{Connection: envConnection})
+		case SourceFile:
+			chain = append(chain, FileProvider{Path: os.Getenv("JIRA_CREDENTIALS_FILE")})
+		case SourceDB:
+			chain = append(chain, DBProvider{DB: db, Cipher: cipher})
+		default:
+			return nil, fmt.Errorf("unknown jira credential source %q (expected env, file or db)", source)
+		}
+	}
+	return chain, nil
+}
@@ -0,0 +1,284 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func clearJiraEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("JIRA_AUTH_TYPE", "")
+	t.Setenv("JIRA_BASE_URL", "")
+	t.Setenv("JIRA_EMAIL", "")
+	t.Setenv("JIRA_API_TOKEN", "")
+}
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jira-credentials.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write credentials file: %v", err)
+	}
+	return path
+}
+
+var dbCredentialColumns = []string{"base_url", "email", "api_token", "auth_type", "cloud_id"}
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { mockDB.Close() })
+	return sqlx.NewDb(mockDB, "sqlmock"), mock
+}
+
+func TestEnvProvider(t *testing.T) {
+	t.Setenv("JIRA_BASE_URL", "https://env.atlassian.net/")
+	t.Setenv("JIRA_EMAIL", "env@example.com")
+	t.Setenv("JIRA_API_TOKEN", "envtoken")
+	p := EnvProvider{Connection: "default"}
+
+	creds, err := p.Credentials(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if creds.BaseURL != "https://env.atlassian.net" || creds.Source != SourceEnv {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+
+	// 環境変数は 1 つの接続の認証情報としてのみ使う
+	if _, err := p.Credentials(context.Background(), "acquired"); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials for another connection, got: %v", err)
+	}
+}
+
+func TestEnvProvider_PAT(t *testing.T) {
+	clearJiraEnv(t)
+	t.Setenv("JIRA_AUTH_TYPE", "pat")
+	t.Setenv("JIRA_BASE_URL", "https://jira.example.com")
+	t.Setenv("JIRA_API_TOKEN", "pat-token")
+
+	creds, err := EnvProvider{Connection: "default"}.Credentials(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if creds.AuthType != AuthTypePAT || creds.APIToken != "pat-token" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestEnvProvider_Unset(t *testing.T) {
+	clearJiraEnv(t)
+
+	_, err := EnvProvider{Connection: "default"}.Credentials(context.Background(), "default")
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got: %v", err)
+	}
+}
+
+func TestEnvProvider_Partial(t *testing.T) {
+	clearJiraEnv(t)
+	t.Setenv("JIRA_BASE_URL", "https://env.atlassian.net")
+
+	_, err := EnvProvider{Connection: "default"}.Credentials(context.Background(), "default")
+	if err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected error for partially set variables, got: %v", err)
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	path := writeCredentialsFile(t, `{
+		"acquired": {"base_url": "https://acquired.atlassian.net/", "email": "file@example.com", "api_token": "filetoken"},
+		"broken": {"base_url": "https://broken.atlassian.net"}
+	}`)
+	p := FileProvider{Path: path}
+
+	creds, err := p.Credentials(context.Background(), "acquired")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := JiraCredentials{BaseURL: "https://acquired.atlassian.net", Email: "file@example.com", APIToken: "filetoken", AuthType: AuthTypeBasic, Source: SourceFile}
+	if creds != want {
+		t.Errorf("expected %+v, got: %+v", want, creds)
+	}
+
+	if _, err := p.Credentials(context.Background(), "default"); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials for a connection not in the file, got: %v", err)
+	}
+	if _, err := p.Credentials(context.Background(), "broken"); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected error for incomplete entry, got: %v", err)
+	}
+}
+
+func TestFileProvider_Errors(t *testing.T) {
+	if _, err := (FileProvider{}).Credentials(context.Background(), "default"); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials without a path, got: %v", err)
+	}
+
+	missing := FileProvider{Path: filepath.Join(t.TempDir(), "missing.json")}
+	if _, err := missing.Credentials(context.Background(), "default"); err == nil {
+		t.Error("expected error for a missing file")
+	}
+
+	invalid := FileProvider{Path: writeCredentialsFile(t, "not json")}
+	if _, err := invalid.Credentials(context.Background(), "default"); err == nil {
+		t.Error("expected error for an invalid file")
+	}
+}
+
+func TestDBProvider(t *testing.T) {
+	db, mock := newMockDB(t)
+	cipher, _ := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+	encrypted, _ := cipher.Encrypt("dbtoken")
+	mock.ExpectQuery(`SELECT base_url, email, api_token, auth_type, COALESCE\(cloud_id, ''\) FROM jira_connections WHERE name = \$1`).
+		WithArgs("default").
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns).
+			AddRow("https://db.atlassian.net", "db@example.com", encrypted, "basic", ""))
+
+	creds, err := DBProvider{DB: db, Cipher: cipher}.Credentials(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	want := JiraCredentials{BaseURL: "https://db.atlassian.net", Email: "db@example.com", APIToken: "dbtoken", AuthType: AuthTypeBasic, Source: SourceDB}
+	if creds != want {
+		t.Errorf("expected %+v, got: %+v", want, creds)
+	}
+}
+
+func TestDBProvider_NoCredentials(t *testing.T) {
+	db, mock := newMockDB(t)
+	cipher, _ := NewTokenCipher("", nil)
+	mock.ExpectQuery(`FROM jira_connections`).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns))
+	mock.ExpectQuery(`FROM jira_connections`).WithArgs("empty").
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns).AddRow("", "", "", "basic", ""))
+	p := DBProvider{DB: db, Cipher: cipher}
+
+	for _, name := range []string{"missing", "empty"} {
+		if _, err := p.Credentials(context.Background(), name); !errors.Is(err, ErrNoCredentials) {
+			t.Errorf("expected ErrNoCredentials for %s, got: %v", name, err)
+		}
+	}
+}
+
+func TestDBProvider_AuthTypes(t *testing.T) {
+	db, mock := newMockDB(t)
+	cipher, _ := NewTokenCipher("", nil)
+	mock.ExpectQuery(`FROM jira_connections`).WithArgs("dc").
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns).AddRow("https://jira.example.com", "", "pat-token", "pat", ""))
+	mock.ExpectQuery(`FROM jira_connections`).WithArgs("oauth").
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns).AddRow("https://oauth.atlassian.net", "", "", "oauth", "cloud-1"))
+	mock.ExpectQuery(`FROM jira_connections`).WithArgs("unauthorized").
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns).AddRow("https://oauth.atlassian.net", "", "", "oauth", ""))
+	p := DBProvider{DB: db, Cipher: cipher}
+
+	creds, err := p.Credentials(context.Background(), "dc")
+	if err != nil || creds.AuthType != AuthTypePAT || creds.APIToken != "pat-token" {
+		t.Errorf("expected PAT credentials without email, got %+v, %v", creds, err)
+	}
+	creds, err = p.Credentials(context.Background(), "oauth")
+	if err != nil || creds.AuthType != AuthTypeOAuth || creds.CloudID != "cloud-1" {
+		t.Errorf("expected OAuth credentials, got %+v, %v", creds, err)
+	}
+	// 同意フローが完了していない OAuth 接続は認証情報なし
+	if _, err := p.Credentials(context.Background(), "unauthorized"); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got: %v", err)
+	}
+}
+
+func TestDBProvider_DecryptError(t *testing.T) {
+	db, mock := newMockDB(t)
+	cipher, _ := NewTokenCipher("k1", map[string][]byte{"k1": testKey(1)})
+	encrypted, _ := cipher.Encrypt("dbtoken")
+	mock.ExpectQuery(`FROM jira_connections`).
+		WillReturnRows(sqlmock.NewRows(dbCredentialColumns).
+			AddRow("https://db.atlassian.net", "db@example.com", encrypted, "basic", ""))
+	disabled, _ := NewTokenCipher("", nil)
+
+	_, err := DBProvider{DB: db, Cipher: disabled}.Credentials(context.Background(), "default")
+	if !errors.Is(err, ErrNoEncryptionKey) {
+		t.Errorf("expected ErrNoEncryptionKey, got: %v", err)
+	}
+}
+
+type stubProvider struct {
+	creds JiraCredentials
+	err   error
+}
+
+func (s stubProvider) Credentials(context.Context, string) (JiraCredentials, error) {
+	return s.creds, s.err
+}
+
+func TestChainProvider(t *testing.T) {
+	env := stubProvider{err: ErrNoCredentials}
+	file := stubProvider{creds: JiraCredentials{APIToken: "filetoken", Source: SourceFile}}
+	db := stubProvider{creds: JiraCredentials{APIToken: "dbtoken", Source: SourceDB}}
+
+	// 認証情報を持つ最初の取得元が優先される
+	creds, err := ChainProvider{env, file, db}.Credentials(context.Background(), "default")
+	if err != nil || creds.Source != SourceFile {
+		t.Errorf("expected file credentials, got %+v, %v", creds, err)
+	}
+
+	if _, err := (ChainProvider{env}).Credentials(context.Background(), "default"); !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected ErrNoCredentials, got: %v", err)
+	}
+
+	// 取得元のエラーでは後続の取得元にフォールバックしない
+	failing := stubProvider{err: errors.New("read jira credentials file: permission denied")}
+	if _, err := (ChainProvider{failing, db}).Credentials(context.Background(), "default"); err == nil || errors.Is(err, ErrNoCredentials) {
+		t.Errorf("expected provider error, got: %v", err)
+	}
+}
+
+func TestLoadCredentialProvider(t *testing.T) {
+	clearJiraEnv(t)
+	t.Setenv("JIRA_CREDENTIAL_SOURCES", "")
+	t.Setenv("JIRA_CREDENTIALS_FILE", writeCredentialsFile(t, `{"acquired": {"base_url": "https://acquired.atlassian.net", "email": "file@example.com", "api_token": "filetoken"}}`))
+	t.Setenv("JIRA_CONNECTION_NAME", "acquired")
+
+	chain, err := LoadCredentialProvider(nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(chain) != 3 {
+		t.Fatalf("expected env, file and db sources, got %d", len(chain))
+	}
+
+	// 環境変数がファイルより優先される
+	t.Setenv("JIRA_BASE_URL", "https://env.atlassian.net")
+	t.Setenv("JIRA_EMAIL", "env@example.com")
+	t.Setenv("JIRA_API_TOKEN", "envtoken")
+	creds, err := chain.Credentials(context.Background(), "acquired")
+	if err != nil || creds.Source != SourceEnv {
+		t.Errorf("expected env credentials, got %+v, %v", creds, err)
+	}
+
+	t.Setenv("JIRA_CREDENTIAL_SOURCES", "file, env")
+	chain, err = LoadCredentialProvider(nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	creds, err = chain.Credentials(context.Background(), "acquired")
+	if err != nil || creds.Source != SourceFile {
+		t.Errorf("expected file credentials, got %+v, %v", creds, err)
+	}
+}
+
+func TestLoadCredentialProvider_Invalid(t *testing.T) {
+	for _, sources := range []string{"env,vault", "env,env"} {
+		t.Setenv("JIRA_CREDENTIAL_SOURCES", sources)
+		if _, err := LoadCredentialProvider(nil, nil); err == nil {
+			t.Errorf("expected error for %q, got nil", sources)
+		}
+	}
+}
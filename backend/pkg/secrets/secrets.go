@@ -12,23 +12,46 @@ import (
 	"strings"
 )
 
+// Jira authentication types reported in JiraCredentials.AuthType.
+const (
+	// AuthTypeBasic authenticates with an Atlassian account email and API token (Jira Cloud).
+	AuthTypeBasic = "basic"
+	// AuthTypePAT authenticates with a personal access token (Jira Data Center).
+	AuthTypePAT = "pat"
+	// AuthTypeOAuth authenticates with OAuth 2.0 (3LO) tokens stored in jira_oauth_tokens.
+	AuthTypeOAuth = "oauth"
+)
+
 // JiraCredentials holds the authentication information required to access the Jira Cloud API.
 type JiraCredentials struct {
 	// BaseURL is the base URL of the Jira Cloud instance (e.g. https://your-org.atlassian.net).
 	BaseURL string
 	// Email is the Atlassian account email used for Basic authentication.
 	Email string
-	// APIToken is the Jira API token associated with the email account.
+	// APIToken is the Jira API token associated with the email account, or the
+	// personal access token with AuthTypePAT.
 	APIToken string
+	// AuthType is AuthTypeBasic, AuthTypePAT or AuthTypeOAuth.
+	AuthType string
+	// CloudID is the Atlassian site ID the OAuth tokens were granted for; set
+	// once the consent flow has completed.
+	CloudID string
+	// Source is the credential source the credentials came from (SourceEnv,
+	// SourceFile or SourceDB); empty for LoadJira.
+	Source string
 }
 
 // LoadJira reads Jira API credentials from environment variables and validates them.
 //
 // Expected environment variables:
 //
+//	JIRA_AUTH_TYPE — "basic" (default) or "pat"
 //	JIRA_BASE_URL  — Jira Cloud base URL
-//	JIRA_EMAIL     — Atlassian account email
-//	JIRA_API_TOKEN — Jira API token
+//	JIRA_EMAIL     — Atlassian account email (not used with "pat")
+//	JIRA_API_TOKEN — Jira API token, or the personal access token with "pat"
+//
+// OAuth 2.0 cannot be configured through environment variables because its
+// refresh tokens rotate; use a connection saved from the admin UI instead.
 //
 // In production (ECS), these variables are populated via AWS Secrets Manager
 // using the task definition "secrets" field. See docs/secrets-management.md.
@@ -37,13 +60,20 @@ func LoadJira() (JiraCredentials, error) {
 		BaseURL:  strings.TrimRight(os.Getenv("JIRA_BASE_URL"), "/"),
 		Email:    os.Getenv("JIRA_EMAIL"),
 		APIToken: os.Getenv("JIRA_API_TOKEN"),
+		AuthType: os.Getenv("JIRA_AUTH_TYPE"),
+	}
+	if creds.AuthType == "" {
+		creds.AuthType = AuthTypeBasic
+	}
+	if creds.AuthType != AuthTypeBasic && creds.AuthType != AuthTypePAT {
+		return JiraCredentials{}, fmt.Errorf("unsupported JIRA_AUTH_TYPE %q (expected basic or pat)", creds.AuthType)
 	}
 
 	var missing []string
 	if creds.BaseURL == "" {
 		missing = append(missing, "JIRA_BASE_URL")
 	}
-	if creds.Email == "" {
+	if creds.Email == "" && creds.AuthType == AuthTypeBasic {
 		missing = append(missing, "JIRA_EMAIL")
 	}
 	if creds.APIToken == "" {
@@ -61,3 +61,14 @@ func TestLoadJira_MissingPartial(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadJira_UnsupportedAuthType(t *testing.T) {
+	t.Setenv("JIRA_AUTH_TYPE", "oauth")
+	t.Setenv("JIRA_BASE_URL", "https://example.atlassian.net")
+	t.Setenv("JIRA_EMAIL", "user@example.com")
+	t.Setenv("JIRA_API_TOKEN", "token123")
+
+	if _, err := LoadJira(); err == nil {
+		t.Fatal("expected error for unsupported auth type, got nil")
+	}
+}
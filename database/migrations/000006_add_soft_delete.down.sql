@@ -0,0 +1,24 @@
+CREATE OR REPLACE VIEW project_delay_summary AS
+SELECT
+    p.id AS project_id,
+    p.jira_project_id,
+    p.key AS project_key,
+    p.name AS project_name,
+    p.organization_id,
+    COUNT(i.id) AS total_issues,
+    COUNT(CASE WHEN i.delay_status = 'RED' THEN 1 END) AS red_issues,
+    COUNT(CASE WHEN i.delay_status = 'YELLOW' THEN 1 END) AS yellow_issues,
+    COUNT(CASE WHEN i.delay_status = 'GREEN' THEN 1 END) AS green_issues,
+    COUNT(CASE WHEN i.status_category != 'Done' THEN 1 END) AS open_issues,
+    COUNT(CASE WHEN i.status_category = 'Done' THEN 1 END) AS done_issues
+FROM
+    projects p
+    LEFT JOIN issues i ON p.id = i.project_id
+GROUP BY
+    p.id, p.jira_project_id, p.key, p.name, p.organization_id;
+
+DROP INDEX IF EXISTS idx_issues_not_deleted;
+DROP INDEX IF EXISTS idx_projects_not_deleted;
+
+ALTER TABLE issues   DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE projects DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,31 @@
+-- Jira から消えたプロジェクト・チケットを論理削除するための列
+ALTER TABLE projects ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE issues   ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_projects_not_deleted ON projects(id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_issues_not_deleted   ON issues(project_id) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN projects.deleted_at IS '論理削除日時（フル同期で Jira に存在しなかった場合に設定）';
+COMMENT ON COLUMN issues.deleted_at   IS '論理削除日時（フル同期で Jira に存在しなかった場合に設定）';
+
+-- 集計ビューから論理削除済みのレコードを除外する
+CREATE OR REPLACE VIEW project_delay_summary AS
+SELECT
+    p.id AS project_id,
+    p.jira_project_id,
+    p.key AS project_key,
+    p.name AS project_name,
+    p.organization_id,
+    COUNT(i.id) AS total_issues,
+    COUNT(CASE WHEN i.delay_status = 'RED' THEN 1 END) AS red_issues,
+    COUNT(CASE WHEN i.delay_status = 'YELLOW' THEN 1 END) AS yellow_issues,
+    COUNT(CASE WHEN i.delay_status = 'GREEN' THEN 1 END) AS green_issues,
+    COUNT(CASE WHEN i.status_category != 'Done' THEN 1 END) AS open_issues,
+    COUNT(CASE WHEN i.status_category = 'Done' THEN 1 END) AS done_issues
+FROM
+    projects p
+    LEFT JOIN issues i ON p.id = i.project_id AND i.deleted_at IS NULL
+WHERE
+    p.deleted_at IS NULL
+GROUP BY
+    p.id, p.jira_project_id, p.key, p.name, p.organization_id;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS sync_project_results;
+
+UPDATE sync_logs SET status = 'SUCCESS' WHERE status = 'PARTIAL';
+ALTER TABLE sync_logs DROP CONSTRAINT sync_logs_status_check;
+ALTER TABLE sync_logs ADD CONSTRAINT sync_logs_status_check
+  CHECK (status IN ('RUNNING', 'SUCCESS', 'FAILURE'));
@@ -0,0 +1,26 @@
+-- 一部のプロジェクトのみ失敗した同期を表す PARTIAL ステータスを追加
+ALTER TABLE sync_logs DROP CONSTRAINT sync_logs_status_check;
+ALTER TABLE sync_logs ADD CONSTRAINT sync_logs_status_check
+  CHECK (status IN ('RUNNING', 'SUCCESS', 'PARTIAL', 'FAILURE'));
+
+-- プロジェクト単位の同期結果
+CREATE TABLE sync_project_results (
+    id               BIGSERIAL PRIMARY KEY,
+    sync_log_id      BIGINT       NOT NULL REFERENCES sync_logs(id) ON DELETE CASCADE,
+    project_id       BIGINT       REFERENCES projects(id) ON DELETE SET NULL,
+    jira_project_id  VARCHAR(100) NOT NULL,
+    project_key      VARCHAR(50)  NOT NULL,
+    status           VARCHAR(20)  NOT NULL CHECK (status IN ('SUCCESS', 'FAILURE')),
+    issues_synced    INTEGER      NOT NULL DEFAULT 0,
+    error_message    TEXT,
+    duration_ms      INTEGER      NOT NULL DEFAULT 0,
+    created_at       TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sync_project_results_sync_log_id ON sync_project_results(sync_log_id);
+CREATE INDEX idx_sync_project_results_project_id  ON sync_project_results(project_id);
+
+COMMENT ON TABLE  sync_project_results               IS 'Jira同期のプロジェクト単位の実行結果';
+COMMENT ON COLUMN sync_project_results.project_id    IS 'DBのプロジェクトID（未登録のプロジェクトはNULL）';
+COMMENT ON COLUMN sync_project_results.issues_synced IS 'Jiraから取得したチケット数';
+COMMENT ON COLUMN sync_project_results.duration_ms   IS 'チケット取得に要した時間（ミリ秒）';
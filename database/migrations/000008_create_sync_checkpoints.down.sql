@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sync_project_results_log_project;
+DROP TABLE IF EXISTS sync_checkpoints;
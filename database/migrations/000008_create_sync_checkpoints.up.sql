@@ -0,0 +1,19 @@
+-- フル同期の再開用チェックポイント（プロジェクト単位）
+CREATE TABLE sync_checkpoints (
+    sync_log_id      BIGINT       NOT NULL REFERENCES sync_logs(id) ON DELETE CASCADE,
+    jira_project_id  VARCHAR(100) NOT NULL,
+    next_start_at    INTEGER      NOT NULL DEFAULT 0,
+    issues_synced    INTEGER      NOT NULL DEFAULT 0,
+    completed        BOOLEAN      NOT NULL DEFAULT FALSE,
+    updated_at       TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (sync_log_id, jira_project_id)
+);
+
+COMMENT ON TABLE  sync_checkpoints               IS 'フル同期の再開用チェックポイント';
+COMMENT ON COLUMN sync_checkpoints.next_start_at IS '次に取得するページの startAt（ページカーソル）';
+COMMENT ON COLUMN sync_checkpoints.issues_synced IS 'これまでに取り込んだチケット数';
+COMMENT ON COLUMN sync_checkpoints.completed     IS 'プロジェクトの全ページ取り込み完了フラグ';
+
+-- 再開時に同じプロジェクトの結果を上書きできるよう一意制約を追加
+CREATE UNIQUE INDEX idx_sync_project_results_log_project
+    ON sync_project_results(sync_log_id, jira_project_id);
@@ -0,0 +1,4 @@
+-- 拡張 JQL 検索（/rest/api/3/search/jql）のページカーソルを保存する
+ALTER TABLE sync_checkpoints ADD COLUMN next_page_token TEXT;
+
+COMMENT ON COLUMN sync_checkpoints.next_page_token IS '次に取得するページの nextPageToken（拡張 JQL 検索使用時のページカーソル）';
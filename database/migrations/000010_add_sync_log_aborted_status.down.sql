@@ -0,0 +1,4 @@
+UPDATE sync_logs SET status = 'FAILURE' WHERE status = 'ABORTED';
+ALTER TABLE sync_logs DROP CONSTRAINT sync_logs_status_check;
+ALTER TABLE sync_logs ADD CONSTRAINT sync_logs_status_check
+  CHECK (status IN ('RUNNING', 'SUCCESS', 'PARTIAL', 'FAILURE'));
@@ -0,0 +1,4 @@
+-- シグナル等で中断された同期を表す ABORTED ステータスを追加
+ALTER TABLE sync_logs DROP CONSTRAINT sync_logs_status_check;
+ALTER TABLE sync_logs ADD CONSTRAINT sync_logs_status_check
+  CHECK (status IN ('RUNNING', 'SUCCESS', 'PARTIAL', 'FAILURE', 'ABORTED'));
@@ -0,0 +1,23 @@
+-- チケットのステータス・期日の変更履歴（Jira changelog から取り込む）
+CREATE TABLE issue_history (
+    id                BIGSERIAL    PRIMARY KEY,
+    issue_id          BIGINT       NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    jira_history_id   VARCHAR(100) NOT NULL,
+    field             VARCHAR(20)  NOT NULL CHECK (field IN ('status', 'duedate')),
+    from_value        VARCHAR(255),
+    to_value          VARCHAR(255),
+    author_account_id VARCHAR(255),
+    author_name       VARCHAR(255),
+    changed_at        TIMESTAMP    NOT NULL,
+    created_at        TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issue_id, jira_history_id, field)
+);
+
+CREATE INDEX idx_issue_history_issue_changed ON issue_history(issue_id, changed_at);
+
+COMMENT ON TABLE  issue_history                 IS 'チケットのステータス・期日の変更履歴';
+COMMENT ON COLUMN issue_history.jira_history_id IS 'Jira changelog の履歴 ID';
+COMMENT ON COLUMN issue_history.field           IS '変更されたフィールド（status / duedate）';
+COMMENT ON COLUMN issue_history.from_value      IS '変更前の値（status はステータス名、duedate は YYYY-MM-DD）';
+COMMENT ON COLUMN issue_history.to_value        IS '変更後の値（未設定になった場合は NULL）';
+COMMENT ON COLUMN issue_history.changed_at      IS '変更日時（UTC）';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_issues_slipped;
+ALTER TABLE issues DROP COLUMN IF EXISTS slipped;
+ALTER TABLE issues DROP COLUMN IF EXISTS postpone_count;
+ALTER TABLE issues DROP COLUMN IF EXISTS original_due_date;
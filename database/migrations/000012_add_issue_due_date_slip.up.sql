@@ -0,0 +1,35 @@
+-- 期日の延期（スリップ）検知用カラム
+ALTER TABLE issues ADD COLUMN original_due_date DATE;
+ALTER TABLE issues ADD COLUMN postpone_count    INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE issues ADD COLUMN slipped           BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN issues.original_due_date IS '最初に設定された期日';
+COMMENT ON COLUMN issues.postpone_count    IS '期日が後ろ倒しされた回数';
+COMMENT ON COLUMN issues.slipped           IS '現在の期日が最初の期日より後ろ倒しされているか';
+
+-- 既存データ: 取り込み済みの変更履歴があればそこから、なければ現在の期日を初期値とする
+UPDATE issues SET original_due_date = due_date;
+
+UPDATE issues i
+SET original_due_date = COALESCE(h.from_value, h.to_value)::date
+FROM (
+    SELECT DISTINCT ON (issue_id) issue_id, from_value, to_value
+    FROM issue_history
+    WHERE field = 'duedate'
+    ORDER BY issue_id, changed_at, id
+) h
+WHERE h.issue_id = i.id AND COALESCE(h.from_value, h.to_value) IS NOT NULL;
+
+UPDATE issues i
+SET postpone_count = h.cnt
+FROM (
+    SELECT issue_id, COUNT(*) AS cnt
+    FROM issue_history
+    WHERE field = 'duedate' AND to_value::date > from_value::date
+    GROUP BY issue_id
+) h
+WHERE h.issue_id = i.id;
+
+UPDATE issues SET slipped = TRUE WHERE due_date > original_due_date;
+
+CREATE INDEX idx_issues_slipped ON issues(project_id) WHERE slipped AND deleted_at IS NULL;
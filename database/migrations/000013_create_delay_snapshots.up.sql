@@ -0,0 +1,30 @@
+-- 日次の遅延状況スナップショット（プロジェクト単位・組織単位）
+CREATE TABLE delay_snapshots (
+    id              BIGSERIAL PRIMARY KEY,
+    snapshot_date   DATE      NOT NULL,
+    project_id      BIGINT    REFERENCES projects(id) ON DELETE CASCADE,
+    organization_id BIGINT    REFERENCES organizations(id) ON DELETE CASCADE,
+    red_issues      INTEGER   NOT NULL DEFAULT 0,
+    yellow_issues   INTEGER   NOT NULL DEFAULT 0,
+    green_issues    INTEGER   NOT NULL DEFAULT 0,
+    total_issues    INTEGER   NOT NULL DEFAULT 0,
+    red_projects    INTEGER   NOT NULL DEFAULT 0,
+    yellow_projects INTEGER   NOT NULL DEFAULT 0,
+    green_projects  INTEGER   NOT NULL DEFAULT 0,
+    total_projects  INTEGER   NOT NULL DEFAULT 0,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- プロジェクト単位か組織単位のどちらか一方
+    CHECK ((project_id IS NULL) <> (organization_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_delay_snapshots_project
+    ON delay_snapshots(snapshot_date, project_id) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_delay_snapshots_organization
+    ON delay_snapshots(snapshot_date, organization_id) WHERE organization_id IS NOT NULL;
+CREATE INDEX idx_delay_snapshots_org_date ON delay_snapshots(organization_id, snapshot_date);
+
+COMMENT ON TABLE  delay_snapshots                 IS '日次の遅延状況スナップショット';
+COMMENT ON COLUMN delay_snapshots.snapshot_date   IS '集計日（JST）';
+COMMENT ON COLUMN delay_snapshots.project_id      IS 'プロジェクト単位の集計の場合に設定';
+COMMENT ON COLUMN delay_snapshots.organization_id IS '組織単位の集計の場合に設定（配下の組織のプロジェクトを含む）';
+COMMENT ON COLUMN delay_snapshots.red_projects    IS '遅延状況が RED のプロジェクト数（プロジェクト単位では 0 または 1）';
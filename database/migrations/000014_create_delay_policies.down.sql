@@ -0,0 +1,19 @@
+CREATE OR REPLACE FUNCTION calculate_delay_status()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.delay_status := CASE
+        WHEN NEW.status_category != 'Done' AND NEW.due_date < CURRENT_DATE THEN 'RED'
+        WHEN NEW.status_category != 'Done' AND NEW.due_date BETWEEN CURRENT_DATE AND CURRENT_DATE + INTERVAL '3 days' THEN 'YELLOW'
+        WHEN NEW.status_category != 'Done' AND NEW.due_date IS NULL THEN 'YELLOW'
+        ELSE 'GREEN'
+    END;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER calculate_issue_delay_status
+    BEFORE INSERT OR UPDATE ON issues
+    FOR EACH ROW
+    EXECUTE FUNCTION calculate_delay_status();
+
+DROP TABLE IF EXISTS delay_policies;
@@ -0,0 +1,32 @@
+-- 遅延判定ポリシー（全体デフォルト → 組織（親から順に） → プロジェクトの順に上書き）
+CREATE TABLE delay_policies (
+    id                 BIGSERIAL   PRIMARY KEY,
+    organization_id    BIGINT      UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    project_id         BIGINT      UNIQUE REFERENCES projects(id) ON DELETE CASCADE,
+    yellow_days        INTEGER     CHECK (yellow_days >= 0),
+    no_due_date_status VARCHAR(10) CHECK (no_due_date_status IN ('RED', 'YELLOW', 'GREEN')),
+    created_at         TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- 組織とプロジェクトの両方には設定できない（両方 NULL は全体デフォルト）
+    CHECK (organization_id IS NULL OR project_id IS NULL)
+);
+
+-- 全体デフォルトは 1 件のみ
+CREATE UNIQUE INDEX idx_delay_policies_global
+    ON delay_policies((TRUE)) WHERE organization_id IS NULL AND project_id IS NULL;
+
+CREATE TRIGGER update_delay_policies_updated_at
+    BEFORE UPDATE ON delay_policies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  delay_policies                    IS '遅延判定ポリシー';
+COMMENT ON COLUMN delay_policies.yellow_days        IS '期日の何日前から YELLOW とするか（NULL は上位のポリシーを継承）';
+COMMENT ON COLUMN delay_policies.no_due_date_status IS '期日未設定の未完了チケットの遅延ステータス（NULL は上位のポリシーを継承）';
+
+-- 従来の固定値を全体デフォルトとして登録する
+INSERT INTO delay_policies (yellow_days, no_due_date_status) VALUES (3, 'YELLOW');
+
+-- 遅延ステータスはバッチがポリシーに従って計算するため、固定値で上書きするトリガーを削除する
+DROP TRIGGER IF EXISTS calculate_issue_delay_status ON issues;
+DROP FUNCTION IF EXISTS calculate_delay_status();
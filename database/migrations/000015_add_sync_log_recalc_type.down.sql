@@ -0,0 +1,4 @@
+DELETE FROM sync_logs WHERE sync_type = 'RECALC';
+ALTER TABLE sync_logs DROP CONSTRAINT sync_logs_sync_type_check;
+ALTER TABLE sync_logs ADD CONSTRAINT sync_logs_sync_type_check
+  CHECK (sync_type IN ('FULL', 'DELTA'));
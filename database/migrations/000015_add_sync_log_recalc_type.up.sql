@@ -0,0 +1,4 @@
+-- Jira にアクセスせず遅延ステータスを再計算する RECALC を追加
+ALTER TABLE sync_logs DROP CONSTRAINT sync_logs_sync_type_check;
+ALTER TABLE sync_logs ADD CONSTRAINT sync_logs_sync_type_check
+  CHECK (sync_type IN ('FULL', 'DELTA', 'RECALC'));
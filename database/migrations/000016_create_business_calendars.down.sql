@@ -0,0 +1,3 @@
+ALTER TABLE organizations DROP COLUMN IF EXISTS business_calendar_id;
+DROP TABLE IF EXISTS calendar_holidays;
+DROP TABLE IF EXISTS business_calendars;
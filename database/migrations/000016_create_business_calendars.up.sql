@@ -0,0 +1,37 @@
+-- 営業日カレンダー（土日と祝日テーブルの日付を休業日とする）
+CREATE TABLE business_calendars (
+    id          BIGSERIAL    PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    -- 組織にカレンダーが設定されていないプロジェクトで使うカレンダー
+    is_default  BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- デフォルトのカレンダーは 1 件のみ
+CREATE UNIQUE INDEX idx_business_calendars_default
+    ON business_calendars(is_default) WHERE is_default;
+
+CREATE TRIGGER update_business_calendars_updated_at
+    BEFORE UPDATE ON business_calendars
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE calendar_holidays (
+    id           BIGSERIAL    PRIMARY KEY,
+    calendar_id  BIGINT       NOT NULL REFERENCES business_calendars(id) ON DELETE CASCADE,
+    holiday_date DATE         NOT NULL,
+    name         VARCHAR(100) NOT NULL DEFAULT '',
+    created_at   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (calendar_id, holiday_date)
+);
+
+-- 組織ごとのカレンダー（NULL は上位の組織、さらに無ければデフォルトのカレンダーを使う）
+ALTER TABLE organizations
+    ADD COLUMN business_calendar_id BIGINT REFERENCES business_calendars(id) ON DELETE SET NULL;
+
+COMMENT ON TABLE  business_calendars              IS '営業日カレンダー';
+COMMENT ON COLUMN business_calendars.is_default   IS '組織にカレンダーが設定されていない場合に使うカレンダー';
+COMMENT ON TABLE  calendar_holidays               IS '営業日カレンダーの休日';
+COMMENT ON COLUMN organizations.business_calendar_id IS '遅延判定の営業日計算に使うカレンダー（NULL は上位の組織を継承）';
@@ -0,0 +1,4 @@
+-- 組織ごとのタイムゾーン（NULL は上位の組織、さらに無ければ REPORT_TIMEZONE を使う）
+ALTER TABLE organizations ADD COLUMN timezone VARCHAR(64);
+
+COMMENT ON COLUMN organizations.timezone IS '期日判定で「今日」を決めるタイムゾーン（IANA 名、NULL は上位の組織を継承）';
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS jira_field_mappings;
+DROP INDEX IF EXISTS idx_issues_components;
+DROP INDEX IF EXISTS idx_issues_labels;
+DROP INDEX IF EXISTS idx_issues_sprint_name;
+DROP INDEX IF EXISTS idx_issues_epic_key;
+ALTER TABLE issues DROP COLUMN IF EXISTS components;
+ALTER TABLE issues DROP COLUMN IF EXISTS labels;
+ALTER TABLE issues DROP COLUMN IF EXISTS sprint_name;
+ALTER TABLE issues DROP COLUMN IF EXISTS epic_key;
+ALTER TABLE issues DROP COLUMN IF EXISTS story_points;
+ALTER TABLE issues DROP COLUMN IF EXISTS start_date;
@@ -0,0 +1,43 @@
+-- Jira のカスタムフィールド等から取り込むチケットのカラム
+ALTER TABLE issues ADD COLUMN start_date   DATE;
+ALTER TABLE issues ADD COLUMN story_points NUMERIC(10, 2);
+ALTER TABLE issues ADD COLUMN epic_key     VARCHAR(50);
+ALTER TABLE issues ADD COLUMN sprint_name  VARCHAR(255);
+ALTER TABLE issues ADD COLUMN labels       TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE issues ADD COLUMN components   TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN issues.start_date   IS '開始日';
+COMMENT ON COLUMN issues.story_points IS 'ストーリーポイント';
+COMMENT ON COLUMN issues.epic_key     IS '所属するエピックのチケットキー';
+COMMENT ON COLUMN issues.sprint_name  IS 'スプリント名（アクティブなスプリント、無ければ最後のスプリント）';
+COMMENT ON COLUMN issues.labels       IS 'ラベル';
+COMMENT ON COLUMN issues.components   IS 'コンポーネント';
+
+CREATE INDEX idx_issues_epic_key    ON issues(epic_key) WHERE epic_key IS NOT NULL;
+CREATE INDEX idx_issues_sprint_name ON issues(sprint_name) WHERE sprint_name IS NOT NULL;
+CREATE INDEX idx_issues_labels      ON issues USING GIN (labels);
+CREATE INDEX idx_issues_components  ON issues USING GIN (components);
+
+-- Jira フィールド → チケットのカラムの対応（管理画面で設定）
+CREATE TABLE jira_field_mappings (
+    id            BIGSERIAL    PRIMARY KEY,
+    column_name   VARCHAR(50)  NOT NULL UNIQUE
+        CHECK (column_name IN ('start_date', 'story_points', 'epic_key', 'sprint_name', 'labels', 'components')),
+    jira_field_id VARCHAR(255) NOT NULL CHECK (jira_field_id != ''),
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_jira_field_mappings_updated_at
+    BEFORE UPDATE ON jira_field_mappings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  jira_field_mappings               IS 'Jira フィールドとチケットのカラムの対応';
+COMMENT ON COLUMN jira_field_mappings.column_name   IS '値を格納する issues のカラム';
+COMMENT ON COLUMN jira_field_mappings.jira_field_id IS 'Jira のフィールド ID（例: customfield_10015, labels）';
+
+-- ラベルとコンポーネントは Jira の標準フィールドのため初期設定しておく
+INSERT INTO jira_field_mappings (column_name, jira_field_id) VALUES
+    ('labels', 'labels'),
+    ('components', 'components');
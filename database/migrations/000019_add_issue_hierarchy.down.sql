@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS issue_links;
+
+DROP INDEX IF EXISTS idx_issues_parent_jira_issue_id;
+ALTER TABLE issues DROP COLUMN IF EXISTS parent_issue_key;
+ALTER TABLE issues DROP COLUMN IF EXISTS parent_jira_issue_id;
@@ -0,0 +1,32 @@
+-- 親チケット（ストーリーのエピック、サブタスクの親チケット）
+-- 親が別ページ・別プロジェクトで後から取り込まれることがあるため、Jira の ID で保持する
+ALTER TABLE issues ADD COLUMN parent_jira_issue_id VARCHAR(100);
+ALTER TABLE issues ADD COLUMN parent_issue_key     VARCHAR(100);
+
+COMMENT ON COLUMN issues.parent_jira_issue_id IS '親チケットの Jira ID（最上位のチケットは NULL）';
+COMMENT ON COLUMN issues.parent_issue_key     IS '親チケットのチケットキー';
+
+CREATE INDEX idx_issues_parent_jira_issue_id ON issues(parent_jira_issue_id) WHERE parent_jira_issue_id IS NOT NULL;
+
+-- チケット間のリンク（Jira の issuelinks から取り込む）
+CREATE TABLE issue_links (
+    id                   BIGSERIAL    PRIMARY KEY,
+    issue_id             BIGINT       NOT NULL REFERENCES issues(id) ON DELETE CASCADE,
+    jira_link_id         VARCHAR(100) NOT NULL,
+    link_type            VARCHAR(255) NOT NULL,
+    direction            VARCHAR(10)  NOT NULL CHECK (direction IN ('inward', 'outward')),
+    description          VARCHAR(255),
+    linked_jira_issue_id VARCHAR(100) NOT NULL,
+    linked_issue_key     VARCHAR(100) NOT NULL,
+    created_at           TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (issue_id, jira_link_id)
+);
+
+CREATE INDEX idx_issue_links_linked_jira_issue_id ON issue_links(linked_jira_issue_id);
+
+COMMENT ON TABLE  issue_links                      IS 'チケット間のリンク';
+COMMENT ON COLUMN issue_links.jira_link_id         IS 'Jira のリンク ID';
+COMMENT ON COLUMN issue_links.link_type            IS 'リンクの種類（例: Blocks）';
+COMMENT ON COLUMN issue_links.direction            IS 'outward: このチケットから相手へ（blocks）、inward: 相手からこのチケットへ（is blocked by）';
+COMMENT ON COLUMN issue_links.description          IS 'このチケットから見たリンクの説明（例: is blocked by）';
+COMMENT ON COLUMN issue_links.linked_jira_issue_id IS 'リンク先チケットの Jira ID';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_issues_fix_version_ids;
+ALTER TABLE issues DROP COLUMN IF EXISTS fix_version_ids;
+DROP TABLE IF EXISTS project_versions;
@@ -0,0 +1,34 @@
+-- プロジェクトのバージョン（リリース）
+CREATE TABLE project_versions (
+    id              BIGSERIAL    PRIMARY KEY,
+    jira_version_id VARCHAR(100) NOT NULL UNIQUE,
+    project_id      BIGINT       NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name            VARCHAR(255) NOT NULL,
+    description     TEXT,
+    start_date      DATE,
+    release_date    DATE,
+    released        BOOLEAN      NOT NULL DEFAULT FALSE,
+    archived        BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_project_versions_project_id ON project_versions(project_id);
+
+CREATE TRIGGER update_project_versions_updated_at
+    BEFORE UPDATE ON project_versions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  project_versions                 IS 'プロジェクトのバージョン（Jira の fixVersion）';
+COMMENT ON COLUMN project_versions.jira_version_id IS 'Jira のバージョン ID';
+COMMENT ON COLUMN project_versions.release_date    IS 'リリース予定日';
+COMMENT ON COLUMN project_versions.released        IS 'リリース済みか';
+COMMENT ON COLUMN project_versions.archived        IS 'アーカイブ済みか';
+
+-- チケットの修正バージョン（fixVersions）。バージョンが後から取り込まれることがあるため Jira の ID で保持する
+ALTER TABLE issues ADD COLUMN fix_version_ids TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN issues.fix_version_ids IS '修正バージョンの Jira ID';
+
+CREATE INDEX idx_issues_fix_version_ids ON issues USING GIN (fix_version_ids);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sprint_issues;
+DROP TABLE IF EXISTS sprints;
+DROP TABLE IF EXISTS jira_boards;
@@ -0,0 +1,65 @@
+-- Jira Software のボード
+CREATE TABLE jira_boards (
+    id            BIGSERIAL    PRIMARY KEY,
+    jira_board_id BIGINT       NOT NULL UNIQUE,
+    project_id    BIGINT       NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name          VARCHAR(255) NOT NULL,
+    board_type    VARCHAR(20)  NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jira_boards_project_id ON jira_boards(project_id);
+
+CREATE TRIGGER update_jira_boards_updated_at
+    BEFORE UPDATE ON jira_boards
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  jira_boards               IS 'Jira Software のボード';
+COMMENT ON COLUMN jira_boards.jira_board_id IS 'Jira のボード ID';
+COMMENT ON COLUMN jira_boards.board_type    IS 'ボードの種類（scrum / kanban / simple）';
+
+-- ボードのスプリント
+CREATE TABLE sprints (
+    id                  BIGSERIAL    PRIMARY KEY,
+    jira_sprint_id      BIGINT       NOT NULL UNIQUE,
+    board_id            BIGINT       NOT NULL REFERENCES jira_boards(id) ON DELETE CASCADE,
+    name                VARCHAR(255) NOT NULL,
+    state               VARCHAR(20)  NOT NULL CHECK (state IN ('future', 'active', 'closed')),
+    goal                TEXT,
+    start_date          TIMESTAMP,
+    end_date            TIMESTAMP,
+    complete_date       TIMESTAMP,
+    issues_synced_state VARCHAR(20),
+    created_at          TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sprints_board_id_state ON sprints(board_id, state);
+
+CREATE TRIGGER update_sprints_updated_at
+    BEFORE UPDATE ON sprints
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  sprints                     IS 'ボードのスプリント';
+COMMENT ON COLUMN sprints.jira_sprint_id      IS 'Jira のスプリント ID';
+COMMENT ON COLUMN sprints.state               IS 'スプリントの状態（future / active / closed）';
+COMMENT ON COLUMN sprints.start_date          IS 'スプリントの開始日時（UTC）';
+COMMENT ON COLUMN sprints.end_date            IS 'スプリントの終了予定日時（UTC）';
+COMMENT ON COLUMN sprints.complete_date       IS 'スプリントを完了した日時（UTC）';
+COMMENT ON COLUMN sprints.issues_synced_state IS 'チケットを最後に取り込んだときのスプリントの状態（closed なら再取得しない）';
+
+-- スプリントに含まれるチケット。チケットが後から取り込まれることがあるため Jira の ID で保持する
+CREATE TABLE sprint_issues (
+    sprint_id     BIGINT       NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    jira_issue_id VARCHAR(100) NOT NULL,
+    first_seen_at TIMESTAMP    NOT NULL DEFAULT (CURRENT_TIMESTAMP AT TIME ZONE 'UTC'),
+    PRIMARY KEY (sprint_id, jira_issue_id)
+);
+
+CREATE INDEX idx_sprint_issues_jira_issue_id ON sprint_issues(jira_issue_id);
+
+COMMENT ON TABLE  sprint_issues               IS 'スプリントに含まれるチケット';
+COMMENT ON COLUMN sprint_issues.first_seen_at IS 'チケットがスプリントに含まれていることを最初に取り込んだ日時（UTC）。スプリント開始後に追加されたチケットの判定に使う';
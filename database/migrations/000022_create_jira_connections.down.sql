@@ -0,0 +1,59 @@
+-- 単一サイトの制約に戻すため、最初の接続以外のデータは削除する
+DELETE FROM projects WHERE connection_id <> (SELECT MIN(id) FROM jira_connections);
+DELETE FROM jira_field_mappings WHERE connection_id <> (SELECT MIN(id) FROM jira_connections);
+
+DROP INDEX IF EXISTS idx_jira_field_mappings_connection_column;
+ALTER TABLE jira_field_mappings DROP COLUMN IF EXISTS connection_id;
+ALTER TABLE jira_field_mappings ADD CONSTRAINT jira_field_mappings_column_name_key UNIQUE (column_name);
+
+DROP INDEX IF EXISTS idx_sync_logs_connection_id;
+ALTER TABLE sync_logs DROP COLUMN IF EXISTS connection_id;
+
+DROP INDEX IF EXISTS idx_sprints_connection_jira_sprint_id;
+ALTER TABLE sprints DROP COLUMN IF EXISTS connection_id;
+ALTER TABLE sprints ADD CONSTRAINT sprints_jira_sprint_id_key UNIQUE (jira_sprint_id);
+
+DROP INDEX IF EXISTS idx_jira_boards_connection_jira_board_id;
+ALTER TABLE jira_boards DROP COLUMN IF EXISTS connection_id;
+ALTER TABLE jira_boards ADD CONSTRAINT jira_boards_jira_board_id_key UNIQUE (jira_board_id);
+
+DROP INDEX IF EXISTS idx_project_versions_connection_jira_version_id;
+ALTER TABLE project_versions DROP COLUMN IF EXISTS connection_id;
+ALTER TABLE project_versions ADD CONSTRAINT project_versions_jira_version_id_key UNIQUE (jira_version_id);
+
+DROP INDEX IF EXISTS idx_issues_connection_jira_issue_id;
+ALTER TABLE issues DROP COLUMN IF EXISTS connection_id;
+ALTER TABLE issues ADD CONSTRAINT issues_jira_issue_id_key UNIQUE (jira_issue_id);
+CREATE UNIQUE INDEX idx_issues_jira_issue_id ON issues(jira_issue_id);
+
+DROP INDEX IF EXISTS idx_projects_connection_jira_project_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS connection_id;
+ALTER TABLE projects ADD CONSTRAINT projects_jira_project_id_key UNIQUE (jira_project_id);
+CREATE UNIQUE INDEX idx_projects_jira_project_id ON projects(jira_project_id);
+
+CREATE TABLE jira_settings (
+    id          BIGSERIAL PRIMARY KEY,
+    jira_url    VARCHAR(500)  NOT NULL,
+    email       VARCHAR(255)  NOT NULL,
+    api_token   VARCHAR(500)  NOT NULL,
+    created_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_jira_settings_updated_at
+    BEFORE UPDATE ON jira_settings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  jira_settings             IS 'Jira API接続設定';
+COMMENT ON COLUMN jira_settings.api_token   IS 'Jira APIトークン（平文）';
+
+INSERT INTO jira_settings (jira_url, email, api_token, created_at, updated_at)
+SELECT base_url, email, api_token, created_at, updated_at
+FROM jira_connections
+WHERE base_url <> ''
+ORDER BY id
+LIMIT 1;
+
+DROP TRIGGER IF EXISTS update_jira_connections_updated_at ON jira_connections;
+DROP TABLE IF EXISTS jira_connections;
@@ -0,0 +1,87 @@
+-- Jira サイトへの接続。複数の Atlassian Cloud サイトを同期するため、単一行の jira_settings を置き換える
+CREATE TABLE jira_connections (
+    id          BIGSERIAL     PRIMARY KEY,
+    name        VARCHAR(100)  NOT NULL UNIQUE,
+    base_url    VARCHAR(500)  NOT NULL,
+    email       VARCHAR(255)  NOT NULL,
+    api_token   VARCHAR(500)  NOT NULL,
+    enabled     BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_jira_connections_updated_at
+    BEFORE UPDATE ON jira_connections
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  jira_connections           IS 'Jira サイトへの接続設定';
+COMMENT ON COLUMN jira_connections.name      IS '接続名（画面・ログでの識別用）';
+COMMENT ON COLUMN jira_connections.base_url  IS 'Jira サイトのベース URL（例: https://your-org.atlassian.net）';
+COMMENT ON COLUMN jira_connections.api_token IS 'Jira APIトークン（平文）';
+COMMENT ON COLUMN jira_connections.enabled   IS 'バッチ同期・Webhook の対象とするか';
+
+-- 既存の設定を default 接続として引き継ぐ。未設定の場合も既存データの接続先として作成する
+-- （バッチは環境変数 JIRA_* が設定されていれば default 接続をその認証情報で同期する）
+INSERT INTO jira_connections (name, base_url, email, api_token)
+SELECT 'default', COALESCE(s.jira_url, ''), COALESCE(s.email, ''), COALESCE(s.api_token, '')
+FROM (SELECT 1) AS one
+LEFT JOIN (SELECT * FROM jira_settings ORDER BY id LIMIT 1) s ON TRUE;
+
+DROP TRIGGER IF EXISTS update_jira_settings_updated_at ON jira_settings;
+DROP TABLE jira_settings;
+
+-- プロジェクト・チケットは (接続, Jira の ID) で一意にする
+ALTER TABLE projects ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE RESTRICT;
+UPDATE projects SET connection_id = (SELECT id FROM jira_connections WHERE name = 'default');
+ALTER TABLE projects ALTER COLUMN connection_id SET NOT NULL;
+ALTER TABLE projects DROP CONSTRAINT projects_jira_project_id_key;
+DROP INDEX idx_projects_jira_project_id;
+CREATE UNIQUE INDEX idx_projects_connection_jira_project_id ON projects(connection_id, jira_project_id);
+
+COMMENT ON COLUMN projects.connection_id IS '取り込み元の Jira 接続ID';
+
+ALTER TABLE issues ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE RESTRICT;
+UPDATE issues i SET connection_id = p.connection_id FROM projects p WHERE p.id = i.project_id;
+ALTER TABLE issues ALTER COLUMN connection_id SET NOT NULL;
+ALTER TABLE issues DROP CONSTRAINT issues_jira_issue_id_key;
+DROP INDEX idx_issues_jira_issue_id;
+CREATE UNIQUE INDEX idx_issues_connection_jira_issue_id ON issues(connection_id, jira_issue_id);
+
+COMMENT ON COLUMN issues.connection_id IS '取り込み元の Jira 接続ID（プロジェクトと同じ）';
+
+-- バージョン・ボード・スプリントの Jira の ID もサイトごとの採番のため、接続ごとに一意にする
+ALTER TABLE project_versions ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE RESTRICT;
+UPDATE project_versions v SET connection_id = p.connection_id FROM projects p WHERE p.id = v.project_id;
+ALTER TABLE project_versions ALTER COLUMN connection_id SET NOT NULL;
+ALTER TABLE project_versions DROP CONSTRAINT project_versions_jira_version_id_key;
+CREATE UNIQUE INDEX idx_project_versions_connection_jira_version_id ON project_versions(connection_id, jira_version_id);
+
+ALTER TABLE jira_boards ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE RESTRICT;
+UPDATE jira_boards b SET connection_id = p.connection_id FROM projects p WHERE p.id = b.project_id;
+ALTER TABLE jira_boards ALTER COLUMN connection_id SET NOT NULL;
+ALTER TABLE jira_boards DROP CONSTRAINT jira_boards_jira_board_id_key;
+CREATE UNIQUE INDEX idx_jira_boards_connection_jira_board_id ON jira_boards(connection_id, jira_board_id);
+
+ALTER TABLE sprints ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE RESTRICT;
+UPDATE sprints s SET connection_id = b.connection_id FROM jira_boards b WHERE b.id = s.board_id;
+ALTER TABLE sprints ALTER COLUMN connection_id SET NOT NULL;
+ALTER TABLE sprints DROP CONSTRAINT sprints_jira_sprint_id_key;
+CREATE UNIQUE INDEX idx_sprints_connection_jira_sprint_id ON sprints(connection_id, jira_sprint_id);
+
+-- 同期ログは接続ごとに記録する（差分同期の起点・中断した同期の再開を接続ごとに判定する）
+ALTER TABLE sync_logs ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE SET NULL;
+UPDATE sync_logs SET connection_id = (SELECT id FROM jira_connections WHERE name = 'default');
+
+CREATE INDEX idx_sync_logs_connection_id ON sync_logs(connection_id, executed_at DESC);
+
+COMMENT ON COLUMN sync_logs.connection_id IS '同期した Jira 接続ID';
+
+-- カスタムフィールドの ID（customfield_XXXXX）はサイトごとの採番のため、フィールドの対応は接続ごとに設定する
+ALTER TABLE jira_field_mappings ADD COLUMN connection_id BIGINT REFERENCES jira_connections(id) ON DELETE CASCADE;
+UPDATE jira_field_mappings SET connection_id = (SELECT id FROM jira_connections WHERE name = 'default');
+ALTER TABLE jira_field_mappings ALTER COLUMN connection_id SET NOT NULL;
+ALTER TABLE jira_field_mappings DROP CONSTRAINT jira_field_mappings_column_name_key;
+CREATE UNIQUE INDEX idx_jira_field_mappings_connection_column ON jira_field_mappings(connection_id, column_name);
+
+COMMENT ON COLUMN jira_field_mappings.connection_id IS '対応を適用する Jira 接続ID';
@@ -0,0 +1,5 @@
+-- 暗号化済みのトークンは VARCHAR(500) に収まらない場合があるため、ロールバック前に
+-- 鍵を無効にして平文に戻しておくこと（docs/secrets-management.md を参照）
+ALTER TABLE jira_connections ALTER COLUMN api_token TYPE VARCHAR(500);
+
+COMMENT ON COLUMN jira_connections.api_token IS 'Jira APIトークン（平文）';
@@ -0,0 +1,5 @@
+-- API トークンをアプリケーションで暗号化して保存する（pkg/secrets.TokenCipher）
+-- 暗号化した値は平文より長いため TEXT に拡張する。既存の平文のトークンは reencrypt-tokens コマンドで暗号化する
+ALTER TABLE jira_connections ALTER COLUMN api_token TYPE TEXT;
+
+COMMENT ON COLUMN jira_connections.api_token IS 'Jira APIトークン（enc:v1: で始まる値は暗号化済み、それ以外は暗号化前の平文）';
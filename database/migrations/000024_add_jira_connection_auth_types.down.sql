@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS jira_oauth_states;
+DROP TABLE IF EXISTS jira_oauth_tokens;
+
+-- basic 以外の接続は認証情報が無くなるため無効にする
+UPDATE jira_connections SET enabled = FALSE WHERE auth_type <> 'basic';
+
+ALTER TABLE jira_connections
+    DROP COLUMN IF EXISTS cloud_id,
+    DROP COLUMN IF EXISTS auth_type;
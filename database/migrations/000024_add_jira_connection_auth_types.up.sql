@@ -0,0 +1,44 @@
+-- Jira 接続の認証方式を選べるようにする
+--   basic: メールアドレス + API トークン（Jira Cloud、従来の方式）
+--   pat:   パーソナルアクセストークン（Jira Data Center。api_token に保存）
+--   oauth: OAuth 2.0 (3LO)。トークンは jira_oauth_tokens に保存する
+ALTER TABLE jira_connections
+    ADD COLUMN auth_type VARCHAR(20)  NOT NULL DEFAULT 'basic'
+        CONSTRAINT jira_connections_auth_type_check CHECK (auth_type IN ('basic', 'pat', 'oauth')),
+    ADD COLUMN cloud_id  VARCHAR(100);
+
+COMMENT ON COLUMN jira_connections.auth_type IS '認証方式（basic / pat / oauth）';
+COMMENT ON COLUMN jira_connections.cloud_id  IS 'OAuth で認可された Atlassian サイトの ID（同意フロー完了時に設定）';
+
+-- OAuth 2.0 のアクセストークンとリフレッシュトークン（接続ごとに 1 行）
+-- リフレッシュトークンは更新のたびにローテーションされるため、更新は行ロックを取って直列化する
+CREATE TABLE jira_oauth_tokens (
+    connection_id  BIGINT       PRIMARY KEY REFERENCES jira_connections(id) ON DELETE CASCADE,
+    access_token   TEXT         NOT NULL,
+    refresh_token  TEXT         NOT NULL,
+    expires_at     TIMESTAMPTZ  NOT NULL,
+    scope          TEXT         NOT NULL DEFAULT '',
+    created_at     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_jira_oauth_tokens_updated_at
+    BEFORE UPDATE ON jira_oauth_tokens
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE  jira_oauth_tokens               IS 'Jira 接続の OAuth 2.0 トークン';
+COMMENT ON COLUMN jira_oauth_tokens.access_token  IS 'アクセストークン（api_token と同じく暗号化して保存）';
+COMMENT ON COLUMN jira_oauth_tokens.refresh_token IS 'リフレッシュトークン（暗号化して保存。更新のたびに置き換わる）';
+COMMENT ON COLUMN jira_oauth_tokens.expires_at    IS 'アクセストークンの有効期限';
+
+-- OAuth 同意フローの state（CSRF 対策）。コールバックで一度だけ消費する
+CREATE TABLE jira_oauth_states (
+    state          VARCHAR(64)  PRIMARY KEY,
+    connection_id  BIGINT       NOT NULL REFERENCES jira_connections(id) ON DELETE CASCADE,
+    created_by     BIGINT       REFERENCES users(id) ON DELETE SET NULL,
+    expires_at     TIMESTAMPTZ  NOT NULL,
+    created_at     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE jira_oauth_states IS 'Jira OAuth 同意フローの state（コールバックで検証して削除）';
@@ -0,0 +1,6 @@
+-- Data Center の接続は v3 API では同期できないため無効にする
+UPDATE jira_connections SET enabled = FALSE WHERE api_flavor = 'datacenter';
+
+ALTER TABLE jira_connections
+    DROP CONSTRAINT IF EXISTS jira_connections_oauth_cloud_check,
+    DROP COLUMN IF EXISTS api_flavor;
@@ -0,0 +1,15 @@
+-- Jira 接続ごとに REST API の種類を選べるようにする
+--   cloud:      Jira Cloud（REST API v3）
+--   datacenter: Jira Data Center / Server（REST API v2）
+ALTER TABLE jira_connections
+    ADD COLUMN api_flavor VARCHAR(20) NOT NULL DEFAULT 'cloud'
+        CONSTRAINT jira_connections_api_flavor_check CHECK (api_flavor IN ('cloud', 'datacenter'));
+
+-- 個人用アクセストークンは Data Center 向けの認証方式のため、既存の PAT 接続は datacenter にする
+UPDATE jira_connections SET api_flavor = 'datacenter' WHERE auth_type = 'pat';
+
+-- OAuth 2.0 (3LO) は Jira Cloud でのみ使える
+ALTER TABLE jira_connections
+    ADD CONSTRAINT jira_connections_oauth_cloud_check CHECK (auth_type <> 'oauth' OR api_flavor = 'cloud');
+
+COMMENT ON COLUMN jira_connections.api_flavor IS 'REST API の種類（cloud: Jira Cloud v3 / datacenter: Jira Data Center v2）';
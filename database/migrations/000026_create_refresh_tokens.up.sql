@@ -0,0 +1,19 @@
+-- ログインセッションのリフレッシュトークン
+-- トークン本体は保存せず SHA-256 ハッシュのみを保存する。更新のたびに新しいトークンへ置き換え（ローテーション）、
+-- 置き換え済みのトークンが再使用された場合は漏えいとみなしてそのユーザーの全トークンを失効させる
+CREATE TABLE refresh_tokens (
+    id           BIGSERIAL    PRIMARY KEY,
+    user_id      BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash   CHAR(64)     NOT NULL UNIQUE,
+    expires_at   TIMESTAMPTZ  NOT NULL,
+    revoked_at   TIMESTAMPTZ,
+    replaced_by  BIGINT       REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+
+COMMENT ON TABLE  refresh_tokens             IS 'ログインセッションのリフレッシュトークン';
+COMMENT ON COLUMN refresh_tokens.token_hash  IS 'リフレッシュトークンの SHA-256（16 進）';
+COMMENT ON COLUMN refresh_tokens.revoked_at  IS '失効日時（ローテーション・ログアウト・ユーザー無効化）。NULL は有効';
+COMMENT ON COLUMN refresh_tokens.replaced_by IS 'ローテーションで発行した後継トークン';
@@ -0,0 +1 @@
+ALTER TABLE jira_connections DROP COLUMN IF EXISTS webhook_secret;
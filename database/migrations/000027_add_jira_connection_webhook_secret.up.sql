@@ -0,0 +1,7 @@
+-- Jira 接続ごとの Webhook 署名シークレット
+-- 接続ごとに分けることで、あるサイトのシークレットで他の接続のイベントを送れないようにする。
+-- api_token と同じく TOKEN_ENCRYPTION_KEYS で暗号化して保存する（空文字は未設定）
+ALTER TABLE jira_connections
+    ADD COLUMN webhook_secret TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN jira_connections.webhook_secret IS 'Jira Webhook の署名検証に使う共有シークレット（暗号化して保存。空文字は未設定）';
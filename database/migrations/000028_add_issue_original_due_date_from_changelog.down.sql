@@ -0,0 +1 @@
+ALTER TABLE issues DROP COLUMN IF EXISTS original_due_date_from_changelog;
@@ -0,0 +1,6 @@
+-- original_due_date を完全な変更履歴から求めたかどうか
+-- 000012 で既存チケットの original_due_date を現在の期日で初期化したため、
+-- 変更履歴から求めた値は保存済みの値より優先して上書きする（履歴が無い場合のみ保存済みの値を保持する）
+ALTER TABLE issues ADD COLUMN original_due_date_from_changelog BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN issues.original_due_date_from_changelog IS 'original_due_date・postpone_count を完全な変更履歴から求めたか';
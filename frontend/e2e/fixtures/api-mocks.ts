@@ -181,6 +181,7 @@ export async function setupAuth(page: Page): Promise<void> {
     const authState = {
       state: {
         token: 'mock-token-for-e2e',
+        refreshToken: 'mock-refresh-token-for-e2e',
         user: { id: 1, email: 'admin@example.com', role: 'admin' },
       },
       version: 0,
@@ -1,4 +1,5 @@
-import axios from 'axios'
+import axios, { type AxiosError, type InternalAxiosRequestConfig } from 'axios'
+import { useAuthStore } from '../stores/authStore'
 
 const apiClient = axios.create({
   baseURL: '/api/v1',
@@ -24,15 +25,69 @@ apiClient.interceptors.request.use((config) => {
   return config
 })
 
-// Redirect to login on 401 (except when already on the login page)
+interface RefreshResponse {
+  access_token: string
+  refresh_token: string
+}
+
+// 同時に 401 になったリクエストでリフレッシュを共有する
+// （リフレッシュトークンは一度しか使えず、再使用するとすべてのセッションが失効するため）
+let refreshing: Promise<string> | null = null
+
+const refreshAccessToken = (): Promise<string> => {
+  if (!refreshing) {
+    const { refreshToken } = useAuthStore.getState()
+    refreshing = (async () => {
+      if (!refreshToken) {
+        throw new Error('no refresh token')
+      }
+      // apiClient を使うとこのインターセプターを再帰的に通るため、素の axios で呼ぶ
+      const res = await axios.post<RefreshResponse>('/api/v1/auth/refresh', { refresh_token: refreshToken })
+      useAuthStore.getState().setTokens(res.data.access_token, res.data.refresh_token)
+      return res.data.access_token
+    })().finally(() => {
+      refreshing = null
+    })
+  }
+  return refreshing
+}
+
+const sessionEndpoints = ['/auth/login', '/auth/refresh', '/auth/logout']
+
+const redirectToLogin = () => {
+  if (!window.location.pathname.startsWith('/login')) {
+    localStorage.removeItem('auth-storage')
+    window.location.href = '/login'
+  }
+}
+
+// On 401, renew the access token with the refresh token once and retry;
+// redirect to login when that fails (except when already on the login page)
 apiClient.interceptors.response.use(
   (res) => res,
-  (error) => {
-    if (error.response?.status === 401 && !window.location.pathname.startsWith('/login')) {
-      localStorage.removeItem('auth-storage')
-      window.location.href = '/login'
+  async (error: AxiosError) => {
+    const original = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined
+    if (error.response?.status !== 401 || !original) {
+      return Promise.reject(error)
+    }
+    // ログイン・リフレッシュ・ログアウト自体の 401 はそのまま返す
+    if (sessionEndpoints.some((path) => original.url?.startsWith(path))) {
+      return Promise.reject(error)
+    }
+    if (original._retried) {
+      redirectToLogin()
+      return Promise.reject(error)
+    }
+
+    original._retried = true
+    try {
+      const token = await refreshAccessToken()
+      original.headers['Authorization'] = `Bearer ${token}`
+      return apiClient(original)
+    } catch {
+      redirectToLogin()
+      return Promise.reject(error)
     }
-    return Promise.reject(error)
   }
 )
 
@@ -8,7 +8,7 @@ vi.mock('./apiClient', () => ({
 }))
 
 import apiClient from './apiClient'
-import { login } from './auth'
+import { login, logout, refresh } from './auth'
 
 const mockPost = vi.mocked(apiClient.post)
 
@@ -23,6 +23,7 @@ describe('login', () => {
         access_token: 'jwt-token-abc',
         token_type: 'Bearer',
         expires_in: 3600,
+        refresh_token: 'refresh-abc',
         user: { id: 1, email: 'admin@example.com', role: 'admin' },
       },
     })
@@ -34,6 +35,7 @@ describe('login', () => {
       password: 'secret',
     })
     expect(result.token).toBe('jwt-token-abc')
+    expect(result.refreshToken).toBe('refresh-abc')
     expect(result.user).toEqual({ id: 1, email: 'admin@example.com', role: 'admin' })
   })
 
@@ -46,3 +48,33 @@ describe('login', () => {
     )
   })
 })
+
+describe('refresh', () => {
+  it('exchanges the refresh token for new tokens', async () => {
+    mockPost.mockResolvedValueOnce({
+      data: {
+        access_token: 'jwt-token-new',
+        token_type: 'Bearer',
+        expires_in: 900,
+        refresh_token: 'refresh-new',
+        user: { id: 1, email: 'admin@example.com', role: 'admin' },
+      },
+    })
+
+    const result = await refresh('refresh-old')
+
+    expect(mockPost).toHaveBeenCalledWith('/auth/refresh', { refresh_token: 'refresh-old' })
+    expect(result.token).toBe('jwt-token-new')
+    expect(result.refreshToken).toBe('refresh-new')
+  })
+})
+
+describe('logout', () => {
+  it('revokes the refresh token', async () => {
+    mockPost.mockResolvedValueOnce({ data: { message: 'logged out' } })
+
+    await logout('refresh-abc')
+
+    expect(mockPost).toHaveBeenCalledWith('/auth/logout', { refresh_token: 'refresh-abc' })
+  })
+})
@@ -10,6 +10,7 @@ interface LoginResponse {
   access_token: string
   token_type: string
   expires_in: number
+  refresh_token: string
   user: {
     id: number
     email: string
@@ -17,14 +18,34 @@ interface LoginResponse {
   }
 }
 
-export async function login(data: LoginRequest): Promise<{ token: string; user: AuthUser }> {
+export interface AuthTokens {
+  token: string
+  refreshToken: string
+  user: AuthUser
+}
+
+const toAuthTokens = (data: LoginResponse): AuthTokens => ({
+  token: data.access_token,
+  refreshToken: data.refresh_token,
+  user: {
+    id: data.user.id,
+    email: data.user.email,
+    role: data.user.role as AuthUser['role'],
+  },
+})
+
+export async function login(data: LoginRequest): Promise<AuthTokens> {
   const res = await apiClient.post<LoginResponse>('/auth/login', data)
-  return {
-    token: res.data.access_token,
-    user: {
-      id: res.data.user.id,
-      email: res.data.user.email,
-      role: res.data.user.role as AuthUser['role'],
-    },
-  }
+  return toAuthTokens(res.data)
+}
+
+// リフレッシュトークンを新しいアクセストークンと交換する（使ったリフレッシュトークンは無効になる）
+export async function refresh(refreshToken: string): Promise<AuthTokens> {
+  const res = await apiClient.post<LoginResponse>('/auth/refresh', { refresh_token: refreshToken })
+  return toAuthTokens(res.data)
+}
+
+// リフレッシュトークンを失効させる
+export async function logout(refreshToken: string): Promise<void> {
+  await apiClient.post('/auth/logout', { refresh_token: refreshToken })
 }
@@ -1,5 +1,11 @@
 import apiClient from './apiClient'
-import type { DashboardSummary, OrgSummaryResponse, ProjectSummaryResponse } from '../types/dashboard'
+import type {
+  DashboardSummary,
+  OrgSummaryResponse,
+  ProjectSummaryResponse,
+  TrendsParams,
+  TrendsResponse,
+} from '../types/dashboard'
 
 export const getDashboardSummary = async (): Promise<DashboardSummary> => {
   const response = await apiClient.get<DashboardSummary>('/dashboard/summary')
@@ -15,3 +21,8 @@ export const getProjectSummary = async (projectId: number): Promise<ProjectSumma
   const response = await apiClient.get<ProjectSummaryResponse>(`/dashboard/projects/${projectId}`)
   return response.data
 }
+
+export const getTrends = async (params?: TrendsParams): Promise<TrendsResponse> => {
+  const response = await apiClient.get<TrendsResponse>('/dashboard/trends', { params })
+  return response.data
+}
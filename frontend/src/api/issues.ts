@@ -1,5 +1,14 @@
 import apiClient from './apiClient'
-import type { IssueListResponse, IssueListParams } from '../types/issue'
+import type {
+  IssueListResponse,
+  IssueListParams,
+  IssueHistoryField,
+  IssueHistoryResponse,
+  IssueChildrenResponse,
+  IssueLinksResponse,
+  EpicListResponse,
+  EpicListParams,
+} from '../types/issue'
 
 export const getIssues = async (params?: IssueListParams): Promise<IssueListResponse> => {
   const query: Record<string, string | number | boolean> = {}
@@ -13,7 +22,42 @@ export const getIssues = async (params?: IssueListParams): Promise<IssueListResp
   if (params?.no_due_date) query.no_due_date = true
   if (params?.status_category) query.status_category = params.status_category
   if (params?.assignee_name) query.assignee_name = params.assignee_name
+  if (params?.slipped !== undefined) query.slipped = params.slipped
+  if (params?.min_slip_days) query.min_slip_days = params.min_slip_days
+  if (params?.epic_key) query.epic_key = params.epic_key
+  if (params?.sprint_name) query.sprint_name = params.sprint_name
+  if (params?.label) query.label = params.label
+  if (params?.component) query.component = params.component
+  if (params?.start_date_from) query.start_date_from = params.start_date_from
+  if (params?.start_date_to) query.start_date_to = params.start_date_to
+  if (params?.min_story_points !== undefined) query.min_story_points = params.min_story_points
+  if (params?.max_story_points !== undefined) query.max_story_points = params.max_story_points
 
   const response = await apiClient.get<IssueListResponse>('/issues', { params: query })
   return response.data
 }
+
+export const getIssueHistory = async (
+  id: number,
+  field?: IssueHistoryField,
+): Promise<IssueHistoryResponse> => {
+  const response = await apiClient.get<IssueHistoryResponse>(`/issues/${id}/history`, {
+    params: field ? { field } : undefined,
+  })
+  return response.data
+}
+
+export const getIssueChildren = async (id: number): Promise<IssueChildrenResponse> => {
+  const response = await apiClient.get<IssueChildrenResponse>(`/issues/${id}/children`)
+  return response.data
+}
+
+export const getIssueLinks = async (id: number): Promise<IssueLinksResponse> => {
+  const response = await apiClient.get<IssueLinksResponse>(`/issues/${id}/links`)
+  return response.data
+}
+
+export const getEpics = async (params?: EpicListParams): Promise<EpicListResponse> => {
+  const response = await apiClient.get<EpicListResponse>('/epics', { params })
+  return response.data
+}
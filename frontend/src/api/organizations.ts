@@ -24,3 +24,15 @@ export const updateOrganization = async (id: number, data: { name: string }): Pr
 export const deleteOrganization = async (id: number): Promise<void> => {
   await apiClient.delete(`/organizations/${id}`)
 }
+
+export const setOrganizationCalendar = async (id: number, businessCalendarId: number | null): Promise<Organization> => {
+  const response = await apiClient.put<Organization>(`/organizations/${id}/calendar`, {
+    business_calendar_id: businessCalendarId,
+  })
+  return response.data
+}
+
+export const setOrganizationTimezone = async (id: number, timezone: string | null): Promise<Organization> => {
+  const response = await apiClient.put<Organization>(`/organizations/${id}/timezone`, { timezone })
+  return response.data
+}
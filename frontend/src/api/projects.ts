@@ -1,5 +1,13 @@
 import apiClient from './apiClient'
-import type { ProjectListResponse, SortOption, DelayFilter } from '../types/project'
+import type {
+  ProjectListResponse,
+  ReleaseListResponse,
+  SprintListResponse,
+  SprintHealthResponse,
+  SprintState,
+  SortOption,
+  DelayFilter,
+} from '../types/project'
 
 export interface ProjectListParams {
   page?: number
@@ -44,3 +52,25 @@ export const getAllProjectsForAdmin = async (): Promise<ProjectListResponse> =>
 export const updateProject = async (projectId: number, data: { is_active: boolean }): Promise<void> => {
   await apiClient.put(`/projects/${projectId}`, data)
 }
+
+export const getProjectReleases = async (
+  projectId: number,
+  includeArchived = false,
+): Promise<ReleaseListResponse> => {
+  const response = await apiClient.get<ReleaseListResponse>(`/projects/${projectId}/releases`, {
+    params: includeArchived ? { include_archived: true } : undefined,
+  })
+  return response.data
+}
+
+export const getProjectSprints = async (projectId: number, state?: SprintState): Promise<SprintListResponse> => {
+  const response = await apiClient.get<SprintListResponse>(`/projects/${projectId}/sprints`, {
+    params: state ? { state } : undefined,
+  })
+  return response.data
+}
+
+export const getCurrentSprints = async (projectId: number): Promise<SprintHealthResponse> => {
+  const response = await apiClient.get<SprintHealthResponse>(`/projects/${projectId}/sprints/current`)
+  return response.data
+}
@@ -1,26 +1,174 @@
 import apiClient from './apiClient'
-import type { JiraSettings } from '../types/settings'
+import type {
+  BusinessCalendar,
+  DelayPolicy,
+  DelayPolicySettings,
+  FieldMapping,
+  FieldMappingColumn,
+  Holiday,
+  JiraAPIFlavor,
+  JiraAuthType,
+  JiraConnection,
+} from '../types/settings'
 
-export interface UpdateJiraSettingsRequest {
-  jira_url: string
-  email: string
-  api_token: string
+export interface JiraConnectionRequest {
+  name: string
+  base_url: string
+  api_flavor: JiraAPIFlavor // oauth は cloud のみ
+  auth_type: JiraAuthType
+  email: string // basic のみ必須
+  api_token?: string // 更新時に省略すると保存済みのトークンを維持する（oauth では使わない）
+  webhook_secret?: string // 更新時に省略すると保存済みのシークレットを維持する
+  enabled?: boolean
 }
 
-export const getJiraSettings = async (): Promise<JiraSettings> => {
-  const res = await apiClient.get<JiraSettings>('/settings/jira')
+export type TestJiraConnectionRequest = Pick<JiraConnectionRequest, 'api_flavor' | 'auth_type' | 'base_url' | 'email' | 'api_token'>
+
+export const getJiraConnections = async (): Promise<JiraConnection[]> => {
+  const res = await apiClient.get<{ data: JiraConnection[] }>('/settings/jira-connections')
+  return res.data.data
+}
+
+export const createJiraConnection = async (data: JiraConnectionRequest): Promise<JiraConnection> => {
+  const res = await apiClient.post<JiraConnection>('/settings/jira-connections', data)
+  return res.data
+}
+
+export const updateJiraConnection = async (id: number, data: JiraConnectionRequest): Promise<JiraConnection> => {
+  const res = await apiClient.put<JiraConnection>(`/settings/jira-connections/${id}`, data)
+  return res.data
+}
+
+export const deleteJiraConnection = async (id: number): Promise<void> => {
+  await apiClient.delete(`/settings/jira-connections/${id}`)
+}
+
+// 保存前の認証情報で接続を確認する
+export const testJiraConnection = async (data: TestJiraConnectionRequest): Promise<void> => {
+  await apiClient.post('/settings/jira-connections/test', data)
+}
+
+// 保存済みの接続を確認する。指定した項目は保存済みの値より優先される
+export const testStoredJiraConnection = async (
+  id: number,
+  data?: Partial<TestJiraConnectionRequest>,
+): Promise<void> => {
+  await apiClient.post(`/settings/jira-connections/${id}/test`, data ?? {})
+}
+
+export const syncJiraConnection = async (id: number): Promise<void> => {
+  await apiClient.post(`/settings/jira-connections/${id}/sync`)
+}
+
+// OAuth の同意画面の URL を取得する。同意後は JIRA_OAUTH_RESULT_URL に戻る
+export const authorizeJiraConnection = async (id: number): Promise<string> => {
+  const res = await apiClient.post<{ authorization_url: string }>(`/settings/jira-connections/${id}/oauth/authorize`)
+  return res.data.authorization_url
+}
+
+export interface CreateDelayPolicyRequest extends Partial<DelayPolicySettings> {
+  organization_id?: number
+  project_id?: number
+}
+
+export const getDelayPolicies = async (): Promise<DelayPolicy[]> => {
+  const res = await apiClient.get<{ data: DelayPolicy[] }>('/settings/delay-policies')
+  return res.data.data
+}
+
+export const createDelayPolicy = async (data: CreateDelayPolicyRequest): Promise<DelayPolicy> => {
+  const res = await apiClient.post<DelayPolicy>('/settings/delay-policies', data)
+  return res.data
+}
+
+export const updateDelayPolicy = async (id: number, data: Partial<DelayPolicySettings>): Promise<DelayPolicy> => {
+  const res = await apiClient.put<DelayPolicy>(`/settings/delay-policies/${id}`, data)
+  return res.data
+}
+
+export const deleteDelayPolicy = async (id: number): Promise<void> => {
+  await apiClient.delete(`/settings/delay-policies/${id}`)
+}
+
+export interface CalendarRequest {
+  name: string
+  description?: string | null
+  is_default?: boolean
+}
+
+export const getCalendars = async (): Promise<BusinessCalendar[]> => {
+  const res = await apiClient.get<{ data: BusinessCalendar[] }>('/settings/calendars')
+  return res.data.data
+}
+
+export const createCalendar = async (data: CalendarRequest): Promise<BusinessCalendar> => {
+  const res = await apiClient.post<BusinessCalendar>('/settings/calendars', data)
+  return res.data
+}
+
+export const updateCalendar = async (id: number, data: CalendarRequest): Promise<BusinessCalendar> => {
+  const res = await apiClient.put<BusinessCalendar>(`/settings/calendars/${id}`, data)
   return res.data
 }
 
-export const updateJiraSettings = async (data: UpdateJiraSettingsRequest): Promise<void> => {
-  await apiClient.put('/settings/jira', data)
+export const deleteCalendar = async (id: number): Promise<void> => {
+  await apiClient.delete(`/settings/calendars/${id}`)
 }
 
-export const testJiraConnection = async (data?: Partial<UpdateJiraSettingsRequest>): Promise<void> => {
-  await apiClient.post('/settings/jira/test', data ?? {})
+export const getHolidays = async (calendarId: number, year?: number): Promise<Holiday[]> => {
+  const res = await apiClient.get<{ data: Holiday[] }>(`/settings/calendars/${calendarId}/holidays`, {
+    params: year ? { year } : undefined,
+  })
+  return res.data.data
 }
 
-export const triggerSync = async (): Promise<{ log_id: number }> => {
-  const res = await apiClient.post<{ log_id: number }>('/settings/jira/sync')
+export const addHoliday = async (calendarId: number, data: Holiday): Promise<Holiday> => {
+  const res = await apiClient.post<Holiday>(`/settings/calendars/${calendarId}/holidays`, data)
   return res.data
 }
+
+export const deleteHoliday = async (calendarId: number, date: string): Promise<void> => {
+  await apiClient.delete(`/settings/calendars/${calendarId}/holidays/${date}`)
+}
+
+// 祝日リスト（CSV、内閣府の syukujitsu.csv も可）を取り込む
+export const importHolidays = async (
+  calendarId: number,
+  file: File,
+  replace = false,
+): Promise<{ imported: number; removed: number }> => {
+  const form = new FormData()
+  form.append('file', file)
+  const res = await apiClient.post<{ imported: number; removed: number }>(
+    `/settings/calendars/${calendarId}/holidays/import`,
+    form,
+    { params: replace ? { replace: true } : undefined },
+  )
+  return res.data
+}
+
+export const getFieldMappings = async (connectionId: number): Promise<FieldMapping[]> => {
+  const res = await apiClient.get<{ data: FieldMapping[] }>(
+    `/settings/jira-connections/${connectionId}/field-mappings`,
+  )
+  return res.data.data
+}
+
+export const updateFieldMapping = async (
+  connectionId: number,
+  column: FieldMappingColumn,
+  jiraFieldId: string,
+): Promise<FieldMapping> => {
+  const res = await apiClient.put<FieldMapping>(
+    `/settings/jira-connections/${connectionId}/field-mappings/${column}`,
+    { jira_field_id: jiraFieldId },
+  )
+  return res.data
+}
+
+export const deleteFieldMapping = async (
+  connectionId: number,
+  column: FieldMappingColumn,
+): Promise<void> => {
+  await apiClient.delete(`/settings/jira-connections/${connectionId}/field-mappings/${column}`)
+}
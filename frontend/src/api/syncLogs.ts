@@ -1,7 +1,12 @@
 import apiClient from './apiClient'
-import type { SyncLog } from '../types/settings'
+import type { SyncLog, SyncLogDetail } from '../types/settings'
 
 export const getSyncLogs = async (): Promise<SyncLog[]> => {
   const res = await apiClient.get<{ data: SyncLog[] }>('/sync-logs')
   return res.data.data
 }
+
+export const getSyncLog = async (id: number): Promise<SyncLogDetail> => {
+  const res = await apiClient.get<SyncLogDetail>(`/sync-logs/${id}`)
+  return res.data
+}
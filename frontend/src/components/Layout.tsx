@@ -27,6 +27,7 @@ import {
   Tune as TuneIcon,
 } from '@mui/icons-material'
 import { useNavigate, useLocation } from 'react-router-dom'
+import { logout as revokeSession } from '../api/auth'
 import { useAuthStore } from '../stores/authStore'
 import { useNotificationStore } from '../stores/notificationStore'
 import { canManageUsers, canAccessSettings } from '../utils/permissions'
@@ -69,6 +70,15 @@ function SidebarContent() {
   const user       = useAuthStore((s) => s.user)
   const logout     = useAuthStore((s) => s.logout)
 
+  // サーバー側のセッション（リフレッシュトークン）を失効させてからログアウトする。失敗してもログアウトは続行する
+  const handleLogout = async () => {
+    const { refreshToken } = useAuthStore.getState()
+    if (refreshToken) {
+      await revokeSession(refreshToken).catch(() => undefined)
+    }
+    logout()
+  }
+
   const menuItems = [
     ...baseMenuItems,
     // ユーザー管理は admin のみ表示
@@ -231,7 +241,7 @@ function SidebarContent() {
           <Tooltip title="ログアウト">
             <IconButton
               size="small"
-              onClick={logout}
+              onClick={handleLogout}
               sx={{
                 color: SIDEBAR_TEXT,
                 '&:hover': { color: '#f87171', bgcolor: alpha('#f87171', 0.1) },
@@ -1,13 +1,25 @@
 import { useCallback, useEffect, useState } from 'react'
+import { useSearchParams } from 'react-router-dom'
 import {
   Alert,
   Box,
   Button,
   Chip,
   CircularProgress,
+  Dialog,
+  DialogActions,
+  DialogContent,
+  DialogTitle,
   Divider,
+  FormControl,
+  FormControlLabel,
+  IconButton,
+  InputLabel,
+  MenuItem,
   Paper,
+  Select,
   Stack,
+  Switch,
   Table,
   TableBody,
   TableCell,
@@ -15,36 +27,82 @@ import {
   TableHead,
   TableRow,
   TextField,
+  Tooltip,
   Typography,
 } from '@mui/material'
 import {
+  Add as AddIcon,
+  Delete as DeleteIcon,
+  Edit as EditIcon,
   Refresh as RefreshIcon,
-  Save as SaveIcon,
   CheckCircle as TestIcon,
   Sync as SyncIcon,
+  Link as LinkIcon,
 } from '@mui/icons-material'
-import { getJiraSettings, updateJiraSettings, testJiraConnection, triggerSync } from '../../api/settings'
+import {
+  authorizeJiraConnection,
+  createJiraConnection,
+  deleteJiraConnection,
+  getJiraConnections,
+  syncJiraConnection,
+  testJiraConnection,
+  testStoredJiraConnection,
+  updateJiraConnection,
+} from '../../api/settings'
 import { getSyncLogs } from '../../api/syncLogs'
-import type { JiraSettings, SyncLog } from '../../types/settings'
+import type { CredentialSource, JiraAPIFlavor, JiraAuthType, JiraConnection, SyncLog } from '../../types/settings'
 
-const statusColor: Record<string, 'info' | 'success' | 'error' | 'default'> = {
+const statusColor: Record<string, 'info' | 'success' | 'warning' | 'error' | 'default'> = {
   RUNNING: 'info',
   SUCCESS: 'success',
-  FAILED: 'error',
+  PARTIAL: 'warning',
+  FAILURE: 'error',
+  ABORTED: 'default',
+}
+
+const credentialSourceLabel: Record<CredentialSource, string> = {
+  env: '環境変数',
+  file: 'ファイル',
+  db: '設定画面',
+  '': '未設定',
+}
+
+const apiFlavorLabel: Record<JiraAPIFlavor, string> = {
+  cloud: 'Jira Cloud',
+  datacenter: 'Jira Data Center',
+}
+
+const authTypeLabel: Record<JiraAuthType, string> = {
+  basic: 'APIトークン',
+  pat: '個人用アクセストークン（PAT）',
+  oauth: 'OAuth 2.0',
 }
 
+const errorMessage = (e: unknown, fallback: string) =>
+  (e as { response?: { data?: { error?: string } } })?.response?.data?.error ?? fallback
+
 export default function JiraSettingsTab() {
-  const [settings, setSettings] = useState<JiraSettings | null>(null)
+  const [searchParams, setSearchParams] = useSearchParams()
+  const [connections, setConnections] = useState<JiraConnection[]>([])
   const [logs, setLogs] = useState<SyncLog[]>([])
   const [loading, setLoading] = useState(true)
+  const [busyId, setBusyId] = useState<number | null>(null)
+  const [successMsg, setSuccessMsg] = useState<string | null>(null)
+  const [errorMsg, setErrorMsg] = useState<string | null>(null)
+
+  // 接続の追加・編集ダイアログ（editing が null の場合は追加）
+  const [dialogOpen, setDialogOpen] = useState(false)
+  const [editing, setEditing] = useState<JiraConnection | null>(null)
+  const [formName, setFormName] = useState('')
   const [formUrl, setFormUrl] = useState('')
+  const [formApiFlavor, setFormApiFlavor] = useState<JiraAPIFlavor>('cloud')
+  const [formAuthType, setFormAuthType] = useState<JiraAuthType>('basic')
   const [formEmail, setFormEmail] = useState('')
   const [formToken, setFormToken] = useState('')
+  const [formWebhookSecret, setFormWebhookSecret] = useState('')
+  const [formEnabled, setFormEnabled] = useState(true)
   const [saving, setSaving] = useState(false)
   const [testing, setTesting] = useState(false)
-  const [syncing, setSyncing] = useState(false)
-  const [successMsg, setSuccessMsg] = useState<string | null>(null)
-  const [errorMsg, setErrorMsg] = useState<string | null>(null)
 
   const showSuccess = (msg: string) => {
     setSuccessMsg(msg)
@@ -58,13 +116,9 @@ export default function JiraSettingsTab() {
   const loadData = useCallback(async () => {
     setLoading(true)
     try {
-      const [s, l] = await Promise.all([getJiraSettings(), getSyncLogs()])
-      setSettings(s)
+      const [c, l] = await Promise.all([getJiraConnections(), getSyncLogs()])
+      setConnections(c)
       setLogs(l)
-      if (s.configured) {
-        setFormUrl(s.jira_url)
-        setFormEmail(s.email)
-      }
     } catch {
       showError('設定の取得に失敗しました')
     } finally {
@@ -76,54 +130,145 @@ export default function JiraSettingsTab() {
     loadData()
   }, [loadData])
 
+  // OAuth の同意フローから戻ってきた場合は結果を表示してクエリを消す
+  useEffect(() => {
+    const result = searchParams.get('jira_oauth')
+    if (!result) return
+    if (result === 'success') {
+      showSuccess('Jiraとの連携が完了しました')
+    } else {
+      showError(`Jiraとの連携に失敗しました: ${searchParams.get('message') ?? ''}`)
+    }
+    const next = new URLSearchParams(searchParams)
+    next.delete('jira_oauth')
+    next.delete('message')
+    next.delete('connection_id')
+    setSearchParams(next, { replace: true })
+  }, [searchParams, setSearchParams])
+
+  const openDialog = (conn: JiraConnection | null) => {
+    setEditing(conn)
+    setFormName(conn?.name ?? '')
+    setFormUrl(conn?.base_url ?? '')
+    setFormApiFlavor(conn?.api_flavor ?? 'cloud')
+    setFormAuthType(conn?.auth_type ?? 'basic')
+    setFormEmail(conn?.email ?? '')
+    setFormToken('')
+    setFormWebhookSecret('')
+    setFormEnabled(conn?.enabled ?? true)
+    setDialogOpen(true)
+  }
+
   const handleSave = async () => {
-    if (!formUrl || !formEmail || !formToken) {
+    const needsEmail = formAuthType === 'basic'
+    const needsToken = formAuthType !== 'oauth' && (!editing || editing.auth_type === 'oauth')
+    if (!formName || !formUrl || (needsEmail && !formEmail) || (needsToken && !formToken)) {
       showError('すべての項目を入力してください')
       return
     }
     setSaving(true)
     try {
-      await updateJiraSettings({ jira_url: formUrl, email: formEmail, api_token: formToken })
-      showSuccess('Jira設定を保存しました')
-      setFormToken('')
+      const data = {
+        name: formName,
+        base_url: formUrl,
+        api_flavor: formApiFlavor,
+        auth_type: formAuthType,
+        email: needsEmail ? formEmail : '',
+        api_token: formAuthType === 'oauth' ? '' : formToken,
+        webhook_secret: formWebhookSecret,
+        enabled: formEnabled,
+      }
+      if (editing) {
+        await updateJiraConnection(editing.id, data)
+      } else {
+        await createJiraConnection(data)
+      }
+      showSuccess(
+        formAuthType === 'oauth'
+          ? 'Jira接続を保存しました。「Jiraと連携」から認可してください'
+          : 'Jira接続を保存しました',
+      )
+      setDialogOpen(false)
       loadData()
     } catch (e: unknown) {
-      const msg = (e as { response?: { data?: { error?: string } } })?.response?.data?.error
-      showError(msg ?? '保存に失敗しました')
+      showError(errorMessage(e, '保存に失敗しました'))
     } finally {
       setSaving(false)
     }
   }
 
-  const handleTest = async () => {
+  const handleTestForm = async () => {
     setTesting(true)
     try {
-      // フォームにトークンが入力済みの場合はその値を使用、なければDB設定を使用
-      const payload = formToken
-        ? { jira_url: formUrl, email: formEmail, api_token: formToken }
-        : {}
-      await testJiraConnection(payload)
+      // 編集中は未入力の項目に保存済みの値を使う
+      const data = {
+        api_flavor: formApiFlavor,
+        auth_type: formAuthType,
+        base_url: formUrl,
+        email: formEmail,
+        api_token: formToken,
+      }
+      if (editing) {
+        await testStoredJiraConnection(editing.id, data)
+      } else {
+        await testJiraConnection(data)
+      }
       showSuccess('Jira接続に成功しました')
     } catch (e: unknown) {
-      const msg = (e as { response?: { data?: { error?: string } } })?.response?.data?.error
-      showError(msg ?? 'Jira接続テストに失敗しました')
+      showError(errorMessage(e, 'Jira接続テストに失敗しました'))
     } finally {
       setTesting(false)
     }
   }
 
-  const handleSync = async () => {
-    setSyncing(true)
+  const handleTest = async (conn: JiraConnection) => {
+    setBusyId(conn.id)
     try {
-      await triggerSync()
-      showSuccess('同期を開始しました')
+      await testStoredJiraConnection(conn.id)
+      showSuccess(`${conn.name}: Jira接続に成功しました`)
+    } catch (e: unknown) {
+      showError(errorMessage(e, 'Jira接続テストに失敗しました'))
+    } finally {
+      setBusyId(null)
+    }
+  }
+
+  const handleSync = async (conn: JiraConnection) => {
+    setBusyId(conn.id)
+    try {
+      await syncJiraConnection(conn.id)
+      showSuccess(`${conn.name}: 同期を開始しました`)
       // 少し待ってからログを再取得
       setTimeout(() => loadData(), 1500)
     } catch (e: unknown) {
-      const msg = (e as { response?: { data?: { error?: string } } })?.response?.data?.error
-      showError(msg ?? '同期の開始に失敗しました')
+      showError(errorMessage(e, '同期の開始に失敗しました'))
+    } finally {
+      setBusyId(null)
+    }
+  }
+
+  const handleAuthorize = async (conn: JiraConnection) => {
+    setBusyId(conn.id)
+    try {
+      // Atlassian の同意画面に遷移する。同意後はこの画面に戻る
+      window.location.href = await authorizeJiraConnection(conn.id)
+    } catch (e: unknown) {
+      showError(errorMessage(e, 'Jiraとの連携を開始できませんでした'))
+      setBusyId(null)
+    }
+  }
+
+  const handleDelete = async (conn: JiraConnection) => {
+    if (!window.confirm(`Jira接続「${conn.name}」を削除しますか？`)) return
+    setBusyId(conn.id)
+    try {
+      await deleteJiraConnection(conn.id)
+      showSuccess('Jira接続を削除しました')
+      loadData()
+    } catch (e: unknown) {
+      showError(errorMessage(e, '削除に失敗しました'))
     } finally {
-      setSyncing(false)
+      setBusyId(null)
     }
   }
 
@@ -138,76 +283,134 @@ export default function JiraSettingsTab() {
         </Alert>
       )}
 
-      {/* Jira接続設定フォーム */}
+      {/* Jira接続一覧 */}
       <Paper variant="outlined" sx={{ p: 3, mb: 3 }}>
-        <Typography variant="h6" sx={{ mb: 2 }}>Jira接続設定</Typography>
-        <Divider sx={{ mb: 2 }} />
-        <Stack spacing={2} sx={{ maxWidth: 480 }}>
-          <TextField
-            label="Jira URL"
-            placeholder="https://yourcompany.atlassian.net"
-            value={formUrl}
-            onChange={(e) => setFormUrl(e.target.value)}
-            size="small"
-            fullWidth
-            helperText={settings?.configured ? `現在: ${settings.jira_url}` : '未設定'}
-          />
-          <TextField
-            label="メールアドレス"
-            value={formEmail}
-            onChange={(e) => setFormEmail(e.target.value)}
-            size="small"
-            fullWidth
-          />
-          <TextField
-            label="APIトークン"
-            type="password"
-            value={formToken}
-            onChange={(e) => setFormToken(e.target.value)}
-            size="small"
-            fullWidth
-            helperText={settings?.configured ? `現在: ${settings.api_token_mask}` : '未設定'}
-            placeholder={settings?.configured ? '変更する場合のみ入力' : ''}
-          />
-        </Stack>
-        <Stack direction="row" spacing={2} sx={{ mt: 3 }}>
-          <Button
-            variant="contained"
-            startIcon={saving ? <CircularProgress size={16} color="inherit" /> : <SaveIcon />}
-            onClick={handleSave}
-            disabled={saving}
-          >
-            保存
-          </Button>
-          <Button
-            variant="outlined"
-            startIcon={testing ? <CircularProgress size={16} color="inherit" /> : <TestIcon />}
-            onClick={handleTest}
-            disabled={testing || (!settings?.configured && !formToken)}
-          >
-            接続テスト
+        <Stack direction="row" alignItems="center" justifyContent="space-between" sx={{ mb: 2 }}>
+          <Typography variant="h6">Jira接続</Typography>
+          <Button variant="contained" size="small" startIcon={<AddIcon />} onClick={() => openDialog(null)}>
+            接続を追加
           </Button>
         </Stack>
+        <Divider sx={{ mb: 2 }} />
+
+        {connections.length === 0 ? (
+          <Typography variant="body2" color="text.secondary" sx={{ py: 2, textAlign: 'center' }}>
+            Jira接続がありません
+          </Typography>
+        ) : (
+          <TableContainer>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>名前</TableCell>
+                  <TableCell>Jira URL</TableCell>
+                  <TableCell>種別</TableCell>
+                  <TableCell>認証方式</TableCell>
+                  <TableCell>メールアドレス</TableCell>
+                  <TableCell>APIトークン</TableCell>
+                  <TableCell>認証情報</TableCell>
+                  <TableCell align="right">PJ数</TableCell>
+                  <TableCell>状態</TableCell>
+                  <TableCell align="center">操作</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {connections.map((conn) => (
+                  <TableRow key={conn.id} sx={{ opacity: conn.enabled ? 1 : 0.5 }}>
+                    <TableCell>{conn.name}</TableCell>
+                    <TableCell>{conn.base_url || '未設定'}</TableCell>
+                    <TableCell>{apiFlavorLabel[conn.api_flavor]}</TableCell>
+                    <TableCell>
+                      {authTypeLabel[conn.auth_type]}
+                      {conn.auth_type === 'oauth' && (
+                        <Chip
+                          label={conn.oauth_connected ? '連携済み' : '未連携'}
+                          size="small"
+                          color={conn.oauth_connected ? 'success' : 'warning'}
+                          sx={{ ml: 1 }}
+                        />
+                      )}
+                    </TableCell>
+                    <TableCell>{conn.auth_type === 'basic' ? conn.email || '未設定' : '-'}</TableCell>
+                    <TableCell>{conn.auth_type === 'oauth' ? '-' : conn.api_token_mask}</TableCell>
+                    <TableCell>
+                      <Chip
+                        label={credentialSourceLabel[conn.credential_source]}
+                        size="small"
+                        variant="outlined"
+                        color={conn.credential_source === '' ? 'warning' : 'default'}
+                      />
+                    </TableCell>
+                    <TableCell align="right">{conn.project_count}</TableCell>
+                    <TableCell>
+                      <Chip
+                        label={conn.enabled ? '有効' : '無効'}
+                        size="small"
+                        color={conn.enabled ? 'success' : 'default'}
+                      />
+                    </TableCell>
+                    <TableCell align="center">
+                      <Stack direction="row" spacing={0.5} justifyContent="center">
+                        <Tooltip title="編集">
+                          <IconButton size="small" onClick={() => openDialog(conn)}>
+                            <EditIcon fontSize="small" />
+                          </IconButton>
+                        </Tooltip>
+                        {conn.auth_type === 'oauth' && (
+                          <Tooltip title="Jiraと連携">
+                            <span>
+                              <IconButton size="small" onClick={() => handleAuthorize(conn)} disabled={busyId === conn.id}>
+                                <LinkIcon fontSize="small" />
+                              </IconButton>
+                            </span>
+                          </Tooltip>
+                        )}
+                        <Tooltip title="接続テスト">
+                          <span>
+                            <IconButton size="small" onClick={() => handleTest(conn)} disabled={busyId === conn.id}>
+                              <TestIcon fontSize="small" />
+                            </IconButton>
+                          </span>
+                        </Tooltip>
+                        <Tooltip title="今すぐ同期">
+                          <span>
+                            <IconButton
+                              size="small"
+                              onClick={() => handleSync(conn)}
+                              disabled={busyId === conn.id || !conn.enabled}
+                            >
+                              <SyncIcon fontSize="small" />
+                            </IconButton>
+                          </span>
+                        </Tooltip>
+                        <Tooltip title={conn.project_count > 0 ? '同期済みのプロジェクトがあるため削除できません' : '削除'}>
+                          <span>
+                            <IconButton
+                              size="small"
+                              onClick={() => handleDelete(conn)}
+                              disabled={busyId === conn.id || conn.project_count > 0}
+                            >
+                              <DeleteIcon fontSize="small" />
+                            </IconButton>
+                          </span>
+                        </Tooltip>
+                      </Stack>
+                    </TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        )}
       </Paper>
 
-      {/* 同期管理 */}
+      {/* 同期ログ */}
       <Paper variant="outlined" sx={{ p: 3 }}>
         <Stack direction="row" alignItems="center" justifyContent="space-between" sx={{ mb: 2 }}>
           <Typography variant="h6">データ同期</Typography>
-          <Stack direction="row" spacing={1}>
-            <Button size="small" startIcon={<RefreshIcon />} onClick={loadData}>
-              更新
-            </Button>
-            <Button
-              variant="contained"
-              size="small"
-              startIcon={syncing ? <CircularProgress size={14} color="inherit" /> : <SyncIcon />}
-              onClick={handleSync}
-              disabled={syncing || !settings?.configured}
-            >
-              今すぐ同期
-            </Button>
-          </Stack>
+          <Button size="small" startIcon={<RefreshIcon />} onClick={loadData}>
+            更新
+          </Button>
         </Stack>
         <Divider sx={{ mb: 2 }} />
 
@@ -221,6 +424,7 @@ export default function JiraSettingsTab() {
               <TableHead>
                 <TableRow>
                   <TableCell>開始日時</TableCell>
+                  <TableCell>接続</TableCell>
                   <TableCell>ステータス</TableCell>
                   <TableCell align="right">PJ数</TableCell>
                   <TableCell align="right">チケット数</TableCell>
@@ -233,6 +437,7 @@ export default function JiraSettingsTab() {
                     <TableCell>
                       {new Date(log.executed_at).toLocaleString('ja-JP')}
                     </TableCell>
+                    <TableCell>{log.connection_name ?? '-'}</TableCell>
                     <TableCell>
                       <Chip
                         label={log.status}
@@ -257,6 +462,123 @@ export default function JiraSettingsTab() {
           </TableContainer>
         )}
       </Paper>
+
+      {/* 接続の追加・編集ダイアログ */}
+      <Dialog open={dialogOpen} onClose={() => setDialogOpen(false)} maxWidth="sm" fullWidth>
+        <DialogTitle>{editing ? 'Jira接続を編集' : 'Jira接続を追加'}</DialogTitle>
+        <DialogContent>
+          <Stack spacing={2} sx={{ pt: 1 }}>
+            {editing && (editing.credential_source === 'env' || editing.credential_source === 'file') && (
+              <Alert severity="info">
+                この接続の同期には{credentialSourceLabel[editing.credential_source]}の認証情報が使われるため、ここで保存した値は同期に反映されません。
+              </Alert>
+            )}
+            <TextField
+              label="名前"
+              value={formName}
+              onChange={(e) => setFormName(e.target.value)}
+              size="small"
+              fullWidth
+            />
+            <TextField
+              label="Jira URL"
+              placeholder="https://yourcompany.atlassian.net"
+              value={formUrl}
+              onChange={(e) => setFormUrl(e.target.value)}
+              size="small"
+              fullWidth
+            />
+            <FormControl size="small" fullWidth>
+              <InputLabel>種別</InputLabel>
+              <Select
+                value={formApiFlavor}
+                label="種別"
+                onChange={(e) => {
+                  const flavor = e.target.value as JiraAPIFlavor
+                  setFormApiFlavor(flavor)
+                  // OAuth 2.0 は Jira Cloud でのみ使える
+                  if (flavor === 'datacenter' && formAuthType !== 'pat') {
+                    setFormAuthType('pat')
+                  }
+                }}
+              >
+                <MenuItem value="cloud">{apiFlavorLabel.cloud}（REST API v3）</MenuItem>
+                <MenuItem value="datacenter">{apiFlavorLabel.datacenter} / Server（REST API v2）</MenuItem>
+              </Select>
+            </FormControl>
+            <FormControl size="small" fullWidth>
+              <InputLabel>認証方式</InputLabel>
+              <Select
+                value={formAuthType}
+                label="認証方式"
+                onChange={(e) => setFormAuthType(e.target.value as JiraAuthType)}
+              >
+                <MenuItem value="basic">{authTypeLabel.basic}（Jira Cloud）</MenuItem>
+                <MenuItem value="pat">{authTypeLabel.pat}（Jira Data Center）</MenuItem>
+                <MenuItem value="oauth" disabled={formApiFlavor !== 'cloud'}>
+                  {authTypeLabel.oauth}（Jira Cloud）
+                </MenuItem>
+              </Select>
+            </FormControl>
+            {formAuthType === 'basic' && (
+              <TextField
+                label="メールアドレス"
+                value={formEmail}
+                onChange={(e) => setFormEmail(e.target.value)}
+                size="small"
+                fullWidth
+              />
+            )}
+            {formAuthType === 'oauth' ? (
+              <Alert severity="info">
+                保存後に一覧の「Jiraと連携」から Atlassian アカウントで認可してください。
+              </Alert>
+            ) : (
+              <TextField
+                label={formAuthType === 'pat' ? '個人用アクセストークン' : 'APIトークン'}
+                type="password"
+                value={formToken}
+                onChange={(e) => setFormToken(e.target.value)}
+                size="small"
+                fullWidth
+                helperText={editing && editing.auth_type !== 'oauth' ? `現在: ${editing.api_token_mask}` : undefined}
+                placeholder={editing && editing.auth_type !== 'oauth' ? '変更する場合のみ入力' : ''}
+              />
+            )}
+            <TextField
+              label="Webhook シークレット"
+              type="password"
+              value={formWebhookSecret}
+              onChange={(e) => setFormWebhookSecret(e.target.value)}
+              size="small"
+              fullWidth
+              helperText={
+                editing?.webhook_secret_set
+                  ? '設定済み。変更する場合のみ入力'
+                  : 'Jira の Webhook に設定する署名用のシークレット（任意）'
+              }
+            />
+            <FormControlLabel
+              control={<Switch checked={formEnabled} onChange={(e) => setFormEnabled(e.target.checked)} />}
+              label="同期を有効にする"
+            />
+          </Stack>
+        </DialogContent>
+        <DialogActions>
+          <Button
+            startIcon={testing ? <CircularProgress size={16} color="inherit" /> : <TestIcon />}
+            onClick={handleTestForm}
+            disabled={testing || formAuthType === 'oauth' || (!editing && !formToken)}
+          >
+            接続テスト
+          </Button>
+          <Box sx={{ flexGrow: 1 }} />
+          <Button onClick={() => setDialogOpen(false)}>キャンセル</Button>
+          <Button onClick={handleSave} variant="contained" disabled={saving}>
+            {saving ? '保存中...' : '保存'}
+          </Button>
+        </DialogActions>
+      </Dialog>
     </Box>
   )
 }
@@ -27,8 +27,8 @@ export default function Login() {
     setError(null)
     setLoading(true)
     try {
-      const { token, user } = await login({ email, password })
-      authLogin(token, user)
+      const { token, user, refreshToken } = await login({ email, password })
+      authLogin(token, user, refreshToken)
       navigate('/', { replace: true })
     } catch (err: unknown) {
       if (
@@ -26,7 +26,7 @@ Object.defineProperty(window, 'location', {
 
 beforeEach(() => {
   locationHref = ''
-  useAuthStore.setState({ token: null, user: null })
+  useAuthStore.setState({ token: null, refreshToken: null, user: null })
 })
 
 describe('login', () => {
@@ -38,17 +38,39 @@ describe('login', () => {
     expect(state.token).toBe('my-token')
     expect(state.user).toEqual(mockUser)
   })
+
+  it('stores the refresh token', () => {
+    const { login } = useAuthStore.getState()
+    login('my-token', mockUser, 'my-refresh-token')
+
+    expect(useAuthStore.getState().refreshToken).toBe('my-refresh-token')
+  })
+})
+
+describe('setTokens', () => {
+  it('replaces both tokens and keeps the user', () => {
+    useAuthStore.setState({ token: 'old-token', refreshToken: 'old-refresh', user: mockUser })
+
+    const { setTokens } = useAuthStore.getState()
+    setTokens('new-token', 'new-refresh')
+
+    const state = useAuthStore.getState()
+    expect(state.token).toBe('new-token')
+    expect(state.refreshToken).toBe('new-refresh')
+    expect(state.user).toEqual(mockUser)
+  })
 })
 
 describe('logout', () => {
   it('clears token and user', () => {
-    useAuthStore.setState({ token: 'old-token', user: mockUser })
+    useAuthStore.setState({ token: 'old-token', refreshToken: 'old-refresh', user: mockUser })
 
     const { logout } = useAuthStore.getState()
     logout()
 
     const state = useAuthStore.getState()
     expect(state.token).toBeNull()
+    expect(state.refreshToken).toBeNull()
     expect(state.user).toBeNull()
   })
 
@@ -11,8 +11,11 @@ export interface AuthUser {
 
 interface AuthState {
   token: string | null
+  refreshToken: string | null
   user: AuthUser | null
-  login: (token: string, user: AuthUser) => void
+  login: (token: string, user: AuthUser, refreshToken?: string | null) => void
+  // アクセストークンの更新（リフレッシュトークンもローテーションされる）
+  setTokens: (token: string, refreshToken: string, user?: AuthUser) => void
   logout: () => void
   isAuthenticated: () => boolean
 }
@@ -21,14 +24,19 @@ export const useAuthStore = create<AuthState>()(
   persist(
     (set, get) => ({
       token: null,
+      refreshToken: null,
       user: null,
 
-      login: (token: string, user: AuthUser) => {
-        set({ token, user })
+      login: (token: string, user: AuthUser, refreshToken: string | null = null) => {
+        set({ token, user, refreshToken })
+      },
+
+      setTokens: (token: string, refreshToken: string, user?: AuthUser) => {
+        set(user ? { token, refreshToken, user } : { token, refreshToken })
       },
 
       logout: () => {
-        set({ token: null, user: null })
+        set({ token: null, refreshToken: null, user: null })
         window.location.href = '/login'
       },
 
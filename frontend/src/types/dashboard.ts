@@ -38,6 +38,8 @@ export interface ProjectIssueSummary {
   green_count: number
   open_count: number
   total_count: number
+  slipped_count: number
+  postpone_count: number
 }
 
 export interface ProjectSummaryResponse {
@@ -46,6 +48,35 @@ export interface ProjectSummaryResponse {
   summary: ProjectIssueSummary
 }
 
+export type TrendGranularity = 'day' | 'week'
+
+export interface TrendPoint {
+  date: string
+  red_issues: number
+  yellow_issues: number
+  green_issues: number
+  total_issues: number
+  red_projects: number
+  yellow_projects: number
+  green_projects: number
+  total_projects: number
+}
+
+export interface TrendsParams {
+  organization_id?: number
+  from?: string
+  to?: string
+  granularity?: TrendGranularity
+}
+
+export interface TrendsResponse {
+  organization_id: number | null
+  from: string
+  to: string
+  granularity: TrendGranularity
+  data: TrendPoint[]
+}
+
 export interface DashboardOrgNode extends DashboardOrg {
   children: DashboardOrgNode[]
 }
@@ -3,6 +3,7 @@ import type { PaginationMeta } from './project'
 
 export interface Issue {
   id: number
+  connection_id: number
   jira_issue_id: string
   jira_issue_key: string
   project_id: number
@@ -12,6 +13,10 @@ export interface Issue {
   status: string
   status_category: string
   due_date: string | null
+  original_due_date: string | null
+  postpone_count: number
+  slipped: boolean
+  slip_days: number
   assignee_name: string | null
   assignee_account_id: string | null
   delay_status: DelayStatus
@@ -20,6 +25,14 @@ export interface Issue {
   last_updated_at: string
   created_at: string
   updated_at: string
+  start_date: string | null
+  story_points: number | null
+  epic_key: string | null
+  sprint_name: string | null
+  labels: string[]
+  components: string[]
+  parent_id: number | null
+  parent_issue_key: string | null
 }
 
 export interface IssueListResponse {
@@ -27,7 +40,13 @@ export interface IssueListResponse {
   pagination: PaginationMeta
 }
 
-export type IssueSortKey = 'due_date' | 'last_updated_at' | 'jira_issue_key' | 'delay_status'
+export type IssueSortKey =
+  | 'due_date'
+  | 'last_updated_at'
+  | 'jira_issue_key'
+  | 'delay_status'
+  | 'start_date'
+  | 'story_points'
 export type SortOrder = 'asc' | 'desc'
 
 export interface IssueListParams {
@@ -40,4 +59,73 @@ export interface IssueListParams {
   no_due_date?: boolean
   status_category?: string
   assignee_name?: string
+  slipped?: boolean
+  min_slip_days?: number
+  epic_key?: string
+  sprint_name?: string
+  label?: string
+  component?: string
+  start_date_from?: string // YYYY-MM-DD
+  start_date_to?: string // YYYY-MM-DD
+  min_story_points?: number
+  max_story_points?: number
+}
+
+export type IssueHistoryField = 'status' | 'duedate'
+
+export interface IssueHistory {
+  id: number
+  field: IssueHistoryField
+  from_value: string | null
+  to_value: string | null
+  author_account_id: string | null
+  author_name: string | null
+  changed_at: string
+}
+
+export interface IssueHistoryResponse {
+  data: IssueHistory[]
+}
+
+export interface IssueRollup {
+  total_count: number
+  done_count: number
+  red_count: number
+  yellow_count: number
+  green_count: number
+  progress_percent: number
+  delay_status: DelayStatus
+}
+
+export interface IssueChildrenResponse {
+  data: Issue[]
+  rollup: IssueRollup
+}
+
+export interface IssueLink {
+  id: number
+  link_type: string
+  direction: 'outward' | 'inward'
+  description: string | null
+  linked_issue_key: string
+  linked_issue_id: number | null
+}
+
+export interface IssueLinksResponse {
+  data: IssueLink[]
+}
+
+export interface Epic extends Issue {
+  rollup: IssueRollup
+}
+
+export interface EpicListResponse {
+  data: Epic[]
+  pagination: PaginationMeta
+}
+
+export interface EpicListParams {
+  page?: number
+  per_page?: number
+  project_id?: number
 }
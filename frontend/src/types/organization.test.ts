@@ -6,6 +6,8 @@ const makeOrg = (partial: Partial<Organization> & Pick<Organization, 'id' | 'nam
   parent_id: null,
   path: `/${partial.id}/`,
   level: 0,
+  business_calendar_id: null,
+  timezone: null,
   created_at: '2026-01-01T00:00:00Z',
   updated_at: '2026-01-01T00:00:00Z',
   total_projects: 0,
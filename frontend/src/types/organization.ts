@@ -6,6 +6,10 @@ export interface Organization {
   parent_id: number | null
   path: string
   level: number
+  // null は上位の組織の営業日カレンダーを継承する
+  business_calendar_id: number | null
+  // IANA タイムゾーン名。null は上位の組織（最上位では REPORT_TIMEZONE）を継承する
+  timezone: string | null
   created_at: string
   updated_at: string
   total_projects: number
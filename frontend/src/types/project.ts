@@ -34,3 +34,61 @@ export interface ProjectListResponse {
 export type SortOption = 'name' | 'name_desc' | 'delay_count'
 
 export type DelayFilter = 'ALL' | DelayStatus
+
+export interface Release {
+  id: number
+  jira_version_id: string
+  name: string
+  description: string | null
+  start_date: string | null
+  release_date: string | null
+  released: boolean
+  archived: boolean
+  total_count: number
+  open_count: number
+  red_count: number
+  yellow_count: number
+  due_after_release_count: number
+  delay_status: DelayStatus
+}
+
+export interface ReleaseListResponse {
+  data: Release[]
+}
+
+export type SprintState = 'future' | 'active' | 'closed'
+
+export interface Sprint {
+  id: number
+  jira_sprint_id: number
+  board_id: number
+  board_name: string
+  name: string
+  state: SprintState
+  goal: string | null
+  start_date: string | null
+  end_date: string | null
+  complete_date: string | null
+  total_count: number
+  done_count: number
+}
+
+export interface SprintListResponse {
+  data: Sprint[]
+}
+
+export interface SprintHealth extends Sprint {
+  committed_count: number
+  added_count: number
+  committed_done_count: number
+  carried_over_count: number
+  red_count: number
+  open_count: number
+  days_remaining: number | null
+  progress_percent: number
+  delay_status: DelayStatus
+}
+
+export interface SprintHealthResponse {
+  data: SprintHealth[]
+}
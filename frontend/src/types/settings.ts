@@ -1,21 +1,105 @@
-export interface JiraSettings {
+import type { DelayStatus } from './project'
+
+// 同期で使う認証情報の取得元（JIRA_CREDENTIAL_SOURCES の優先順位で解決。未設定は空文字）
+export type CredentialSource = 'env' | 'file' | 'db' | ''
+
+// 接続先の種別（cloud: Jira Cloud の REST API v3、datacenter: Jira Data Center / Server の REST API v2）
+export type JiraAPIFlavor = 'cloud' | 'datacenter'
+
+// 認証方式（basic: メール + API トークン、pat: Data Center の個人用アクセストークン、oauth: OAuth 2.0 (3LO)）
+export type JiraAuthType = 'basic' | 'pat' | 'oauth'
+
+export interface JiraConnection {
   id: number
-  jira_url: string
+  name: string
+  base_url: string
+  api_flavor: JiraAPIFlavor
+  auth_type: JiraAuthType
   email: string
   api_token_mask: string
-  configured: boolean
+  credential_source: CredentialSource
+  cloud_id: string
+  oauth_connected: boolean // OAuth トークンが保存済みか
+  webhook_secret_set: boolean // Webhook シークレットが保存済みか
+  enabled: boolean
+  project_count: number
   created_at: string
   updated_at: string
 }
 
 export interface SyncLog {
   id: number
+  connection_id: number | null
+  connection_name: string | null
   sync_type: string
   executed_at: string
   completed_at: string | null
-  status: 'RUNNING' | 'SUCCESS' | 'FAILED'
+  status: 'RUNNING' | 'SUCCESS' | 'PARTIAL' | 'FAILURE' | 'ABORTED'
   projects_synced: number
   issues_synced: number
   error_message: string | null
   duration_seconds: number | null
 }
+
+export interface SyncProjectResult {
+  id: number
+  project_id: number | null
+  jira_project_id: string
+  project_key: string
+  status: 'SUCCESS' | 'FAILURE'
+  issues_synced: number
+  error_message: string | null
+  duration_ms: number
+}
+
+export interface SyncLogDetail extends SyncLog {
+  failed_projects: number
+  projects: SyncProjectResult[]
+}
+
+// null の項目は上位（組織 → 全体デフォルト）のポリシーを継承する
+export interface DelayPolicySettings {
+  yellow_days: number | null
+  no_due_date_status: DelayStatus | null
+}
+
+export interface DelayPolicy extends DelayPolicySettings {
+  id: number
+  scope: 'global' | 'organization' | 'project'
+  organization_id: number | null
+  organization_name: string | null
+  project_id: number | null
+  project_key: string | null
+  created_at: string
+  updated_at: string
+}
+
+export interface BusinessCalendar {
+  id: number
+  name: string
+  description: string | null
+  is_default: boolean
+  holiday_count: number
+  organization_count: number
+  created_at: string
+  updated_at: string
+}
+
+export interface Holiday {
+  date: string // YYYY-MM-DD
+  name: string
+}
+
+export type FieldMappingColumn =
+  | 'start_date'
+  | 'story_points'
+  | 'epic_key'
+  | 'sprint_name'
+  | 'labels'
+  | 'components'
+
+export interface FieldMapping {
+  column: FieldMappingColumn
+  jira_field_id: string | null // 未設定の場合は null
+  updated_at: string | null
+}